@@ -0,0 +1,412 @@
+// Package archive provides export and import of the complete state of the
+// system, for backups or for moving data between environments.
+//
+// An archive is a stream of JSON objects, one per line. The first line is a
+// header that holds the format version. Every following line is a record that
+// holds a single location, voyage, handling event, cargo, booking amendment or
+// webhook subscription:
+//
+//	{"type":"header","version":1,"created":"2016-03-21T19:50:24Z"}
+//	{"type":"location","data":{"unlocode":"SESTO","name":"Stockholm"}}
+//	{"type":"cargo","data":{"tracking_id":"ABC123",...}}
+//
+// Only the state that can't be derived is archived. The delivery progress of
+// a cargo is derived from its handling history when imported. Webhook
+// subscriptions are archived with their secrets, so archives must be kept as
+// safe as the database.
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+// Version is the version of the archive format written by Export. Version 2
+// added amendments and subscriptions. Archives of earlier versions can still
+// be imported.
+const Version = 2
+
+// ErrUnsupportedVersion is returned when importing an archive written in an
+// unknown format.
+var ErrUnsupportedVersion = errors.New("unsupported archive version")
+
+// Repositories holds the repositories to export from or import into.
+type Repositories struct {
+	Cargos         shipping.CargoRepository
+	Locations      shipping.LocationRepository
+	Voyages        shipping.VoyageRepository
+	HandlingEvents shipping.HandlingEventRepository
+	Amendments     booking.AmendmentRepository
+	Subscriptions  webhook.SubscriptionRepository
+}
+
+// Record types.
+const (
+	typeHeader        = "header"
+	typeLocation      = "location"
+	typeVoyage        = "voyage"
+	typeHandlingEvent = "handling_event"
+	typeCargo         = "cargo"
+	typeAmendment     = "amendment"
+	typeSubscription  = "subscription"
+)
+
+type header struct {
+	Type    string    `json:"type"`
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+}
+
+type record struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type location struct {
+	UNLocode string `json:"unlocode"`
+	Name     string `json:"name"`
+}
+
+type voyage struct {
+	VoyageNumber string            `json:"voyage_number"`
+	Schedule     []carrierMovement `json:"schedule"`
+}
+
+type carrierMovement struct {
+	DepartureLocation string    `json:"departure_location"`
+	ArrivalLocation   string    `json:"arrival_location"`
+	DepartureTime     time.Time `json:"departure_time"`
+	ArrivalTime       time.Time `json:"arrival_time"`
+}
+
+type handlingEvent struct {
+	TrackingID   string `json:"tracking_id"`
+	Type         string `json:"type"`
+	Location     string `json:"location"`
+	VoyageNumber string `json:"voyage_number,omitempty"`
+}
+
+type cargo struct {
+	TrackingID        string          `json:"tracking_id"`
+	Origin            string          `json:"origin"`
+	SpecOrigin        string          `json:"route_origin"`
+	Destination       string          `json:"destination"`
+	ArrivalDeadline   time.Time       `json:"arrival_deadline"`
+	EarliestDeparture *time.Time      `json:"earliest_departure,omitempty"`
+	MaxLegs           int             `json:"max_legs,omitempty"`
+	AvoidLocations    []string        `json:"avoid_locations,omitempty"`
+	Legs              []shipping.Leg  `json:"legs,omitempty"`
+	Price             *shipping.Money `json:"price,omitempty"`
+}
+
+// Export writes every location, voyage, cargo, handling event, amendment and
+// webhook subscription in repos to w.
+//
+// Records are written in an order that allows them to be imported one at a
+// time: handling events are written before the cargos they belong to.
+// Amendments and subscriptions are written as they are returned by the
+// booking and webhook APIs.
+func Export(ctx context.Context, w io.Writer, repos Repositories) error {
+	enc := json.NewEncoder(w)
+
+	if err := enc.Encode(header{Type: typeHeader, Version: Version, Created: time.Now().UTC()}); err != nil {
+		return err
+	}
+
+	write := func(typ string, v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return enc.Encode(record{Type: typ, Data: data})
+	}
+
+	for _, l := range repos.Locations.FindAll(ctx) {
+		if err := write(typeLocation, location{
+			UNLocode: string(l.UNLocode),
+			Name:     l.Name,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range repos.Voyages.FindAll(ctx) {
+		var movements []carrierMovement
+		for _, m := range v.Schedule.CarrierMovements {
+			movements = append(movements, carrierMovement{
+				DepartureLocation: string(m.DepartureLocation),
+				ArrivalLocation:   string(m.ArrivalLocation),
+				DepartureTime:     m.DepartureTime,
+				ArrivalTime:       m.ArrivalTime,
+			})
+		}
+		if err := write(typeVoyage, voyage{
+			VoyageNumber: string(v.VoyageNumber),
+			Schedule:     movements,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range repos.Cargos.FindAll(ctx) {
+		h := repos.HandlingEvents.QueryHandlingHistory(ctx, c.TrackingID)
+		for _, e := range h.HandlingEvents {
+			if err := write(typeHandlingEvent, handlingEvent{
+				TrackingID:   string(e.TrackingID),
+				Type:         e.Activity.Type.String(),
+				Location:     string(e.Activity.Location),
+				VoyageNumber: string(e.Activity.VoyageNumber),
+			}); err != nil {
+				return err
+			}
+		}
+
+		rec := cargo{
+			TrackingID:      string(c.TrackingID),
+			Origin:          string(c.Origin),
+			SpecOrigin:      string(c.RouteSpecification.Origin),
+			Destination:     string(c.RouteSpecification.Destination),
+			ArrivalDeadline: c.RouteSpecification.ArrivalDeadline,
+			MaxLegs:         c.RouteSpecification.Constraints.MaxLegs,
+			Legs:            c.Itinerary.Legs,
+		}
+		if t := c.RouteSpecification.EarliestDeparture; !t.IsZero() {
+			rec.EarliestDeparture = &t
+		}
+		for _, l := range c.RouteSpecification.Constraints.AvoidLocations {
+			rec.AvoidLocations = append(rec.AvoidLocations, string(l))
+		}
+		if p := c.Price; !p.IsZero() {
+			rec.Price = &p
+		}
+
+		if err := write(typeCargo, rec); err != nil {
+			return err
+		}
+
+		for _, a := range repos.Amendments.FindByTrackingID(ctx, c.TrackingID) {
+			if err := write(typeAmendment, a); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, s := range repos.Subscriptions.FindAll(ctx) {
+		if err := write(typeSubscription, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Import reads an archive from r and stores its content in repos. Existing
+// locations, voyages, cargos, amendments and subscriptions with the same
+// identity are replaced.
+//
+// Handling events have no identity of their own, so the events of a cargo in
+// the archive are matched by position against its handling history. Events
+// already in the history are skipped, which makes it safe to import the same
+// archive twice, and a history that differs from the archive is an error.
+func Import(ctx context.Context, r io.Reader, repos Repositories) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	if !s.Scan() {
+		if err := s.Err(); err != nil {
+			return err
+		}
+		return errors.New("archive is empty")
+	}
+
+	var h header
+	if err := json.Unmarshal(s.Bytes(), &h); err != nil {
+		return fmt.Errorf("line 1: %v", err)
+	}
+	if h.Type != typeHeader {
+		return fmt.Errorf("line 1: missing header")
+	}
+	if h.Version < 1 || h.Version > Version {
+		return ErrUnsupportedVersion
+	}
+
+	im := importer{repos: repos, histories: make(map[shipping.TrackingID]*history)}
+
+	for line := 2; s.Scan(); line++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var rec record
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+
+		if err := im.importRecord(ctx, rec); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+	}
+
+	return s.Err()
+}
+
+// importer imports records into repos, keeping track of the handling events
+// imported so far.
+type importer struct {
+	repos     Repositories
+	histories map[shipping.TrackingID]*history
+}
+
+// history holds the handling events that a cargo had before the import, and
+// how many of its events in the archive have been imported.
+type history struct {
+	existing []shipping.HandlingEvent
+	imported int
+}
+
+func (im *importer) importRecord(ctx context.Context, rec record) error {
+	repos := im.repos
+
+	switch rec.Type {
+	case typeLocation:
+		var l location
+		if err := json.Unmarshal(rec.Data, &l); err != nil {
+			return err
+		}
+		return repos.Locations.Store(ctx, &shipping.Location{
+			UNLocode: shipping.UNLocode(l.UNLocode),
+			Name:     l.Name,
+		})
+	case typeVoyage:
+		var v voyage
+		if err := json.Unmarshal(rec.Data, &v); err != nil {
+			return err
+		}
+		var movements []shipping.CarrierMovement
+		for _, m := range v.Schedule {
+			movements = append(movements, shipping.CarrierMovement{
+				DepartureLocation: shipping.UNLocode(m.DepartureLocation),
+				ArrivalLocation:   shipping.UNLocode(m.ArrivalLocation),
+				DepartureTime:     m.DepartureTime,
+				ArrivalTime:       m.ArrivalTime,
+			})
+		}
+		return repos.Voyages.Store(ctx, shipping.NewVoyage(shipping.VoyageNumber(v.VoyageNumber), shipping.Schedule{
+			CarrierMovements: movements,
+		}))
+	case typeHandlingEvent:
+		var e handlingEvent
+		if err := json.Unmarshal(rec.Data, &e); err != nil {
+			return err
+		}
+		typ, ok := eventTypes[e.Type]
+		if !ok {
+			return fmt.Errorf("unknown handling event type %q", e.Type)
+		}
+		return im.importHandlingEvent(ctx, shipping.HandlingEvent{
+			TrackingID: shipping.TrackingID(e.TrackingID),
+			Activity: shipping.HandlingActivity{
+				Type:         typ,
+				Location:     shipping.UNLocode(e.Location),
+				VoyageNumber: shipping.VoyageNumber(e.VoyageNumber),
+			},
+		})
+	case typeCargo:
+		var c cargo
+		if err := json.Unmarshal(rec.Data, &c); err != nil {
+			return err
+		}
+		return repos.Cargos.Store(ctx, restoreCargo(ctx, c, repos.HandlingEvents))
+	case typeAmendment:
+		var a booking.Amendment
+		if err := json.Unmarshal(rec.Data, &a); err != nil {
+			return err
+		}
+		return repos.Amendments.Store(ctx, &a)
+	case typeSubscription:
+		var s webhook.Subscription
+		if err := json.Unmarshal(rec.Data, &s); err != nil {
+			return err
+		}
+		return repos.Subscriptions.Store(ctx, &s)
+	}
+
+	return fmt.Errorf("unknown record type %q", rec.Type)
+}
+
+// importHandlingEvent stores e, unless it's already in the handling history
+// of its cargo.
+func (im *importer) importHandlingEvent(ctx context.Context, e shipping.HandlingEvent) error {
+	h, ok := im.histories[e.TrackingID]
+	if !ok {
+		h = &history{existing: im.repos.HandlingEvents.QueryHandlingHistory(ctx, e.TrackingID).HandlingEvents}
+		im.histories[e.TrackingID] = h
+	}
+
+	i := h.imported
+	h.imported++
+
+	if i < len(h.existing) {
+		if h.existing[i] != e {
+			return fmt.Errorf("handling history of cargo %s differs from the archive", e.TrackingID)
+		}
+		return nil
+	}
+
+	im.repos.HandlingEvents.Store(ctx, e)
+	return nil
+}
+
+// restoreCargo rebuilds a cargo aggregate from its archived state and the
+// handling history already imported for it.
+func restoreCargo(ctx context.Context, c cargo, events shipping.HandlingEventRepository) *shipping.Cargo {
+	id := shipping.TrackingID(c.TrackingID)
+
+	rs := shipping.RouteSpecification{
+		Origin:          shipping.UNLocode(c.SpecOrigin),
+		Destination:     shipping.UNLocode(c.Destination),
+		ArrivalDeadline: c.ArrivalDeadline,
+		Constraints:     shipping.RouteConstraints{MaxLegs: c.MaxLegs},
+	}
+	if c.EarliestDeparture != nil {
+		rs.EarliestDeparture = *c.EarliestDeparture
+	}
+	for _, l := range c.AvoidLocations {
+		rs.Constraints.AvoidLocations = append(rs.Constraints.AvoidLocations, shipping.UNLocode(l))
+	}
+
+	res := shipping.NewCargo(id, rs)
+	res.Origin = shipping.UNLocode(c.Origin)
+
+	if len(c.Legs) > 0 {
+		res.AssignToRoute(shipping.Itinerary{Legs: c.Legs})
+	}
+	if c.Price != nil {
+		res.Price = *c.Price
+	}
+
+	res.DeriveDeliveryProgress(events.QueryHandlingHistory(ctx, id))
+
+	return res
+}
+
+var eventTypes = map[string]shipping.HandlingEventType{
+	shipping.Receive.String(): shipping.Receive,
+	shipping.Load.String():    shipping.Load,
+	shipping.Unload.String():  shipping.Unload,
+	shipping.Customs.String(): shipping.Customs,
+	shipping.Claim.String():   shipping.Claim,
+}
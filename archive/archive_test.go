@@ -0,0 +1,209 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+func newRepositories() Repositories {
+	return Repositories{
+		Cargos:         inmem.NewCargoRepository(),
+		Locations:      inmem.NewLocationRepository(),
+		Voyages:        inmem.NewVoyageRepository(),
+		HandlingEvents: inmem.NewHandlingEventRepository(),
+		Amendments:     inmem.NewAmendmentRepository(),
+		Subscriptions:  inmem.NewSubscriptionRepository(),
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+
+	src := newRepositories()
+
+	c := shipping.NewCargo("ABC123", shipping.RouteSpecification{
+		Origin:            shipping.CNHKG,
+		Destination:       shipping.SESTO,
+		ArrivalDeadline:   time.Date(2009, time.March, 18, 12, 0, 0, 0, time.UTC),
+		EarliestDeparture: time.Date(2009, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Constraints: shipping.RouteConstraints{
+			MaxLegs:        3,
+			AvoidLocations: []shipping.UNLocode{shipping.DEHAM},
+		},
+	})
+	c.AssignToRoute(shipping.Itinerary{Legs: []shipping.Leg{
+		shipping.NewLeg("V100", shipping.CNHKG, shipping.USNYC,
+			time.Date(2009, time.March, 3, 12, 0, 0, 0, time.UTC),
+			time.Date(2009, time.March, 9, 12, 0, 0, 0, time.UTC)),
+	}})
+	c.Price = shipping.Money{Amount: 180000, Currency: "USD"}
+
+	events := []shipping.HandlingEvent{
+		{TrackingID: c.TrackingID, Activity: shipping.HandlingActivity{Type: shipping.Receive, Location: shipping.CNHKG}},
+		{TrackingID: c.TrackingID, Activity: shipping.HandlingActivity{Type: shipping.Load, Location: shipping.CNHKG, VoyageNumber: "V100"}},
+	}
+	for _, e := range events {
+		src.HandlingEvents.Store(ctx, e)
+	}
+	c.DeriveDeliveryProgress(src.HandlingEvents.QueryHandlingHistory(ctx, c.TrackingID))
+
+	if err := src.Cargos.Store(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Locations.Store(ctx, shipping.Helsinki); err != nil {
+		t.Fatal(err)
+	}
+
+	amendment := &booking.Amendment{
+		ID:         "A1",
+		TrackingID: c.TrackingID,
+		Type:       booking.RouteAssigned,
+		Actor:      "alice",
+		Time:       time.Date(2009, time.March, 2, 12, 0, 0, 0, time.UTC),
+		Previous:   booking.Terms{Origin: shipping.CNHKG, Destination: shipping.SESTO, ArrivalDeadline: c.RouteSpecification.ArrivalDeadline},
+		New:        booking.Terms{Origin: shipping.CNHKG, Destination: shipping.SESTO, ArrivalDeadline: c.RouteSpecification.ArrivalDeadline, Legs: c.Itinerary.Legs, Price: &c.Price},
+	}
+	if err := src.Amendments.Store(ctx, amendment); err != nil {
+		t.Fatal(err)
+	}
+
+	subscription := &webhook.Subscription{
+		ID:       "S1",
+		URL:      "https://example.com/hook",
+		Customer: "acme",
+		Events:   []webhook.EventType{webhook.CargoArrived},
+		Created:  time.Date(2009, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Secret:   "s3cr3t",
+	}
+	if err := src.Subscriptions.Store(ctx, subscription); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Export(ctx, &buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newRepositories()
+	if err := Import(ctx, &buf, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := dst.Cargos.Find(ctx, c.TrackingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Origin != c.Origin || !reflect.DeepEqual(got.RouteSpecification, c.RouteSpecification) {
+		t.Errorf("cargo = %v; want = %v", got, c)
+	}
+	if len(got.Itinerary.Legs) != 1 {
+		t.Errorf("len(Itinerary.Legs) = %d; want = %d", len(got.Itinerary.Legs), 1)
+	}
+	if got.Price != c.Price {
+		t.Errorf("Price = %v; want = %v", got.Price, c.Price)
+	}
+	if got.Delivery.TransportStatus != shipping.OnboardCarrier {
+		t.Errorf("Delivery.TransportStatus = %v; want = %v", got.Delivery.TransportStatus, shipping.OnboardCarrier)
+	}
+	if got.Delivery.CurrentVoyage != "V100" {
+		t.Errorf("Delivery.CurrentVoyage = %v; want = %v", got.Delivery.CurrentVoyage, "V100")
+	}
+
+	h := dst.HandlingEvents.QueryHandlingHistory(ctx, c.TrackingID)
+	if len(h.HandlingEvents) != len(events) {
+		t.Fatalf("len(HandlingEvents) = %d; want = %d", len(h.HandlingEvents), len(events))
+	}
+	for i := range events {
+		if h.HandlingEvents[i] != events[i] {
+			t.Errorf("HandlingEvents[%d] = %v; want = %v", i, h.HandlingEvents[i], events[i])
+		}
+	}
+
+	if _, err := dst.Locations.Find(ctx, shipping.FIHEL); err != nil {
+		t.Errorf("Find(%s): %v", shipping.FIHEL, err)
+	}
+	if n := len(dst.Voyages.FindAll(ctx)); n != len(src.Voyages.FindAll(ctx)) {
+		t.Errorf("len(Voyages.FindAll()) = %d; want = %d", n, len(src.Voyages.FindAll(ctx)))
+	}
+
+	if as := dst.Amendments.FindByTrackingID(ctx, c.TrackingID); len(as) != 1 || !reflect.DeepEqual(as[0], amendment) {
+		t.Errorf("Amendments = %v; want = [%v]", as, amendment)
+	}
+
+	if s, err := dst.Subscriptions.Find(ctx, subscription.ID); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(s, subscription) {
+		t.Errorf("Subscription = %v; want = %v", s, subscription)
+	}
+}
+
+func TestImportTwice(t *testing.T) {
+	ctx := context.Background()
+
+	src := newRepositories()
+
+	c := shipping.NewCargo("ABC123", shipping.RouteSpecification{
+		Origin:          shipping.CNHKG,
+		Destination:     shipping.SESTO,
+		ArrivalDeadline: time.Date(2009, time.March, 18, 12, 0, 0, 0, time.UTC),
+	})
+	if err := src.Cargos.Store(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	receive := shipping.HandlingEvent{TrackingID: c.TrackingID, Activity: shipping.HandlingActivity{Type: shipping.Receive, Location: shipping.CNHKG}}
+	src.HandlingEvents.Store(ctx, receive)
+
+	var buf bytes.Buffer
+	if err := Export(ctx, &buf, src); err != nil {
+		t.Fatal(err)
+	}
+	archived := buf.String()
+
+	dst := newRepositories()
+	for i := 0; i < 2; i++ {
+		if err := Import(ctx, strings.NewReader(archived), dst); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if h := dst.HandlingEvents.QueryHandlingHistory(ctx, c.TrackingID); len(h.HandlingEvents) != 1 {
+		t.Errorf("len(HandlingEvents) = %d; want = %d", len(h.HandlingEvents), 1)
+	}
+
+	// A history that differs from the archive isn't merged with it.
+	dst = newRepositories()
+	dst.HandlingEvents.Store(ctx, shipping.HandlingEvent{TrackingID: c.TrackingID, Activity: shipping.HandlingActivity{Type: shipping.Customs, Location: shipping.CNHKG}})
+
+	err := Import(ctx, strings.NewReader(archived), dst)
+	if err == nil || !strings.Contains(err.Error(), "differs from the archive") {
+		t.Errorf("err = %v; want differing history", err)
+	}
+}
+
+func TestImportUnsupportedVersion(t *testing.T) {
+	r := strings.NewReader(`{"type":"header","version":99}` + "\n")
+
+	if err := Import(context.Background(), r, newRepositories()); err != ErrUnsupportedVersion {
+		t.Errorf("err = %v; want = %v", err, ErrUnsupportedVersion)
+	}
+}
+
+func TestImportUnknownRecord(t *testing.T) {
+	r := strings.NewReader(`{"type":"header","version":1}
+{"type":"spaceship","data":{}}
+`)
+
+	err := Import(context.Background(), r, newRepositories())
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("err = %v; want error on line 2", err)
+	}
+}
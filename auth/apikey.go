@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// APIKeys authenticates clients by static API keys. Keys are only kept as
+// hashes.
+type APIKeys struct {
+	keys map[[sha256.Size]byte]Principal
+}
+
+// NewAPIKeys returns an authenticator for the given keys and the principals
+// they belong to.
+func NewAPIKeys(keys map[string]Principal) *APIKeys {
+	a := &APIKeys{keys: make(map[[sha256.Size]byte]Principal)}
+	for k, p := range keys {
+		a.keys[sha256.Sum256([]byte(k))] = p
+	}
+	return a
+}
+
+// LoadAPIKeys reads API keys from a JSON array of principals, each with a
+// "key" property.
+func LoadAPIKeys(r io.Reader) (*APIKeys, error) {
+	var entries []struct {
+		Key string `json:"key"`
+		Principal
+	}
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]Principal)
+	for i, e := range entries {
+		if e.Key == "" {
+			return nil, fmt.Errorf("api key %d: key is required", i)
+		}
+		if len(e.Roles) == 0 {
+			return nil, fmt.Errorf("api key %d: roles are required", i)
+		}
+		keys[e.Key] = e.Principal
+	}
+	return NewAPIKeys(keys), nil
+}
+
+// Authenticate returns the principal of c.APIKey.
+func (a *APIKeys) Authenticate(_ context.Context, c Credentials) (*Principal, error) {
+	if c.APIKey == "" {
+		return nil, ErrNoCredentials
+	}
+
+	// Looking up the hash rather than the key itself keeps the lookup time
+	// independent of how much of the key is correct.
+	p, ok := a.keys[sha256.Sum256([]byte(c.APIKey))]
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return &p, nil
+}
@@ -0,0 +1,124 @@
+// Package auth provides authentication of API clients, using static API keys
+// or JWT bearer tokens, and the roles that authorize them.
+package auth
+
+import (
+	"context"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// ErrUnauthenticated is returned when credentials are missing or invalid.
+var ErrUnauthenticated = shipping.NewError(shipping.CodeUnauthenticated, "missing or invalid credentials")
+
+// ErrPermissionDenied is returned when a principal isn't allowed to perform
+// an operation.
+var ErrPermissionDenied = shipping.NewError(shipping.CodePermissionDenied, "permission denied")
+
+// ErrNoCredentials is returned by an Authenticator when the kind of
+// credentials it verifies weren't given. It's reported to clients as
+// ErrUnauthenticated.
+var ErrNoCredentials = shipping.NewError(shipping.CodeUnauthenticated, "missing credentials")
+
+// Role grants access to a part of the API.
+type Role string
+
+// Roles known by the API.
+const (
+	// RoleAdmin may book and route cargos, and manage webhooks. It also
+	// grants every other role.
+	RoleAdmin Role = "admin"
+
+	// RoleHandler may register handling events at its locations.
+	RoleHandler Role = "handler"
+
+	// RolePublic may track cargos.
+	RolePublic Role = "public"
+)
+
+// Principal is an authenticated client.
+type Principal struct {
+	Subject string `json:"subject"`
+	Roles   []Role `json:"roles"`
+
+	// Locations limits where a handler may register handling events.
+	Locations []shipping.UNLocode `json:"locations,omitempty"`
+
+	// Anonymous is set for clients that didn't present any credentials.
+	Anonymous bool `json:"-"`
+}
+
+// HasRole returns whether p has any of the given roles. Admins have all
+// roles.
+func (p *Principal) HasRole(roles ...Role) bool {
+	for _, r := range p.Roles {
+		if r == RoleAdmin {
+			return true
+		}
+		for _, want := range roles {
+			if r == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanHandleAt returns whether p may register handling events at loc.
+func (p *Principal) CanHandleAt(loc shipping.UNLocode) bool {
+	if p.HasRole(RoleAdmin) {
+		return true
+	}
+	if !p.HasRole(RoleHandler) {
+		return false
+	}
+	for _, l := range p.Locations {
+		if l == loc {
+			return true
+		}
+	}
+	return false
+}
+
+// Credentials are the credentials presented by a client. Any of them may be
+// empty.
+type Credentials struct {
+	APIKey      string
+	BearerToken string
+}
+
+// Authenticator verifies credentials and returns the principal they belong
+// to.
+type Authenticator interface {
+	Authenticate(ctx context.Context, c Credentials) (*Principal, error)
+}
+
+// Authenticators is an Authenticator that tries each of its authenticators,
+// in order, until one of them finds the kind of credentials it verifies.
+type Authenticators []Authenticator
+
+// Authenticate returns the principal of the first authenticator that doesn't
+// return ErrNoCredentials.
+func (as Authenticators) Authenticate(ctx context.Context, c Credentials) (*Principal, error) {
+	for _, a := range as {
+		p, err := a.Authenticate(ctx, c)
+		if err == ErrNoCredentials {
+			continue
+		}
+		return p, err
+	}
+	return nil, ErrNoCredentials
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries p.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the principal carried by ctx, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+func TestPrincipal(t *testing.T) {
+	var (
+		admin   = &Principal{Roles: []Role{RoleAdmin}}
+		handler = &Principal{Roles: []Role{RoleHandler}, Locations: []shipping.UNLocode{shipping.SESTO}}
+		public  = &Principal{Roles: []Role{RolePublic}}
+	)
+
+	for _, tt := range []struct {
+		p    *Principal
+		role Role
+		want bool
+	}{
+		{admin, RoleAdmin, true},
+		{admin, RoleHandler, true},
+		{handler, RoleHandler, true},
+		{handler, RoleAdmin, false},
+		{public, RolePublic, true},
+		{public, RoleHandler, false},
+	} {
+		if got := tt.p.HasRole(tt.role); got != tt.want {
+			t.Errorf("%v.HasRole(%s) = %v; want = %v", tt.p.Roles, tt.role, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		p    *Principal
+		loc  shipping.UNLocode
+		want bool
+	}{
+		{admin, shipping.AUMEL, true},
+		{handler, shipping.SESTO, true},
+		{handler, shipping.AUMEL, false},
+		{public, shipping.SESTO, false},
+	} {
+		if got := tt.p.CanHandleAt(tt.loc); got != tt.want {
+			t.Errorf("%v.CanHandleAt(%s) = %v; want = %v", tt.p.Roles, tt.loc, got, tt.want)
+		}
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	keys, err := LoadAPIKeys(strings.NewReader(`[
+		{"key": "s3cr3t", "subject": "stockholm", "roles": ["handler"], "locations": ["SESTO"]}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := Authenticators{keys, NewJWT(KeySet{})}
+
+	p, err := a.Authenticate(context.Background(), Credentials{APIKey: "s3cr3t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Subject != "stockholm" || !p.CanHandleAt(shipping.SESTO) {
+		t.Errorf("p = %+v", p)
+	}
+
+	if _, err := a.Authenticate(context.Background(), Credentials{APIKey: "wrong"}); err != ErrUnauthenticated {
+		t.Errorf("err = %v; want = %v", err, ErrUnauthenticated)
+	}
+	if _, err := a.Authenticate(context.Background(), Credentials{}); err != ErrNoCredentials {
+		t.Errorf("err = %v; want = %v", err, ErrNoCredentials)
+	}
+
+	if _, err := LoadAPIKeys(strings.NewReader(`[{"key": "s3cr3t"}]`)); err == nil {
+		t.Error("want error for key without roles")
+	}
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// Key is a key used to verify tokens.
+type Key struct {
+	// Algorithm is the signing algorithm the key is used with, such as
+	// RS256, ES256 or HS256.
+	Algorithm string
+
+	// Key is a *rsa.PublicKey, *ecdsa.PublicKey or, for HMAC, a []byte.
+	Key interface{}
+}
+
+// KeySet holds the keys used to verify tokens, by key ID.
+type KeySet map[string]Key
+
+// LoadKeySet reads a JSON Web Key Set, as described by RFC 7517. RSA, EC and
+// symmetric keys are supported. Every key must have a key ID.
+func LoadKeySet(r io.Reader) (KeySet, error) {
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(r).Decode(&jwks); err != nil {
+		return nil, err
+	}
+
+	ks := make(KeySet)
+	for i, k := range jwks.Keys {
+		if k.Kid == "" {
+			return nil, fmt.Errorf("key %d: kid is required", i)
+		}
+
+		var (
+			key interface{}
+			alg = k.Alg
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			var n, e *big.Int
+			if n, err = decodeInt(k.N); err == nil {
+				e, err = decodeInt(k.E)
+			}
+			if err == nil {
+				key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+			}
+			if alg == "" {
+				alg = "RS256"
+			}
+		case "EC":
+			var curve elliptic.Curve
+			switch k.Crv {
+			case "P-256":
+				curve, alg = elliptic.P256(), "ES256"
+			case "P-384":
+				curve, alg = elliptic.P384(), "ES384"
+			case "P-521":
+				curve, alg = elliptic.P521(), "ES512"
+			default:
+				return nil, fmt.Errorf("key %s: unsupported curve %q", k.Kid, k.Crv)
+			}
+			var x, y *big.Int
+			if x, err = decodeInt(k.X); err == nil {
+				y, err = decodeInt(k.Y)
+			}
+			if err == nil {
+				key = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+			}
+		case "oct":
+			key, err = base64.RawURLEncoding.DecodeString(k.K)
+			if alg == "" {
+				alg = "HS256"
+			}
+		default:
+			return nil, fmt.Errorf("key %s: unsupported key type %q", k.Kid, k.Kty)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %v", k.Kid, err)
+		}
+
+		ks[k.Kid] = Key{Algorithm: alg, Key: key}
+	}
+	return ks, nil
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// Claims are the claims of a bearer token. The principal is taken from the
+// subject and the roles and locations claims.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles     []Role              `json:"roles"`
+	Locations []shipping.UNLocode `json:"locations,omitempty"`
+}
+
+// JWT authenticates clients by JWT bearer tokens, signed by one of the keys
+// in a local key set.
+type JWT struct {
+	keys KeySet
+
+	// Issuer and Audience, if set, must match the claims of the token.
+	Issuer   string
+	Audience string
+
+	// Leeway allows for clock skew when validating the time claims.
+	Leeway time.Duration
+}
+
+// NewJWT returns an authenticator of tokens signed by keys.
+func NewJWT(keys KeySet) *JWT {
+	return &JWT{keys: keys, Leeway: time.Minute}
+}
+
+// Authenticate returns the principal of c.BearerToken. Tokens must have an
+// expiration time and name their key in the kid header.
+func (a *JWT) Authenticate(_ context.Context, c Credentials) (*Principal, error) {
+	if c.BearerToken == "" {
+		return nil, ErrNoCredentials
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(a.Leeway),
+	}
+	if a.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.Issuer))
+	}
+	if a.Audience != "" {
+		opts = append(opts, jwt.WithAudience(a.Audience))
+	}
+
+	var claims Claims
+	_, err := jwt.ParseWithClaims(c.BearerToken, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		k, ok := a.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+		// The algorithm is dictated by the key, never by the token.
+		if t.Method.Alg() != k.Algorithm {
+			return nil, fmt.Errorf("unexpected algorithm %q", t.Method.Alg())
+		}
+		return k.Key, nil
+	}, opts...)
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+
+	return &Principal{
+		Subject:   claims.Subject,
+		Roles:     claims.Roles,
+		Locations: claims.Locations,
+	}, nil
+}
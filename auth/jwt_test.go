@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+func TestJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hmacKey := []byte("0123456789abcdef0123456789abcdef")
+
+	enc := base64.RawURLEncoding.EncodeToString
+	jwks := `{"keys": [
+		{"kty": "RSA", "kid": "rsa", "n": "` + enc(rsaKey.N.Bytes()) + `", "e": "` + enc(big.NewInt(int64(rsaKey.E)).Bytes()) + `"},
+		{"kty": "oct", "kid": "hmac", "k": "` + enc(hmacKey) + `"}
+	]}`
+
+	ks, err := LoadKeySet(strings.NewReader(jwks))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewJWT(ks)
+	a.Issuer = "goddd-test"
+
+	claims := func(exp time.Time) Claims {
+		return Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "rotterdam",
+				Issuer:    "goddd-test",
+				ExpiresAt: jwt.NewNumericDate(exp),
+			},
+			Roles:     []Role{RoleHandler},
+			Locations: []shipping.UNLocode{shipping.NLRTM},
+		}
+	}
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}, c Claims) string {
+		tok := jwt.NewWithClaims(method, c)
+		tok.Header["kid"] = kid
+		s, err := tok.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	valid := claims(time.Now().Add(time.Hour))
+
+	for _, token := range []string{
+		sign(jwt.SigningMethodRS256, "rsa", rsaKey, valid),
+		sign(jwt.SigningMethodHS256, "hmac", hmacKey, valid),
+	} {
+		p, err := a.Authenticate(context.Background(), Credentials{BearerToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Subject != "rotterdam" || !p.CanHandleAt(shipping.NLRTM) {
+			t.Errorf("p = %+v", p)
+		}
+	}
+
+	wrongIssuer := valid
+	wrongIssuer.Issuer = "someone-else"
+
+	for name, token := range map[string]string{
+		"expired":       sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims(time.Now().Add(-time.Hour))),
+		"unknown key":   sign(jwt.SigningMethodRS256, "other", rsaKey, valid),
+		"wrong issuer":  sign(jwt.SigningMethodRS256, "rsa", rsaKey, wrongIssuer),
+		"wrong alg":     sign(jwt.SigningMethodHS256, "rsa", hmacKey, valid),
+		"no expiration": sign(jwt.SigningMethodHS256, "hmac", hmacKey, Claims{Roles: []Role{RoleAdmin}}),
+		"garbage":       "not.a.token",
+	} {
+		if _, err := a.Authenticate(context.Background(), Credentials{BearerToken: token}); err != ErrUnauthenticated {
+			t.Errorf("%s: err = %v; want = %v", name, err, ErrUnauthenticated)
+		}
+	}
+}
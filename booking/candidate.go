@@ -0,0 +1,52 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/pborman/uuid"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/pricing"
+)
+
+// ErrUnknownRouteCandidate is used when a route candidate could not be found
+// for a cargo.
+var ErrUnknownRouteCandidate = shipping.NewError(shipping.CodeUnknownRouteCandidate, "unknown route candidate")
+
+// ErrStaleRouteCandidate is returned when assigning a route candidate that has
+// expired, or no longer satisfies the route specification of its cargo.
+var ErrStaleRouteCandidate = shipping.NewError(shipping.CodeStaleRouteCandidate, "stale route candidate")
+
+// DefaultRouteCandidateTTL is how long route candidates can be assigned
+// unless configured otherwise.
+const DefaultRouteCandidateTTL = 15 * time.Minute
+
+// RouteCandidateID uniquely identifies a route candidate.
+type RouteCandidateID string
+
+// NextRouteCandidateID generates a new route candidate ID.
+func NextRouteCandidateID() RouteCandidateID {
+	return RouteCandidateID(uuid.New())
+}
+
+// RouteCandidate is a possible route offered for a cargo. It can be assigned
+// to that cargo by ID until it expires, which saves clients from sending the
+// itinerary back.
+type RouteCandidate struct {
+	ID         RouteCandidateID    `json:"id"`
+	TrackingID shipping.TrackingID `json:"tracking_id"`
+	ExpiresAt  time.Time           `json:"expires_at"`
+	shipping.RouteCandidate
+
+	// Quote is the latest price quoted for the candidate, if any. It
+	// becomes the price of the cargo when the candidate is assigned.
+	Quote *pricing.Quote `json:"quote,omitempty"`
+}
+
+// RouteCandidateRepository provides access to a route candidate store.
+// Repositories may forget candidates once they have expired.
+type RouteCandidateRepository interface {
+	Store(ctx context.Context, c *RouteCandidate) error
+	Find(ctx context.Context, id RouteCandidateID) (*RouteCandidate, error)
+}
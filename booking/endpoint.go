@@ -0,0 +1,294 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/pricing"
+)
+
+// Endpoints collects the endpoints of a booking service, to be exposed by a
+// transport.
+type Endpoints struct {
+	BookNewCargoEndpoint                  endpoint.Endpoint
+	LoadCargoEndpoint                     endpoint.Endpoint
+	RequestPossibleRoutesForCargoEndpoint endpoint.Endpoint
+	QuoteRouteEndpoint                    endpoint.Endpoint
+	AssignCargoToRouteEndpoint            endpoint.Endpoint
+	AssignCargoToRouteCandidateEndpoint   endpoint.Endpoint
+	ChangeDestinationEndpoint             endpoint.Endpoint
+	ChangeArrivalDeadlineEndpoint         endpoint.Endpoint
+	ChangeOriginEndpoint                  endpoint.Endpoint
+	HistoryEndpoint                       endpoint.Endpoint
+	CargosEndpoint                        endpoint.Endpoint
+	LocationsEndpoint                     endpoint.Endpoint
+}
+
+// MakeEndpoints returns the endpoints of s.
+func MakeEndpoints(s Service) Endpoints {
+	return Endpoints{
+		BookNewCargoEndpoint:                  makeBookNewCargoEndpoint(s),
+		LoadCargoEndpoint:                     makeLoadCargoEndpoint(s),
+		RequestPossibleRoutesForCargoEndpoint: makeRequestPossibleRoutesForCargoEndpoint(s),
+		QuoteRouteEndpoint:                    makeQuoteRouteEndpoint(s),
+		AssignCargoToRouteEndpoint:            makeAssignCargoToRouteEndpoint(s),
+		AssignCargoToRouteCandidateEndpoint:   makeAssignCargoToRouteCandidateEndpoint(s),
+		ChangeDestinationEndpoint:             makeChangeDestinationEndpoint(s),
+		ChangeArrivalDeadlineEndpoint:         makeChangeArrivalDeadlineEndpoint(s),
+		ChangeOriginEndpoint:                  makeChangeOriginEndpoint(s),
+		HistoryEndpoint:                       makeHistoryEndpoint(s),
+		CargosEndpoint:                        makeCargosEndpoint(s),
+		LocationsEndpoint:                     makeLocationsEndpoint(s),
+	}
+}
+
+// BookNewCargoRequest is the request of the BookNewCargo endpoint.
+type BookNewCargoRequest struct {
+	RouteSpecification shipping.RouteSpecification
+}
+
+// BookNewCargoResponse is the response of the BookNewCargo endpoint.
+type BookNewCargoResponse struct {
+	ID shipping.TrackingID
+}
+
+func makeBookNewCargoEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(BookNewCargoRequest)
+		id, err := s.BookNewCargo(ctx, req.RouteSpecification)
+		if err != nil {
+			return nil, err
+		}
+		return BookNewCargoResponse{ID: id}, nil
+	}
+}
+
+// LoadCargoRequest is the request of the LoadCargo endpoint.
+type LoadCargoRequest struct {
+	ID shipping.TrackingID
+}
+
+// LoadCargoResponse is the response of the LoadCargo endpoint.
+type LoadCargoResponse struct {
+	Cargo Cargo
+}
+
+func makeLoadCargoEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(LoadCargoRequest)
+		c, err := s.LoadCargo(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		return LoadCargoResponse{Cargo: c}, nil
+	}
+}
+
+// RequestPossibleRoutesForCargoRequest is the request of the
+// RequestPossibleRoutesForCargo endpoint.
+type RequestPossibleRoutesForCargoRequest struct {
+	ID       shipping.TrackingID
+	Strategy string
+}
+
+// RequestPossibleRoutesForCargoResponse is the response of the
+// RequestPossibleRoutesForCargo endpoint.
+type RequestPossibleRoutesForCargoResponse struct {
+	Routes []RouteCandidate
+}
+
+func makeRequestPossibleRoutesForCargoEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RequestPossibleRoutesForCargoRequest)
+		candidates, err := s.RequestPossibleRoutesForCargo(ctx, req.ID, req.Strategy)
+		if err != nil {
+			return nil, err
+		}
+		return RequestPossibleRoutesForCargoResponse{Routes: candidates}, nil
+	}
+}
+
+// QuoteRouteRequest is the request of the QuoteRoute endpoint.
+type QuoteRouteRequest struct {
+	ID          shipping.TrackingID
+	CandidateID RouteCandidateID
+	Size        int
+	Currency    string
+}
+
+// QuoteRouteResponse is the response of the QuoteRoute endpoint.
+type QuoteRouteResponse struct {
+	Quote pricing.Quote
+}
+
+func makeQuoteRouteEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(QuoteRouteRequest)
+		q, err := s.QuoteRoute(ctx, req.ID, req.CandidateID, req.Size, req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		return QuoteRouteResponse{Quote: q}, nil
+	}
+}
+
+// AssignCargoToRouteRequest is the request of the AssignCargoToRoute
+// endpoint.
+type AssignCargoToRouteRequest struct {
+	ID        shipping.TrackingID
+	Itinerary shipping.Itinerary
+}
+
+// AssignCargoToRouteResponse is the response of the AssignCargoToRoute
+// endpoint.
+type AssignCargoToRouteResponse struct{}
+
+func makeAssignCargoToRouteEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AssignCargoToRouteRequest)
+		if err := s.AssignCargoToRoute(ctx, req.ID, req.Itinerary); err != nil {
+			return nil, err
+		}
+		return AssignCargoToRouteResponse{}, nil
+	}
+}
+
+// AssignCargoToRouteCandidateRequest is the request of the
+// AssignCargoToRouteCandidate endpoint.
+type AssignCargoToRouteCandidateRequest struct {
+	ID          shipping.TrackingID
+	CandidateID RouteCandidateID
+}
+
+// AssignCargoToRouteCandidateResponse is the response of the
+// AssignCargoToRouteCandidate endpoint.
+type AssignCargoToRouteCandidateResponse struct{}
+
+func makeAssignCargoToRouteCandidateEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AssignCargoToRouteCandidateRequest)
+		if err := s.AssignCargoToRouteCandidate(ctx, req.ID, req.CandidateID); err != nil {
+			return nil, err
+		}
+		return AssignCargoToRouteCandidateResponse{}, nil
+	}
+}
+
+// ChangeDestinationRequest is the request of the ChangeDestination endpoint.
+type ChangeDestinationRequest struct {
+	ID          shipping.TrackingID
+	Destination shipping.UNLocode
+}
+
+// ChangeDestinationResponse is the response of the ChangeDestination
+// endpoint.
+type ChangeDestinationResponse struct{}
+
+func makeChangeDestinationEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ChangeDestinationRequest)
+		if err := s.ChangeDestination(ctx, req.ID, req.Destination); err != nil {
+			return nil, err
+		}
+		return ChangeDestinationResponse{}, nil
+	}
+}
+
+// ChangeArrivalDeadlineRequest is the request of the ChangeArrivalDeadline
+// endpoint.
+type ChangeArrivalDeadlineRequest struct {
+	ID       shipping.TrackingID
+	Deadline time.Time
+}
+
+// ChangeArrivalDeadlineResponse is the response of the ChangeArrivalDeadline
+// endpoint.
+type ChangeArrivalDeadlineResponse struct{}
+
+func makeChangeArrivalDeadlineEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ChangeArrivalDeadlineRequest)
+		if err := s.ChangeArrivalDeadline(ctx, req.ID, req.Deadline); err != nil {
+			return nil, err
+		}
+		return ChangeArrivalDeadlineResponse{}, nil
+	}
+}
+
+// ChangeOriginRequest is the request of the ChangeOrigin endpoint.
+type ChangeOriginRequest struct {
+	ID     shipping.TrackingID
+	Origin shipping.UNLocode
+}
+
+// ChangeOriginResponse is the response of the ChangeOrigin endpoint.
+type ChangeOriginResponse struct{}
+
+func makeChangeOriginEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ChangeOriginRequest)
+		if err := s.ChangeOrigin(ctx, req.ID, req.Origin); err != nil {
+			return nil, err
+		}
+		return ChangeOriginResponse{}, nil
+	}
+}
+
+// HistoryRequest is the request of the History endpoint.
+type HistoryRequest struct {
+	ID shipping.TrackingID
+}
+
+// HistoryResponse is the response of the History endpoint.
+type HistoryResponse struct {
+	Amendments []Amendment
+}
+
+func makeHistoryEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(HistoryRequest)
+		amendments, err := s.History(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		return HistoryResponse{Amendments: amendments}, nil
+	}
+}
+
+// CargosRequest is the request of the Cargos endpoint.
+type CargosRequest struct {
+	Query shipping.CargoQuery
+}
+
+// CargosResponse is the response of the Cargos endpoint.
+type CargosResponse struct {
+	Page CargoPage
+}
+
+func makeCargosEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(CargosRequest)
+		page, err := s.Cargos(ctx, req.Query)
+		if err != nil {
+			return nil, err
+		}
+		return CargosResponse{Page: page}, nil
+	}
+}
+
+// LocationsRequest is the request of the Locations endpoint.
+type LocationsRequest struct{}
+
+// LocationsResponse is the response of the Locations endpoint.
+type LocationsResponse struct {
+	Locations []Location
+}
+
+func makeLocationsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return LocationsResponse{Locations: s.Locations(ctx)}, nil
+	}
+}
@@ -0,0 +1,126 @@
+package booking
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pborman/uuid"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// AmendmentID uniquely identifies an amendment.
+type AmendmentID string
+
+// NextAmendmentID generates a new amendment ID.
+func NextAmendmentID() AmendmentID {
+	return AmendmentID(uuid.New())
+}
+
+// AmendmentType describes what an amendment changed.
+type AmendmentType string
+
+// Types of amendments.
+const (
+	DestinationChanged     AmendmentType = "destination_changed"
+	ArrivalDeadlineChanged AmendmentType = "arrival_deadline_changed"
+	OriginChanged          AmendmentType = "origin_changed"
+	RouteAssigned          AmendmentType = "route_assigned"
+)
+
+// Terms are the parts of a booking that amendments change.
+type Terms struct {
+	Origin          shipping.UNLocode `json:"origin"`
+	Destination     shipping.UNLocode `json:"destination"`
+	ArrivalDeadline time.Time         `json:"arrival_deadline"`
+	Legs            []shipping.Leg    `json:"legs,omitempty"`
+	Price           *shipping.Money   `json:"price,omitempty"`
+}
+
+func termsOf(c *shipping.Cargo) Terms {
+	t := Terms{
+		Origin:          c.RouteSpecification.Origin,
+		Destination:     c.RouteSpecification.Destination,
+		ArrivalDeadline: c.RouteSpecification.ArrivalDeadline,
+		Legs:            append([]shipping.Leg(nil), c.Itinerary.Legs...),
+	}
+	if !c.Price.IsZero() {
+		p := c.Price
+		t.Price = &p
+	}
+	return t
+}
+
+// Amendment is an entry in the history of a booking. It holds the terms of
+// the booking before and after a change, who made it and when. The actor is
+// taken from the context of the change, see NewActorContext, and is empty if
+// it's unknown.
+type Amendment struct {
+	ID         AmendmentID         `json:"id"`
+	TrackingID shipping.TrackingID `json:"tracking_id"`
+	Type       AmendmentType       `json:"type"`
+	Actor      string              `json:"actor,omitempty"`
+	Time       time.Time           `json:"time"`
+	Previous   Terms               `json:"previous"`
+	New        Terms               `json:"new"`
+}
+
+// AmendmentRepository provides access to the history of bookings.
+type AmendmentRepository interface {
+	Store(ctx context.Context, a *Amendment) error
+
+	// Remove removes an amendment. Removing an unknown amendment does
+	// nothing.
+	Remove(ctx context.Context, id AmendmentID) error
+
+	// FindByTrackingID returns the amendments of a cargo, oldest first.
+	FindByTrackingID(ctx context.Context, id shipping.TrackingID) []*Amendment
+}
+
+type actorKey struct{}
+
+// NewActorContext returns a copy of ctx that carries the actor of the
+// changes made with it. It's up to the transport to tell who the actor is,
+// usually the subject of the authenticated client.
+func NewActorContext(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFromContext returns the actor carried by ctx, if any.
+func actorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// storeAmended stores c along with an amendment recording that it was
+// changed from the terms in previous, unless the change left the terms as
+// they were. The amendment is recorded first and removed again if c can't
+// be stored, so that no change is saved without its amendment and no
+// amendment is kept without its change.
+func (s *service) storeAmended(ctx context.Context, typ AmendmentType, previous Terms, c *shipping.Cargo) error {
+	terms := termsOf(c)
+	if reflect.DeepEqual(previous, terms) {
+		return s.cargos.Store(ctx, c)
+	}
+
+	a := &Amendment{
+		ID:         NextAmendmentID(),
+		TrackingID: c.TrackingID,
+		Type:       typ,
+		Actor:      actorFromContext(ctx),
+		Time:       s.now(),
+		Previous:   previous,
+		New:        terms,
+	}
+	if err := s.amendments.Store(ctx, a); err != nil {
+		return err
+	}
+
+	if err := s.cargos.Store(ctx, c); err != nil {
+		s.amendments.Remove(ctx, a.ID)
+		return err
+	}
+
+	return nil
+}
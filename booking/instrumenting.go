@@ -1,11 +1,13 @@
 package booking
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-kit/kit/metrics"
 
 	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/pricing"
 )
 
 type instrumentingService struct {
@@ -23,65 +25,110 @@ func NewInstrumentingService(counter metrics.Counter, latency metrics.Histogram,
 	}
 }
 
-func (s *instrumentingService) BookNewCargo(origin, destination shipping.UNLocode, deadline time.Time) (shipping.TrackingID, error) {
+func (s *instrumentingService) BookNewCargo(ctx context.Context, rs shipping.RouteSpecification) (shipping.TrackingID, error) {
 	defer func(begin time.Time) {
 		s.requestCount.With("method", "book").Add(1)
 		s.requestLatency.With("method", "book").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return s.next.BookNewCargo(origin, destination, deadline)
+	return s.next.BookNewCargo(ctx, rs)
 }
 
-func (s *instrumentingService) LoadCargo(id shipping.TrackingID) (c Cargo, err error) {
+func (s *instrumentingService) LoadCargo(ctx context.Context, id shipping.TrackingID) (c Cargo, err error) {
 	defer func(begin time.Time) {
 		s.requestCount.With("method", "load").Add(1)
 		s.requestLatency.With("method", "load").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return s.next.LoadCargo(id)
+	return s.next.LoadCargo(ctx, id)
 }
 
-func (s *instrumentingService) RequestPossibleRoutesForCargo(id shipping.TrackingID) []shipping.Itinerary {
+func (s *instrumentingService) RequestPossibleRoutesForCargo(ctx context.Context, id shipping.TrackingID, strategy string) ([]RouteCandidate, error) {
 	defer func(begin time.Time) {
 		s.requestCount.With("method", "request_routes").Add(1)
 		s.requestLatency.With("method", "request_routes").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return s.next.RequestPossibleRoutesForCargo(id)
+	return s.next.RequestPossibleRoutesForCargo(ctx, id, strategy)
 }
 
-func (s *instrumentingService) AssignCargoToRoute(id shipping.TrackingID, itinerary shipping.Itinerary) (err error) {
+func (s *instrumentingService) AssignCargoToRoute(ctx context.Context, id shipping.TrackingID, itinerary shipping.Itinerary) (err error) {
 	defer func(begin time.Time) {
 		s.requestCount.With("method", "assign_to_route").Add(1)
 		s.requestLatency.With("method", "assign_to_route").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return s.next.AssignCargoToRoute(id, itinerary)
+	return s.next.AssignCargoToRoute(ctx, id, itinerary)
 }
 
-func (s *instrumentingService) ChangeDestination(id shipping.TrackingID, l shipping.UNLocode) (err error) {
+func (s *instrumentingService) QuoteRoute(ctx context.Context, id shipping.TrackingID, candidate RouteCandidateID, size int, currency string) (pricing.Quote, error) {
+	defer func(begin time.Time) {
+		s.requestCount.With("method", "quote_route").Add(1)
+		s.requestLatency.With("method", "quote_route").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return s.next.QuoteRoute(ctx, id, candidate, size, currency)
+}
+
+func (s *instrumentingService) AssignCargoToRouteCandidate(ctx context.Context, id shipping.TrackingID, candidate RouteCandidateID) (err error) {
+	defer func(begin time.Time) {
+		s.requestCount.With("method", "assign_to_route_candidate").Add(1)
+		s.requestLatency.With("method", "assign_to_route_candidate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return s.next.AssignCargoToRouteCandidate(ctx, id, candidate)
+}
+
+func (s *instrumentingService) ChangeDestination(ctx context.Context, id shipping.TrackingID, l shipping.UNLocode) (err error) {
 	defer func(begin time.Time) {
 		s.requestCount.With("method", "change_destination").Add(1)
 		s.requestLatency.With("method", "change_destination").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return s.next.ChangeDestination(id, l)
+	return s.next.ChangeDestination(ctx, id, l)
+}
+
+func (s *instrumentingService) ChangeArrivalDeadline(ctx context.Context, id shipping.TrackingID, deadline time.Time) (err error) {
+	defer func(begin time.Time) {
+		s.requestCount.With("method", "change_arrival_deadline").Add(1)
+		s.requestLatency.With("method", "change_arrival_deadline").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return s.next.ChangeArrivalDeadline(ctx, id, deadline)
+}
+
+func (s *instrumentingService) ChangeOrigin(ctx context.Context, id shipping.TrackingID, l shipping.UNLocode) (err error) {
+	defer func(begin time.Time) {
+		s.requestCount.With("method", "change_origin").Add(1)
+		s.requestLatency.With("method", "change_origin").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return s.next.ChangeOrigin(ctx, id, l)
+}
+
+func (s *instrumentingService) History(ctx context.Context, id shipping.TrackingID) ([]Amendment, error) {
+	defer func(begin time.Time) {
+		s.requestCount.With("method", "history").Add(1)
+		s.requestLatency.With("method", "history").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return s.next.History(ctx, id)
 }
 
-func (s *instrumentingService) Cargos() []Cargo {
+func (s *instrumentingService) Cargos(ctx context.Context, q shipping.CargoQuery) (CargoPage, error) {
 	defer func(begin time.Time) {
 		s.requestCount.With("method", "list_cargos").Add(1)
 		s.requestLatency.With("method", "list_cargos").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return s.next.Cargos()
+	return s.next.Cargos(ctx, q)
 }
 
-func (s *instrumentingService) Locations() []Location {
+func (s *instrumentingService) Locations(ctx context.Context) []Location {
 	defer func(begin time.Time) {
 		s.requestCount.With("method", "list_locations").Add(1)
 		s.requestLatency.With("method", "list_locations").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return s.next.Locations()
+	return s.next.Locations(ctx)
 }
@@ -1,11 +1,13 @@
 package booking
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-kit/kit/log"
 
 	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/pricing"
 )
 
 type loggingService struct {
@@ -18,21 +20,21 @@ func NewLoggingService(logger log.Logger, s Service) Service {
 	return &loggingService{logger, s}
 }
 
-func (s *loggingService) BookNewCargo(origin shipping.UNLocode, destination shipping.UNLocode, deadline time.Time) (id shipping.TrackingID, err error) {
+func (s *loggingService) BookNewCargo(ctx context.Context, rs shipping.RouteSpecification) (id shipping.TrackingID, err error) {
 	defer func(begin time.Time) {
 		s.logger.Log(
 			"method", "book",
-			"origin", origin,
-			"destination", destination,
-			"arrival_deadline", deadline,
+			"origin", rs.Origin,
+			"destination", rs.Destination,
+			"arrival_deadline", rs.ArrivalDeadline,
 			"took", time.Since(begin),
 			"err", err,
 		)
 	}(time.Now())
-	return s.next.BookNewCargo(origin, destination, deadline)
+	return s.next.BookNewCargo(ctx, rs)
 }
 
-func (s *loggingService) LoadCargo(id shipping.TrackingID) (c Cargo, err error) {
+func (s *loggingService) LoadCargo(ctx context.Context, id shipping.TrackingID) (c Cargo, err error) {
 	defer func(begin time.Time) {
 		s.logger.Log(
 			"method", "load",
@@ -41,21 +43,39 @@ func (s *loggingService) LoadCargo(id shipping.TrackingID) (c Cargo, err error)
 			"err", err,
 		)
 	}(time.Now())
-	return s.next.LoadCargo(id)
+	return s.next.LoadCargo(ctx, id)
 }
 
-func (s *loggingService) RequestPossibleRoutesForCargo(id shipping.TrackingID) []shipping.Itinerary {
+func (s *loggingService) RequestPossibleRoutesForCargo(ctx context.Context, id shipping.TrackingID, strategy string) (candidates []RouteCandidate, err error) {
 	defer func(begin time.Time) {
 		s.logger.Log(
 			"method", "request_routes",
 			"tracking_id", id,
+			"strategy", strategy,
 			"took", time.Since(begin),
+			"err", err,
 		)
 	}(time.Now())
-	return s.next.RequestPossibleRoutesForCargo(id)
+	return s.next.RequestPossibleRoutesForCargo(ctx, id, strategy)
 }
 
-func (s *loggingService) AssignCargoToRoute(id shipping.TrackingID, itinerary shipping.Itinerary) (err error) {
+func (s *loggingService) QuoteRoute(ctx context.Context, id shipping.TrackingID, candidate RouteCandidateID, size int, currency string) (q pricing.Quote, err error) {
+	defer func(begin time.Time) {
+		s.logger.Log(
+			"method", "quote_route",
+			"tracking_id", id,
+			"candidate_id", candidate,
+			"size", size,
+			"currency", currency,
+			"total", q.Total,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+	return s.next.QuoteRoute(ctx, id, candidate, size, currency)
+}
+
+func (s *loggingService) AssignCargoToRoute(ctx context.Context, id shipping.TrackingID, itinerary shipping.Itinerary) (err error) {
 	defer func(begin time.Time) {
 		s.logger.Log(
 			"method", "assign_to_route",
@@ -64,10 +84,23 @@ func (s *loggingService) AssignCargoToRoute(id shipping.TrackingID, itinerary sh
 			"err", err,
 		)
 	}(time.Now())
-	return s.next.AssignCargoToRoute(id, itinerary)
+	return s.next.AssignCargoToRoute(ctx, id, itinerary)
 }
 
-func (s *loggingService) ChangeDestination(id shipping.TrackingID, l shipping.UNLocode) (err error) {
+func (s *loggingService) AssignCargoToRouteCandidate(ctx context.Context, id shipping.TrackingID, candidate RouteCandidateID) (err error) {
+	defer func(begin time.Time) {
+		s.logger.Log(
+			"method", "assign_to_route_candidate",
+			"tracking_id", id,
+			"candidate_id", candidate,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+	return s.next.AssignCargoToRouteCandidate(ctx, id, candidate)
+}
+
+func (s *loggingService) ChangeDestination(ctx context.Context, id shipping.TrackingID, l shipping.UNLocode) (err error) {
 	defer func(begin time.Time) {
 		s.logger.Log(
 			"method", "change_destination",
@@ -77,25 +110,66 @@ func (s *loggingService) ChangeDestination(id shipping.TrackingID, l shipping.UN
 			"err", err,
 		)
 	}(time.Now())
-	return s.next.ChangeDestination(id, l)
+	return s.next.ChangeDestination(ctx, id, l)
 }
 
-func (s *loggingService) Cargos() []Cargo {
+func (s *loggingService) ChangeArrivalDeadline(ctx context.Context, id shipping.TrackingID, deadline time.Time) (err error) {
+	defer func(begin time.Time) {
+		s.logger.Log(
+			"method", "change_arrival_deadline",
+			"tracking_id", id,
+			"arrival_deadline", deadline,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+	return s.next.ChangeArrivalDeadline(ctx, id, deadline)
+}
+
+func (s *loggingService) ChangeOrigin(ctx context.Context, id shipping.TrackingID, l shipping.UNLocode) (err error) {
+	defer func(begin time.Time) {
+		s.logger.Log(
+			"method", "change_origin",
+			"tracking_id", id,
+			"origin", l,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+	return s.next.ChangeOrigin(ctx, id, l)
+}
+
+func (s *loggingService) History(ctx context.Context, id shipping.TrackingID) (amendments []Amendment, err error) {
+	defer func(begin time.Time) {
+		s.logger.Log(
+			"method", "history",
+			"tracking_id", id,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+	return s.next.History(ctx, id)
+}
+
+func (s *loggingService) Cargos(ctx context.Context, q shipping.CargoQuery) (p CargoPage, err error) {
 	defer func(begin time.Time) {
 		s.logger.Log(
 			"method", "list_cargos",
+			"limit", q.Limit,
+			"cursor", q.Cursor,
 			"took", time.Since(begin),
+			"err", err,
 		)
 	}(time.Now())
-	return s.next.Cargos()
+	return s.next.Cargos(ctx, q)
 }
 
-func (s *loggingService) Locations() []Location {
+func (s *loggingService) Locations(ctx context.Context) []Location {
 	defer func(begin time.Time) {
 		s.logger.Log(
 			"method", "list_locations",
 			"took", time.Since(begin),
 		)
 	}(time.Now())
-	return s.next.Locations()
+	return s.next.Locations(ctx)
 }
@@ -3,40 +3,105 @@
 package booking
 
 import (
-	"errors"
+	"context"
 	"time"
 
 	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/pricing"
 )
 
 // ErrInvalidArgument is returned when one or more arguments are invalid.
-var ErrInvalidArgument = errors.New("invalid argument")
+var ErrInvalidArgument = shipping.NewError(shipping.CodeInvalidArgument, "invalid argument")
+
+// ErrCargoReceived is returned when changing what can only be changed before
+// a cargo has been received.
+var ErrCargoReceived = shipping.NewError(shipping.CodeCargoReceived, "cargo has been received")
+
+// errRequired describes a required field that is missing.
+func errRequired(name string) shipping.FieldError {
+	return shipping.FieldError{Name: name, Reason: "is required"}
+}
+
+// Page sizes used when listing cargos.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
 
 // Service is the interface that provides booking methods.
 type Service interface {
 	// BookNewCargo registers a new cargo in the tracking system, not yet
-	// routed.
-	BookNewCargo(origin shipping.UNLocode, destination shipping.UNLocode, deadline time.Time) (shipping.TrackingID, error)
+	// routed. The route specification needs an origin, a destination and
+	// an arrival deadline, and may give an earliest departure and
+	// constraints that its routes must keep to.
+	BookNewCargo(ctx context.Context, rs shipping.RouteSpecification) (shipping.TrackingID, error)
 
 	// LoadCargo returns a read model of a shipping.
-	LoadCargo(id shipping.TrackingID) (Cargo, error)
+	LoadCargo(ctx context.Context, id shipping.TrackingID) (Cargo, error)
 
 	// RequestPossibleRoutesForCargo requests a list of itineraries describing
-	// possible routes for this shipping.
-	RequestPossibleRoutesForCargo(id shipping.TrackingID) []shipping.Itinerary
+	// possible routes for this shipping, ranked by the cost model of the
+	// given strategy. An empty strategy means shipping.DefaultStrategy. The
+	// routes are kept as candidates that can be assigned by ID for a while.
+	RequestPossibleRoutesForCargo(ctx context.Context, id shipping.TrackingID, strategy string) ([]RouteCandidate, error)
+
+	// QuoteRoute prices carrying size units of a cargo along a route
+	// previously returned by RequestPossibleRoutesForCargo for that cargo,
+	// in the given currency. An empty currency means the currency of the
+	// tariff. The quote is kept with the route candidate, replacing any
+	// earlier quote.
+	QuoteRoute(ctx context.Context, id shipping.TrackingID, candidate RouteCandidateID, size int, currency string) (pricing.Quote, error)
 
 	// AssignCargoToRoute assigns a cargo to the route specified by the
-	// itinerary.
-	AssignCargoToRoute(id shipping.TrackingID, itinerary shipping.Itinerary) error
+	// itinerary. The cargo is left without a price.
+	AssignCargoToRoute(ctx context.Context, id shipping.TrackingID, itinerary shipping.Itinerary) error
+
+	// AssignCargoToRouteCandidate assigns a cargo to a route previously
+	// returned by RequestPossibleRoutesForCargo for that cargo. If the route
+	// has been quoted, the quoted total becomes the price of the cargo.
+	AssignCargoToRouteCandidate(ctx context.Context, id shipping.TrackingID, candidate RouteCandidateID) error
 
 	// ChangeDestination changes the destination of a shipping.
-	ChangeDestination(id shipping.TrackingID, destination shipping.UNLocode) error
+	ChangeDestination(ctx context.Context, id shipping.TrackingID, destination shipping.UNLocode) error
 
-	// Cargos returns a list of all cargos that have been booked.
-	Cargos() []Cargo
+	// ChangeArrivalDeadline changes the arrival deadline of a cargo. The
+	// deadline must be in the future and after the earliest departure.
+	ChangeArrivalDeadline(ctx context.Context, id shipping.TrackingID, deadline time.Time) error
+
+	// ChangeOrigin changes where a cargo is picked up. It fails with
+	// ErrCargoReceived once the cargo has been received.
+	ChangeOrigin(ctx context.Context, id shipping.TrackingID, origin shipping.UNLocode) error
+
+	// History returns the amendments made to the booking of a cargo, oldest
+	// first.
+	History(ctx context.Context, id shipping.TrackingID) ([]Amendment, error)
+
+	// Cargos returns a page of the cargos that have been booked, filtered
+	// and ordered according to the query. If no limit is given, the page
+	// holds at most DefaultPageSize cargos.
+	Cargos(ctx context.Context, q shipping.CargoQuery) (CargoPage, error)
 
 	// Locations returns a list of registered locations.
-	Locations() []Location
+	Locations(ctx context.Context) []Location
+}
+
+// EventHandler provides means of subscribing to booking events.
+type EventHandler interface {
+	// CargoWasAmended is called after a cargo has been booked, or its
+	// route specification or itinerary has changed, and its delivery has
+	// been re-derived and stored.
+	CargoWasAmended(context.Context, *shipping.Cargo)
+}
+
+// EventHandlers is an EventHandler that notifies each of its handlers, in
+// order.
+type EventHandlers []EventHandler
+
+// CargoWasAmended notifies each of the handlers.
+func (hs EventHandlers) CargoWasAmended(ctx context.Context, c *shipping.Cargo) {
+	for _, h := range hs {
+		h.CargoWasAmended(ctx, c)
+	}
 }
 
 type service struct {
@@ -44,50 +109,104 @@ type service struct {
 	locations      shipping.LocationRepository
 	handlingEvents shipping.HandlingEventRepository
 	routingService shipping.RoutingService
+	candidates     RouteCandidateRepository
+	amendments     AmendmentRepository
+	candidateTTL   time.Duration
+	costModels     map[string]shipping.CostModel
+	tariff         *pricing.Tariff
+	handler        EventHandler
+	now            func() time.Time
 }
 
-func (s *service) AssignCargoToRoute(id shipping.TrackingID, itinerary shipping.Itinerary) error {
-	if id == "" || len(itinerary.Legs) == 0 {
-		return ErrInvalidArgument
+func (s *service) AssignCargoToRoute(ctx context.Context, id shipping.TrackingID, itinerary shipping.Itinerary) error {
+	var fields []shipping.FieldError
+	if id == "" {
+		fields = append(fields, errRequired("tracking_id"))
+	}
+	if len(itinerary.Legs) == 0 {
+		fields = append(fields, shipping.FieldError{Name: "route", Reason: "must have at least one leg"})
+	}
+	if len(fields) > 0 {
+		return ErrInvalidArgument.WithFields(fields...)
 	}
 
-	c, err := s.cargos.Find(id)
+	c, err := s.cargos.Find(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	previous := termsOf(c)
+
 	c.AssignToRoute(itinerary)
+	c.Price = shipping.Money{}
+
+	if err := s.storeAmended(ctx, RouteAssigned, previous, c); err != nil {
+		return err
+	}
+
+	s.notify(ctx, c)
 
-	return s.cargos.Store(c)
+	return nil
 }
 
-func (s *service) BookNewCargo(origin, destination shipping.UNLocode, deadline time.Time) (shipping.TrackingID, error) {
-	if origin == "" || destination == "" || deadline.IsZero() {
-		return "", ErrInvalidArgument
+func (s *service) BookNewCargo(ctx context.Context, rs shipping.RouteSpecification) (shipping.TrackingID, error) {
+	var fields []shipping.FieldError
+	if rs.Origin == "" {
+		fields = append(fields, errRequired("origin"))
 	}
-
-	id := shipping.NextTrackingID()
-	rs := shipping.RouteSpecification{
-		Origin:          origin,
-		Destination:     destination,
-		ArrivalDeadline: deadline,
+	if rs.Destination == "" {
+		fields = append(fields, errRequired("destination"))
+	}
+	if rs.ArrivalDeadline.IsZero() {
+		fields = append(fields, errRequired("arrival_deadline"))
+	}
+	fields = append(fields, specificationErrors(rs)...)
+	if len(fields) > 0 {
+		return "", ErrInvalidArgument.WithFields(fields...)
 	}
 
-	c := shipping.NewCargo(id, rs)
+	rs.Constraints.AvoidLocations = append([]shipping.UNLocode(nil), rs.Constraints.AvoidLocations...)
 
-	if err := s.cargos.Store(c); err != nil {
+	c := shipping.NewCargo(shipping.NextTrackingID(), rs)
+
+	if err := s.cargos.Store(ctx, c); err != nil {
 		return "", err
 	}
 
+	s.notify(ctx, c)
+
 	return c.TrackingID, nil
 }
 
-func (s *service) LoadCargo(id shipping.TrackingID) (Cargo, error) {
+// specificationErrors checks the consistency of the fields of rs that are
+// set. It's shared by booking and by changes to a booking, so that a cargo
+// can't be changed into one that couldn't have been booked.
+func specificationErrors(rs shipping.RouteSpecification) []shipping.FieldError {
+	var fields []shipping.FieldError
+	if rs.Origin != "" && rs.Origin == rs.Destination {
+		fields = append(fields, shipping.FieldError{Name: "destination", Reason: "must differ from the origin"})
+	}
+	if !rs.EarliestDeparture.IsZero() && !rs.ArrivalDeadline.IsZero() && !rs.EarliestDeparture.Before(rs.ArrivalDeadline) {
+		fields = append(fields, shipping.FieldError{Name: "earliest_departure", Reason: "must be before the arrival deadline"})
+	}
+	if rs.Constraints.MaxLegs < 0 {
+		fields = append(fields, shipping.FieldError{Name: "max_legs", Reason: "must not be negative"})
+	}
+	for _, l := range rs.Constraints.AvoidLocations {
+		if l == rs.Origin || l == rs.Destination {
+			fields = append(fields, shipping.FieldError{Name: "avoid_locations", Reason: "must not include the origin or the destination"})
+			break
+		}
+	}
+	return fields
+}
+
+func (s *service) LoadCargo(ctx context.Context, id shipping.TrackingID) (Cargo, error) {
 	if id == "" {
-		return Cargo{}, ErrInvalidArgument
+		return Cargo{}, ErrInvalidArgument.WithFields(errRequired("tracking_id"))
 	}
 
-	c, err := s.cargos.Find(id)
+	c, err := s.cargos.Find(ctx, id)
 	if err != nil {
 		return Cargo{}, err
 	}
@@ -95,58 +214,328 @@ func (s *service) LoadCargo(id shipping.TrackingID) (Cargo, error) {
 	return assemble(c, s.handlingEvents), nil
 }
 
-func (s *service) ChangeDestination(id shipping.TrackingID, destination shipping.UNLocode) error {
-	if id == "" || destination == "" {
-		return ErrInvalidArgument
+func (s *service) ChangeDestination(ctx context.Context, id shipping.TrackingID, destination shipping.UNLocode) error {
+	var fields []shipping.FieldError
+	if id == "" {
+		fields = append(fields, errRequired("tracking_id"))
+	}
+	if destination == "" {
+		fields = append(fields, errRequired("destination"))
+	}
+	if len(fields) > 0 {
+		return ErrInvalidArgument.WithFields(fields...)
+	}
+
+	c, err := s.cargos.Find(ctx, id)
+	if err != nil {
+		return err
 	}
 
-	c, err := s.cargos.Find(id)
+	l, err := s.locations.Find(ctx, destination)
 	if err != nil {
 		return err
 	}
 
-	l, err := s.locations.Find(destination)
+	previous := termsOf(c)
+
+	rs := c.RouteSpecification
+	rs.Origin = c.Origin
+	rs.Destination = l.UNLocode
+
+	if fields := specificationErrors(rs); len(fields) > 0 {
+		return ErrInvalidArgument.WithFields(fields...)
+	}
+
+	c.SpecifyNewRoute(rs)
+
+	if err := s.storeAmended(ctx, DestinationChanged, previous, c); err != nil {
+		return err
+	}
+
+	s.notify(ctx, c)
+
+	return nil
+}
+
+func (s *service) ChangeArrivalDeadline(ctx context.Context, id shipping.TrackingID, deadline time.Time) error {
+	var fields []shipping.FieldError
+	if id == "" {
+		fields = append(fields, errRequired("tracking_id"))
+	}
+	if deadline.IsZero() {
+		fields = append(fields, errRequired("arrival_deadline"))
+	} else if !deadline.After(s.now()) {
+		fields = append(fields, shipping.FieldError{Name: "arrival_deadline", Reason: "must be in the future"})
+	}
+	if len(fields) > 0 {
+		return ErrInvalidArgument.WithFields(fields...)
+	}
+
+	c, err := s.cargos.Find(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	c.SpecifyNewRoute(shipping.RouteSpecification{
-		Origin:          c.Origin,
-		Destination:     l.UNLocode,
-		ArrivalDeadline: c.RouteSpecification.ArrivalDeadline,
-	})
+	previous := termsOf(c)
 
-	if err := s.cargos.Store(c); err != nil {
+	rs := c.RouteSpecification
+	rs.ArrivalDeadline = deadline
+
+	if fields := specificationErrors(rs); len(fields) > 0 {
+		return ErrInvalidArgument.WithFields(fields...)
+	}
+
+	c.SpecifyNewRoute(rs)
+
+	if err := s.storeAmended(ctx, ArrivalDeadlineChanged, previous, c); err != nil {
 		return err
 	}
 
+	s.notify(ctx, c)
+
 	return nil
 }
 
-func (s *service) RequestPossibleRoutesForCargo(id shipping.TrackingID) []shipping.Itinerary {
+func (s *service) ChangeOrigin(ctx context.Context, id shipping.TrackingID, origin shipping.UNLocode) error {
+	var fields []shipping.FieldError
 	if id == "" {
-		return nil
+		fields = append(fields, errRequired("tracking_id"))
+	}
+	if origin == "" {
+		fields = append(fields, errRequired("origin"))
+	}
+	if len(fields) > 0 {
+		return ErrInvalidArgument.WithFields(fields...)
 	}
 
-	c, err := s.cargos.Find(id)
+	c, err := s.cargos.Find(ctx, id)
 	if err != nil {
-		return []shipping.Itinerary{}
+		return err
+	}
+
+	for _, e := range s.handlingEvents.QueryHandlingHistory(ctx, id).HandlingEvents {
+		if e.Activity.Type == shipping.Receive {
+			return ErrCargoReceived
+		}
 	}
 
-	return s.routingService.FetchRoutesForSpecification(c.RouteSpecification)
+	l, err := s.locations.Find(ctx, origin)
+	if err != nil {
+		return err
+	}
+
+	previous := termsOf(c)
+
+	rs := c.RouteSpecification
+	rs.Origin = l.UNLocode
+
+	if fields := specificationErrors(rs); len(fields) > 0 {
+		return ErrInvalidArgument.WithFields(fields...)
+	}
+
+	// The cargo hasn't left, so it's still where it's picked up.
+	c.Origin = l.UNLocode
+	c.SpecifyNewRoute(rs)
+
+	if err := s.storeAmended(ctx, OriginChanged, previous, c); err != nil {
+		return err
+	}
+
+	s.notify(ctx, c)
+
+	return nil
 }
 
-func (s *service) Cargos() []Cargo {
-	var result []Cargo
-	for _, c := range s.cargos.FindAll() {
-		result = append(result, assemble(c, s.handlingEvents))
+func (s *service) History(ctx context.Context, id shipping.TrackingID) ([]Amendment, error) {
+	if id == "" {
+		return nil, ErrInvalidArgument.WithFields(errRequired("tracking_id"))
 	}
-	return result
+
+	if _, err := s.cargos.Find(ctx, id); err != nil {
+		return nil, err
+	}
+
+	amendments := s.amendments.FindByTrackingID(ctx, id)
+
+	result := make([]Amendment, 0, len(amendments))
+	for _, a := range amendments {
+		result = append(result, *a)
+	}
+	return result, nil
+}
+
+func (s *service) RequestPossibleRoutesForCargo(ctx context.Context, id shipping.TrackingID, strategy string) ([]RouteCandidate, error) {
+	if id == "" {
+		return nil, ErrInvalidArgument.WithFields(errRequired("tracking_id"))
+	}
+
+	if strategy == "" {
+		strategy = shipping.DefaultStrategy
+	}
+	m, ok := s.costModels[strategy]
+	if !ok {
+		return nil, ErrInvalidArgument.WithFields(shipping.FieldError{Name: "strategy", Reason: "unknown ranking strategy"})
+	}
+
+	c, err := s.cargos.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	itineraries, err := s.routingService.FetchRoutesForSpecification(ctx, c.RouteSpecification)
+	if err != nil {
+		return nil, err
+	}
+
+	expires := s.now().Add(s.candidateTTL)
+
+	ranked := shipping.RankRoutes(m, c.RouteSpecification, itineraries)
+
+	result := make([]RouteCandidate, 0, len(ranked))
+	for _, rc := range ranked {
+		candidate := RouteCandidate{
+			ID:             NextRouteCandidateID(),
+			TrackingID:     c.TrackingID,
+			ExpiresAt:      expires,
+			RouteCandidate: rc,
+		}
+		if err := s.candidates.Store(ctx, &candidate); err != nil {
+			return nil, err
+		}
+		result = append(result, candidate)
+	}
+
+	return result, nil
+}
+
+func (s *service) QuoteRoute(ctx context.Context, id shipping.TrackingID, candidate RouteCandidateID, size int, currency string) (pricing.Quote, error) {
+	var fields []shipping.FieldError
+	if id == "" {
+		fields = append(fields, errRequired("tracking_id"))
+	}
+	if candidate == "" {
+		fields = append(fields, errRequired("candidate_id"))
+	}
+	if len(fields) > 0 {
+		return pricing.Quote{}, ErrInvalidArgument.WithFields(fields...)
+	}
+
+	c, err := s.cargos.Find(ctx, id)
+	if err != nil {
+		return pricing.Quote{}, err
+	}
+
+	rc, err := s.findCandidate(ctx, c, candidate)
+	if err != nil {
+		return pricing.Quote{}, err
+	}
+
+	q, err := s.tariff.Quote(rc.Itinerary, size, currency)
+	if err != nil {
+		return pricing.Quote{}, err
+	}
+
+	rc.Quote = &q
+	if err := s.candidates.Store(ctx, rc); err != nil {
+		return pricing.Quote{}, err
+	}
+
+	return q, nil
+}
+
+func (s *service) AssignCargoToRouteCandidate(ctx context.Context, id shipping.TrackingID, candidate RouteCandidateID) error {
+	var fields []shipping.FieldError
+	if id == "" {
+		fields = append(fields, errRequired("tracking_id"))
+	}
+	if candidate == "" {
+		fields = append(fields, errRequired("candidate_id"))
+	}
+	if len(fields) > 0 {
+		return ErrInvalidArgument.WithFields(fields...)
+	}
+
+	c, err := s.cargos.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	rc, err := s.findCandidate(ctx, c, candidate)
+	if err != nil {
+		return err
+	}
+
+	previous := termsOf(c)
+
+	c.AssignToRoute(rc.Itinerary)
+	c.Price = shipping.Money{}
+	if rc.Quote != nil {
+		c.Price = rc.Quote.Total
+	}
+
+	if err := s.storeAmended(ctx, RouteAssigned, previous, c); err != nil {
+		return err
+	}
+
+	s.notify(ctx, c)
+
+	return nil
+}
+
+// notify tells the event handler, if any, that c was booked or amended.
+func (s *service) notify(ctx context.Context, c *shipping.Cargo) {
+	if s.handler != nil {
+		s.handler.CargoWasAmended(ctx, c)
+	}
+}
+
+// findCandidate returns the route candidate with the given ID, as long as it
+// belongs to c and can still be assigned to it.
+func (s *service) findCandidate(ctx context.Context, c *shipping.Cargo, id RouteCandidateID) (*RouteCandidate, error) {
+	rc, err := s.candidates.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A candidate of another cargo is as good as unknown.
+	if rc.TrackingID != c.TrackingID {
+		return nil, ErrUnknownRouteCandidate
+	}
+	if !s.now().Before(rc.ExpiresAt) || !c.RouteSpecification.IsFullySatisfiedBy(rc.Itinerary) {
+		return nil, ErrStaleRouteCandidate
+	}
+
+	return rc, nil
 }
 
-func (s *service) Locations() []Location {
+func (s *service) Cargos(ctx context.Context, q shipping.CargoQuery) (CargoPage, error) {
+	switch {
+	case q.Limit < 0:
+		return CargoPage{}, ErrInvalidArgument.WithFields(shipping.FieldError{Name: "limit", Reason: "must not be negative"})
+	case q.Limit == 0:
+		q.Limit = DefaultPageSize
+	case q.Limit > MaxPageSize:
+		q.Limit = MaxPageSize
+	}
+
+	page, err := s.cargos.Query(ctx, q)
+	if err != nil {
+		return CargoPage{}, err
+	}
+
+	result := CargoPage{
+		Cargos:     make([]Cargo, 0, len(page.Cargos)),
+		NextCursor: page.NextCursor,
+	}
+	for _, c := range page.Cargos {
+		result.Cargos = append(result.Cargos, assemble(c, s.handlingEvents))
+	}
+	return result, nil
+}
+
+func (s *service) Locations(ctx context.Context) []Location {
 	var result []Location
-	for _, v := range s.locations.FindAll() {
+	for _, v := range s.locations.FindAll(ctx) {
 		result = append(result, Location{
 			UNLocode: string(v.UNLocode),
 			Name:     v.Name,
@@ -155,14 +544,63 @@ func (s *service) Locations() []Location {
 	return result
 }
 
-// NewService creates a booking service with necessary dependencies.
-func NewService(cargos shipping.CargoRepository, locations shipping.LocationRepository, events shipping.HandlingEventRepository, rs shipping.RoutingService) Service {
-	return &service{
+// Option configures optional features of a booking service.
+type Option func(*service)
+
+// WithRouteCandidateTTL keeps route candidates assignable for d rather than
+// DefaultRouteCandidateTTL.
+func WithRouteCandidateTTL(d time.Duration) Option {
+	return func(s *service) { s.candidateTTL = d }
+}
+
+// WithCostModel ranks routes with m when asked for the given strategy,
+// replacing the predefined cost model of that name, if any.
+func WithCostModel(strategy string, m shipping.CostModel) Option {
+	return func(s *service) { s.costModels[strategy] = m }
+}
+
+// WithCostModels ranks routes with the given cost models by strategy,
+// instead of shipping.DefaultCostModels. The DefaultStrategy should be among
+// them.
+func WithCostModels(models map[string]shipping.CostModel) Option {
+	return func(s *service) {
+		s.costModels = make(map[string]shipping.CostModel, len(models))
+		for strategy, m := range models {
+			s.costModels[strategy] = m
+		}
+	}
+}
+
+// WithEventHandler notifies h when a cargo is booked or its booking
+// changes.
+func WithEventHandler(h EventHandler) Option {
+	return func(s *service) { s.handler = h }
+}
+
+// WithTariff prices routes with t. Without a tariff, no route can be quoted.
+func WithTariff(t *pricing.Tariff) Option {
+	return func(s *service) { s.tariff = t }
+}
+
+// NewService creates a booking service with necessary dependencies. Changes
+// to bookings are recorded in amendments.
+func NewService(cargos shipping.CargoRepository, locations shipping.LocationRepository, events shipping.HandlingEventRepository, rs shipping.RoutingService, candidates RouteCandidateRepository, amendments AmendmentRepository, opts ...Option) Service {
+	s := &service{
 		cargos:         cargos,
 		locations:      locations,
 		handlingEvents: events,
 		routingService: rs,
+		candidates:     candidates,
+		amendments:     amendments,
+		candidateTTL:   DefaultRouteCandidateTTL,
+		costModels:     shipping.DefaultCostModels(),
+		tariff:         &pricing.Tariff{},
+		now:            time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Location is a read model for booking views.
@@ -171,25 +609,55 @@ type Location struct {
 	Name     string `json:"name"`
 }
 
+// CargoPage is a page of cargos in a listing.
+type CargoPage struct {
+	Cargos     []Cargo
+	NextCursor string
+}
+
 // Cargo is a read model for booking views.
 type Cargo struct {
-	ArrivalDeadline time.Time      `json:"arrival_deadline"`
-	Destination     string         `json:"destination"`
-	Legs            []shipping.Leg `json:"legs,omitempty"`
-	Misrouted       bool           `json:"misrouted"`
-	Origin          string         `json:"origin"`
-	Routed          bool           `json:"routed"`
-	TrackingID      string         `json:"tracking_id"`
+	ArrivalDeadline   time.Time       `json:"arrival_deadline"`
+	AvoidLocations    []string        `json:"avoid_locations,omitempty"`
+	Destination       string          `json:"destination"`
+	EarliestDeparture *time.Time      `json:"earliest_departure,omitempty"`
+	Legs              []shipping.Leg  `json:"legs,omitempty"`
+	MaxLegs           int             `json:"max_legs,omitempty"`
+	Misrouted         bool            `json:"misrouted"`
+	Origin            string          `json:"origin"`
+	Price             *shipping.Money `json:"price,omitempty"`
+	Routed            bool            `json:"routed"`
+	TrackingID        string          `json:"tracking_id"`
 }
 
 func assemble(c *shipping.Cargo, events shipping.HandlingEventRepository) Cargo {
+	var price *shipping.Money
+	if !c.Price.IsZero() {
+		p := c.Price
+		price = &p
+	}
+
+	var earliestDeparture *time.Time
+	if t := c.RouteSpecification.EarliestDeparture; !t.IsZero() {
+		earliestDeparture = &t
+	}
+
+	var avoid []string
+	for _, l := range c.RouteSpecification.Constraints.AvoidLocations {
+		avoid = append(avoid, string(l))
+	}
+
 	return Cargo{
-		TrackingID:      string(c.TrackingID),
-		Origin:          string(c.Origin),
-		Destination:     string(c.RouteSpecification.Destination),
-		Misrouted:       c.Delivery.RoutingStatus == shipping.Misrouted,
-		Routed:          !c.Itinerary.IsEmpty(),
-		ArrivalDeadline: c.RouteSpecification.ArrivalDeadline,
-		Legs:            c.Itinerary.Legs,
+		TrackingID:        string(c.TrackingID),
+		Origin:            string(c.Origin),
+		Destination:       string(c.RouteSpecification.Destination),
+		Misrouted:         c.Delivery.RoutingStatus == shipping.Misrouted,
+		Routed:            !c.Itinerary.IsEmpty(),
+		ArrivalDeadline:   c.RouteSpecification.ArrivalDeadline,
+		EarliestDeparture: earliestDeparture,
+		MaxLegs:           c.RouteSpecification.Constraints.MaxLegs,
+		AvoidLocations:    avoid,
+		Legs:              c.Itinerary.Legs,
+		Price:             price,
 	}
 }
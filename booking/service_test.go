@@ -1,30 +1,49 @@
 package booking
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	shipping "github.com/marcusolsson/goddd"
 	"github.com/marcusolsson/goddd/mock"
+	"github.com/marcusolsson/goddd/pricing"
 )
 
 func TestBookNewCargo(t *testing.T) {
+	ctx := context.Background()
+
 	var (
 		origin      = shipping.SESTO
 		destination = shipping.AUMEL
 		deadline    = time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)
+		departure   = time.Date(2015, time.November, 1, 12, 0, 0, 0, time.UTC)
 	)
 
-	var cargos mockCargoRepository
-
-	s := NewService(&cargos, nil, nil, nil)
+	var (
+		cargos   mockCargoRepository
+		handlers [2]stubEventHandler
+	)
 
-	id, err := s.BookNewCargo(origin, destination, deadline)
+	s := NewService(&cargos, nil, nil, nil, nil, nil, WithEventHandler(EventHandlers{&handlers[0], &handlers[1]}))
+
+	id, err := s.BookNewCargo(ctx, shipping.RouteSpecification{
+		Origin:            origin,
+		Destination:       destination,
+		ArrivalDeadline:   deadline,
+		EarliestDeparture: departure,
+		Constraints: shipping.RouteConstraints{
+			MaxLegs:        2,
+			AvoidLocations: []shipping.UNLocode{shipping.USNYC},
+		},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	c, err := cargos.Find(id)
+	c, err := cargos.Find(ctx, id)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -43,21 +62,84 @@ func TestBookNewCargo(t *testing.T) {
 		t.Errorf("c.RouteSpecification.ArrivalDeadline = %s; want = %s",
 			c.RouteSpecification.ArrivalDeadline, deadline)
 	}
+	if c.RouteSpecification.EarliestDeparture != departure {
+		t.Errorf("c.RouteSpecification.EarliestDeparture = %s; want = %s",
+			c.RouteSpecification.EarliestDeparture, departure)
+	}
+	if c.RouteSpecification.Constraints.MaxLegs != 2 {
+		t.Errorf("c.RouteSpecification.Constraints.MaxLegs = %d; want = %d",
+			c.RouteSpecification.Constraints.MaxLegs, 2)
+	}
+	if avoid := c.RouteSpecification.Constraints.AvoidLocations; len(avoid) != 1 || avoid[0] != shipping.USNYC {
+		t.Errorf("c.RouteSpecification.Constraints.AvoidLocations = %v; want = [%s]", avoid, shipping.USNYC)
+	}
+	for i, h := range handlers {
+		if len(h.amended) != 1 || h.amended[0] != id {
+			t.Errorf("handlers[%d].amended = %v; want = [%s]", i, h.amended, id)
+		}
+	}
+
+	loaded, err := s.LoadCargo(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.EarliestDeparture == nil || !loaded.EarliestDeparture.Equal(departure) {
+		t.Errorf("loaded.EarliestDeparture = %v; want = %s", loaded.EarliestDeparture, departure)
+	}
+	if loaded.MaxLegs != 2 || len(loaded.AvoidLocations) != 1 {
+		t.Errorf("loaded = %+v; want max legs and avoided locations", loaded)
+	}
+}
+
+func TestBookNewCargoInvalidConstraints(t *testing.T) {
+	ctx := context.Background()
+
+	var cargos mockCargoRepository
+
+	s := NewService(&cargos, nil, nil, nil, nil, nil)
+
+	deadline := time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+	_, err := s.BookNewCargo(ctx, shipping.RouteSpecification{
+		Origin:            shipping.SESTO,
+		Destination:       shipping.AUMEL,
+		ArrivalDeadline:   deadline,
+		EarliestDeparture: deadline.AddDate(0, 0, 1),
+		Constraints: shipping.RouteConstraints{
+			MaxLegs:        -1,
+			AvoidLocations: []shipping.UNLocode{shipping.AUMEL},
+		},
+	})
+
+	var e *shipping.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("err = %v; want a domain error", err)
+	}
+
+	var fields []string
+	for _, f := range e.Fields {
+		fields = append(fields, f.Name)
+	}
+	if got, want := strings.Join(fields, ","), "earliest_departure,max_legs,avoid_locations"; got != want {
+		t.Errorf("fields = %s; want = %s", got, want)
+	}
 }
 
 type stubRoutingService struct{}
 
-func (s *stubRoutingService) FetchRoutesForSpecification(rs shipping.RouteSpecification) []shipping.Itinerary {
+func (s *stubRoutingService) FetchRoutesForSpecification(_ context.Context, rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
 	legs := []shipping.Leg{
 		{LoadLocation: rs.Origin, UnloadLocation: rs.Destination},
 	}
 
 	return []shipping.Itinerary{
 		{Legs: legs},
-	}
+	}, nil
 }
 
 func TestRequestPossibleRoutesForCargo(t *testing.T) {
+	ctx := context.Background()
+
 	var (
 		origin      = shipping.SESTO
 		destination = shipping.AUMEL
@@ -68,32 +150,107 @@ func TestRequestPossibleRoutesForCargo(t *testing.T) {
 
 	var rs stubRoutingService
 
-	s := NewService(&cargos, nil, nil, &rs)
+	s := NewService(&cargos, nil, nil, &rs, &mockRouteCandidateRepository{}, &mockAmendmentRepository{})
 
-	r := s.RequestPossibleRoutesForCargo("no_such_id")
-
-	if len(r) != 0 {
-		t.Errorf("len(r) = %d; want = %d", len(r), 0)
+	if _, err := s.RequestPossibleRoutesForCargo(ctx, "no_such_id", ""); err != shipping.ErrUnknownCargo {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrUnknownCargo)
 	}
 
-	id, err := s.BookNewCargo(origin, destination, deadline)
+	id, err := s.BookNewCargo(ctx, shipping.RouteSpecification{Origin: origin, Destination: destination, ArrivalDeadline: deadline})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	i := s.RequestPossibleRoutesForCargo(id)
+	i, err := s.RequestPossibleRoutesForCargo(ctx, id, "")
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if len(i) != 1 {
 		t.Errorf("len(i) = %d; want = %d", len(i), 1)
 	}
 }
 
+func TestWithCostModels(t *testing.T) {
+	ctx := context.Background()
+
+	var cargos mockCargoRepository
+	var rs stubRoutingService
+
+	custom := costModelFunc(func(shipping.RouteSpecification, shipping.Itinerary) []shipping.Cost {
+		return []shipping.Cost{{Criterion: "custom", Value: 2, Weight: 1, Score: 2}}
+	})
+
+	s := NewService(&cargos, nil, nil, &rs, &mockRouteCandidateRepository{}, &mockAmendmentRepository{},
+		WithCostModels(map[string]shipping.CostModel{shipping.DefaultStrategy: custom}))
+
+	id, err := s.BookNewCargo(ctx, shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL, ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	candidates, err := s.RequestPossibleRoutesForCargo(ctx, id, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 1 || candidates[0].Score != 2 {
+		t.Errorf("candidates = %+v; want one with score 2", candidates)
+	}
+
+	if _, err := s.RequestPossibleRoutesForCargo(ctx, id, shipping.StrategyFastest); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("err = %v; want = %v", err, ErrInvalidArgument)
+	}
+}
+
+func TestRequestPossibleRoutesForCargoStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	var cargos mockCargoRepository
+	var rs stubRoutingService
+
+	var used bool
+	custom := costModelFunc(func(shipping.RouteSpecification, shipping.Itinerary) []shipping.Cost {
+		used = true
+		return []shipping.Cost{{Criterion: "custom", Value: 1, Weight: 1, Score: 1}}
+	})
+
+	s := NewService(&cargos, nil, nil, &rs, &mockRouteCandidateRepository{}, &mockAmendmentRepository{}, WithCostModel("custom", custom))
+
+	id, err := s.BookNewCargo(ctx, shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL, ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.RequestPossibleRoutesForCargo(ctx, id, "nope"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("err = %v; want = %v", err, ErrInvalidArgument)
+	}
+
+	candidates, err := s.RequestPossibleRoutesForCargo(ctx, id, "custom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !used {
+		t.Errorf("custom cost model wasn't used")
+	}
+	if len(candidates) != 1 || candidates[0].Score != 1 {
+		t.Errorf("candidates = %+v; want one with score 1", candidates)
+	}
+}
+
+type costModelFunc func(shipping.RouteSpecification, shipping.Itinerary) []shipping.Cost
+
+func (f costModelFunc) Costs(rs shipping.RouteSpecification, it shipping.Itinerary) []shipping.Cost {
+	return f(rs, it)
+}
+
 func TestAssignCargoToRoute(t *testing.T) {
+	ctx := context.Background()
+
 	var cargos mockCargoRepository
 
 	var rs stubRoutingService
 
-	s := NewService(&cargos, nil, nil, &rs)
+	s := NewService(&cargos, nil, nil, &rs, &mockRouteCandidateRepository{}, &mockAmendmentRepository{})
 
 	var (
 		origin      = shipping.SESTO
@@ -101,27 +258,203 @@ func TestAssignCargoToRoute(t *testing.T) {
 		deadline    = time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)
 	)
 
-	id, err := s.BookNewCargo(origin, destination, deadline)
+	id, err := s.BookNewCargo(ctx, shipping.RouteSpecification{Origin: origin, Destination: destination, ArrivalDeadline: deadline})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	i := s.RequestPossibleRoutesForCargo(id)
+	i, err := s.RequestPossibleRoutesForCargo(ctx, id, "")
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if len(i) != 1 {
 		t.Errorf("len(i) = %d; want = %d", len(i), 1)
 	}
 
-	if err := s.AssignCargoToRoute(id, i[0]); err != nil {
+	if err := s.AssignCargoToRoute(ctx, id, i[0].Itinerary); err != nil {
 		t.Fatal(err)
 	}
 
-	if err := s.AssignCargoToRoute("no_such_id", shipping.Itinerary{}); err != ErrInvalidArgument {
+	if err := s.AssignCargoToRoute(ctx, "no_such_id", shipping.Itinerary{}); !errors.Is(err, ErrInvalidArgument) {
 		t.Errorf("err = %s; want = %s", err, ErrInvalidArgument)
 	}
 }
 
+func TestAssignCargoToRouteCandidate(t *testing.T) {
+	ctx := context.Background()
+
+	var cargos mockCargoRepository
+	var rs stubRoutingService
+	candidates := &mockRouteCandidateRepository{}
+
+	now := time.Date(2015, time.November, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewService(&cargos, nil, nil, &rs, candidates, &mockAmendmentRepository{}, WithRouteCandidateTTL(time.Minute))
+	s.(*service).now = func() time.Time { return now }
+
+	id, err := s.BookNewCargo(ctx, shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL, ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := s.RequestPossibleRoutesForCargo(ctx, id, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 {
+		t.Fatalf("len(routes) = %d; want = %d", len(routes), 1)
+	}
+	if routes[0].ID == "" || routes[0].TrackingID != id {
+		t.Errorf("routes[0] = %+v; want an ID and tracking ID %s", routes[0], id)
+	}
+	if want := now.Add(time.Minute); !routes[0].ExpiresAt.Equal(want) {
+		t.Errorf("routes[0].ExpiresAt = %v; want = %v", routes[0].ExpiresAt, want)
+	}
+
+	foreign := routes[0]
+	foreign.ID = "foreign"
+	foreign.TrackingID = "OTHER"
+	candidates.Store(ctx, &foreign)
+
+	for _, tt := range []struct {
+		name      string
+		candidate RouteCandidateID
+		want      error
+	}{
+		{"Missing", "", ErrInvalidArgument},
+		{"Unknown", "no_such_id", ErrUnknownRouteCandidate},
+		{"Foreign", "foreign", ErrUnknownRouteCandidate},
+	} {
+		if err := s.AssignCargoToRouteCandidate(ctx, id, tt.candidate); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v; want = %v", tt.name, err, tt.want)
+		}
+	}
+
+	// The candidate no longer reaches the destination.
+	cargos.cargo.SpecifyNewRoute(shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.CNHKG})
+	if err := s.AssignCargoToRouteCandidate(ctx, id, routes[0].ID); !errors.Is(err, ErrStaleRouteCandidate) {
+		t.Errorf("err = %v; want = %v", err, ErrStaleRouteCandidate)
+	}
+	cargos.cargo.SpecifyNewRoute(shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL})
+
+	if err := s.AssignCargoToRouteCandidate(ctx, id, routes[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if cargos.cargo.Delivery.RoutingStatus != shipping.Routed {
+		t.Errorf("RoutingStatus = %s; want = %s", cargos.cargo.Delivery.RoutingStatus, shipping.Routed)
+	}
+
+	now = now.Add(time.Minute)
+	if err := s.AssignCargoToRouteCandidate(ctx, id, routes[0].ID); !errors.Is(err, ErrStaleRouteCandidate) {
+		t.Errorf("err = %v; want = %v", err, ErrStaleRouteCandidate)
+	}
+}
+
+func TestQuoteRoute(t *testing.T) {
+	ctx := context.Background()
+
+	var cargos mockCargoRepository
+	var rs stubRoutingService
+	candidates := &mockRouteCandidateRepository{}
+
+	tariff := &pricing.Tariff{
+		Currency:      "USD",
+		Rates:         []pricing.Rate{{Amount: 1000}},
+		Surcharges:    []pricing.Surcharge{{Name: "Bunker adjustment", Percent: 10}},
+		ExchangeRates: map[string]float64{"EUR": 0.5},
+	}
+
+	s := NewService(&cargos, nil, nil, &rs, candidates, &mockAmendmentRepository{}, WithTariff(tariff))
+
+	id, err := s.BookNewCargo(ctx, shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL, ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := s.RequestPossibleRoutesForCargo(ctx, id, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		candidate RouteCandidateID
+		size      int
+		currency  string
+		want      error
+	}{
+		{"MissingCandidate", "", 1, "", ErrInvalidArgument},
+		{"UnknownCandidate", "no_such_id", 1, "", ErrUnknownRouteCandidate},
+		{"NoSize", routes[0].ID, 0, "", ErrInvalidArgument},
+		{"UnknownCurrency", routes[0].ID, 1, "SEK", ErrInvalidArgument},
+	} {
+		if _, err := s.QuoteRoute(ctx, id, tt.candidate, tt.size, tt.currency); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v; want = %v", tt.name, err, tt.want)
+		}
+	}
+
+	q, err := s.QuoteRoute(ctx, id, routes[0].ID, 2, "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (shipping.Money{Amount: 1100, Currency: "EUR"}); q.Total != want {
+		t.Errorf("q.Total = %v; want = %v", q.Total, want)
+	}
+	if rc, _ := candidates.Find(ctx, routes[0].ID); rc.Quote == nil || rc.Quote.Total != q.Total {
+		t.Errorf("candidate quote = %+v; want = %+v", rc.Quote, q)
+	}
+
+	if err := s.AssignCargoToRouteCandidate(ctx, id, routes[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if cargos.cargo.Price != q.Total {
+		t.Errorf("Price = %v; want = %v", cargos.cargo.Price, q.Total)
+	}
+
+	c, err := s.LoadCargo(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Price == nil || *c.Price != q.Total {
+		t.Errorf("c.Price = %v; want = %v", c.Price, q.Total)
+	}
+
+	// An itinerary given in full hasn't been priced.
+	if err := s.AssignCargoToRoute(ctx, id, routes[0].Itinerary); err != nil {
+		t.Fatal(err)
+	}
+	if !cargos.cargo.Price.IsZero() {
+		t.Errorf("Price = %v; want none", cargos.cargo.Price)
+	}
+}
+
+func TestQuoteRouteWithoutTariff(t *testing.T) {
+	ctx := context.Background()
+
+	var cargos mockCargoRepository
+	var rs stubRoutingService
+
+	s := NewService(&cargos, nil, nil, &rs, &mockRouteCandidateRepository{}, &mockAmendmentRepository{})
+
+	id, err := s.BookNewCargo(ctx, shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL, ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := s.RequestPossibleRoutesForCargo(ctx, id, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.QuoteRoute(ctx, id, routes[0].ID, 1, ""); !errors.Is(err, pricing.ErrNoRate) {
+		t.Errorf("err = %v; want = %v", err, pricing.ErrNoRate)
+	}
+}
+
 func TestChangeCargoDestination(t *testing.T) {
+	ctx := context.Background()
+
 	var cargos mockCargoRepository
 	var locations mock.LocationRepository
 
@@ -132,9 +465,12 @@ func TestChangeCargoDestination(t *testing.T) {
 		return shipping.Melbourne, nil
 	}
 
-	var rs stubRoutingService
+	var (
+		rs      stubRoutingService
+		handler stubEventHandler
+	)
 
-	s := NewService(&cargos, &locations, nil, &rs)
+	s := NewService(&cargos, &locations, nil, &rs, &mockRouteCandidateRepository{}, &mockAmendmentRepository{}, WithEventHandler(&handler))
 
 	c := shipping.NewCargo("ABC", shipping.RouteSpecification{
 		Origin:          shipping.SESTO,
@@ -142,15 +478,15 @@ func TestChangeCargoDestination(t *testing.T) {
 		ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC),
 	})
 
-	if err := s.ChangeDestination("no_such_id", shipping.SESTO); err != shipping.ErrUnknownCargo {
+	if err := s.ChangeDestination(ctx, "no_such_id", shipping.SESTO); err != shipping.ErrUnknownCargo {
 		t.Errorf("err = %s; want = %s", err, shipping.ErrUnknownCargo)
 	}
 
-	if err := cargos.Store(c); err != nil {
+	if err := cargos.Store(ctx, c); err != nil {
 		t.Fatal(err)
 	}
 
-	if err := s.ChangeDestination(c.TrackingID, "no_such_unlocode"); err != shipping.ErrUnknownLocation {
+	if err := s.ChangeDestination(ctx, c.TrackingID, "no_such_unlocode"); err != shipping.ErrUnknownLocation {
 		t.Errorf("err = %s; want = %s", err, shipping.ErrUnknownLocation)
 	}
 
@@ -159,11 +495,11 @@ func TestChangeCargoDestination(t *testing.T) {
 			c.RouteSpecification.Destination, shipping.CNHKG)
 	}
 
-	if err := s.ChangeDestination(c.TrackingID, shipping.AUMEL); err != nil {
+	if err := s.ChangeDestination(ctx, c.TrackingID, shipping.AUMEL); err != nil {
 		t.Fatal(err)
 	}
 
-	uc, err := cargos.Find(c.TrackingID)
+	uc, err := cargos.Find(ctx, c.TrackingID)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -172,9 +508,222 @@ func TestChangeCargoDestination(t *testing.T) {
 		t.Errorf("uc.RouteSpecification.Destination = %s; want = %s",
 			uc.RouteSpecification.Destination, shipping.AUMEL)
 	}
+
+	if len(handler.amended) != 1 || handler.amended[0] != c.TrackingID {
+		t.Errorf("amended = %v; want = [%s]", handler.amended, c.TrackingID)
+	}
+}
+
+func TestChangeOriginAndDestinationConstraints(t *testing.T) {
+	ctx := context.Background()
+
+	var cargos mockCargoRepository
+	var locations mock.LocationRepository
+	var events mock.HandlingEventRepository
+
+	locations.FindFn = func(loc shipping.UNLocode) (*shipping.Location, error) {
+		return &shipping.Location{UNLocode: loc}, nil
+	}
+	events.QueryHandlingHistoryFn = func(shipping.TrackingID) shipping.HandlingHistory {
+		return shipping.HandlingHistory{}
+	}
+
+	s := NewService(&cargos, &locations, &events, &stubRoutingService{}, &mockRouteCandidateRepository{}, &mockAmendmentRepository{})
+
+	c := shipping.NewCargo("ABC", shipping.RouteSpecification{
+		Origin:          shipping.SESTO,
+		Destination:     shipping.CNHKG,
+		ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC),
+		Constraints:     shipping.RouteConstraints{AvoidLocations: []shipping.UNLocode{shipping.USNYC}},
+	})
+	if err := cargos.Store(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		change func() error
+		field  string
+	}{
+		{"origin is destination", func() error { return s.ChangeOrigin(ctx, c.TrackingID, shipping.CNHKG) }, "destination"},
+		{"destination is origin", func() error { return s.ChangeDestination(ctx, c.TrackingID, shipping.SESTO) }, "destination"},
+		{"avoided origin", func() error { return s.ChangeOrigin(ctx, c.TrackingID, shipping.USNYC) }, "avoid_locations"},
+		{"avoided destination", func() error { return s.ChangeDestination(ctx, c.TrackingID, shipping.USNYC) }, "avoid_locations"},
+	} {
+		err := tt.change()
+		var e *shipping.Error
+		if !errors.As(err, &e) || e.Code != shipping.CodeInvalidArgument || len(e.Fields) != 1 || e.Fields[0].Name != tt.field {
+			t.Errorf("%s: err = %v; want invalid %s", tt.name, err, tt.field)
+		}
+	}
+
+	if cargos.stores != 1 {
+		t.Errorf("stores = %d; want = %d", cargos.stores, 1)
+	}
+}
+
+type stubEventHandler struct {
+	amended []shipping.TrackingID
+}
+
+func (h *stubEventHandler) CargoWasAmended(_ context.Context, c *shipping.Cargo) {
+	h.amended = append(h.amended, c.TrackingID)
+}
+
+func TestChangeArrivalDeadline(t *testing.T) {
+	ctx := context.Background()
+
+	var cargos mockCargoRepository
+	var amendments mockAmendmentRepository
+
+	s := NewService(&cargos, nil, nil, &stubRoutingService{}, &mockRouteCandidateRepository{}, &amendments)
+
+	deadline := time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)
+	now := deadline.AddDate(0, -1, 0)
+	s.(*service).now = func() time.Time { return now }
+
+	c := shipping.NewCargo("ABC", shipping.RouteSpecification{
+		Origin:            shipping.SESTO,
+		Destination:       shipping.CNHKG,
+		EarliestDeparture: now.AddDate(0, 0, 7),
+		ArrivalDeadline:   deadline,
+	})
+
+	if err := s.ChangeArrivalDeadline(ctx, c.TrackingID, time.Time{}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("err = %v; want = %v", err, ErrInvalidArgument)
+	}
+
+	if err := s.ChangeArrivalDeadline(ctx, "no_such_id", deadline); err != shipping.ErrUnknownCargo {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrUnknownCargo)
+	}
+
+	if err := cargos.Store(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		deadline time.Time
+		field    string
+	}{
+		{now.Add(-time.Hour), "arrival_deadline"},
+		{now.AddDate(0, 0, 3), "earliest_departure"},
+	} {
+		err := s.ChangeArrivalDeadline(ctx, c.TrackingID, tt.deadline)
+		var e *shipping.Error
+		if !errors.As(err, &e) || e.Code != shipping.CodeInvalidArgument || len(e.Fields) != 1 || e.Fields[0].Name != tt.field {
+			t.Errorf("ChangeArrivalDeadline(%v) = %v; want invalid %s", tt.deadline, err, tt.field)
+		}
+	}
+
+	// Keeping the deadline records no amendment.
+	if err := s.ChangeArrivalDeadline(ctx, c.TrackingID, deadline); err != nil {
+		t.Fatal(err)
+	}
+
+	later := deadline.AddDate(0, 0, 7)
+	if err := s.ChangeArrivalDeadline(ctx, c.TrackingID, later); err != nil {
+		t.Fatal(err)
+	}
+
+	uc, err := cargos.Find(ctx, c.TrackingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !uc.RouteSpecification.ArrivalDeadline.Equal(later) {
+		t.Errorf("uc.RouteSpecification.ArrivalDeadline = %v; want = %v",
+			uc.RouteSpecification.ArrivalDeadline, later)
+	}
+
+	if len(amendments.amendments) != 1 {
+		t.Fatalf("len(amendments) = %d; want = %d", len(amendments.amendments), 1)
+	}
+	if a := amendments.amendments[0]; a.Type != ArrivalDeadlineChanged || !a.Previous.ArrivalDeadline.Equal(deadline) || !a.New.ArrivalDeadline.Equal(later) {
+		t.Errorf("amendment = %+v; want arrival deadline changed to %v", a, later)
+	}
+}
+
+func TestChangeOrigin(t *testing.T) {
+	ctx := context.Background()
+
+	var cargos mockCargoRepository
+	var amendments mockAmendmentRepository
+	var locations mock.LocationRepository
+	var events mock.HandlingEventRepository
+
+	locations.FindFn = func(loc shipping.UNLocode) (*shipping.Location, error) {
+		if loc != shipping.DEHAM {
+			return nil, shipping.ErrUnknownLocation
+		}
+		return shipping.Hamburg, nil
+	}
+
+	var history shipping.HandlingHistory
+	events.QueryHandlingHistoryFn = func(shipping.TrackingID) shipping.HandlingHistory {
+		return history
+	}
+
+	s := NewService(&cargos, &locations, &events, &stubRoutingService{}, &mockRouteCandidateRepository{}, &amendments)
+
+	c := shipping.NewCargo("ABC", shipping.RouteSpecification{
+		Origin:          shipping.SESTO,
+		Destination:     shipping.CNHKG,
+		ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC),
+	})
+	c.AssignToRoute(shipping.Itinerary{Legs: []shipping.Leg{
+		{LoadLocation: shipping.SESTO, UnloadLocation: shipping.CNHKG},
+	}})
+
+	if err := s.ChangeOrigin(ctx, c.TrackingID, ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("err = %v; want = %v", err, ErrInvalidArgument)
+	}
+
+	if err := s.ChangeOrigin(ctx, "no_such_id", shipping.DEHAM); err != shipping.ErrUnknownCargo {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrUnknownCargo)
+	}
+
+	if err := cargos.Store(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ChangeOrigin(ctx, c.TrackingID, "no_such_unlocode"); err != shipping.ErrUnknownLocation {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrUnknownLocation)
+	}
+
+	if err := s.ChangeOrigin(ctx, c.TrackingID, shipping.DEHAM); err != nil {
+		t.Fatal(err)
+	}
+
+	uc, err := cargos.Find(ctx, c.TrackingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uc.Origin != shipping.DEHAM || uc.RouteSpecification.Origin != shipping.DEHAM {
+		t.Errorf("origin = %s, %s; want = %s", uc.Origin, uc.RouteSpecification.Origin, shipping.DEHAM)
+	}
+
+	// The assigned route no longer departs from the origin.
+	if uc.Delivery.RoutingStatus != shipping.Misrouted {
+		t.Errorf("uc.Delivery.RoutingStatus = %v; want = %v", uc.Delivery.RoutingStatus, shipping.Misrouted)
+	}
+
+	if len(amendments.amendments) != 1 || amendments.amendments[0].Type != OriginChanged {
+		t.Errorf("amendments = %v; want origin changed", amendments.amendments)
+	}
+
+	history = shipping.HandlingHistory{HandlingEvents: []shipping.HandlingEvent{
+		{TrackingID: c.TrackingID, Activity: shipping.HandlingActivity{Type: shipping.Receive, Location: shipping.DEHAM}},
+	}}
+
+	if err := s.ChangeOrigin(ctx, c.TrackingID, shipping.DEHAM); err != ErrCargoReceived {
+		t.Errorf("err = %v; want = %v", err, ErrCargoReceived)
+	}
 }
 
 func TestLoadCargo(t *testing.T) {
+	ctx := context.Background()
+
 	deadline := time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)
 
 	var cargos mock.CargoRepository
@@ -190,9 +739,9 @@ func TestLoadCargo(t *testing.T) {
 		}, nil
 	}
 
-	s := NewService(&cargos, nil, nil, nil)
+	s := NewService(&cargos, nil, nil, nil, nil, nil)
 
-	c, err := s.LoadCargo("test_id")
+	c, err := s.LoadCargo(ctx, "test_id")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -221,21 +770,248 @@ func TestLoadCargo(t *testing.T) {
 }
 
 type mockCargoRepository struct {
-	cargo *shipping.Cargo
+	cargo  *shipping.Cargo
+	stores int
+	err    error
 }
 
-func (r *mockCargoRepository) Store(c *shipping.Cargo) error {
+func (r *mockCargoRepository) Store(_ context.Context, c *shipping.Cargo) error {
+	if r.err != nil {
+		return r.err
+	}
 	r.cargo = c
+	r.stores++
 	return nil
 }
 
-func (r *mockCargoRepository) Find(id shipping.TrackingID) (*shipping.Cargo, error) {
+func (r *mockCargoRepository) Find(_ context.Context, id shipping.TrackingID) (*shipping.Cargo, error) {
 	if r.cargo != nil {
 		return r.cargo, nil
 	}
 	return nil, shipping.ErrUnknownCargo
 }
 
-func (r *mockCargoRepository) FindAll() []*shipping.Cargo {
+func (r *mockCargoRepository) FindAll(_ context.Context) []*shipping.Cargo {
 	return []*shipping.Cargo{r.cargo}
 }
+
+func (r *mockCargoRepository) Query(_ context.Context, q shipping.CargoQuery) (shipping.CargoPage, error) {
+	return q.Apply([]*shipping.Cargo{r.cargo})
+}
+
+type mockRouteCandidateRepository struct {
+	candidates map[RouteCandidateID]*RouteCandidate
+}
+
+func (r *mockRouteCandidateRepository) Store(_ context.Context, c *RouteCandidate) error {
+	if r.candidates == nil {
+		r.candidates = make(map[RouteCandidateID]*RouteCandidate)
+	}
+	r.candidates[c.ID] = c
+	return nil
+}
+
+func (r *mockRouteCandidateRepository) Find(_ context.Context, id RouteCandidateID) (*RouteCandidate, error) {
+	if c, ok := r.candidates[id]; ok {
+		return c, nil
+	}
+	return nil, ErrUnknownRouteCandidate
+}
+
+type mockAmendmentRepository struct {
+	amendments []*Amendment
+	err        error
+}
+
+func (r *mockAmendmentRepository) Store(_ context.Context, a *Amendment) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.amendments = append(r.amendments, a)
+	return nil
+}
+
+func (r *mockAmendmentRepository) Remove(_ context.Context, id AmendmentID) error {
+	for i, a := range r.amendments {
+		if a.ID == id {
+			r.amendments = append(r.amendments[:i], r.amendments[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *mockAmendmentRepository) FindByTrackingID(_ context.Context, id shipping.TrackingID) []*Amendment {
+	var as []*Amendment
+	for _, a := range r.amendments {
+		if a.TrackingID == id {
+			as = append(as, a)
+		}
+	}
+	return as
+}
+
+func TestAmendmentFailureSavesNoChange(t *testing.T) {
+	var cargos mockCargoRepository
+	var locations mock.LocationRepository
+	var amendments mockAmendmentRepository
+
+	locations.FindFn = func(loc shipping.UNLocode) (*shipping.Location, error) {
+		return &shipping.Location{UNLocode: loc}, nil
+	}
+
+	s := NewService(&cargos, &locations, nil, &stubRoutingService{}, &mockRouteCandidateRepository{}, &amendments)
+
+	id, err := s.BookNewCargo(context.Background(), shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.CNHKG, ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	amendments.err = errors.New("unavailable")
+
+	if err := s.ChangeDestination(context.Background(), id, shipping.AUMEL); err != amendments.err {
+		t.Errorf("err = %v; want = %v", err, amendments.err)
+	}
+	if cargos.stores != 1 {
+		t.Errorf("stores = %d; want = %d", cargos.stores, 1)
+	}
+}
+
+func TestCargoFailureKeepsNoAmendment(t *testing.T) {
+	var cargos mockCargoRepository
+	var locations mock.LocationRepository
+	var amendments mockAmendmentRepository
+
+	locations.FindFn = func(loc shipping.UNLocode) (*shipping.Location, error) {
+		return &shipping.Location{UNLocode: loc}, nil
+	}
+
+	s := NewService(&cargos, &locations, nil, &stubRoutingService{}, &mockRouteCandidateRepository{}, &amendments)
+
+	id, err := s.BookNewCargo(context.Background(), shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.CNHKG, ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cargos.err = errors.New("unavailable")
+
+	if err := s.ChangeDestination(context.Background(), id, shipping.AUMEL); err != cargos.err {
+		t.Errorf("err = %v; want = %v", err, cargos.err)
+	}
+	if len(amendments.amendments) != 0 {
+		t.Errorf("amendments = %v; want none", amendments.amendments)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ctx := NewActorContext(context.Background(), "alice")
+
+	var cargos mockCargoRepository
+	var locations mock.LocationRepository
+	var rs stubRoutingService
+
+	locations.FindFn = func(loc shipping.UNLocode) (*shipping.Location, error) {
+		return &shipping.Location{UNLocode: loc}, nil
+	}
+
+	now := time.Date(2015, time.November, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewService(&cargos, &locations, nil, &rs, &mockRouteCandidateRepository{}, &mockAmendmentRepository{})
+	s.(*service).now = func() time.Time { return now }
+
+	id, err := s.BookNewCargo(ctx, shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.CNHKG, ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := s.History(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history == nil || len(history) != 0 {
+		t.Errorf("history = %v; want empty", history)
+	}
+
+	if err := s.ChangeDestination(ctx, id, shipping.AUMEL); err != nil {
+		t.Fatal(err)
+	}
+	// Changing to the same destination changes nothing.
+	if err := s.ChangeDestination(ctx, id, shipping.AUMEL); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := s.RequestPossibleRoutesForCargo(ctx, id, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	if err := s.AssignCargoToRouteCandidate(NewActorContext(ctx, ""), id, routes[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err = s.History(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("len(history) = %d; want = %d", len(history), 2)
+	}
+
+	a := history[0]
+	if a.Type != DestinationChanged || a.Actor != "alice" || !a.Time.Equal(now.Add(-time.Minute)) {
+		t.Errorf("history[0] = %+v; want destination changed by alice", a)
+	}
+	if a.Previous.Destination != shipping.CNHKG || a.New.Destination != shipping.AUMEL {
+		t.Errorf("destination = %s -> %s; want = %s -> %s", a.Previous.Destination, a.New.Destination, shipping.CNHKG, shipping.AUMEL)
+	}
+
+	a = history[1]
+	if a.Type != RouteAssigned || a.Actor != "" {
+		t.Errorf("history[1] = %+v; want route assigned anonymously", a)
+	}
+	if len(a.Previous.Legs) != 0 || len(a.New.Legs) != 1 {
+		t.Errorf("legs = %v -> %v; want none -> one", a.Previous.Legs, a.New.Legs)
+	}
+
+	if _, err := s.History(ctx, ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("err = %v; want = %v", err, ErrInvalidArgument)
+	}
+}
+
+func TestCargos(t *testing.T) {
+	ctx := context.Background()
+
+	var cargos mock.CargoRepository
+	cargos.QueryFn = func(q shipping.CargoQuery) (shipping.CargoPage, error) {
+		if q.Limit != DefaultPageSize {
+			t.Errorf("q.Limit = %d; want = %d", q.Limit, DefaultPageSize)
+		}
+		return shipping.CargoPage{
+			Cargos: []*shipping.Cargo{
+				shipping.NewCargo("ABC123", shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL}),
+			},
+			NextCursor: "next",
+		}, nil
+	}
+
+	s := NewService(&cargos, nil, nil, nil, nil, nil)
+
+	page, err := s.Cargos(ctx, shipping.CargoQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Cargos) != 1 {
+		t.Fatalf("len(page.Cargos) = %d; want = %d", len(page.Cargos), 1)
+	}
+	if page.Cargos[0].TrackingID != "ABC123" {
+		t.Errorf("page.Cargos[0].TrackingID = %s; want = %s", page.Cargos[0].TrackingID, "ABC123")
+	}
+	if page.NextCursor != "next" {
+		t.Errorf("page.NextCursor = %s; want = %s", page.NextCursor, "next")
+	}
+
+	if _, err := s.Cargos(ctx, shipping.CargoQuery{Limit: -1}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("err = %v; want = %v", err, ErrInvalidArgument)
+	}
+}
@@ -0,0 +1,144 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/pricing"
+)
+
+type tracingService struct {
+	tracer trace.Tracer
+	next   Service
+}
+
+// NewTracingService returns a new instance of a tracing Service.
+func NewTracingService(tracer trace.Tracer, s Service) Service {
+	return &tracingService{tracer, s}
+}
+
+func (s *tracingService) BookNewCargo(ctx context.Context, rs shipping.RouteSpecification) (id shipping.TrackingID, err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.BookNewCargo", trace.WithAttributes(
+		attribute.String("origin", string(rs.Origin)),
+		attribute.String("destination", string(rs.Destination)),
+	))
+	defer func() {
+		span.SetAttributes(attribute.String("tracking_id", string(id)))
+		endSpan(span, err)
+	}()
+	return s.next.BookNewCargo(ctx, rs)
+}
+
+func (s *tracingService) LoadCargo(ctx context.Context, id shipping.TrackingID) (c Cargo, err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.LoadCargo", trace.WithAttributes(
+		attribute.String("tracking_id", string(id)),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.LoadCargo(ctx, id)
+}
+
+func (s *tracingService) RequestPossibleRoutesForCargo(ctx context.Context, id shipping.TrackingID, strategy string) (candidates []RouteCandidate, err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.RequestPossibleRoutesForCargo", trace.WithAttributes(
+		attribute.String("tracking_id", string(id)),
+		attribute.String("strategy", strategy),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("routes", len(candidates)))
+		endSpan(span, err)
+	}()
+	return s.next.RequestPossibleRoutesForCargo(ctx, id, strategy)
+}
+
+func (s *tracingService) AssignCargoToRoute(ctx context.Context, id shipping.TrackingID, itinerary shipping.Itinerary) (err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.AssignCargoToRoute", trace.WithAttributes(
+		attribute.String("tracking_id", string(id)),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.AssignCargoToRoute(ctx, id, itinerary)
+}
+
+func (s *tracingService) QuoteRoute(ctx context.Context, id shipping.TrackingID, candidate RouteCandidateID, size int, currency string) (q pricing.Quote, err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.QuoteRoute", trace.WithAttributes(
+		attribute.String("tracking_id", string(id)),
+		attribute.String("candidate_id", string(candidate)),
+		attribute.Int("size", size),
+		attribute.String("currency", currency),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.QuoteRoute(ctx, id, candidate, size, currency)
+}
+
+func (s *tracingService) AssignCargoToRouteCandidate(ctx context.Context, id shipping.TrackingID, candidate RouteCandidateID) (err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.AssignCargoToRouteCandidate", trace.WithAttributes(
+		attribute.String("tracking_id", string(id)),
+		attribute.String("candidate_id", string(candidate)),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.AssignCargoToRouteCandidate(ctx, id, candidate)
+}
+
+func (s *tracingService) ChangeDestination(ctx context.Context, id shipping.TrackingID, l shipping.UNLocode) (err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.ChangeDestination", trace.WithAttributes(
+		attribute.String("tracking_id", string(id)),
+		attribute.String("destination", string(l)),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.ChangeDestination(ctx, id, l)
+}
+
+func (s *tracingService) ChangeArrivalDeadline(ctx context.Context, id shipping.TrackingID, deadline time.Time) (err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.ChangeArrivalDeadline", trace.WithAttributes(
+		attribute.String("tracking_id", string(id)),
+		attribute.String("arrival_deadline", deadline.Format(time.RFC3339)),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.ChangeArrivalDeadline(ctx, id, deadline)
+}
+
+func (s *tracingService) ChangeOrigin(ctx context.Context, id shipping.TrackingID, l shipping.UNLocode) (err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.ChangeOrigin", trace.WithAttributes(
+		attribute.String("tracking_id", string(id)),
+		attribute.String("origin", string(l)),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.ChangeOrigin(ctx, id, l)
+}
+
+func (s *tracingService) History(ctx context.Context, id shipping.TrackingID) (amendments []Amendment, err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.History", trace.WithAttributes(
+		attribute.String("tracking_id", string(id)),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.History(ctx, id)
+}
+
+func (s *tracingService) Cargos(ctx context.Context, q shipping.CargoQuery) (page CargoPage, err error) {
+	ctx, span := s.tracer.Start(ctx, "booking.Cargos", trace.WithAttributes(
+		attribute.Int("limit", q.Limit),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("cargos", len(page.Cargos)))
+		endSpan(span, err)
+	}()
+	return s.next.Cargos(ctx, q)
+}
+
+func (s *tracingService) Locations(ctx context.Context) []Location {
+	ctx, span := s.tracer.Start(ctx, "booking.Locations")
+	defer span.End()
+	return s.next.Locations(ctx)
+}
+
+// endSpan ends span, marking it as failed if err isn't nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
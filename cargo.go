@@ -2,7 +2,7 @@
 package shipping
 
 import (
-	"errors"
+	"context"
 	"strings"
 	"time"
 
@@ -19,6 +19,10 @@ type Cargo struct {
 	RouteSpecification RouteSpecification
 	Itinerary          Itinerary
 	Delivery           Delivery
+
+	// Price is the accepted price of carrying the cargo along its
+	// itinerary. It's zero if the itinerary hasn't been priced.
+	Price Money
 }
 
 // SpecifyNewRoute specifies a new route for this cargo.
@@ -54,13 +58,14 @@ func NewCargo(id TrackingID, rs RouteSpecification) *Cargo {
 
 // CargoRepository provides access a cargo store.
 type CargoRepository interface {
-	Store(cargo *Cargo) error
-	Find(id TrackingID) (*Cargo, error)
-	FindAll() []*Cargo
+	Store(ctx context.Context, cargo *Cargo) error
+	Find(ctx context.Context, id TrackingID) (*Cargo, error)
+	FindAll(ctx context.Context) []*Cargo
+	Query(ctx context.Context, q CargoQuery) (CargoPage, error)
 }
 
 // ErrUnknownCargo is used when a cargo could not be found.
-var ErrUnknownCargo = errors.New("unknown cargo")
+var ErrUnknownCargo = NewError(CodeUnknownCargo, "unknown cargo")
 
 // NextTrackingID generates a new tracking ID.
 // TODO: Move to infrastructure(?)
@@ -74,6 +79,22 @@ type RouteSpecification struct {
 	Origin          UNLocode
 	Destination     UNLocode
 	ArrivalDeadline time.Time
+
+	// EarliestDeparture is when the cargo is ready to leave its origin at
+	// the earliest. The zero time means it can leave at any time.
+	EarliestDeparture time.Time
+
+	Constraints RouteConstraints
+}
+
+// RouteConstraints further restrict the itineraries of a cargo.
+type RouteConstraints struct {
+	// MaxLegs is the largest number of legs of an itinerary, where zero
+	// means that there is no limit.
+	MaxLegs int
+
+	// AvoidLocations are locations where the cargo must not be handled.
+	AvoidLocations []UNLocode
 }
 
 // IsSatisfiedBy checks whether provided itinerary satisfies this
@@ -84,6 +105,42 @@ func (s RouteSpecification) IsSatisfiedBy(itinerary Itinerary) bool {
 		s.Destination == itinerary.FinalArrivalLocation()
 }
 
+// IsFullySatisfiedBy checks whether provided itinerary satisfies this
+// specification, and also departs no earlier than the earliest departure,
+// arrives by the arrival deadline and keeps to the constraints. Legs without
+// scheduled times are assumed to be on time.
+//
+// Unlike IsSatisfiedBy, which determines the routing status of a cargo, it is
+// meant for choosing among candidate itineraries.
+func (s RouteSpecification) IsFullySatisfiedBy(itinerary Itinerary) bool {
+	if itinerary.IsEmpty() || !s.IsSatisfiedBy(itinerary) {
+		return false
+	}
+
+	if dep := itinerary.Legs[0].LoadTime; !s.EarliestDeparture.IsZero() && !dep.IsZero() && dep.Before(s.EarliestDeparture) {
+		return false
+	}
+	if arr := itinerary.FinalArrivalTime(); !s.ArrivalDeadline.IsZero() && !arr.IsZero() && arr.After(s.ArrivalDeadline) {
+		return false
+	}
+
+	return s.Constraints.allow(itinerary)
+}
+
+func (c RouteConstraints) allow(itinerary Itinerary) bool {
+	if c.MaxLegs > 0 && len(itinerary.Legs) > c.MaxLegs {
+		return false
+	}
+	for _, l := range itinerary.Legs {
+		for _, avoid := range c.AvoidLocations {
+			if l.LoadLocation == avoid || l.UnloadLocation == avoid {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // RoutingStatus describes status of cargo routing.
 type RoutingStatus int
 
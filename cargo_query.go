@@ -0,0 +1,175 @@
+package shipping
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// ErrInvalidCursor is used when a cursor could not be decoded or doesn't
+// belong to the query it was used with.
+var ErrInvalidCursor = NewError(CodeInvalidCursor, "invalid cursor")
+
+// CargoSortKey describes the order in which cargos are listed. Cargos with
+// the same key are ordered by tracking ID.
+type CargoSortKey int
+
+// Valid sort keys.
+const (
+	SortByTrackingID CargoSortKey = iota
+	SortByArrivalDeadline
+)
+
+// CargoQuery selects a page of cargos. Zero-valued filters match all cargos.
+type CargoQuery struct {
+	// RoutingStatus, if not nil, only matches cargos with the given routing
+	// status.
+	RoutingStatus *RoutingStatus
+
+	// Misrouted, if not nil, only matches cargos that are (or aren't)
+	// misrouted.
+	Misrouted *bool
+
+	Origin      UNLocode
+	Destination UNLocode
+
+	// DeadlineAfter and DeadlineBefore restrict the arrival deadline to the
+	// half-open interval [DeadlineAfter, DeadlineBefore).
+	DeadlineAfter  time.Time
+	DeadlineBefore time.Time
+
+	SortBy     CargoSortKey
+	Descending bool
+
+	// Cursor continues a previous query where it left off. It is returned
+	// as the next cursor of a CargoPage.
+	Cursor string
+
+	// Limit is the maximum number of cargos in the page. Zero means no
+	// limit.
+	Limit int
+}
+
+// CargoPage is the result of a CargoQuery.
+type CargoPage struct {
+	Cargos []*Cargo
+
+	// NextCursor is used to fetch the next page. It is empty if this is the
+	// last page.
+	NextCursor string
+}
+
+// Matches returns whether c satisfies the filters of the query. It doesn't
+// take the cursor into account.
+func (q CargoQuery) Matches(c *Cargo) bool {
+	if q.RoutingStatus != nil && c.Delivery.RoutingStatus != *q.RoutingStatus {
+		return false
+	}
+	if q.Misrouted != nil && (c.Delivery.RoutingStatus == Misrouted) != *q.Misrouted {
+		return false
+	}
+	if q.Origin != "" && c.Origin != q.Origin {
+		return false
+	}
+	if q.Destination != "" && c.RouteSpecification.Destination != q.Destination {
+		return false
+	}
+	if !q.DeadlineAfter.IsZero() && c.RouteSpecification.ArrivalDeadline.Before(q.DeadlineAfter) {
+		return false
+	}
+	if !q.DeadlineBefore.IsZero() && !c.RouteSpecification.ArrivalDeadline.Before(q.DeadlineBefore) {
+		return false
+	}
+	return true
+}
+
+// less returns whether the cargo at a is listed before the one at b.
+func (q CargoQuery) less(a, b CargoCursor) bool {
+	if q.Descending {
+		a, b = b, a
+	}
+	if q.SortBy == SortByArrivalDeadline && !a.Deadline.Equal(b.Deadline) {
+		return a.Deadline.Before(b.Deadline)
+	}
+	return a.TrackingID < b.TrackingID
+}
+
+// CargoCursor is the position of a cargo in a listing.
+type CargoCursor struct {
+	SortBy     CargoSortKey `json:"s"`
+	TrackingID TrackingID   `json:"id"`
+	Deadline   time.Time    `json:"d"`
+}
+
+func cursorOf(key CargoSortKey, c *Cargo) CargoCursor {
+	cur := CargoCursor{SortBy: key, TrackingID: c.TrackingID}
+	if key == SortByArrivalDeadline {
+		cur.Deadline = c.RouteSpecification.ArrivalDeadline.UTC()
+	}
+	return cur
+}
+
+// NewCargoCursor returns the encoded cursor positioned at c in a listing
+// ordered by q.
+func (q CargoQuery) NewCargoCursor(c *Cargo) string {
+	b, _ := json.Marshal(cursorOf(q.SortBy, c))
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decodes the cursor of the query. It returns ErrInvalidCursor
+// if the cursor is malformed or was created for another sort order.
+func (q CargoQuery) DecodeCursor() (CargoCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return CargoCursor{}, ErrInvalidCursor
+	}
+
+	var cur CargoCursor
+	if err := json.Unmarshal(b, &cur); err != nil {
+		return CargoCursor{}, ErrInvalidCursor
+	}
+
+	if cur.SortBy != q.SortBy || cur.TrackingID == "" {
+		return CargoCursor{}, ErrInvalidCursor
+	}
+
+	return cur, nil
+}
+
+// Apply filters, sorts and paginates cargos according to the query. It's
+// intended for repositories that can't do this natively.
+func (q CargoQuery) Apply(cargos []*Cargo) (CargoPage, error) {
+	var after *CargoCursor
+	if q.Cursor != "" {
+		cur, err := q.DecodeCursor()
+		if err != nil {
+			return CargoPage{}, err
+		}
+		after = &cur
+	}
+
+	var result []*Cargo
+	for _, c := range cargos {
+		if !q.Matches(c) {
+			continue
+		}
+		if after != nil && !q.less(*after, cursorOf(q.SortBy, c)) {
+			continue
+		}
+		result = append(result, c)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return q.less(cursorOf(q.SortBy, result[i]), cursorOf(q.SortBy, result[j]))
+	})
+
+	var page CargoPage
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+		page.NextCursor = q.NewCargoCursor(result[len(result)-1])
+	}
+	page.Cargos = result
+
+	return page, nil
+}
@@ -182,3 +182,46 @@ func populateCargoClaimedInMelbourne() *Cargo {
 
 	return c
 }
+
+func TestIsFullySatisfiedBy(t *testing.T) {
+	date := func(day int) time.Time {
+		return time.Date(2009, time.March, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	spec := RouteSpecification{
+		Origin:            SESTO,
+		Destination:       AUMEL,
+		ArrivalDeadline:   date(20),
+		EarliestDeparture: date(5),
+		Constraints: RouteConstraints{
+			MaxLegs:        2,
+			AvoidLocations: []UNLocode{DEHAM},
+		},
+	}
+
+	for _, tt := range []struct {
+		name string
+		legs []Leg
+		want bool
+	}{
+		{"satisfied", []Leg{NewLeg("V1", SESTO, AUMEL, date(6), date(18))}, true},
+		{"unscheduled", []Leg{{LoadLocation: SESTO, UnloadLocation: AUMEL}}, true},
+		{"wrong destination", []Leg{NewLeg("V1", SESTO, CNHKG, date(6), date(18))}, false},
+		{"too late", []Leg{NewLeg("V1", SESTO, AUMEL, date(6), date(21))}, false},
+		{"too early", []Leg{NewLeg("V1", SESTO, AUMEL, date(4), date(18))}, false},
+		{"too many legs", []Leg{
+			NewLeg("V1", SESTO, FIHEL, date(6), date(7)),
+			NewLeg("V2", FIHEL, CNHKG, date(8), date(12)),
+			NewLeg("V3", CNHKG, AUMEL, date(13), date(18)),
+		}, false},
+		{"avoided location", []Leg{
+			NewLeg("V1", SESTO, DEHAM, date(6), date(7)),
+			NewLeg("V2", DEHAM, AUMEL, date(8), date(18)),
+		}, false},
+		{"empty", []Leg{}, false},
+	} {
+		if got := spec.IsFullySatisfiedBy(Itinerary{Legs: tt.legs}); got != tt.want {
+			t.Errorf("%s: IsFullySatisfiedBy = %v; want = %v", tt.name, got, tt.want)
+		}
+	}
+}
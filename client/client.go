@@ -0,0 +1,394 @@
+// Package client provides a client for the booking, tracking and handling
+// HTTP APIs.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/pricing"
+	"github.com/marcusolsson/goddd/tracking"
+)
+
+// Client calls the HTTP APIs of a shipping service. Errors reported by the
+// service are returned as *shipping.Error, so they can be compared with the
+// errors of the services using errors.Is.
+type Client struct {
+	baseURL *url.URL
+
+	// HTTPClient is used to make requests. It defaults to a client with a
+	// 30 second timeout.
+	HTTPClient *http.Client
+
+	// APIKey and BearerToken, if set, are sent to authenticate requests.
+	APIKey      string
+	BearerToken string
+}
+
+// Option configures optional features of a Client.
+type Option func(*Client)
+
+// WithHTTPClient makes requests using hc.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.HTTPClient = hc }
+}
+
+// WithAPIKey authenticates requests with an API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.APIKey = key }
+}
+
+// WithBearerToken authenticates requests with a bearer token.
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.BearerToken = token }
+}
+
+// New returns a client of the service at baseURL, such as
+// http://localhost:8080.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	c := &Client{
+		baseURL:    u,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// BookCargo books a new cargo with the given route specification and
+// returns its tracking ID.
+func (c *Client) BookCargo(ctx context.Context, rs shipping.RouteSpecification) (shipping.TrackingID, error) {
+	request := struct {
+		Origin            shipping.UNLocode   `json:"origin"`
+		Destination       shipping.UNLocode   `json:"destination"`
+		ArrivalDeadline   time.Time           `json:"arrival_deadline"`
+		EarliestDeparture *time.Time          `json:"earliest_departure,omitempty"`
+		MaxLegs           int                 `json:"max_legs,omitempty"`
+		AvoidLocations    []shipping.UNLocode `json:"avoid_locations,omitempty"`
+	}{
+		Origin:          rs.Origin,
+		Destination:     rs.Destination,
+		ArrivalDeadline: rs.ArrivalDeadline,
+		MaxLegs:         rs.Constraints.MaxLegs,
+		AvoidLocations:  rs.Constraints.AvoidLocations,
+	}
+	if !rs.EarliestDeparture.IsZero() {
+		request.EarliestDeparture = &rs.EarliestDeparture
+	}
+
+	var response struct {
+		ID shipping.TrackingID `json:"tracking_id"`
+	}
+	if err := c.do(ctx, "POST", "/booking/v1/cargos", nil, request, &response); err != nil {
+		return "", err
+	}
+	return response.ID, nil
+}
+
+// LoadCargo returns the booking view of a cargo.
+func (c *Client) LoadCargo(ctx context.Context, id shipping.TrackingID) (booking.Cargo, error) {
+	var response struct {
+		Cargo booking.Cargo `json:"cargo"`
+	}
+	if err := c.do(ctx, "GET", "/booking/v1/cargos/"+url.PathEscape(string(id)), nil, nil, &response); err != nil {
+		return booking.Cargo{}, err
+	}
+	return response.Cargo, nil
+}
+
+// Cargos returns a page of the booked cargos that match q.
+func (c *Client) Cargos(ctx context.Context, q shipping.CargoQuery) (booking.CargoPage, error) {
+	var response struct {
+		Cargos     []booking.Cargo `json:"cargos"`
+		NextCursor string          `json:"next_cursor"`
+	}
+	if err := c.do(ctx, "GET", "/booking/v1/cargos", encodeCargoQuery(q), nil, &response); err != nil {
+		return booking.CargoPage{}, err
+	}
+	return booking.CargoPage{Cargos: response.Cargos, NextCursor: response.NextCursor}, nil
+}
+
+// History returns the amendments made to the booking of a cargo, oldest
+// first.
+func (c *Client) History(ctx context.Context, id shipping.TrackingID) ([]booking.Amendment, error) {
+	var response struct {
+		Amendments []booking.Amendment `json:"amendments"`
+	}
+	if err := c.do(ctx, "GET", "/booking/v1/cargos/"+url.PathEscape(string(id))+"/history", nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Amendments, nil
+}
+
+// RequestRoutes returns the possible routes of a cargo, ranked by the given
+// strategy. An empty strategy leaves the choice to the server.
+func (c *Client) RequestRoutes(ctx context.Context, id shipping.TrackingID, strategy string) ([]booking.RouteCandidate, error) {
+	var response struct {
+		Routes []booking.RouteCandidate `json:"routes"`
+	}
+	query := url.Values{}
+	if strategy != "" {
+		query.Set("strategy", strategy)
+	}
+	if err := c.do(ctx, "GET", "/booking/v1/cargos/"+url.PathEscape(string(id))+"/request_routes", query, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Routes, nil
+}
+
+// QuoteRoute prices carrying size units of a cargo along a route previously
+// returned by RequestRoutes. An empty currency leaves the choice to the
+// server. Assigning the route afterwards accepts the quote.
+func (c *Client) QuoteRoute(ctx context.Context, id shipping.TrackingID, candidate booking.RouteCandidateID, size int, currency string) (pricing.Quote, error) {
+	request := struct {
+		CandidateID booking.RouteCandidateID `json:"candidate_id"`
+		Size        int                      `json:"size"`
+		Currency    string                   `json:"currency,omitempty"`
+	}{
+		CandidateID: candidate,
+		Size:        size,
+		Currency:    currency,
+	}
+	var response struct {
+		Quote pricing.Quote `json:"quote"`
+	}
+	if err := c.do(ctx, "POST", "/booking/v1/cargos/"+url.PathEscape(string(id))+"/quote", nil, request, &response); err != nil {
+		return pricing.Quote{}, err
+	}
+	return response.Quote, nil
+}
+
+// AssignToRoute assigns a cargo to the route described by itinerary.
+func (c *Client) AssignToRoute(ctx context.Context, id shipping.TrackingID, itinerary shipping.Itinerary) error {
+	request := struct {
+		Itinerary shipping.Itinerary `json:"route"`
+	}{
+		Itinerary: itinerary,
+	}
+	return c.do(ctx, "POST", "/booking/v1/cargos/"+url.PathEscape(string(id))+"/assign_to_route", nil, request, nil)
+}
+
+// AssignToRouteCandidate assigns a cargo to a route previously returned by
+// RequestRoutes.
+func (c *Client) AssignToRouteCandidate(ctx context.Context, id shipping.TrackingID, candidate booking.RouteCandidateID) error {
+	request := struct {
+		CandidateID booking.RouteCandidateID `json:"candidate_id"`
+	}{
+		CandidateID: candidate,
+	}
+	return c.do(ctx, "POST", "/booking/v1/cargos/"+url.PathEscape(string(id))+"/assign_to_route", nil, request, nil)
+}
+
+// ChangeDestination changes the destination of a cargo.
+func (c *Client) ChangeDestination(ctx context.Context, id shipping.TrackingID, destination shipping.UNLocode) error {
+	request := struct {
+		Destination shipping.UNLocode `json:"destination"`
+	}{
+		Destination: destination,
+	}
+	return c.do(ctx, "POST", "/booking/v1/cargos/"+url.PathEscape(string(id))+"/change_destination", nil, request, nil)
+}
+
+// ChangeArrivalDeadline changes the arrival deadline of a cargo.
+func (c *Client) ChangeArrivalDeadline(ctx context.Context, id shipping.TrackingID, deadline time.Time) error {
+	request := struct {
+		ArrivalDeadline time.Time `json:"arrival_deadline"`
+	}{
+		ArrivalDeadline: deadline,
+	}
+	return c.do(ctx, "POST", "/booking/v1/cargos/"+url.PathEscape(string(id))+"/change_arrival_deadline", nil, request, nil)
+}
+
+// ChangeOrigin changes where a cargo is picked up, which is only possible
+// until it has been received.
+func (c *Client) ChangeOrigin(ctx context.Context, id shipping.TrackingID, origin shipping.UNLocode) error {
+	request := struct {
+		Origin shipping.UNLocode `json:"origin"`
+	}{
+		Origin: origin,
+	}
+	return c.do(ctx, "POST", "/booking/v1/cargos/"+url.PathEscape(string(id))+"/change_origin", nil, request, nil)
+}
+
+// Locations returns the locations that cargos may be booked between.
+func (c *Client) Locations(ctx context.Context) ([]booking.Location, error) {
+	var response struct {
+		Locations []booking.Location `json:"locations"`
+	}
+	if err := c.do(ctx, "GET", "/booking/v1/locations", nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Locations, nil
+}
+
+// RegisterHandlingEvent registers that a cargo has been handled. The voyage
+// is only required for loading and unloading.
+func (c *Client) RegisterHandlingEvent(ctx context.Context, completed time.Time, id shipping.TrackingID, voyage shipping.VoyageNumber, location shipping.UNLocode, eventType shipping.HandlingEventType) error {
+	request := struct {
+		CompletionTime time.Time             `json:"completion_time"`
+		TrackingID     shipping.TrackingID   `json:"tracking_id"`
+		VoyageNumber   shipping.VoyageNumber `json:"voyage,omitempty"`
+		Location       shipping.UNLocode     `json:"location"`
+		EventType      string                `json:"event_type"`
+	}{
+		CompletionTime: completed,
+		TrackingID:     id,
+		VoyageNumber:   voyage,
+		Location:       location,
+		EventType:      eventType.String(),
+	}
+	return c.do(ctx, "POST", "/handling/v1/incidents", nil, request, nil)
+}
+
+// Track returns the tracking status of a cargo.
+func (c *Client) Track(ctx context.Context, id shipping.TrackingID) (tracking.Cargo, error) {
+	var response struct {
+		Cargo tracking.Cargo `json:"cargo"`
+	}
+	if err := c.do(ctx, "GET", "/tracking/v1/cargos/"+url.PathEscape(string(id)), nil, nil, &response); err != nil {
+		return tracking.Cargo{}, err
+	}
+	return response.Cargo, nil
+}
+
+// do sends a request with body, if not nil, encoded as JSON, and decodes the
+// response into v, if not nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, u.String(), r)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+	if c.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.BearerToken)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return decodeError(resp)
+	}
+
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %v", err)
+	}
+	return nil
+}
+
+// decodeError returns the problem details of resp as a *shipping.Error, or a
+// generic error if it doesn't hold any.
+func decodeError(resp *http.Response) error {
+	mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mt == "application/problem+json" {
+		var p struct {
+			Detail        string                `json:"detail"`
+			Code          shipping.ErrorCode    `json:"code"`
+			InvalidParams []shipping.FieldError `json:"invalid_params"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&p); err == nil && p.Code != "" {
+			return &shipping.Error{Code: p.Code, Message: p.Detail, Fields: p.InvalidParams}
+		}
+	}
+	return fmt.Errorf("unexpected response: %s", resp.Status)
+}
+
+// encodeCargoQuery returns the query parameters of q, as read by the cargo
+// listing.
+func encodeCargoQuery(q shipping.CargoQuery) url.Values {
+	vals := url.Values{}
+
+	if q.RoutingStatus != nil {
+		switch *q.RoutingStatus {
+		case shipping.NotRouted:
+			vals.Set("routing_status", "not_routed")
+		case shipping.Misrouted:
+			vals.Set("routing_status", "misrouted")
+		case shipping.Routed:
+			vals.Set("routing_status", "routed")
+		}
+	}
+	if q.Misrouted != nil {
+		vals.Set("misrouted", strconv.FormatBool(*q.Misrouted))
+	}
+	if q.Origin != "" {
+		vals.Set("origin", string(q.Origin))
+	}
+	if q.Destination != "" {
+		vals.Set("destination", string(q.Destination))
+	}
+	if !q.DeadlineAfter.IsZero() {
+		vals.Set("deadline_after", q.DeadlineAfter.Format(time.RFC3339))
+	}
+	if !q.DeadlineBefore.IsZero() {
+		vals.Set("deadline_before", q.DeadlineBefore.Format(time.RFC3339))
+	}
+
+	sort := "tracking_id"
+	if q.SortBy == shipping.SortByArrivalDeadline {
+		sort = "arrival_deadline"
+	}
+	if q.Descending {
+		sort = "-" + sort
+	}
+	if sort != "tracking_id" {
+		vals.Set("sort", sort)
+	}
+
+	if q.Limit > 0 {
+		vals.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Cursor != "" {
+		vals.Set("cursor", q.Cursor)
+	}
+
+	return vals
+}
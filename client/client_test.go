@@ -0,0 +1,194 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/client"
+	"github.com/marcusolsson/goddd/handling"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/mock"
+	"github.com/marcusolsson/goddd/server"
+	"github.com/marcusolsson/goddd/tracking"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	var (
+		cargos         = inmem.NewCargoRepository()
+		locations      = inmem.NewLocationRepository()
+		voyages        = inmem.NewVoyageRepository()
+		handlingEvents = inmem.NewHandlingEventRepository()
+	)
+
+	rs := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			return []shipping.Itinerary{{Legs: []shipping.Leg{
+				shipping.NewLeg("V100", rs.Origin, rs.Destination, time.Date(2009, time.March, 2, 0, 0, 0, 0, time.UTC), time.Date(2009, time.March, 12, 0, 0, 0, 0, time.UTC)),
+			}}}, nil
+		},
+	}
+
+	factory := shipping.HandlingEventFactory{
+		CargoRepository:    cargos,
+		VoyageRepository:   voyages,
+		LocationRepository: locations,
+	}
+
+	var (
+		bs = booking.NewService(cargos, locations, handlingEvents, rs, inmem.NewRouteCandidateRepository(), inmem.NewAmendmentRepository())
+		ts = tracking.NewService(cargos, handlingEvents)
+		hs = handling.NewService(handlingEvents, factory, handling.EventHandlers{})
+	)
+
+	return httptest.NewServer(server.New(bs, ts, hs, log.NewLogfmtLogger(ioutil.Discard)))
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	ts := newTestServer(t)
+	defer ts.Close()
+
+	c, err := client.New(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Date(2009, time.March, 18, 12, 0, 0, 0, time.UTC)
+
+	id, err := c.BookCargo(ctx, shipping.RouteSpecification{
+		Origin:            shipping.SESTO,
+		Destination:       shipping.CNHKG,
+		ArrivalDeadline:   deadline,
+		EarliestDeparture: deadline.AddDate(0, 0, -21),
+		Constraints:       shipping.RouteConstraints{MaxLegs: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cargo, err := c.LoadCargo(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cargo.TrackingID != string(id) {
+		t.Errorf("cargo.TrackingID = %s; want = %s", cargo.TrackingID, id)
+	}
+	if !cargo.ArrivalDeadline.Equal(deadline) {
+		t.Errorf("cargo.ArrivalDeadline = %v; want = %v", cargo.ArrivalDeadline, deadline)
+	}
+	if cargo.EarliestDeparture == nil || !cargo.EarliestDeparture.Equal(deadline.AddDate(0, 0, -21)) {
+		t.Errorf("cargo.EarliestDeparture = %v; want = %v", cargo.EarliestDeparture, deadline.AddDate(0, 0, -21))
+	}
+	if cargo.MaxLegs != 3 {
+		t.Errorf("cargo.MaxLegs = %d; want = %d", cargo.MaxLegs, 3)
+	}
+
+	notRouted := shipping.NotRouted
+	page, err := c.Cargos(ctx, shipping.CargoQuery{RoutingStatus: &notRouted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Cargos) != 1 {
+		t.Fatalf("len(page.Cargos) = %d; want = %d", len(page.Cargos), 1)
+	}
+
+	routes, err := c.RequestRoutes(ctx, id, shipping.StrategyFastest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 {
+		t.Fatalf("len(routes) = %d; want = %d", len(routes), 1)
+	}
+	if len(routes[0].Costs) != 1 || routes[0].Costs[0].Criterion != shipping.CriterionTransitTime {
+		t.Errorf("routes[0].Costs = %+v; want transit time only", routes[0].Costs)
+	}
+
+	if err := c.AssignToRouteCandidate(ctx, id, "no_such_id"); !errors.Is(err, booking.ErrUnknownRouteCandidate) {
+		t.Errorf("err = %v; want = %v", err, booking.ErrUnknownRouteCandidate)
+	}
+	if err := c.AssignToRouteCandidate(ctx, id, routes[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	cargo, err = c.LoadCargo(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cargo.Routed {
+		t.Errorf("cargo.Routed = %v; want = %v", cargo.Routed, true)
+	}
+
+	if err := c.ChangeArrivalDeadline(ctx, id, time.Now().AddDate(1, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := c.History(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Type != booking.RouteAssigned || history[1].Type != booking.ArrivalDeadlineChanged {
+		t.Errorf("history = %+v; want the route assigned and the deadline changed", history)
+	}
+
+	if err := c.RegisterHandlingEvent(ctx, time.Date(2009, time.March, 1, 0, 0, 0, 0, time.UTC), id, "", shipping.SESTO, shipping.Receive); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := c.Track(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Events) != 1 {
+		t.Errorf("len(status.Events) = %d; want = %d", len(status.Events), 1)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	ctx := context.Background()
+
+	ts := newTestServer(t)
+	defer ts.Close()
+
+	c, err := client.New(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.LoadCargo(ctx, "NOPE"); !errors.Is(err, shipping.ErrUnknownCargo) {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrUnknownCargo)
+	}
+
+	_, err = c.Cargos(ctx, shipping.CargoQuery{Cursor: "garbage"})
+	if !errors.Is(err, shipping.ErrInvalidCursor) {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrInvalidCursor)
+	}
+
+	_, err = c.BookCargo(ctx, shipping.RouteSpecification{Origin: shipping.SESTO})
+	var e *shipping.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("err = %v; want *shipping.Error", err)
+	}
+	if e.Code != shipping.CodeInvalidArgument {
+		t.Errorf("e.Code = %s; want = %s", e.Code, shipping.CodeInvalidArgument)
+	}
+	if len(e.Fields) != 2 {
+		t.Errorf("len(e.Fields) = %d; want = %d", len(e.Fields), 2)
+	}
+}
+
+func TestNewInvalidURL(t *testing.T) {
+	for _, u := range []string{"localhost:8080", "ftp://example.com", "://"} {
+		if _, err := client.New(u); err == nil {
+			t.Errorf("client.New(%q) = nil error; want error", u)
+		}
+	}
+}
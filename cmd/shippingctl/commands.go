@@ -0,0 +1,643 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+)
+
+func runLocations(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ls, err := e.client.Locations(ctx)
+	if err != nil {
+		return err
+	}
+
+	if e.format == "json" {
+		return e.printJSON(ls)
+	}
+
+	tw := e.table()
+	fmt.Fprintln(tw, "LOCODE\tNAME")
+	for _, l := range ls {
+		fmt.Fprintf(tw, "%s\t%s\n", l.UNLocode, l.Name)
+	}
+	return tw.Flush()
+}
+
+func runBook(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e)
+	var (
+		origin      = fs.String("origin", "", "UN/LOCODE of the origin")
+		destination = fs.String("destination", "", "UN/LOCODE of the destination")
+		deadline    = fs.String("deadline", "", "arrival deadline, as a RFC 3339 time, a date (2006-01-02) or a number of days from now (14d)")
+		departure   = fs.String("earliest-departure", "", "when the cargo is ready to leave at the earliest, in the same formats as -deadline")
+		maxLegs     = fs.Int("max-legs", 0, "largest number of legs of a route, or 0 for no limit")
+		avoid       = fs.String("avoid", "", "comma-separated UN/LOCODEs where the cargo must not be handled")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	rs := shipping.RouteSpecification{
+		Origin:      shipping.UNLocode(*origin),
+		Destination: shipping.UNLocode(*destination),
+		Constraints: shipping.RouteConstraints{MaxLegs: *maxLegs},
+	}
+
+	t, err := parseTime(*deadline, now)
+	if err != nil {
+		return fmt.Errorf("invalid deadline: %v", err)
+	}
+	rs.ArrivalDeadline = t
+
+	if *departure != "" {
+		t, err := parseTime(*departure, now)
+		if err != nil {
+			return fmt.Errorf("invalid earliest departure: %v", err)
+		}
+		rs.EarliestDeparture = t
+	}
+
+	if *avoid != "" {
+		for _, l := range strings.Split(*avoid, ",") {
+			rs.Constraints.AvoidLocations = append(rs.Constraints.AvoidLocations, shipping.UNLocode(strings.TrimSpace(l)))
+		}
+	}
+
+	id, err := e.client.BookCargo(ctx, rs)
+	if err != nil {
+		return err
+	}
+
+	if e.format == "json" {
+		return e.printJSON(struct {
+			ID shipping.TrackingID `json:"tracking_id"`
+		}{id})
+	}
+
+	fmt.Fprintln(e.stdout, id)
+	return nil
+}
+
+func runCargos(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e)
+	var (
+		routingStatus = fs.String("routing-status", "", "only list cargos that are not_routed, misrouted or routed")
+		misrouted     = fs.String("misrouted", "", "only list cargos that are (true) or aren't (false) misrouted")
+		origin        = fs.String("origin", "", "only list cargos from this UN/LOCODE")
+		destination   = fs.String("destination", "", "only list cargos to this UN/LOCODE")
+		sortBy        = fs.String("sort", "tracking_id", "sort by tracking_id or arrival_deadline, prefixed with - to reverse")
+		limit         = fs.Int("limit", 0, "maximum number of cargos per page (default: the service's page size)")
+		cursor        = fs.String("cursor", "", "continue a previous listing")
+		all           = fs.Bool("all", false, "list every page")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	q := shipping.CargoQuery{
+		Origin:      shipping.UNLocode(*origin),
+		Destination: shipping.UNLocode(*destination),
+		Limit:       *limit,
+		Cursor:      *cursor,
+	}
+
+	if *routingStatus != "" {
+		s, ok := routingStatuses[*routingStatus]
+		if !ok {
+			return fmt.Errorf("invalid routing status %q: must be one of not_routed, misrouted, routed", *routingStatus)
+		}
+		q.RoutingStatus = &s
+	}
+	if *misrouted != "" {
+		b, err := strconv.ParseBool(*misrouted)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for -misrouted: must be true or false", *misrouted)
+		}
+		q.Misrouted = &b
+	}
+
+	key := *sortBy
+	if strings.HasPrefix(key, "-") {
+		q.Descending = true
+		key = key[1:]
+	}
+	switch key {
+	case "tracking_id":
+		q.SortBy = shipping.SortByTrackingID
+	case "arrival_deadline":
+		q.SortBy = shipping.SortByArrivalDeadline
+	default:
+		return fmt.Errorf("invalid sort key %q: must be tracking_id or arrival_deadline", *sortBy)
+	}
+
+	page, err := e.client.Cargos(ctx, q)
+	if err != nil {
+		return err
+	}
+	for *all && page.NextCursor != "" {
+		q.Cursor = page.NextCursor
+
+		next, err := e.client.Cargos(ctx, q)
+		if err != nil {
+			return err
+		}
+		page.Cargos = append(page.Cargos, next.Cargos...)
+		page.NextCursor = next.NextCursor
+	}
+
+	if e.format == "json" {
+		return e.printJSON(struct {
+			Cargos     interface{} `json:"cargos"`
+			NextCursor string      `json:"next_cursor,omitempty"`
+		}{page.Cargos, page.NextCursor})
+	}
+
+	tw := e.table()
+	fmt.Fprintln(tw, "TRACKING ID\tORIGIN\tDESTINATION\tARRIVAL DEADLINE\tROUTED\tMISROUTED")
+	for _, c := range page.Cargos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", c.TrackingID, c.Origin, c.Destination, formatTime(c.ArrivalDeadline), yesNo(c.Routed), yesNo(c.Misrouted))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if page.NextCursor != "" {
+		fmt.Fprintf(e.stderr, "\nMore cargos with -cursor %s\n", page.NextCursor)
+	}
+	return nil
+}
+
+func runCargo(ctx context.Context, e *env, args []string) error {
+	id, err := parseTrackingID(e, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := e.client.LoadCargo(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if e.format == "json" {
+		return e.printJSON(c)
+	}
+
+	tw := e.table()
+	fmt.Fprintf(tw, "Tracking ID:\t%s\n", c.TrackingID)
+	fmt.Fprintf(tw, "Origin:\t%s\n", c.Origin)
+	fmt.Fprintf(tw, "Destination:\t%s\n", c.Destination)
+	fmt.Fprintf(tw, "Arrival deadline:\t%s\n", formatTime(c.ArrivalDeadline))
+	fmt.Fprintf(tw, "Routed:\t%s\n", yesNo(c.Routed))
+	fmt.Fprintf(tw, "Misrouted:\t%s\n", yesNo(c.Misrouted))
+	if c.Price != nil {
+		fmt.Fprintf(tw, "Price:\t%s\n", c.Price)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(c.Legs) > 0 {
+		fmt.Fprintln(e.stdout)
+		return printLegs(e.stdout, c.Legs)
+	}
+	return nil
+}
+
+func runHistory(ctx context.Context, e *env, args []string) error {
+	id, err := parseTrackingID(e, args)
+	if err != nil {
+		return err
+	}
+
+	amendments, err := e.client.History(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if e.format == "json" {
+		return e.printJSON(amendments)
+	}
+
+	if len(amendments) == 0 {
+		fmt.Fprintln(e.stderr, "No amendments.")
+		return nil
+	}
+
+	tw := e.table()
+	fmt.Fprintln(tw, "TIME\tTYPE\tACTOR\tCHANGES")
+	for _, a := range amendments {
+		actor := a.Actor
+		if actor == "" {
+			actor = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", formatTime(a.Time), a.Type, actor, formatChanges(a.Previous, a.New))
+	}
+	return tw.Flush()
+}
+
+func runRoutes(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e)
+	strategy := fs.String("strategy", "", strategyUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	id := shipping.TrackingID(fs.Arg(0))
+
+	routes, err := e.client.RequestRoutes(ctx, id, *strategy)
+	if err != nil {
+		return err
+	}
+
+	if e.format == "json" {
+		return e.printJSON(routes)
+	}
+
+	if len(routes) == 0 {
+		fmt.Fprintln(e.stderr, "No routes found.")
+		return nil
+	}
+	return printRoutes(e.stdout, routes)
+}
+
+func runAssign(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e)
+	index := fs.Int("route", 0, "number of the route to assign, as listed by the routes command (default: ask)")
+	strategy := fs.String("strategy", "", strategyUsage)
+	size := fs.Int("size", 0, "price the route for this many TEU before assigning it, which accepts the quote")
+	currency := fs.String("currency", "", "currency of the quote (default: the currency of the tariff)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	id := shipping.TrackingID(fs.Arg(0))
+
+	routes, err := e.client.RequestRoutes(ctx, id, *strategy)
+	if err != nil {
+		return err
+	}
+	if len(routes) == 0 {
+		return errors.New("no routes found")
+	}
+
+	n := *index
+	if n == 0 {
+		// The routes and the prompt are written to stderr, to keep the
+		// output of the command itself parseable.
+		if err := printRoutes(e.stderr, routes); err != nil {
+			return err
+		}
+		if n, err = chooseRoute(e.stdin, e.stderr, len(routes)); err != nil {
+			return err
+		}
+	}
+	if n < 1 || n > len(routes) {
+		return fmt.Errorf("invalid route %d: must be between 1 and %d", n, len(routes))
+	}
+	route := routes[n-1]
+
+	if *size > 0 {
+		q, err := e.client.QuoteRoute(ctx, id, route.ID, *size, *currency)
+		if err != nil {
+			return err
+		}
+		route.Quote = &q
+	}
+
+	if err := e.client.AssignToRouteCandidate(ctx, id, route.ID); err != nil {
+		return err
+	}
+
+	if e.format == "json" {
+		return e.printJSON(route)
+	}
+
+	if route.Quote != nil {
+		fmt.Fprintf(e.stdout, "Assigned %s to route %d at %s.\n", id, n, route.Quote.Total)
+		return nil
+	}
+	fmt.Fprintf(e.stdout, "Assigned %s to route %d.\n", id, n)
+	return nil
+}
+
+func runChangeDestination(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	id, destination := shipping.TrackingID(fs.Arg(0)), shipping.UNLocode(fs.Arg(1))
+	if err := e.client.ChangeDestination(ctx, id, destination); err != nil {
+		return err
+	}
+
+	if e.format == "table" {
+		fmt.Fprintf(e.stdout, "Changed the destination of %s to %s.\n", id, destination)
+	}
+	return nil
+}
+
+func runChangeDeadline(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	id := shipping.TrackingID(fs.Arg(0))
+	deadline, err := parseTime(fs.Arg(1), time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid deadline: %v", err)
+	}
+	if err := e.client.ChangeArrivalDeadline(ctx, id, deadline); err != nil {
+		return err
+	}
+
+	if e.format == "table" {
+		fmt.Fprintf(e.stdout, "Changed the arrival deadline of %s to %s.\n", id, formatTime(deadline))
+	}
+	return nil
+}
+
+func runChangeOrigin(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	id, origin := shipping.TrackingID(fs.Arg(0)), shipping.UNLocode(fs.Arg(1))
+	if err := e.client.ChangeOrigin(ctx, id, origin); err != nil {
+		return err
+	}
+
+	if e.format == "table" {
+		fmt.Fprintf(e.stdout, "Changed the origin of %s to %s.\n", id, origin)
+	}
+	return nil
+}
+
+func runHandle(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e)
+	var (
+		id        = fs.String("id", "", "tracking ID of the cargo")
+		location  = fs.String("location", "", "UN/LOCODE of where the cargo was handled")
+		eventType = fs.String("type", "", "type of handling: Receive, Load, Unload, Customs or Claim")
+		voyage    = fs.String("voyage", "", "voyage number, when loading or unloading")
+		completed = fs.String("time", "", "when the handling was completed, as a RFC 3339 time (default: now)")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	typ, ok := parseEventType(*eventType)
+	if !ok {
+		return fmt.Errorf("invalid handling event type %q: must be one of Receive, Load, Unload, Customs, Claim", *eventType)
+	}
+
+	t := time.Now()
+	if *completed != "" {
+		var err error
+		if t, err = parseTime(*completed, t); err != nil {
+			return fmt.Errorf("invalid time: %v", err)
+		}
+	}
+
+	if err := e.client.RegisterHandlingEvent(ctx, t, shipping.TrackingID(*id), shipping.VoyageNumber(*voyage), shipping.UNLocode(*location), typ); err != nil {
+		return err
+	}
+
+	if e.format == "table" {
+		fmt.Fprintf(e.stdout, "Registered %s of %s at %s.\n", typ, *id, *location)
+	}
+	return nil
+}
+
+func runTrack(ctx context.Context, e *env, args []string) error {
+	id, err := parseTrackingID(e, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := e.client.Track(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if e.format == "json" {
+		return e.printJSON(c)
+	}
+
+	tw := e.table()
+	fmt.Fprintf(tw, "Tracking ID:\t%s\n", c.TrackingID)
+	fmt.Fprintf(tw, "Status:\t%s\n", c.StatusText)
+	fmt.Fprintf(tw, "Origin:\t%s\n", c.Origin)
+	fmt.Fprintf(tw, "Destination:\t%s\n", c.Destination)
+	fmt.Fprintf(tw, "ETA:\t%s\n", formatTime(c.ETA))
+	fmt.Fprintf(tw, "Arrival deadline:\t%s\n", formatTime(c.ArrivalDeadline))
+	fmt.Fprintf(tw, "Next expected activity:\t%s\n", c.NextExpectedActivity)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(c.Events) > 0 {
+		fmt.Fprintln(e.stdout)
+
+		tw := e.table()
+		fmt.Fprintln(tw, "EVENT\tEXPECTED")
+		for _, ev := range c.Events {
+			fmt.Fprintf(tw, "%s\t%s\n", ev.Description, yesNo(ev.Expected))
+		}
+		return tw.Flush()
+	}
+	return nil
+}
+
+// parseTrackingID parses the arguments of commands that only take a tracking
+// ID.
+const strategyUsage = "ranking strategy: balanced, fastest, fewest_transshipments, cheapest or most_slack (default: the server's)"
+
+func parseTrackingID(e *env, args []string) (shipping.TrackingID, error) {
+	fs := newFlagSet(e)
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return "", flag.ErrHelp
+	}
+	return shipping.TrackingID(fs.Arg(0)), nil
+}
+
+// chooseRoute asks for the number of one of n routes until a valid one is
+// given.
+func chooseRoute(r io.Reader, w io.Writer, n int) (int, error) {
+	s := bufio.NewScanner(r)
+	for {
+		fmt.Fprintf(w, "Choose a route [1-%d]: ", n)
+		if !s.Scan() {
+			if err := s.Err(); err != nil {
+				return 0, err
+			}
+			return 0, errors.New("no route chosen")
+		}
+
+		i, err := strconv.Atoi(strings.TrimSpace(s.Text()))
+		if err == nil && i >= 1 && i <= n {
+			return i, nil
+		}
+		fmt.Fprintf(w, "Please enter a number between 1 and %d.\n", n)
+	}
+}
+
+// parseTime parses s as a RFC 3339 time, a date, or a number of days after
+// now, such as 14d.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, errors.New("missing time")
+	}
+	if strings.HasSuffix(s, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil {
+			return now.AddDate(0, 0, days), nil
+		}
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// parseEventType parses the name of a handling event type, ignoring case.
+func parseEventType(s string) (shipping.HandlingEventType, bool) {
+	for _, t := range []shipping.HandlingEventType{shipping.Receive, shipping.Load, shipping.Unload, shipping.Customs, shipping.Claim} {
+		if strings.EqualFold(s, t.String()) {
+			return t, true
+		}
+	}
+	return shipping.NotHandled, false
+}
+
+var routingStatuses = map[string]shipping.RoutingStatus{
+	"not_routed": shipping.NotRouted,
+	"misrouted":  shipping.Misrouted,
+	"routed":     shipping.Routed,
+}
+
+func printRoutes(w io.Writer, routes []booking.RouteCandidate) error {
+	for i, r := range routes {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Route %d: %s to %s, arriving %s, score %.1f (%s)\n", i+1, r.InitialDepartureLocation(), r.FinalArrivalLocation(), formatTime(r.FinalArrivalTime()), r.Score, formatCosts(r.Costs))
+		if err := printLegs(w, r.Legs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatCosts lists the score of every cost, such as "transit_time 240.0,
+// transshipments 24.0".
+func formatCosts(costs []shipping.Cost) string {
+	var s []string
+	for _, c := range costs {
+		s = append(s, fmt.Sprintf("%s %.1f", c.Criterion, c.Score))
+	}
+	return strings.Join(s, ", ")
+}
+
+// formatChanges lists the terms that differ between previous and next, such
+// as "destination CNHKG -> AUMEL, legs 0 -> 2".
+func formatChanges(previous, next booking.Terms) string {
+	var s []string
+	add := func(name, from, to string) {
+		if from != to {
+			s = append(s, fmt.Sprintf("%s %s -> %s", name, from, to))
+		}
+	}
+	add("origin", string(previous.Origin), string(next.Origin))
+	add("destination", string(previous.Destination), string(next.Destination))
+	add("arrival deadline", formatTime(previous.ArrivalDeadline), formatTime(next.ArrivalDeadline))
+	add("legs", strconv.Itoa(len(previous.Legs)), strconv.Itoa(len(next.Legs)))
+	add("price", formatPrice(previous.Price), formatPrice(next.Price))
+	if len(s) == 0 && len(next.Legs) > 0 {
+		// Rerouted over as many legs.
+		s = append(s, "legs changed")
+	}
+	return strings.Join(s, ", ")
+}
+
+func formatPrice(m *shipping.Money) string {
+	if m == nil {
+		return "-"
+	}
+	return m.String()
+}
+
+func printLegs(w io.Writer, legs []shipping.Leg) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "  VOYAGE\tFROM\tTO\tLOAD\tUNLOAD")
+	for _, l := range legs {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", l.VoyageNumber, l.LoadLocation, l.UnloadLocation, formatTime(l.LoadTime), formatTime(l.UnloadTime))
+	}
+	return tw.Flush()
+}
+
+func (e *env) table() *tabwriter.Writer {
+	return tabwriter.NewWriter(e.stdout, 0, 8, 2, ' ', 0)
+}
+
+func (e *env) printJSON(v interface{}) error {
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,164 @@
+// Command shippingctl books, routes, handles and tracks cargos through the
+// HTTP APIs of shippingsvc.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/client"
+)
+
+// command is a subcommand of shippingctl.
+type command struct {
+	name  string
+	args  string
+	short string
+	run   func(ctx context.Context, env *env, args []string) error
+}
+
+var commands = []command{
+	{"locations", "", "list the locations cargos may be booked between", runLocations},
+	{"book", "-origin LOCODE -destination LOCODE -deadline TIME", "book a new cargo", runBook},
+	{"cargos", "[flags]", "list booked cargos", runCargos},
+	{"cargo", "TRACKING_ID", "show the booking details of a cargo", runCargo},
+	{"history", "TRACKING_ID", "show who changed the booking of a cargo, and how", runHistory},
+	{"routes", "[-strategy NAME] TRACKING_ID", "list the possible routes of a cargo, best first", runRoutes},
+	{"assign", "[-strategy NAME] [-route N] [-size TEU [-currency CODE]] TRACKING_ID", "assign a cargo to one of its possible routes", runAssign},
+	{"change-destination", "TRACKING_ID LOCODE", "change the destination of a cargo", runChangeDestination},
+	{"change-deadline", "TRACKING_ID TIME", "change the arrival deadline of a cargo", runChangeDeadline},
+	{"change-origin", "TRACKING_ID LOCODE", "change the origin of a cargo that hasn't been received", runChangeOrigin},
+	{"handle", "-id TRACKING_ID -location LOCODE -type TYPE [-voyage VOYAGE] [-time TIME]", "register a handling event", runHandle},
+	{"track", "TRACKING_ID", "show the tracking status of a cargo", runTrack},
+}
+
+// env holds what commands share: the client, and where and how to write
+// their output.
+type env struct {
+	cmd    command
+	client *client.Client
+	format string
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	var (
+		baseURL = flag.String("url", envString("SHIPPING_URL", "http://localhost:8080"), "base URL of shippingsvc")
+		apiKey  = flag.String("api-key", os.Getenv("SHIPPING_API_KEY"), "API key to authenticate with")
+		token   = flag.String("token", os.Getenv("SHIPPING_TOKEN"), "bearer token to authenticate with")
+		format  = flag.String("o", "table", "output format: table or json")
+	)
+
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "invalid output format %q: must be table or json\n", *format)
+		os.Exit(2)
+	}
+
+	cmd, ok := findCommand(flag.Arg(0))
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	c, err := client.New(*baseURL, client.WithAPIKey(*apiKey), client.WithBearerToken(*token))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	e := &env{
+		cmd:    cmd,
+		client: c,
+		format: *format,
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+
+	if err := cmd.run(ctx, e, flag.Args()[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s\n", cmd.name, describeError(err))
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [flags] command [arguments]\n\nCommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-20s%s\n", c.name, c.short)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s command -h' for the arguments of a command.\n\nFlags:\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// newFlagSet returns the flag set of the command being run.
+func newFlagSet(e *env) *flag.FlagSet {
+	cmd := e.cmd
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: shippingctl %s %s\n\n%s.\n", cmd.name, cmd.args, cmd.short)
+		var hasFlags bool
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintf(e.stderr, "\nFlags:\n")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// describeError returns err as shown to the user, including the fields
+// rejected by the service.
+func describeError(err error) string {
+	var e *shipping.Error
+	if !errors.As(err, &e) {
+		return err.Error()
+	}
+
+	s := fmt.Sprintf("%s [%s]", e.Message, e.Code)
+	for _, f := range e.Fields {
+		s += fmt.Sprintf("\n  %s: %s", f.Name, f.Reason)
+	}
+	return s
+}
+
+func envString(env, fallback string) string {
+	e := os.Getenv(env)
+	if e == "" {
+		return fallback
+	}
+	return e
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/client"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/mock"
+	"github.com/marcusolsson/goddd/server"
+)
+
+func TestChooseRoute(t *testing.T) {
+	var out bytes.Buffer
+
+	n, err := chooseRoute(strings.NewReader("x\n4\n 2 \n"), &out, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("n = %d; want = %d", n, 2)
+	}
+	if got := strings.Count(out.String(), "Choose a route [1-3]"); got != 3 {
+		t.Errorf("prompted %d times; want = %d", got, 3)
+	}
+
+	if _, err := chooseRoute(strings.NewReader(""), &out, 3); err == nil {
+		t.Errorf("err = nil; want error when no route is chosen")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2009, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"14d", time.Date(2009, time.March, 15, 12, 0, 0, 0, time.UTC)},
+		{"2009-03-18", time.Date(2009, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"2009-03-18T10:30:00Z", time.Date(2009, time.March, 18, 10, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTime(tt.in, now)
+		if err != nil {
+			t.Errorf("parseTime(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseTime(%q) = %v; want = %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "tomorrow", "d"} {
+		if _, err := parseTime(in, now); err == nil {
+			t.Errorf("parseTime(%q) = nil error; want error", in)
+		}
+	}
+}
+
+func TestAssignInteractively(t *testing.T) {
+	ctx := context.Background()
+
+	cargos := inmem.NewCargoRepository()
+
+	rs := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			var routes []shipping.Itinerary
+			for _, v := range []shipping.VoyageNumber{"V100", "V200", "V300"} {
+				routes = append(routes, shipping.Itinerary{Legs: []shipping.Leg{
+					shipping.NewLeg(v, rs.Origin, rs.Destination, time.Date(2009, time.March, 2, 0, 0, 0, 0, time.UTC), time.Date(2009, time.March, 12, 0, 0, 0, 0, time.UTC)),
+				}})
+			}
+			return routes, nil
+		},
+	}
+
+	bs := booking.NewService(cargos, inmem.NewLocationRepository(), inmem.NewHandlingEventRepository(), rs, inmem.NewRouteCandidateRepository(), inmem.NewAmendmentRepository())
+
+	ts := httptest.NewServer(server.New(bs, nil, nil, log.NewLogfmtLogger(ioutil.Discard)))
+	defer ts.Close()
+
+	c, err := client.New(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := c.BookCargo(ctx, shipping.RouteSpecification{
+		Origin:          shipping.SESTO,
+		Destination:     shipping.CNHKG,
+		ArrivalDeadline: time.Date(2009, time.March, 18, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	e := &env{
+		cmd:    command{name: "assign"},
+		client: c,
+		format: "json",
+		stdin:  strings.NewReader("2\n"),
+		stdout: &stdout,
+		stderr: &stderr,
+	}
+
+	if err := runAssign(ctx, e, []string{string(id)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(stderr.String(), "Route 3:") {
+		t.Errorf("stderr = %q; want the routes listed", stderr.String())
+	}
+
+	var assigned shipping.Itinerary
+	if err := json.Unmarshal(stdout.Bytes(), &assigned); err != nil {
+		t.Fatal(err)
+	}
+	if got := assigned.Legs[0].VoyageNumber; got != "V200" {
+		t.Errorf("assigned voyage = %s; want = %s", got, "V200")
+	}
+
+	cargo, err := cargos.Find(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cargo.Itinerary.Legs[0].VoyageNumber; got != "V200" {
+		t.Errorf("cargo voyage = %s; want = %s", got, "V200")
+	}
+}
+
+func TestFormatChanges(t *testing.T) {
+	deadline := time.Date(2009, time.March, 18, 0, 0, 0, 0, time.UTC)
+	previous := booking.Terms{Origin: shipping.SESTO, Destination: shipping.CNHKG, ArrivalDeadline: deadline}
+
+	for _, tt := range []struct {
+		name string
+		next booking.Terms
+		want string
+	}{
+		{"Destination", booking.Terms{Origin: shipping.SESTO, Destination: shipping.AUMEL, ArrivalDeadline: deadline}, "destination CNHKG -> AUMEL"},
+		{"Route", booking.Terms{
+			Origin:          shipping.SESTO,
+			Destination:     shipping.CNHKG,
+			ArrivalDeadline: deadline,
+			Legs:            []shipping.Leg{{VoyageNumber: "V100"}},
+			Price:           &shipping.Money{Amount: 150000, Currency: "USD"},
+		}, "legs 0 -> 1, price - -> 1500.00 USD"},
+	} {
+		if got := formatChanges(previous, tt.next); got != tt.want {
+			t.Errorf("%s: formatChanges = %q; want = %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/marcusolsson/goddd/archive"
+)
+
+// exportArchive writes an archive of repos to the file at path, or to stdout
+// if path is empty or "-".
+func exportArchive(ctx context.Context, path string, repos archive.Repositories) error {
+	if path == "" || path == "-" {
+		return archive.Export(ctx, os.Stdout, repos)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := archive.Export(ctx, f, repos); err != nil {
+		f.Close()
+		return err
+	}
+
+	// The archive isn't complete until it's been written out, which may
+	// fail on close.
+	return f.Close()
+}
+
+// importArchive reads an archive into repos from the file at path, or from
+// stdin if path is empty or "-".
+func importArchive(ctx context.Context, path string, repos archive.Repositories) error {
+	var r io.Reader = os.Stdin
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	return archive.Import(ctx, r, repos)
+}
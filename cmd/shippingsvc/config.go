@@ -0,0 +1,526 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/ratelimit"
+	"github.com/marcusolsson/goddd/routing"
+)
+
+// config is the configuration of shippingsvc. It's read from a YAML file, if
+// one is given, and then overridden by environment variables and flags.
+type config struct {
+	HTTP    httpConfig    `yaml:"http"`
+	GRPC    grpcConfig    `yaml:"grpc"`
+	Storage storageConfig `yaml:"storage"`
+	Booking bookingConfig `yaml:"booking"`
+	Routing routingConfig `yaml:"routing"`
+	Metrics metricsConfig `yaml:"metrics"`
+	Logging loggingConfig `yaml:"logging"`
+	Tracing tracingConfig `yaml:"tracing"`
+	Auth    authConfig    `yaml:"auth"`
+
+	// RateLimits holds the rate limit of each router, as parsed by
+	// ratelimit.Parse.
+	RateLimits map[string]string `yaml:"rate_limits,omitempty"`
+
+	Shutdown shutdownConfig `yaml:"shutdown"`
+}
+
+type httpConfig struct {
+	Addr     string   `yaml:"addr"`
+	Validate bool     `yaml:"validate"`
+	Origins  []string `yaml:"origins,omitempty"`
+}
+
+type grpcConfig struct {
+	Addr string `yaml:"addr"`
+}
+
+type storageConfig struct {
+	// Backend is either "mongodb" or "inmem".
+	Backend string        `yaml:"backend"`
+	MongoDB mongoDBConfig `yaml:"mongodb"`
+}
+
+type mongoDBConfig struct {
+	URL          string `yaml:"url"`
+	Database     string `yaml:"database"`
+	DialAttempts int    `yaml:"dial_attempts"`
+}
+
+type bookingConfig struct {
+	// RouteTTL is how long requested routes can be assigned by ID.
+	RouteTTL duration `yaml:"route_ttl"`
+
+	// Tariff is a JSON file with the tariff that routes are priced with. If
+	// empty, routes are priced with a sample tariff.
+	Tariff string `yaml:"tariff,omitempty"`
+}
+
+type routingConfig struct {
+	URL string `yaml:"url"`
+
+	// Instances, SRV and InstancesFile find several instances of the
+	// routing service to balance the calls over, instead of URL. At most
+	// one of them may be set.
+	Instances     []string `yaml:"instances,omitempty"`
+	SRV           string   `yaml:"srv,omitempty"`
+	InstancesFile string   `yaml:"instances_file,omitempty"`
+
+	// Refresh is how often SRV and InstancesFile are checked for changes.
+	Refresh duration `yaml:"refresh"`
+
+	// Timeout bounds every call to the routing service.
+	Timeout duration `yaml:"timeout"`
+
+	// Retries is how many times a failed call is retried.
+	Retries int `yaml:"retries"`
+
+	// CacheSize is how many route specifications the routes are cached
+	// for. Zero disables the cache.
+	CacheSize int `yaml:"cache_size"`
+
+	// CacheTTL is how long routes are cached.
+	CacheTTL duration `yaml:"cache_ttl"`
+}
+
+type metricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
+type loggingConfig struct {
+	// Format is either "logfmt" or "json".
+	Format string `yaml:"format"`
+
+	// Level is the least severe level logged: debug, info, warn or error.
+	Level string `yaml:"level"`
+}
+
+type tracingConfig struct {
+	// Exporter is where spans are sent: none, stdout or otlp.
+	Exporter string `yaml:"exporter"`
+
+	// Endpoint is the URL of the OTLP/HTTP collector. It defaults to the
+	// OTEL_EXPORTER_OTLP_ENDPOINT variable, or http://localhost:4318.
+	Endpoint string `yaml:"endpoint,omitempty"`
+
+	// SampleRatio is the fraction of traces that are sampled, unless the
+	// client decided whether to sample it.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+type authConfig struct {
+	APIKeys           string `yaml:"api_keys,omitempty"`
+	JWKS              string `yaml:"jwks,omitempty"`
+	Issuer            string `yaml:"issuer,omitempty"`
+	Audience          string `yaml:"audience,omitempty"`
+	AnonymousTracking bool   `yaml:"anonymous_tracking"`
+}
+
+type shutdownConfig struct {
+	Delay   duration `yaml:"delay"`
+	Timeout duration `yaml:"timeout"`
+}
+
+// duration is a time.Duration written as a string, such as "30s", both in
+// the configuration file and in flags.
+type duration time.Duration
+
+func (d duration) String() string { return time.Duration(d).String() }
+
+func (d *duration) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(v)
+	return nil
+}
+
+func (d duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+func (d *duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return d.Set(s)
+}
+
+// routers are the routers that may be rate limited.
+var routers = []string{"booking", "tracking", "handling", "webhooks"}
+
+func defaultConfig() config {
+	return config{
+		HTTP: httpConfig{Addr: ":" + defaultPort},
+		GRPC: grpcConfig{Addr: ":" + defaultGRPCPort},
+		Storage: storageConfig{
+			Backend: "mongodb",
+			MongoDB: mongoDBConfig{
+				URL:          defaultMongoDBURL,
+				Database:     defaultDBName,
+				DialAttempts: 8,
+			},
+		},
+		Booking:    bookingConfig{RouteTTL: duration(booking.DefaultRouteCandidateTTL)},
+		Routing:    routingConfig{URL: defaultRoutingServiceURL, Refresh: duration(30 * time.Second), Timeout: duration(routing.DefaultTimeout), Retries: 2, CacheSize: routing.DefaultCacheSize, CacheTTL: duration(routing.DefaultCacheTTL)},
+		Metrics:    metricsConfig{Enabled: true, Path: "/metrics"},
+		Logging:    loggingConfig{Format: "logfmt", Level: "info"},
+		Tracing:    tracingConfig{Exporter: "none", SampleRatio: 1},
+		Auth:       authConfig{AnonymousTracking: true},
+		RateLimits: map[string]string{},
+		Shutdown:   shutdownConfig{Timeout: duration(30 * time.Second)},
+	}
+}
+
+// parseConfig parses the command line arguments into fs and returns the
+// effective configuration: the defaults, overridden by the configuration file
+// given by -config or CONFIG_FILE, then by environment variables and lastly
+// by the flags that were set. The configuration isn't validated.
+func parseConfig(fs *flag.FlagSet, args []string, getenv func(string) string) (*config, error) {
+	cfg := defaultConfig()
+	cfg.bindFlags(fs)
+
+	path := fs.String("config", getenv("CONFIG_FILE"), "YAML configuration file")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	// The flags were only parsed to find the configuration file. Since they
+	// take precedence over it and the environment, they're parsed again
+	// once those have been applied.
+	cfg = defaultConfig()
+
+	if *path != "" {
+		if err := cfg.loadFile(*path); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.applyEnv(getenv)
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// bindFlags defines flags in fs that set the fields of c.
+func (c *config) bindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.HTTP.Addr, "http.addr", c.HTTP.Addr, "HTTP listen address")
+	fs.BoolVar(&c.HTTP.Validate, "http.validate", c.HTTP.Validate, "reject requests that don't match the OpenAPI specification")
+	fs.Func("http.origins", "comma-separated origins allowed to make cross-origin requests (default: any)", func(v string) error {
+		c.HTTP.Origins = splitList(v)
+		return nil
+	})
+	fs.StringVar(&c.GRPC.Addr, "grpc.addr", c.GRPC.Addr, "gRPC listen address")
+
+	fs.StringVar(&c.Storage.Backend, "db.backend", c.Storage.Backend, "storage backend: mongodb or inmem")
+	fs.BoolFunc("inmem", "use in-memory repositories; same as -db.backend=inmem", func(v string) error {
+		inmem, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		if inmem {
+			c.Storage.Backend = "inmem"
+		}
+		return nil
+	})
+	fs.StringVar(&c.Storage.MongoDB.URL, "db.url", c.Storage.MongoDB.URL, "MongoDB URL")
+	fs.StringVar(&c.Storage.MongoDB.Database, "db.name", c.Storage.MongoDB.Database, "MongoDB database name")
+	fs.IntVar(&c.Storage.MongoDB.DialAttempts, "db.dial-attempts", c.Storage.MongoDB.DialAttempts, "number of attempts at connecting to MongoDB on startup, with exponential backoff")
+
+	fs.Var(&c.Booking.RouteTTL, "booking.route-ttl", "time that requested routes can be assigned by ID")
+	fs.StringVar(&c.Booking.Tariff, "booking.tariff", c.Booking.Tariff, "JSON file with the tariff that routes are priced with (default: a sample tariff)")
+	fs.StringVar(&c.Routing.URL, "service.routing", c.Routing.URL, "routing service URL")
+	fs.Func("routing.instances", "comma-separated routing service URLs to balance the calls over, instead of -service.routing", func(v string) error {
+		c.Routing.Instances = splitList(v)
+		return nil
+	})
+	fs.StringVar(&c.Routing.SRV, "routing.srv", c.Routing.SRV, "DNS SRV record listing the routing service instances, instead of -service.routing")
+	fs.StringVar(&c.Routing.InstancesFile, "routing.instances-file", c.Routing.InstancesFile, "file listing the routing service instances, one per line, instead of -service.routing")
+	fs.Var(&c.Routing.Refresh, "routing.refresh", "time between checks of -routing.srv or -routing.instances-file for changes")
+	fs.Var(&c.Routing.Timeout, "routing.timeout", "time to wait for the routing service")
+	fs.IntVar(&c.Routing.Retries, "routing.retries", c.Routing.Retries, "number of times a failed call to the routing service is retried")
+	fs.IntVar(&c.Routing.CacheSize, "routing.cache-size", c.Routing.CacheSize, "number of route specifications to cache routes for, or 0 to disable the cache")
+	fs.Var(&c.Routing.CacheTTL, "routing.cache-ttl", "time to cache routes")
+
+	fs.BoolVar(&c.Metrics.Enabled, "metrics.enabled", c.Metrics.Enabled, "serve Prometheus metrics")
+	fs.StringVar(&c.Metrics.Path, "metrics.path", c.Metrics.Path, "path of the Prometheus metrics")
+
+	fs.StringVar(&c.Logging.Format, "log.format", c.Logging.Format, "log format: logfmt or json")
+	fs.StringVar(&c.Logging.Level, "log.level", c.Logging.Level, "least severe level logged: debug, info, warn or error")
+
+	fs.StringVar(&c.Tracing.Exporter, "tracing.exporter", c.Tracing.Exporter, "where to export traces: none, stdout or otlp")
+	fs.StringVar(&c.Tracing.Endpoint, "tracing.endpoint", c.Tracing.Endpoint, "URL of the OTLP/HTTP collector (default: $OTEL_EXPORTER_OTLP_ENDPOINT or http://localhost:4318)")
+	fs.Float64Var(&c.Tracing.SampleRatio, "tracing.sample-ratio", c.Tracing.SampleRatio, "fraction of traces to sample")
+
+	fs.StringVar(&c.Auth.APIKeys, "auth.keys", c.Auth.APIKeys, "JSON file with API keys; enables authentication")
+	fs.StringVar(&c.Auth.JWKS, "auth.jwks", c.Auth.JWKS, "JSON Web Key Set used to verify bearer tokens; enables authentication")
+	fs.StringVar(&c.Auth.Issuer, "auth.issuer", c.Auth.Issuer, "required issuer of bearer tokens")
+	fs.StringVar(&c.Auth.Audience, "auth.audience", c.Auth.Audience, "required audience of bearer tokens")
+	fs.BoolVar(&c.Auth.AnonymousTracking, "auth.anonymous-tracking", c.Auth.AnonymousTracking, "let clients without credentials track cargos")
+
+	for _, router := range routers {
+		router := router
+		fs.Func("ratelimit."+router, "requests per second and client to the "+router+" API, optionally followed by :burst (default: unlimited)", func(v string) error {
+			c.RateLimits[router] = v
+			return nil
+		})
+	}
+	fs.Func("ratelimit."+ratelimit.Auth, "requests per second and IP address to any API before authentication, optionally followed by :burst; should be looser than the per-API limits (default: unlimited)", func(v string) error {
+		c.RateLimits[ratelimit.Auth] = v
+		return nil
+	})
+
+	fs.Var(&c.Shutdown.Delay, "shutdown.delay", "time to keep serving after /readyz starts failing on shutdown, for load balancers to notice")
+	fs.Var(&c.Shutdown.Timeout, "shutdown.timeout", "time to wait for requests in flight on shutdown")
+}
+
+// loadFile overrides c with the configuration file at path. Unknown keys are
+// rejected.
+func (c *config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides c with the environment variables that are set.
+func (c *config) applyEnv(getenv func(string) string) {
+	if v := getenv("PORT"); v != "" {
+		c.HTTP.Addr = ":" + v
+	}
+	if v := getenv("GRPC_PORT"); v != "" {
+		c.GRPC.Addr = ":" + v
+	}
+	if v := getenv("ROUTINGSERVICE_URL"); v != "" {
+		c.Routing.URL = v
+	}
+	if v := getenv("MONGODB_URL"); v != "" {
+		c.Storage.MongoDB.URL = v
+	}
+	if v := getenv("DB_NAME"); v != "" {
+		c.Storage.MongoDB.Database = v
+	}
+	if v := getenv("AUTH_API_KEYS"); v != "" {
+		c.Auth.APIKeys = v
+	}
+	if v := getenv("AUTH_JWKS"); v != "" {
+		c.Auth.JWKS = v
+	}
+}
+
+// validate returns an error listing every invalid setting of c.
+func (c *config) validate() error {
+	var errs []string
+	addf := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	if _, _, err := net.SplitHostPort(c.HTTP.Addr); err != nil {
+		addf("http.addr: %v", err)
+	}
+	if _, _, err := net.SplitHostPort(c.GRPC.Addr); err != nil {
+		addf("grpc.addr: %v", err)
+	}
+	for _, o := range c.HTTP.Origins {
+		if u, err := url.Parse(o); err != nil || u.Scheme == "" || u.Host == "" {
+			addf("http.origins: %q is not an origin", o)
+		}
+	}
+
+	switch c.Storage.Backend {
+	case "inmem":
+	case "mongodb":
+		if c.Storage.MongoDB.URL == "" {
+			addf("storage.mongodb.url: must be set")
+		}
+		if c.Storage.MongoDB.Database == "" {
+			addf("storage.mongodb.database: must be set")
+		}
+		if c.Storage.MongoDB.DialAttempts < 1 {
+			addf("storage.mongodb.dial_attempts: must be at least 1")
+		}
+	default:
+		addf("storage.backend: must be mongodb or inmem, not %q", c.Storage.Backend)
+	}
+
+	if c.Booking.RouteTTL <= 0 {
+		addf("booking.route_ttl: must be positive")
+	}
+	if u, err := url.Parse(c.Routing.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		addf("routing.url: %q is not a HTTP URL", c.Routing.URL)
+	}
+	for _, i := range c.Routing.Instances {
+		if u, err := url.Parse(i); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			addf("routing.instances: %q is not a HTTP URL", i)
+		}
+	}
+	var sources int
+	for _, set := range []bool{len(c.Routing.Instances) > 0, c.Routing.SRV != "", c.Routing.InstancesFile != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		addf("routing: only one of instances, srv and instances_file may be set")
+	}
+	if sources > 0 && c.Routing.Refresh <= 0 {
+		addf("routing.refresh: must be positive")
+	}
+	if c.Routing.Timeout <= 0 {
+		addf("routing.timeout: must be positive")
+	}
+	if c.Routing.Retries < 0 {
+		addf("routing.retries: must not be negative")
+	}
+	if c.Routing.CacheSize < 0 {
+		addf("routing.cache_size: must not be negative")
+	}
+	if c.Routing.CacheSize > 0 && c.Routing.CacheTTL <= 0 {
+		addf("routing.cache_ttl: must be positive")
+	}
+
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		addf("metrics.path: must start with /")
+	}
+
+	switch c.Logging.Format {
+	case "logfmt", "json":
+	default:
+		addf("logging.format: must be logfmt or json, not %q", c.Logging.Format)
+	}
+	switch c.Logging.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		addf("logging.level: must be debug, info, warn or error, not %q", c.Logging.Level)
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		addf("tracing.exporter: must be none, stdout or otlp, not %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			addf("tracing.endpoint: %q is not a HTTP URL", c.Tracing.Endpoint)
+		}
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		addf("tracing.sample_ratio: must be between 0 and 1")
+	}
+
+	if (c.Auth.Issuer != "" || c.Auth.Audience != "") && c.Auth.JWKS == "" {
+		addf("auth.jwks: must be set to verify the issuer or audience of bearer tokens")
+	}
+
+	var names []string
+	for router := range c.RateLimits {
+		names = append(names, router)
+	}
+	sort.Strings(names)
+	for _, router := range names {
+		if !contains(routers, router) && router != ratelimit.Auth {
+			addf("rate_limits.%s: unknown router", router)
+			continue
+		}
+		if _, err := ratelimit.Parse(c.RateLimits[router]); err != nil {
+			addf("rate_limits.%s: %v", router, err)
+		}
+	}
+	if v, ok := c.RateLimits[ratelimit.Auth]; ok {
+		if authLimit, err := ratelimit.Parse(v); err == nil && authLimit.Rate > 0 {
+			for _, router := range names {
+				l, err := ratelimit.Parse(c.RateLimits[router])
+				if router == ratelimit.Auth || err != nil {
+					continue
+				}
+				if l.Rate == 0 || l.Rate > authLimit.Rate || l.Burst > authLimit.Burst {
+					addf("rate_limits.%s: must be looser than rate_limits.%s", ratelimit.Auth, router)
+				}
+			}
+		}
+	}
+
+	if c.Shutdown.Delay < 0 {
+		addf("shutdown.delay: must not be negative")
+	}
+	if c.Shutdown.Timeout < 0 {
+		addf("shutdown.timeout: must not be negative")
+	}
+
+	if len(errs) > 0 {
+		return errors.New("invalid configuration:\n\t" + strings.Join(errs, "\n\t"))
+	}
+	return nil
+}
+
+// rateLimits returns the parsed rate limits of c, which must be valid.
+func (c *config) rateLimits() ratelimit.Limits {
+	limits := ratelimit.Limits{}
+	for router, v := range c.RateLimits {
+		l, _ := ratelimit.Parse(v)
+		limits[router] = l
+	}
+	return limits
+}
+
+// print writes c as YAML to w.
+func (c *config) print(w io.Writer) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func splitList(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func parseTestConfig(args []string, env map[string]string) (*config, error) {
+	fs := flag.NewFlagSet("shippingsvc", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return parseConfig(fs, args, func(k string) string { return env[k] })
+}
+
+func TestParseConfigPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+http:
+  addr: ":9000"
+grpc:
+  addr: ":9001"
+storage:
+  backend: inmem
+routing:
+  url: http://pathfinder:8080
+rate_limits:
+  tracking: "5:20"
+shutdown:
+  timeout: 10s
+`)
+
+	cfg, err := parseTestConfig(
+		[]string{"-config", path, "-grpc.addr", ":7001", "-ratelimit.handling", "50"},
+		map[string]string{
+			"GRPC_PORT":          "8001",
+			"ROUTINGSERVICE_URL": "http://routing:7878",
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// From the file.
+	if cfg.HTTP.Addr != ":9000" {
+		t.Errorf("cfg.HTTP.Addr = %q; want = %q", cfg.HTTP.Addr, ":9000")
+	}
+	if cfg.Storage.Backend != "inmem" {
+		t.Errorf("cfg.Storage.Backend = %q; want = %q", cfg.Storage.Backend, "inmem")
+	}
+	if time.Duration(cfg.Shutdown.Timeout) != 10*time.Second {
+		t.Errorf("cfg.Shutdown.Timeout = %v; want = %v", cfg.Shutdown.Timeout, 10*time.Second)
+	}
+
+	// The environment overrides the file, and the flags the environment.
+	if cfg.Routing.URL != "http://routing:7878" {
+		t.Errorf("cfg.Routing.URL = %q; want = %q", cfg.Routing.URL, "http://routing:7878")
+	}
+	if cfg.GRPC.Addr != ":7001" {
+		t.Errorf("cfg.GRPC.Addr = %q; want = %q", cfg.GRPC.Addr, ":7001")
+	}
+
+	// Flags add to the rate limits of the file.
+	if got := cfg.RateLimits["tracking"]; got != "5:20" {
+		t.Errorf("cfg.RateLimits[tracking] = %q; want = %q", got, "5:20")
+	}
+	if got := cfg.RateLimits["handling"]; got != "50" {
+		t.Errorf("cfg.RateLimits[handling] = %q; want = %q", got, "50")
+	}
+
+	// Untouched settings keep their defaults.
+	if cfg.Storage.MongoDB.Database != defaultDBName {
+		t.Errorf("cfg.Storage.MongoDB.Database = %q; want = %q", cfg.Storage.MongoDB.Database, defaultDBName)
+	}
+
+	if err := cfg.validate(); err != nil {
+		t.Errorf("cfg.validate() = %v; want = nil", err)
+	}
+}
+
+func TestParseConfigFromEnvironment(t *testing.T) {
+	cfg, err := parseTestConfig(nil, map[string]string{
+		"CONFIG_FILE": writeConfig(t, "logging:\n  format: json\n"),
+		"PORT":        "3000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Logging.Format != "json" {
+		t.Errorf("cfg.Logging.Format = %q; want = %q", cfg.Logging.Format, "json")
+	}
+	if cfg.HTTP.Addr != ":3000" {
+		t.Errorf("cfg.HTTP.Addr = %q; want = %q", cfg.HTTP.Addr, ":3000")
+	}
+}
+
+func TestParseConfigUnknownKey(t *testing.T) {
+	path := writeConfig(t, "http:\n  adress: \":9000\"\n")
+
+	if _, err := parseTestConfig([]string{"-config", path}, nil); err == nil {
+		t.Errorf("err = nil; want error for unknown key")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := defaultConfig()
+	if err := cfg.validate(); err != nil {
+		t.Errorf("defaultConfig().validate() = %v; want = nil", err)
+	}
+
+	cfg.HTTP.Addr = "8080"
+	cfg.Storage.Backend = "postgres"
+	cfg.Logging.Level = "verbose"
+	cfg.RateLimits["shipping"] = "1"
+	cfg.RateLimits["booking"] = "fast"
+	cfg.RateLimits["tracking"] = "5"
+	cfg.RateLimits["auth"] = "1"
+	cfg.Routing.Instances = []string{"pathfinder:7878"}
+	cfg.Routing.SRV = "_pathfinder._tcp.example.com"
+
+	err := cfg.validate()
+	if err == nil {
+		t.Fatal("err = nil; want error")
+	}
+
+	for _, want := range []string{
+		"http.addr",
+		"storage.backend",
+		"logging.level",
+		"rate_limits.shipping: unknown router",
+		"rate_limits.booking",
+		"rate_limits.auth: must be looser than rate_limits.tracking",
+		"routing.instances",
+		"routing: only one of",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %q; want it to mention %q", err, want)
+		}
+	}
+}
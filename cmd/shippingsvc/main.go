@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,76 +12,211 @@ import (
 	"time"
 
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-kit/kit/sd"
+	"github.com/go-kit/kit/sd/dnssrv"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/mgo.v2"
 
 	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/archive"
+	"github.com/marcusolsson/goddd/auth"
 	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/grpcserver"
 	"github.com/marcusolsson/goddd/handling"
 	"github.com/marcusolsson/goddd/inmem"
 	"github.com/marcusolsson/goddd/inspection"
+	"github.com/marcusolsson/goddd/metrics"
 	"github.com/marcusolsson/goddd/mongo"
+	"github.com/marcusolsson/goddd/pricing"
 	"github.com/marcusolsson/goddd/routing"
 	"github.com/marcusolsson/goddd/server"
+	"github.com/marcusolsson/goddd/tracing"
 	"github.com/marcusolsson/goddd/tracking"
+	"github.com/marcusolsson/goddd/webhook"
 )
 
 const (
 	defaultPort              = "8080"
+	defaultGRPCPort          = "8081"
 	defaultRoutingServiceURL = "http://localhost:7878"
 	defaultMongoDBURL        = "127.0.0.1"
 	defaultDBName            = "dddsample"
 )
 
 func main() {
-	var (
-		addr   = envString("PORT", defaultPort)
-		rsurl  = envString("ROUTINGSERVICE_URL", defaultRoutingServiceURL)
-		dburl  = envString("MONGODB_URL", defaultMongoDBURL)
-		dbname = envString("DB_NAME", defaultDBName)
-
-		httpAddr          = flag.String("http.addr", ":"+addr, "HTTP listen address")
-		routingServiceURL = flag.String("service.routing", rsurl, "routing service URL")
-		mongoDBURL        = flag.String("db.url", dburl, "MongoDB URL")
-		databaseName      = flag.String("db.name", dbname, "MongoDB database name")
-		inmemory          = flag.Bool("inmem", false, "use in-memory repositories")
-
-		ctx = context.Background()
-	)
+	printConfig := flag.Bool("print-config", false, "print the effective configuration and exit")
 
-	flag.Parse()
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [command]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "  export [file]\twrite all data to an archive file (default: stdout)\n")
+		fmt.Fprintf(os.Stderr, "  import [file]\trestore data from an archive file (default: stdin)\n")
+		fmt.Fprintf(os.Stderr, "\nWithout a command, the HTTP server is started.\n\n")
+		fmt.Fprintf(os.Stderr, "Settings are read from the -config file, then overridden by the environment\n")
+		fmt.Fprintf(os.Stderr, "variables PORT, GRPC_PORT, ROUTINGSERVICE_URL, MONGODB_URL, DB_NAME,\n")
+		fmt.Fprintf(os.Stderr, "AUTH_API_KEYS and AUTH_JWKS, and lastly by flags.\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+
+	cfg, err := parseConfig(flag.CommandLine, os.Args[1:], os.Getenv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := cfg.validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *printConfig {
+		if err := cfg.print(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	var logger log.Logger
-	logger = log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
+	if cfg.Logging.Format == "json" {
+		logger = log.NewJSONLogger(log.NewSyncWriter(os.Stderr))
+	} else {
+		logger = log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
+	}
+	logger = level.NewFilter(logger, logLevel(cfg.Logging.Level))
 	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
 
 	// Setup repositories
 	var (
-		cargos         shipping.CargoRepository
-		locations      shipping.LocationRepository
-		voyages        shipping.VoyageRepository
-		handlingEvents shipping.HandlingEventRepository
+		cargos          shipping.CargoRepository
+		locations       shipping.LocationRepository
+		voyages         shipping.VoyageRepository
+		handlingEvents  shipping.HandlingEventRepository
+		subscriptions   webhook.SubscriptionRepository
+		deliveries      webhook.DeliveryRepository
+		routeCandidates booking.RouteCandidateRepository
+		amendments      booking.AmendmentRepository
+
+		readinessChecks = map[string]server.HealthCheck{
+			"routing": routing.CheckCircuit,
+		}
 	)
 
-	if *inmemory {
+	if cfg.Storage.Backend == "inmem" {
 		cargos = inmem.NewCargoRepository()
 		locations = inmem.NewLocationRepository()
 		voyages = inmem.NewVoyageRepository()
 		handlingEvents = inmem.NewHandlingEventRepository()
+		subscriptions = inmem.NewSubscriptionRepository()
+		deliveries = inmem.NewDeliveryRepository()
+		routeCandidates = inmem.NewRouteCandidateRepository()
+		amendments = inmem.NewAmendmentRepository()
 	} else {
-		session, err := mgo.Dial(*mongoDBURL)
+		dbLogger := log.With(logger, "component", "mongodb")
+
+		session, err := dialMongoDB(cfg.Storage.MongoDB.URL, cfg.Storage.MongoDB.DialAttempts, dbLogger)
 		if err != nil {
-			panic(err)
+			dbLogger.Log("err", err)
+			os.Exit(1)
 		}
 		defer session.Close()
 
 		session.SetMode(mgo.Monotonic, true)
 
-		cargos, _ = mongo.NewCargoRepository(*databaseName, session)
-		locations, _ = mongo.NewLocationRepository(*databaseName, session)
-		voyages, _ = mongo.NewVoyageRepository(*databaseName, session)
-		handlingEvents = mongo.NewHandlingEventRepository(*databaseName, session)
+		must := func(err error) {
+			if err != nil {
+				dbLogger.Log("err", err)
+				os.Exit(1)
+			}
+		}
+
+		cargos, err = mongo.NewCargoRepository(cfg.Storage.MongoDB.Database, session)
+		must(err)
+		locations, err = mongo.NewLocationRepository(cfg.Storage.MongoDB.Database, session)
+		must(err)
+		voyages, err = mongo.NewVoyageRepository(cfg.Storage.MongoDB.Database, session)
+		must(err)
+		handlingEvents = mongo.NewHandlingEventRepository(cfg.Storage.MongoDB.Database, session)
+		subscriptions, err = mongo.NewSubscriptionRepository(cfg.Storage.MongoDB.Database, session)
+		must(err)
+		deliveries, err = mongo.NewDeliveryRepository(cfg.Storage.MongoDB.Database, session)
+		must(err)
+		routeCandidates, err = mongo.NewRouteCandidateRepository(cfg.Storage.MongoDB.Database, session)
+		must(err)
+		amendments, err = mongo.NewAmendmentRepository(cfg.Storage.MongoDB.Database, session)
+		must(err)
+
+		readinessChecks["repository"] = func(ctx context.Context) error {
+			return mongo.Ping(ctx, session)
+		}
+	}
+
+	domainMetrics := metrics.NewCollector(cargos)
+	if cfg.Metrics.Enabled {
+		stdprometheus.MustRegister(domainMetrics)
+	}
+
+	// Routes are cached until they expire or a voyage they use changes. Every
+	// voyage stored by this process, including those imported from an
+	// archive, goes through the cache.
+	var routeCache *routing.Cache
+	if cfg.Routing.CacheSize > 0 {
+		routeCache = routing.NewCache(
+			routing.WithCacheSize(cfg.Routing.CacheSize),
+			routing.WithCacheTTL(time.Duration(cfg.Routing.CacheTTL)),
+			routing.WithCacheCounter(domainMetrics.RouteCacheLookups()),
+		)
+		voyages = routing.NewInvalidatingVoyageRepository(voyages, routeCache)
+	}
+
+	repos := archive.Repositories{
+		Cargos:         cargos,
+		Locations:      locations,
+		Voyages:        voyages,
+		HandlingEvents: handlingEvents,
+		Amendments:     amendments,
+		Subscriptions:  subscriptions,
+	}
+
+	switch cmd := flag.Arg(0); cmd {
+	case "":
+	case "export":
+		if err := exportArchive(context.Background(), flag.Arg(1), repos); err != nil {
+			logger.Log("command", cmd, "err", err)
+			os.Exit(1)
+		}
+		return
+	case "import":
+		if err := importArchive(context.Background(), flag.Arg(1), repos); err != nil {
+			logger.Log("command", cmd, "err", err)
+			os.Exit(1)
+		}
+		return
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	tp, err := newTracerProvider(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Log("component", "tracing", "err", err)
+		os.Exit(1)
+	}
+
+	var tracer trace.Tracer
+	if tp != nil {
+		tracer = tp.Tracer("github.com/marcusolsson/goddd")
+
+		cargos = tracing.NewCargoRepository(tracer, cargos)
+		locations = tracing.NewLocationRepository(tracer, locations)
+		voyages = tracing.NewVoyageRepository(tracer, voyages)
+		handlingEvents = tracing.NewHandlingEventRepository(tracer, handlingEvents)
+		subscriptions = tracing.NewSubscriptionRepository(tracer, subscriptions)
+		deliveries = tracing.NewDeliveryRepository(tracer, deliveries)
+		routeCandidates = tracing.NewRouteCandidateRepository(tracer, routeCandidates)
+		amendments = tracing.NewAmendmentRepository(tracer, amendments)
 	}
 
 	// Configure some questionable dependencies.
@@ -90,21 +226,63 @@ func main() {
 			VoyageRepository:   voyages,
 			LocationRepository: locations,
 		}
-		handlingEventHandler = handling.NewEventHandler(
-			inspection.NewService(cargos, handlingEvents, nil),
-		)
+		trackingUpdates      = tracking.NewBroker()
+		webhooks             = webhook.NewDispatcher(subscriptions, deliveries, log.With(logger, "component", "webhook_dispatcher"))
+		handlingEventHandler = handling.EventHandlers{
+			handling.NewEventHandler(inspection.NewService(cargos, handlingEvents, webhooks)),
+			trackingUpdates,
+			domainMetrics,
+		}
 	)
 
 	// Facilitate testing by adding some cargos.
-	storeTestData(cargos)
+	storeTestData(context.Background(), cargos)
 
 	fieldKeys := []string{"method"}
 
+	routingLogger := log.With(logger, "component", "routing")
+
+	proxyOpts := []routing.ProxyOption{
+		routing.WithTimeout(time.Duration(cfg.Routing.Timeout)),
+		routing.WithRetries(cfg.Routing.Retries),
+		routing.WithFailureCounter(domainMetrics.RoutingFailures()),
+		routing.WithLogger(routingLogger),
+	}
+	if tracer != nil {
+		proxyOpts = append(proxyOpts, routing.WithTracer(tracer))
+	}
+
+	proxy, err := newRoutingProxy(cfg.Routing, routingLogger, proxyOpts...)
+	if err != nil {
+		routingLogger.Log("err", err)
+		os.Exit(1)
+	}
+
+	// While the circuit breaker is open, routes are served from the cache,
+	// even if they've expired. Without a cache, requesting routes fails.
 	var rs shipping.RoutingService
-	rs = routing.NewProxyingMiddleware(ctx, *routingServiceURL)(rs)
+	if routeCache != nil {
+		rs = routing.NewCachingMiddleware(routeCache)(proxy(routeCache.Fallback()))
+	} else {
+		rs = proxy(nil)
+	}
+
+	tariff, err := loadTariff(cfg.Booking.Tariff)
+	if err != nil {
+		logger.Log("component", "booking", "err", err)
+		os.Exit(1)
+	}
 
 	var bs booking.Service
-	bs = booking.NewService(cargos, locations, handlingEvents, rs)
+	bs = booking.NewService(cargos, locations, handlingEvents, rs, routeCandidates, amendments,
+		booking.WithRouteCandidateTTL(time.Duration(cfg.Booking.RouteTTL)),
+		booking.WithTariff(tariff),
+		booking.WithCostModels(costModels(tariff)),
+		booking.WithEventHandler(booking.EventHandlers{trackingUpdates, domainMetrics}),
+	)
+	if tracer != nil {
+		bs = booking.NewTracingService(tracer, bs)
+	}
 	bs = booking.NewLoggingService(log.With(logger, "component", "booking"), bs)
 	bs = booking.NewInstrumentingService(
 		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
@@ -124,6 +302,9 @@ func main() {
 
 	var ts tracking.Service
 	ts = tracking.NewService(cargos, handlingEvents)
+	if tracer != nil {
+		ts = tracking.NewTracingService(tracer, ts)
+	}
 	ts = tracking.NewLoggingService(log.With(logger, "component", "tracking"), ts)
 	ts = tracking.NewInstrumentingService(
 		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
@@ -143,6 +324,9 @@ func main() {
 
 	var hs handling.Service
 	hs = handling.NewService(handlingEvents, handlingEventFactory, handlingEventHandler)
+	if tracer != nil {
+		hs = handling.NewTracingService(tracer, hs)
+	}
 	hs = handling.NewLoggingService(log.With(logger, "component", "handling"), hs)
 	hs = handling.NewInstrumentingService(
 		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
@@ -160,37 +344,277 @@ func main() {
 		hs,
 	)
 
-	srv := server.New(bs, ts, hs, log.With(logger, "component", "http"))
+	var ws webhook.Service
+	ws = webhook.NewService(subscriptions, deliveries, cargos)
+	if tracer != nil {
+		ws = webhook.NewTracingService(tracer, ws)
+	}
+	ws = webhook.NewLoggingService(log.With(logger, "component", "webhook"), ws)
+	ws = webhook.NewInstrumentingService(
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "api",
+			Subsystem: "webhook_service",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, fieldKeys),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "api",
+			Subsystem: "webhook_service",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, fieldKeys),
+		ws,
+	)
+
+	var grpcOpts []grpcserver.Option
+
+	opts := []server.Option{
+		server.WithTrackingUpdates(trackingUpdates),
+		server.WithWebhooks(ws),
+		server.WithReadinessChecks(readinessChecks),
+	}
+	if tracer != nil {
+		opts = append(opts, server.WithTracer(tracer))
+	}
+	if !cfg.Metrics.Enabled {
+		opts = append(opts, server.WithMetricsPath(""))
+	} else if cfg.Metrics.Path != "/metrics" {
+		opts = append(opts, server.WithMetricsPath(cfg.Metrics.Path))
+	}
+	if len(cfg.RateLimits) > 0 {
+		opts = append(opts, server.WithRateLimits(cfg.rateLimits(), kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "api",
+			Subsystem: "http",
+			Name:      "throttled_request_count",
+			Help:      "Number of requests rejected for exceeding the rate limit.",
+		}, []string{"router", "client"})))
+		grpcOpts = append(grpcOpts, grpcserver.WithRateLimits(cfg.rateLimits(), kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "api",
+			Subsystem: "grpc",
+			Name:      "throttled_request_count",
+			Help:      "Number of calls rejected for exceeding the rate limit.",
+		}, []string{"router", "client"})))
+	}
+	if len(cfg.HTTP.Origins) > 0 {
+		opts = append(opts, server.WithAllowedOrigins(cfg.HTTP.Origins...))
+	}
+	if cfg.Auth.APIKeys != "" || cfg.Auth.JWKS != "" {
+		a, err := loadAuthenticator(cfg.Auth.APIKeys, cfg.Auth.JWKS, cfg.Auth.Issuer, cfg.Auth.Audience)
+		if err != nil {
+			logger.Log("component", "auth", "err", err)
+			os.Exit(1)
+		}
+
+		var anonymous []auth.Role
+		if cfg.Auth.AnonymousTracking {
+			anonymous = append(anonymous, auth.RolePublic)
+		}
+		opts = append(opts, server.WithAuthentication(a, anonymous...))
+		grpcOpts = append(grpcOpts, grpcserver.WithAuthentication(a, anonymous...))
+	}
+
+	if cfg.HTTP.Validate {
+		opts = append(opts, server.WithRequestValidation())
+	}
+
+	srv := server.New(bs, ts, hs, log.With(logger, "component", "http"), opts...)
+
+	gs := grpcserver.New(bs, ts, hs, log.With(logger, "component", "grpc"), grpcOpts...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go webhooks.Run(ctx)
+
+	httpServer := &http.Server{Addr: cfg.HTTP.Addr, Handler: srv}
 
 	errs := make(chan error, 2)
 	go func() {
-		logger.Log("transport", "http", "address", *httpAddr, "msg", "listening")
-		errs <- http.ListenAndServe(*httpAddr, srv)
+		logger.Log("transport", "http", "address", cfg.HTTP.Addr, "msg", "listening")
+		if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+			errs <- err
+		}
 	}()
 	go func() {
-		c := make(chan os.Signal, 1)
-		signal.Notify(c, syscall.SIGINT)
-		errs <- fmt.Errorf("%s", <-c)
+		lis, err := net.Listen("tcp", cfg.GRPC.Addr)
+		if err != nil {
+			errs <- err
+			return
+		}
+		logger.Log("transport", "grpc", "address", cfg.GRPC.Addr, "msg", "listening")
+		if err := gs.Serve(lis); err != nil {
+			errs <- err
+		}
 	}()
 
-	logger.Log("terminated", <-errs)
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-errs:
+		logger.Log("terminated", err)
+		os.Exit(1)
+	case s := <-sig:
+		logger.Log("msg", "shutting down", "signal", s)
+	}
+
+	// Stop accepting new requests and let those in flight finish.
+	srv.Drain()
+	time.Sleep(time.Duration(cfg.Shutdown.Delay))
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), time.Duration(cfg.Shutdown.Timeout))
+	defer cancelShutdown()
+
+	go func() {
+		<-shutdownCtx.Done()
+		gs.Stop()
+	}()
+	gs.GracefulStop()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		logger.Log("transport", "http", "msg", "shutdown", "err", err)
+	}
+
+	// Stop delivering webhooks once no more events can be registered.
+	cancel()
+
+	if tp != nil {
+		if err := tp.Shutdown(shutdownCtx); err != nil {
+			logger.Log("component", "tracing", "msg", "shutdown", "err", err)
+		}
+	}
+
+	logger.Log("terminated", "shutdown complete")
+}
+
+// newRoutingProxy returns a proxying middleware for the instances of the
+// routing service given by cfg.
+func newRoutingProxy(cfg routingConfig, logger log.Logger, opts ...routing.ProxyOption) (routing.ServiceMiddleware, error) {
+	refresh := time.Duration(cfg.Refresh)
+	switch {
+	case len(cfg.Instances) > 0:
+		return routing.NewBalancingMiddleware(sd.FixedInstancer(cfg.Instances), opts...), nil
+	case cfg.SRV != "":
+		return routing.NewBalancingMiddleware(dnssrv.NewInstancer(cfg.SRV, refresh, logger), opts...), nil
+	case cfg.InstancesFile != "":
+		return routing.NewBalancingMiddleware(routing.NewFileInstancer(cfg.InstancesFile, refresh, logger), opts...), nil
+	}
+	return routing.NewProxyingMiddleware(cfg.URL, opts...)
+}
+
+// dialMongoDB connects to MongoDB, retrying with exponential backoff so that
+// the service can be started at the same time as the database.
+func dialMongoDB(url string, attempts int, logger log.Logger) (*mgo.Session, error) {
+	backoff := time.Second
+
+	for attempt := 1; ; attempt++ {
+		session, err := mgo.DialWithTimeout(url, 10*time.Second)
+		if err == nil {
+			return session, nil
+		}
+		if attempt >= attempts {
+			return nil, fmt.Errorf("giving up after %d attempts: %v", attempt, err)
+		}
+
+		logger.Log("msg", "retrying", "attempt", attempt, "backoff", backoff, "err", err)
+		time.Sleep(backoff)
+
+		if backoff *= 2; backoff > 30*time.Second {
+			backoff = 30 * time.Second
+		}
+	}
+}
+
+// loadTariff reads the tariff in the file at path, or returns the sample
+// tariff if path is empty.
+func loadTariff(path string) (*pricing.Tariff, error) {
+	if path == "" {
+		return pricing.SampleTariff(), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t, err := pricing.LoadTariff(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return t, nil
+}
+
+// costModels returns the predefined cost models, with the cheapest route
+// being the one with the lowest freight in t.
+func costModels(t *pricing.Tariff) map[string]shipping.CostModel {
+	models := shipping.DefaultCostModels()
+	models[shipping.StrategyCheapest] = shipping.WeightedCostModel{LegCost: 1, CostOfLeg: t.LegCost}
+	return models
+}
+
+// loadAuthenticator returns an authenticator of the API keys and bearer
+// tokens signed by the keys in the given files. Either file may be empty.
+func loadAuthenticator(keysFile, jwksFile, issuer, audience string) (auth.Authenticator, error) {
+	var as auth.Authenticators
+
+	if keysFile != "" {
+		f, err := os.Open(keysFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		keys, err := auth.LoadAPIKeys(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", keysFile, err)
+		}
+		as = append(as, keys)
+	}
+
+	if jwksFile != "" {
+		f, err := os.Open(jwksFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		ks, err := auth.LoadKeySet(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", jwksFile, err)
+		}
+
+		j := auth.NewJWT(ks)
+		j.Issuer = issuer
+		j.Audience = audience
+		as = append(as, j)
+	}
+
+	return as, nil
 }
 
-func envString(env, fallback string) string {
-	e := os.Getenv(env)
-	if e == "" {
-		return fallback
+// logLevel returns the filter option that allows the named level and those
+// more severe.
+func logLevel(name string) level.Option {
+	switch name {
+	case "debug":
+		return level.AllowDebug()
+	case "warn":
+		return level.AllowWarn()
+	case "error":
+		return level.AllowError()
+	default:
+		return level.AllowInfo()
 	}
-	return e
 }
 
-func storeTestData(r shipping.CargoRepository) {
+func storeTestData(ctx context.Context, r shipping.CargoRepository) {
 	test1 := shipping.NewCargo("FTL456", shipping.RouteSpecification{
 		Origin:          shipping.AUMEL,
 		Destination:     shipping.SESTO,
 		ArrivalDeadline: time.Now().AddDate(0, 0, 7),
 	})
-	if err := r.Store(test1); err != nil {
+	if err := r.Store(ctx, test1); err != nil {
 		panic(err)
 	}
 
@@ -199,7 +623,7 @@ func storeTestData(r shipping.CargoRepository) {
 		Destination:     shipping.CNHKG,
 		ArrivalDeadline: time.Now().AddDate(0, 0, 14),
 	})
-	if err := r.Store(test2); err != nil {
+	if err := r.Store(ctx, test2); err != nil {
 		panic(err)
 	}
 }
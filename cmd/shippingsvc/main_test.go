@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -22,6 +23,8 @@ var _ = Suite(&S{})
 func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	var err error
 
+	ctx := context.Background()
+
 	var (
 		cargoRepository         = inmem.NewCargoRepository()
 		locationRepository      = inmem.NewLocationRepository()
@@ -42,7 +45,7 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	handlingEventHandler := &stubHandlingEventHandler{cargoInspectionService}
 
 	var (
-		bookingService       = booking.NewService(cargoRepository, locationRepository, handlingEventRepository, routingService)
+		bookingService       = booking.NewService(cargoRepository, locationRepository, handlingEventRepository, routingService, inmem.NewRouteCandidateRepository(), inmem.NewAmendmentRepository())
 		handlingEventService = handling.NewService(handlingEventRepository, handlingEventFactory, handlingEventHandler)
 	)
 
@@ -56,11 +59,11 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	// Use case 1: booking
 	//
 
-	id, err := bookingService.BookNewCargo(origin, destination, deadline)
+	id, err := bookingService.BookNewCargo(ctx, shipping.RouteSpecification{Origin: origin, Destination: destination, ArrivalDeadline: deadline})
 
 	chk.Assert(err, IsNil)
 
-	c, err := cargoRepository.Find(id)
+	c, err := cargoRepository.Find(ctx, id)
 
 	chk.Assert(err, IsNil)
 	chk.Check(c.Delivery.TransportStatus, Equals, shipping.NotReceived)
@@ -74,12 +77,13 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	// Use case 2: routing
 	//
 
-	itineraries := bookingService.RequestPossibleRoutesForCargo(id)
+	itineraries, err := bookingService.RequestPossibleRoutesForCargo(ctx, id, "")
+	chk.Assert(err, IsNil)
 	itinerary := selectPreferredItinerary(itineraries)
 
 	c.AssignToRoute(itinerary)
 
-	cargoRepository.Store(c)
+	cargoRepository.Store(ctx, c)
 
 	chk.Check(c.Delivery.TransportStatus, Equals, shipping.NotReceived)
 	chk.Check(c.Delivery.RoutingStatus, Equals, shipping.Routed)
@@ -92,20 +96,20 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	// Use case 3: handling
 	//
 
-	err = handlingEventService.RegisterHandlingEvent(toDate(2009, time.March, 1), id, "", shipping.CNHKG, shipping.Receive)
+	err = handlingEventService.RegisterHandlingEvent(ctx, toDate(2009, time.March, 1), id, "", shipping.CNHKG, shipping.Receive)
 	chk.Check(err, IsNil)
 
 	// Ensure we're not working with stale shipping.
-	c, err = cargoRepository.Find(id)
+	c, err = cargoRepository.Find(ctx, id)
 
 	chk.Check(c.Delivery.TransportStatus, Equals, shipping.InPort)
 	chk.Check(c.Delivery.LastKnownLocation, Equals, shipping.CNHKG)
 	chk.Check(c.Delivery.Itinerary.IsEmpty(), Equals, false)
 
-	err = handlingEventService.RegisterHandlingEvent(toDate(2009, time.March, 3), id, shipping.V100.VoyageNumber, shipping.CNHKG, shipping.Load)
+	err = handlingEventService.RegisterHandlingEvent(ctx, toDate(2009, time.March, 3), id, shipping.V100.VoyageNumber, shipping.CNHKG, shipping.Load)
 	chk.Check(err, IsNil)
 
-	c, err = cargoRepository.Find(id)
+	c, err = cargoRepository.Find(ctx, id)
 
 	chk.Check(c.Delivery.TransportStatus, Equals, shipping.OnboardCarrier)
 	chk.Check(c.Delivery.LastKnownLocation, Equals, shipping.CNHKG)
@@ -122,17 +126,17 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 
 	noSuchVoyageNumber := shipping.VoyageNumber("XX000")
 	noSuchUNLocode := shipping.UNLocode("ZZZZZ")
-	err = handlingEventService.RegisterHandlingEvent(toDate(2009, time.March, 5), id, noSuchVoyageNumber, noSuchUNLocode, shipping.Load)
+	err = handlingEventService.RegisterHandlingEvent(ctx, toDate(2009, time.March, 5), id, noSuchVoyageNumber, noSuchUNLocode, shipping.Load)
 	chk.Check(err, NotNil)
 
 	//
 	// Cargo is incorrectly unloaded in Tokyo
 	//
 
-	err = handlingEventService.RegisterHandlingEvent(toDate(2009, time.March, 5), id, shipping.V100.VoyageNumber, shipping.JNTKO, shipping.Unload)
+	err = handlingEventService.RegisterHandlingEvent(ctx, toDate(2009, time.March, 5), id, shipping.V100.VoyageNumber, shipping.JNTKO, shipping.Unload)
 	chk.Check(err, IsNil)
 
-	c, err = cargoRepository.Find(id)
+	c, err = cargoRepository.Find(ctx, id)
 
 	chk.Check(c.Delivery.LastKnownLocation, Equals, shipping.JNTKO)
 	chk.Check(c.Delivery.TransportStatus, Equals, shipping.InPort)
@@ -156,18 +160,19 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	// Specify a new route, this time from Tokyo (where it was incorrectly unloaded) to Stockholm
 	c.SpecifyNewRoute(rs)
 
-	cargoRepository.Store(c)
+	cargoRepository.Store(ctx, c)
 
 	chk.Check(c.Delivery.RoutingStatus, Equals, shipping.Misrouted)
 	chk.Check(c.Delivery.NextExpectedActivity, Equals, shipping.HandlingActivity{})
 
 	// Repeat procedure of selecting one out of a number of possible routes satisfying the route spec
-	newItineraries := bookingService.RequestPossibleRoutesForCargo(id)
+	newItineraries, err := bookingService.RequestPossibleRoutesForCargo(ctx, id, "")
+	chk.Assert(err, IsNil)
 	newItinerary := selectPreferredItinerary(newItineraries)
 
 	c.AssignToRoute(newItinerary)
 
-	cargoRepository.Store(c)
+	cargoRepository.Store(ctx, c)
 
 	chk.Check(c.Delivery.RoutingStatus, Equals, shipping.Routed)
 
@@ -176,10 +181,10 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	//
 
 	// Load in Tokyo
-	err = handlingEventService.RegisterHandlingEvent(toDate(2009, time.March, 8), id, shipping.V300.VoyageNumber, shipping.JNTKO, shipping.Load)
+	err = handlingEventService.RegisterHandlingEvent(ctx, toDate(2009, time.March, 8), id, shipping.V300.VoyageNumber, shipping.JNTKO, shipping.Load)
 	chk.Check(err, IsNil)
 
-	c, err = cargoRepository.Find(id)
+	c, err = cargoRepository.Find(ctx, id)
 
 	chk.Check(c.Delivery.LastKnownLocation, Equals, shipping.JNTKO)
 	chk.Check(c.Delivery.TransportStatus, Equals, shipping.OnboardCarrier)
@@ -188,10 +193,10 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	chk.Check(c.Delivery.NextExpectedActivity, Equals, shipping.HandlingActivity{Type: shipping.Unload, Location: shipping.DEHAM, VoyageNumber: shipping.V300.VoyageNumber})
 
 	// Unload in Hamburg
-	err = handlingEventService.RegisterHandlingEvent(toDate(2009, time.March, 12), id, shipping.V300.VoyageNumber, shipping.DEHAM, shipping.Unload)
+	err = handlingEventService.RegisterHandlingEvent(ctx, toDate(2009, time.March, 12), id, shipping.V300.VoyageNumber, shipping.DEHAM, shipping.Unload)
 	chk.Check(err, IsNil)
 
-	c, err = cargoRepository.Find(id)
+	c, err = cargoRepository.Find(ctx, id)
 
 	chk.Check(c.Delivery.LastKnownLocation, Equals, shipping.DEHAM)
 	chk.Check(c.Delivery.TransportStatus, Equals, shipping.InPort)
@@ -200,10 +205,10 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	chk.Check(c.Delivery.NextExpectedActivity, Equals, shipping.HandlingActivity{Type: shipping.Load, Location: shipping.DEHAM, VoyageNumber: shipping.V400.VoyageNumber})
 
 	// Load in Hamburg
-	err = handlingEventService.RegisterHandlingEvent(toDate(2009, time.March, 14), id, shipping.V400.VoyageNumber, shipping.DEHAM, shipping.Load)
+	err = handlingEventService.RegisterHandlingEvent(ctx, toDate(2009, time.March, 14), id, shipping.V400.VoyageNumber, shipping.DEHAM, shipping.Load)
 	chk.Check(err, IsNil)
 
-	c, err = cargoRepository.Find(id)
+	c, err = cargoRepository.Find(ctx, id)
 
 	chk.Check(c.Delivery.LastKnownLocation, Equals, shipping.DEHAM)
 	chk.Check(c.Delivery.TransportStatus, Equals, shipping.OnboardCarrier)
@@ -212,10 +217,10 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	chk.Check(c.Delivery.NextExpectedActivity, Equals, shipping.HandlingActivity{Type: shipping.Unload, Location: shipping.SESTO, VoyageNumber: shipping.V400.VoyageNumber})
 
 	// Unload in Stockholm
-	err = handlingEventService.RegisterHandlingEvent(toDate(2009, time.March, 15), id, shipping.V400.VoyageNumber, shipping.SESTO, shipping.Unload)
+	err = handlingEventService.RegisterHandlingEvent(ctx, toDate(2009, time.March, 15), id, shipping.V400.VoyageNumber, shipping.SESTO, shipping.Unload)
 	chk.Check(err, IsNil)
 
-	c, err = cargoRepository.Find(id)
+	c, err = cargoRepository.Find(ctx, id)
 
 	chk.Check(c.Delivery.LastKnownLocation, Equals, shipping.SESTO)
 	chk.Check(c.Delivery.TransportStatus, Equals, shipping.InPort)
@@ -224,10 +229,10 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	chk.Check(c.Delivery.NextExpectedActivity, Equals, shipping.HandlingActivity{Type: shipping.Claim, Location: shipping.SESTO})
 
 	// Finally, cargo is claimed in Stockholm. This ends the cargo lifecycle from our perspective.
-	err = handlingEventService.RegisterHandlingEvent(toDate(2009, time.March, 16), id, shipping.V400.VoyageNumber, shipping.SESTO, shipping.Claim)
+	err = handlingEventService.RegisterHandlingEvent(ctx, toDate(2009, time.March, 16), id, shipping.V400.VoyageNumber, shipping.SESTO, shipping.Claim)
 	chk.Check(err, IsNil)
 
-	c, _ = cargoRepository.Find(id)
+	c, _ = cargoRepository.Find(ctx, id)
 
 	chk.Check(c.Delivery.LastKnownLocation, Equals, shipping.SESTO)
 	chk.Check(c.Delivery.TransportStatus, Equals, shipping.Claimed)
@@ -236,8 +241,8 @@ func (s *S) TestCargoFromHongkongToStockholm(chk *C) {
 	chk.Check(c.Delivery.NextExpectedActivity, Equals, shipping.HandlingActivity{})
 }
 
-func selectPreferredItinerary(itineraries []shipping.Itinerary) shipping.Itinerary {
-	return itineraries[0]
+func selectPreferredItinerary(candidates []booking.RouteCandidate) shipping.Itinerary {
+	return candidates[0].Itinerary
 }
 
 func toDate(year int, month time.Month, day int) time.Time {
@@ -247,7 +252,7 @@ func toDate(year int, month time.Month, day int) time.Time {
 // Stub RoutingService
 type stubRoutingService struct{}
 
-func (s *stubRoutingService) FetchRoutesForSpecification(rs shipping.RouteSpecification) []shipping.Itinerary {
+func (s *stubRoutingService) FetchRoutesForSpecification(_ context.Context, rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
 	if rs.Origin == shipping.CNHKG {
 		return []shipping.Itinerary{
 			{Legs: []shipping.Leg{
@@ -255,7 +260,7 @@ func (s *stubRoutingService) FetchRoutesForSpecification(rs shipping.RouteSpecif
 				shipping.NewLeg("V200", shipping.USNYC, shipping.USCHI, toDate(2009, time.March, 10), toDate(2009, time.March, 14)),
 				shipping.NewLeg("V300", shipping.USCHI, shipping.SESTO, toDate(2009, time.March, 7), toDate(2009, time.March, 11)),
 			}},
-		}
+		}, nil
 	}
 
 	return []shipping.Itinerary{
@@ -263,7 +268,7 @@ func (s *stubRoutingService) FetchRoutesForSpecification(rs shipping.RouteSpecif
 			shipping.NewLeg("V300", shipping.JNTKO, shipping.DEHAM, toDate(2009, time.March, 8), toDate(2009, time.March, 12)),
 			shipping.NewLeg("V400", shipping.DEHAM, shipping.SESTO, toDate(2009, time.March, 14), toDate(2009, time.March, 15)),
 		}},
-	}
+	}, nil
 }
 
 // Stub HandlingEventHandler
@@ -271,16 +276,16 @@ type stubHandlingEventHandler struct {
 	InspectionService inspection.Service
 }
 
-func (h *stubHandlingEventHandler) CargoWasHandled(event shipping.HandlingEvent) {
-	h.InspectionService.InspectCargo(event.TrackingID)
+func (h *stubHandlingEventHandler) CargoWasHandled(ctx context.Context, event shipping.HandlingEvent) {
+	h.InspectionService.InspectCargo(ctx, event.TrackingID)
 }
 
 // Stub CargoEventHandler
 type stubCargoEventHandler struct {
 }
 
-func (h *stubCargoEventHandler) CargoWasMisdirected(c *shipping.Cargo) {
+func (h *stubCargoEventHandler) CargoWasMisdirected(_ context.Context, c *shipping.Cargo) {
 }
 
-func (h *stubCargoEventHandler) CargoHasArrived(c *shipping.Cargo) {
+func (h *stubCargoEventHandler) CargoHasArrived(_ context.Context, c *shipping.Cargo) {
 }
@@ -0,0 +1,173 @@
+package shipping
+
+import (
+	"sort"
+	"time"
+)
+
+// Cost criteria, i.e. the aspects of an itinerary that a cost model weighs.
+const (
+	// CriterionTransitTime is the number of hours from the first departure
+	// to the final arrival.
+	CriterionTransitTime = "transit_time"
+
+	// CriterionTransshipments is the number of times the cargo changes
+	// voyage.
+	CriterionTransshipments = "transshipments"
+
+	// CriterionLegCost is the sum of the costs of the legs.
+	CriterionLegCost = "leg_cost"
+
+	// CriterionDeadlineSlack is the number of hours to spare between the
+	// final arrival and the arrival deadline.
+	CriterionDeadlineSlack = "deadline_slack"
+)
+
+// Cost is what a single criterion contributes to the score of an itinerary.
+type Cost struct {
+	Criterion string  `json:"criterion"`
+	Value     float64 `json:"value"`
+	Weight    float64 `json:"weight"`
+	Score     float64 `json:"score"`
+}
+
+// CostModel breaks down the cost of an itinerary for a route specification.
+// The score of the itinerary is the sum of the scores of its costs, and lower
+// scores are better.
+type CostModel interface {
+	Costs(rs RouteSpecification, itinerary Itinerary) []Cost
+}
+
+// WeightedCostModel scores itineraries by a weighted sum of the cost
+// criteria. Criteria with a zero weight are left out. A negative weight turns
+// a criterion into a benefit, which is how deadline slack is usually weighed.
+type WeightedCostModel struct {
+	TransitTime    float64
+	Transshipments float64
+	LegCost        float64
+	DeadlineSlack  float64
+
+	// CostOfLeg returns the cost of a leg. If nil, every leg costs 1.
+	CostOfLeg func(Leg) float64
+}
+
+// Costs implements CostModel.
+func (m WeightedCostModel) Costs(rs RouteSpecification, itinerary Itinerary) []Cost {
+	var costs []Cost
+	add := func(criterion string, weight, value float64) {
+		if weight != 0 {
+			costs = append(costs, Cost{Criterion: criterion, Value: value, Weight: weight, Score: weight * value})
+		}
+	}
+
+	add(CriterionTransitTime, m.TransitTime, transitTime(itinerary).Hours())
+	add(CriterionTransshipments, m.Transshipments, float64(transshipments(itinerary)))
+	add(CriterionLegCost, m.LegCost, m.legCost(itinerary))
+	add(CriterionDeadlineSlack, m.DeadlineSlack, deadlineSlack(rs, itinerary).Hours())
+
+	return costs
+}
+
+func (m WeightedCostModel) legCost(itinerary Itinerary) float64 {
+	var sum float64
+	for _, l := range itinerary.Legs {
+		if m.CostOfLeg == nil {
+			sum++
+		} else {
+			sum += m.CostOfLeg(l)
+		}
+	}
+	return sum
+}
+
+func transitTime(itinerary Itinerary) time.Duration {
+	if itinerary.IsEmpty() {
+		return 0
+	}
+	dep, arr := itinerary.Legs[0].LoadTime, itinerary.FinalArrivalTime()
+	if dep.IsZero() || arr.IsZero() {
+		return 0
+	}
+	return arr.Sub(dep)
+}
+
+func transshipments(itinerary Itinerary) int {
+	var n int
+	for i := 1; i < len(itinerary.Legs); i++ {
+		if itinerary.Legs[i].VoyageNumber != itinerary.Legs[i-1].VoyageNumber {
+			n++
+		}
+	}
+	return n
+}
+
+func deadlineSlack(rs RouteSpecification, itinerary Itinerary) time.Duration {
+	if itinerary.IsEmpty() {
+		return 0
+	}
+	arr := itinerary.FinalArrivalTime()
+	if arr.IsZero() || rs.ArrivalDeadline.IsZero() {
+		return 0
+	}
+	return rs.ArrivalDeadline.Sub(arr)
+}
+
+// Ranking strategies, i.e. the names of the predefined cost models.
+const (
+	StrategyBalanced             = "balanced"
+	StrategyFastest              = "fastest"
+	StrategyFewestTransshipments = "fewest_transshipments"
+	StrategyCheapest             = "cheapest"
+	StrategyMostSlack            = "most_slack"
+	DefaultStrategy              = StrategyBalanced
+)
+
+// DefaultCostModels returns the predefined cost models by ranking strategy.
+// Without prices at hand, the cheapest route is the one with the fewest legs;
+// set CostOfLeg of the StrategyCheapest model to rank by price instead.
+func DefaultCostModels() map[string]CostModel {
+	return map[string]CostModel{
+		// A transshipment weighs as much as a day at sea, and a day to
+		// spare makes up for half a day at sea.
+		StrategyBalanced: WeightedCostModel{
+			TransitTime:    1,
+			Transshipments: 24,
+			LegCost:        1,
+			DeadlineSlack:  -0.5,
+		},
+		StrategyFastest:              WeightedCostModel{TransitTime: 1},
+		StrategyFewestTransshipments: WeightedCostModel{Transshipments: 1},
+		StrategyCheapest:             WeightedCostModel{LegCost: 1},
+		StrategyMostSlack:            WeightedCostModel{DeadlineSlack: -1},
+	}
+}
+
+// RouteCandidate is an itinerary considered for a cargo, along with its
+// score and the costs that make it up.
+type RouteCandidate struct {
+	Itinerary
+	Score float64 `json:"score"`
+	Costs []Cost  `json:"costs"`
+}
+
+// RankRoutes scores itineraries with m and orders them from the lowest score
+// to the highest. Itineraries with equal scores keep their order.
+func RankRoutes(m CostModel, rs RouteSpecification, itineraries []Itinerary) []RouteCandidate {
+	candidates := make([]RouteCandidate, 0, len(itineraries))
+	for _, it := range itineraries {
+		c := RouteCandidate{Itinerary: it, Costs: m.Costs(rs, it)}
+		if c.Costs == nil {
+			c.Costs = []Cost{}
+		}
+		for _, cost := range c.Costs {
+			c.Score += cost.Score
+		}
+		candidates = append(candidates, c)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score < candidates[j].Score
+	})
+
+	return candidates
+}
@@ -0,0 +1,97 @@
+package shipping
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRankRoutes(t *testing.T) {
+	date := func(day int) time.Time {
+		return time.Date(2009, time.March, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	rs := RouteSpecification{
+		Origin:          SESTO,
+		Destination:     AUMEL,
+		ArrivalDeadline: date(25),
+	}
+
+	var (
+		// Arrives on day 20 after 14 days, changing voyage once.
+		slow = Itinerary{Legs: []Leg{
+			NewLeg("V1", SESTO, CNHKG, date(6), date(12)),
+			NewLeg("V2", CNHKG, AUMEL, date(14), date(20)),
+		}}
+		// Arrives on day 16 after 8 days, changing voyage twice.
+		fast = Itinerary{Legs: []Leg{
+			NewLeg("V3", SESTO, DEHAM, date(8), date(9)),
+			NewLeg("V4", DEHAM, CNHKG, date(9), date(13)),
+			NewLeg("V5", CNHKG, AUMEL, date(13), date(16)),
+		}}
+		// Arrives on day 18 after 10 days on the same voyage.
+		direct = Itinerary{Legs: []Leg{
+			NewLeg("V6", SESTO, FIHEL, date(8), date(9)),
+			NewLeg("V6", FIHEL, AUMEL, date(9), date(18)),
+		}}
+	)
+
+	for _, tt := range []struct {
+		strategy string
+		want     []Itinerary
+	}{
+		{StrategyFastest, []Itinerary{fast, direct, slow}},
+		{StrategyFewestTransshipments, []Itinerary{direct, slow, fast}},
+		{StrategyMostSlack, []Itinerary{fast, direct, slow}},
+		{StrategyBalanced, []Itinerary{fast, direct, slow}},
+	} {
+		got := RankRoutes(DefaultCostModels()[tt.strategy], rs, []Itinerary{slow, fast, direct})
+		for i := range tt.want {
+			if got[i].Legs[0].VoyageNumber != tt.want[i].Legs[0].VoyageNumber {
+				t.Errorf("%s: got[%d] starts with %s; want = %s", tt.strategy, i, got[i].Legs[0].VoyageNumber, tt.want[i].Legs[0].VoyageNumber)
+			}
+		}
+	}
+}
+
+func TestWeightedCostModel(t *testing.T) {
+	date := func(day int) time.Time {
+		return time.Date(2009, time.March, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	rs := RouteSpecification{Origin: SESTO, Destination: AUMEL, ArrivalDeadline: date(20)}
+	it := Itinerary{Legs: []Leg{
+		NewLeg("V1", SESTO, CNHKG, date(6), date(12)),
+		NewLeg("V2", CNHKG, AUMEL, date(14), date(18)),
+	}}
+
+	m := WeightedCostModel{
+		TransitTime:    1,
+		Transshipments: 10,
+		LegCost:        2,
+		DeadlineSlack:  -1,
+		CostOfLeg: func(l Leg) float64 {
+			return l.UnloadTime.Sub(l.LoadTime).Hours() / 24
+		},
+	}
+
+	want := []Cost{
+		{CriterionTransitTime, 288, 1, 288},
+		{CriterionTransshipments, 1, 10, 10},
+		{CriterionLegCost, 10, 2, 20},
+		{CriterionDeadlineSlack, 48, -1, -48},
+	}
+
+	got := m.Costs(rs, it)
+	if len(got) != len(want) {
+		t.Fatalf("len(costs) = %d; want = %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("costs[%d] = %+v; want = %+v", i, got[i], want[i])
+		}
+	}
+
+	if c := RankRoutes(m, rs, []Itinerary{it}); c[0].Score != 270 {
+		t.Errorf("Score = %v; want = %v", c[0].Score, 270)
+	}
+}
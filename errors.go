@@ -0,0 +1,85 @@
+package shipping
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrorCode is a stable, machine-readable identifier for a kind of error.
+// Clients may rely on codes not changing, while messages may.
+type ErrorCode string
+
+// Error codes used by the domain and the services built on top of it.
+const (
+	CodeInvalidArgument       ErrorCode = "invalid_argument"
+	CodeInvalidCursor         ErrorCode = "invalid_cursor"
+	CodeUnknownCargo          ErrorCode = "unknown_cargo"
+	CodeUnknownLocation       ErrorCode = "unknown_location"
+	CodeUnknownVoyage         ErrorCode = "unknown_voyage"
+	CodeUnknownSubscription   ErrorCode = "unknown_subscription"
+	CodeUnauthenticated       ErrorCode = "unauthenticated"
+	CodePermissionDenied      ErrorCode = "permission_denied"
+	CodeRoutingUnavailable    ErrorCode = "routing_unavailable"
+	CodeUnknownRouteCandidate ErrorCode = "unknown_route_candidate"
+	CodeStaleRouteCandidate   ErrorCode = "stale_route_candidate"
+	CodeNoRate                ErrorCode = "no_rate"
+	CodeCargoReceived         ErrorCode = "cargo_received"
+	CodeRateLimited           ErrorCode = "rate_limited"
+)
+
+// FieldError describes why a single field of a request was rejected.
+type FieldError struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Error is a domain error. Errors with the same code are considered equal by
+// errors.Is, regardless of their message and fields.
+type Error struct {
+	Code    ErrorCode
+	Message string
+
+	// Fields optionally lists the fields that caused the error.
+	Fields []FieldError
+}
+
+// NewError returns a new error with the given code and message.
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+
+	var fields []string
+	for _, f := range e.Fields {
+		fields = append(fields, f.Name+": "+f.Reason)
+	}
+	return e.Message + " (" + strings.Join(fields, ", ") + ")"
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithFields returns a copy of e that also holds the given field errors.
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	return &Error{
+		Code:    e.Code,
+		Message: e.Message,
+		Fields:  append(append([]FieldError(nil), e.Fields...), fields...),
+	}
+}
+
+// ErrorCodeOf returns the code of err if it is, or wraps, an *Error.
+func ErrorCodeOf(err error) (ErrorCode, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code, true
+	}
+	return "", false
+}
@@ -0,0 +1,94 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/marcusolsson/goddd/auth"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/pb"
+)
+
+// serviceRoles holds the roles required by each service. They're the same
+// as those required by the corresponding HTTP routers.
+var serviceRoles = map[string][]auth.Role{
+	pb.BookingService_ServiceDesc.ServiceName:  {auth.RoleAdmin},
+	pb.TrackingService_ServiceDesc.ServiceName: {auth.RolePublic, auth.RoleHandler},
+	pb.HandlingService_ServiceDesc.ServiceName: {auth.RoleHandler},
+}
+
+// authenticate identifies the client of each call, adds its principal to the
+// call context and only lets it through if it has any of the roles required
+// by the service. Clients without credentials are given the anonymous roles,
+// if any.
+func (c *config) authenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	p, err := c.authenticator.Authenticate(ctx, credentials(ctx))
+	switch {
+	case err == auth.ErrNoCredentials:
+		if len(c.anonymousRoles) == 0 {
+			return nil, encodeError(auth.ErrUnauthenticated)
+		}
+		p = &auth.Principal{Subject: "anonymous", Roles: c.anonymousRoles, Anonymous: true}
+	case err != nil:
+		return nil, encodeError(err)
+	}
+
+	roles, ok := serviceRoles[serviceName(info.FullMethod)]
+	if !ok {
+		return nil, encodeError(auth.ErrPermissionDenied)
+	}
+
+	// Anonymous clients are asked to authenticate rather than being denied
+	// outright.
+	if p.Anonymous && !p.HasRole(roles...) {
+		return nil, encodeError(auth.ErrUnauthenticated)
+	}
+	if !p.HasRole(roles...) {
+		return nil, encodeError(auth.ErrPermissionDenied)
+	}
+
+	return handler(auth.NewContext(ctx, p), req)
+}
+
+// bookingActor passes the subject of the authenticated client on to the
+// booking service, as the actor of the changes it makes.
+func bookingActor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if p, ok := auth.FromContext(ctx); ok && !p.Anonymous && serviceName(info.FullMethod) == pb.BookingService_ServiceDesc.ServiceName {
+		ctx = booking.NewActorContext(ctx, p.Subject)
+	}
+	return handler(ctx, req)
+}
+
+// credentials returns the credentials in the metadata of ctx. API keys are
+// given in the x-api-key key, and bearer tokens in the authorization key.
+func credentials(ctx context.Context) auth.Credentials {
+	var c auth.Credentials
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return c
+	}
+
+	if v := md.Get("x-api-key"); len(v) > 0 {
+		c.APIKey = v[0]
+	}
+
+	if v := md.Get("authorization"); len(v) > 0 && len(v[0]) > 7 && strings.EqualFold(v[0][:7], "Bearer ") {
+		c.BearerToken = strings.TrimSpace(v[0][7:])
+	}
+
+	return c
+}
+
+// serviceName returns the service of a full method name, such as
+// "shipping.v1.BookingService" for "/shipping.v1.BookingService/LoadCargo".
+func serviceName(fullMethod string) string {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,440 @@
+package grpcserver
+
+import (
+	"context"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/pb"
+)
+
+type bookingServer struct {
+	pb.UnimplementedBookingServiceServer
+
+	bookNewCargo                  kitgrpc.Handler
+	loadCargo                     kitgrpc.Handler
+	requestPossibleRoutesForCargo kitgrpc.Handler
+	quoteRoute                    kitgrpc.Handler
+	assignCargoToRoute            kitgrpc.Handler
+	assignCargoToRouteCandidate   kitgrpc.Handler
+	changeDestination             kitgrpc.Handler
+	changeArrivalDeadline         kitgrpc.Handler
+	changeOrigin                  kitgrpc.Handler
+	history                       kitgrpc.Handler
+	listCargos                    kitgrpc.Handler
+	listLocations                 kitgrpc.Handler
+}
+
+func newBookingServer(e booking.Endpoints, opts []kitgrpc.ServerOption) pb.BookingServiceServer {
+	return &bookingServer{
+		bookNewCargo:                  kitgrpc.NewServer(e.BookNewCargoEndpoint, decodeBookNewCargoRequest, encodeBookNewCargoResponse, opts...),
+		loadCargo:                     kitgrpc.NewServer(e.LoadCargoEndpoint, decodeLoadCargoRequest, encodeLoadCargoResponse, opts...),
+		requestPossibleRoutesForCargo: kitgrpc.NewServer(e.RequestPossibleRoutesForCargoEndpoint, decodeRequestPossibleRoutesForCargoRequest, encodeRequestPossibleRoutesForCargoResponse, opts...),
+		quoteRoute:                    kitgrpc.NewServer(e.QuoteRouteEndpoint, decodeQuoteRouteRequest, encodeQuoteRouteResponse, opts...),
+		assignCargoToRoute:            kitgrpc.NewServer(e.AssignCargoToRouteEndpoint, decodeAssignCargoToRouteRequest, encodeAssignCargoToRouteResponse, opts...),
+		assignCargoToRouteCandidate:   kitgrpc.NewServer(e.AssignCargoToRouteCandidateEndpoint, decodeAssignCargoToRouteCandidateRequest, encodeAssignCargoToRouteCandidateResponse, opts...),
+		changeDestination:             kitgrpc.NewServer(e.ChangeDestinationEndpoint, decodeChangeDestinationRequest, encodeChangeDestinationResponse, opts...),
+		changeArrivalDeadline:         kitgrpc.NewServer(e.ChangeArrivalDeadlineEndpoint, decodeChangeArrivalDeadlineRequest, encodeChangeArrivalDeadlineResponse, opts...),
+		changeOrigin:                  kitgrpc.NewServer(e.ChangeOriginEndpoint, decodeChangeOriginRequest, encodeChangeOriginResponse, opts...),
+		history:                       kitgrpc.NewServer(e.HistoryEndpoint, decodeHistoryRequest, encodeHistoryResponse, opts...),
+		listCargos:                    kitgrpc.NewServer(e.CargosEndpoint, decodeListCargosRequest, encodeListCargosResponse, opts...),
+		listLocations:                 kitgrpc.NewServer(e.LocationsEndpoint, decodeListLocationsRequest, encodeListLocationsResponse, opts...),
+	}
+}
+
+func (s *bookingServer) BookNewCargo(ctx context.Context, req *pb.BookNewCargoRequest) (*pb.BookNewCargoResponse, error) {
+	_, resp, err := s.bookNewCargo.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.BookNewCargoResponse), nil
+}
+
+func (s *bookingServer) LoadCargo(ctx context.Context, req *pb.LoadCargoRequest) (*pb.LoadCargoResponse, error) {
+	_, resp, err := s.loadCargo.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.LoadCargoResponse), nil
+}
+
+func (s *bookingServer) RequestPossibleRoutesForCargo(ctx context.Context, req *pb.RequestPossibleRoutesForCargoRequest) (*pb.RequestPossibleRoutesForCargoResponse, error) {
+	_, resp, err := s.requestPossibleRoutesForCargo.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.RequestPossibleRoutesForCargoResponse), nil
+}
+
+func (s *bookingServer) QuoteRoute(ctx context.Context, req *pb.QuoteRouteRequest) (*pb.QuoteRouteResponse, error) {
+	_, resp, err := s.quoteRoute.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.QuoteRouteResponse), nil
+}
+
+func (s *bookingServer) AssignCargoToRoute(ctx context.Context, req *pb.AssignCargoToRouteRequest) (*pb.AssignCargoToRouteResponse, error) {
+	_, resp, err := s.assignCargoToRoute.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.AssignCargoToRouteResponse), nil
+}
+
+func (s *bookingServer) AssignCargoToRouteCandidate(ctx context.Context, req *pb.AssignCargoToRouteCandidateRequest) (*pb.AssignCargoToRouteCandidateResponse, error) {
+	_, resp, err := s.assignCargoToRouteCandidate.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.AssignCargoToRouteCandidateResponse), nil
+}
+
+func (s *bookingServer) ChangeDestination(ctx context.Context, req *pb.ChangeDestinationRequest) (*pb.ChangeDestinationResponse, error) {
+	_, resp, err := s.changeDestination.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.ChangeDestinationResponse), nil
+}
+
+func (s *bookingServer) ChangeArrivalDeadline(ctx context.Context, req *pb.ChangeArrivalDeadlineRequest) (*pb.ChangeArrivalDeadlineResponse, error) {
+	_, resp, err := s.changeArrivalDeadline.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.ChangeArrivalDeadlineResponse), nil
+}
+
+func (s *bookingServer) ChangeOrigin(ctx context.Context, req *pb.ChangeOriginRequest) (*pb.ChangeOriginResponse, error) {
+	_, resp, err := s.changeOrigin.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.ChangeOriginResponse), nil
+}
+
+func (s *bookingServer) History(ctx context.Context, req *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	_, resp, err := s.history.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.HistoryResponse), nil
+}
+
+func (s *bookingServer) ListCargos(ctx context.Context, req *pb.ListCargosRequest) (*pb.ListCargosResponse, error) {
+	_, resp, err := s.listCargos.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.ListCargosResponse), nil
+}
+
+func (s *bookingServer) ListLocations(ctx context.Context, req *pb.ListLocationsRequest) (*pb.ListLocationsResponse, error) {
+	_, resp, err := s.listLocations.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.ListLocationsResponse), nil
+}
+
+func decodeBookNewCargoRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.BookNewCargoRequest)
+
+	rs := shipping.RouteSpecification{
+		Origin:            shipping.UNLocode(req.Origin),
+		Destination:       shipping.UNLocode(req.Destination),
+		ArrivalDeadline:   toTime(req.ArrivalDeadline),
+		EarliestDeparture: toTime(req.EarliestDeparture),
+		Constraints:       shipping.RouteConstraints{MaxLegs: int(req.MaxLegs)},
+	}
+	for _, l := range req.AvoidLocations {
+		rs.Constraints.AvoidLocations = append(rs.Constraints.AvoidLocations, shipping.UNLocode(l))
+	}
+
+	return booking.BookNewCargoRequest{RouteSpecification: rs}, nil
+}
+
+func encodeBookNewCargoResponse(_ context.Context, r interface{}) (interface{}, error) {
+	resp := r.(booking.BookNewCargoResponse)
+	return &pb.BookNewCargoResponse{TrackingId: string(resp.ID)}, nil
+}
+
+func decodeLoadCargoRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.LoadCargoRequest)
+	return booking.LoadCargoRequest{ID: shipping.TrackingID(req.TrackingId)}, nil
+}
+
+func encodeLoadCargoResponse(_ context.Context, r interface{}) (interface{}, error) {
+	resp := r.(booking.LoadCargoResponse)
+	return &pb.LoadCargoResponse{Cargo: encodeCargo(resp.Cargo)}, nil
+}
+
+func decodeRequestPossibleRoutesForCargoRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.RequestPossibleRoutesForCargoRequest)
+	return booking.RequestPossibleRoutesForCargoRequest{
+		ID:       shipping.TrackingID(req.TrackingId),
+		Strategy: req.Strategy,
+	}, nil
+}
+
+func encodeRequestPossibleRoutesForCargoResponse(_ context.Context, r interface{}) (interface{}, error) {
+	resp := r.(booking.RequestPossibleRoutesForCargoResponse)
+	routes := make([]*pb.RouteCandidate, 0, len(resp.Routes))
+	for _, c := range resp.Routes {
+		candidate := &pb.RouteCandidate{
+			Legs:      encodeLegs(c.Legs),
+			Id:        string(c.ID),
+			ExpiresAt: fromTime(c.ExpiresAt),
+			Score:     c.Score,
+		}
+		for _, cost := range c.Costs {
+			candidate.Costs = append(candidate.Costs, &pb.Cost{
+				Criterion: cost.Criterion,
+				Value:     cost.Value,
+				Weight:    cost.Weight,
+				Score:     cost.Score,
+			})
+		}
+		routes = append(routes, candidate)
+	}
+	return &pb.RequestPossibleRoutesForCargoResponse{Routes: routes}, nil
+}
+
+func decodeQuoteRouteRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.QuoteRouteRequest)
+	return booking.QuoteRouteRequest{
+		ID:          shipping.TrackingID(req.TrackingId),
+		CandidateID: booking.RouteCandidateID(req.CandidateId),
+		Size:        int(req.Size),
+		Currency:    req.Currency,
+	}, nil
+}
+
+func encodeQuoteRouteResponse(_ context.Context, r interface{}) (interface{}, error) {
+	resp := r.(booking.QuoteRouteResponse)
+	q := &pb.Quote{
+		Size:  int32(resp.Quote.Size),
+		Total: encodeMoney(resp.Quote.Total),
+	}
+	for _, l := range resp.Quote.Lines {
+		q.Lines = append(q.Lines, &pb.Quote_Line{Description: l.Description, Amount: encodeMoney(l.Amount)})
+	}
+	return &pb.QuoteRouteResponse{Quote: q}, nil
+}
+
+func decodeAssignCargoToRouteRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.AssignCargoToRouteRequest)
+
+	var itin shipping.Itinerary
+	for _, l := range req.GetRoute().GetLegs() {
+		itin.Legs = append(itin.Legs, shipping.Leg{
+			VoyageNumber:   shipping.VoyageNumber(l.VoyageNumber),
+			LoadLocation:   shipping.UNLocode(l.From),
+			UnloadLocation: shipping.UNLocode(l.To),
+			LoadTime:       toTime(l.LoadTime),
+			UnloadTime:     toTime(l.UnloadTime),
+		})
+	}
+
+	return booking.AssignCargoToRouteRequest{
+		ID:        shipping.TrackingID(req.TrackingId),
+		Itinerary: itin,
+	}, nil
+}
+
+func encodeAssignCargoToRouteResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return &pb.AssignCargoToRouteResponse{}, nil
+}
+
+func decodeAssignCargoToRouteCandidateRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.AssignCargoToRouteCandidateRequest)
+	return booking.AssignCargoToRouteCandidateRequest{
+		ID:          shipping.TrackingID(req.TrackingId),
+		CandidateID: booking.RouteCandidateID(req.CandidateId),
+	}, nil
+}
+
+func encodeAssignCargoToRouteCandidateResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return &pb.AssignCargoToRouteCandidateResponse{}, nil
+}
+
+func decodeChangeDestinationRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.ChangeDestinationRequest)
+	return booking.ChangeDestinationRequest{
+		ID:          shipping.TrackingID(req.TrackingId),
+		Destination: shipping.UNLocode(req.Destination),
+	}, nil
+}
+
+func encodeChangeDestinationResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return &pb.ChangeDestinationResponse{}, nil
+}
+
+func decodeChangeArrivalDeadlineRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.ChangeArrivalDeadlineRequest)
+	return booking.ChangeArrivalDeadlineRequest{
+		ID:       shipping.TrackingID(req.TrackingId),
+		Deadline: toTime(req.ArrivalDeadline),
+	}, nil
+}
+
+func encodeChangeArrivalDeadlineResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return &pb.ChangeArrivalDeadlineResponse{}, nil
+}
+
+func decodeChangeOriginRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.ChangeOriginRequest)
+	return booking.ChangeOriginRequest{
+		ID:     shipping.TrackingID(req.TrackingId),
+		Origin: shipping.UNLocode(req.Origin),
+	}, nil
+}
+
+func encodeChangeOriginResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return &pb.ChangeOriginResponse{}, nil
+}
+
+func decodeHistoryRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.HistoryRequest)
+	return booking.HistoryRequest{ID: shipping.TrackingID(req.TrackingId)}, nil
+}
+
+var amendmentTypes = map[booking.AmendmentType]pb.AmendmentType{
+	booking.DestinationChanged:     pb.AmendmentType_AMENDMENT_TYPE_DESTINATION_CHANGED,
+	booking.ArrivalDeadlineChanged: pb.AmendmentType_AMENDMENT_TYPE_ARRIVAL_DEADLINE_CHANGED,
+	booking.OriginChanged:          pb.AmendmentType_AMENDMENT_TYPE_ORIGIN_CHANGED,
+	booking.RouteAssigned:          pb.AmendmentType_AMENDMENT_TYPE_ROUTE_ASSIGNED,
+}
+
+func encodeHistoryResponse(_ context.Context, r interface{}) (interface{}, error) {
+	resp := r.(booking.HistoryResponse)
+	amendments := make([]*pb.Amendment, 0, len(resp.Amendments))
+	for _, a := range resp.Amendments {
+		amendments = append(amendments, &pb.Amendment{
+			Id:       string(a.ID),
+			Type:     amendmentTypes[a.Type],
+			Actor:    a.Actor,
+			Time:     fromTime(a.Time),
+			Previous: encodeTerms(a.Previous),
+			New:      encodeTerms(a.New),
+		})
+	}
+	return &pb.HistoryResponse{Amendments: amendments}, nil
+}
+
+func encodeTerms(t booking.Terms) *pb.Terms {
+	result := &pb.Terms{
+		Origin:          string(t.Origin),
+		Destination:     string(t.Destination),
+		ArrivalDeadline: fromTime(t.ArrivalDeadline),
+		Legs:            encodeLegs(t.Legs),
+	}
+	if t.Price != nil {
+		result.Price = encodeMoney(*t.Price)
+	}
+	return result
+}
+
+var routingStatuses = map[pb.RoutingStatus]shipping.RoutingStatus{
+	pb.RoutingStatus_ROUTING_STATUS_NOT_ROUTED: shipping.NotRouted,
+	pb.RoutingStatus_ROUTING_STATUS_MISROUTED:  shipping.Misrouted,
+	pb.RoutingStatus_ROUTING_STATUS_ROUTED:     shipping.Routed,
+}
+
+func decodeListCargosRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.ListCargosRequest)
+
+	q := shipping.CargoQuery{
+		Misrouted:      req.Misrouted,
+		Origin:         shipping.UNLocode(req.Origin),
+		Destination:    shipping.UNLocode(req.Destination),
+		DeadlineAfter:  toTime(req.DeadlineAfter),
+		DeadlineBefore: toTime(req.DeadlineBefore),
+		Descending:     req.Descending,
+		Cursor:         req.Cursor,
+		Limit:          int(req.Limit),
+	}
+
+	if req.RoutingStatus != pb.RoutingStatus_ROUTING_STATUS_UNSPECIFIED {
+		s, ok := routingStatuses[req.RoutingStatus]
+		if !ok {
+			return nil, booking.ErrInvalidArgument.WithFields(shipping.FieldError{Name: "routing_status", Reason: "is unknown"})
+		}
+		q.RoutingStatus = &s
+	}
+
+	switch req.SortBy {
+	case pb.CargoSortKey_CARGO_SORT_KEY_TRACKING_ID:
+		q.SortBy = shipping.SortByTrackingID
+	case pb.CargoSortKey_CARGO_SORT_KEY_ARRIVAL_DEADLINE:
+		q.SortBy = shipping.SortByArrivalDeadline
+	default:
+		return nil, booking.ErrInvalidArgument.WithFields(shipping.FieldError{Name: "sort_by", Reason: "is unknown"})
+	}
+
+	return booking.CargosRequest{Query: q}, nil
+}
+
+func encodeListCargosResponse(_ context.Context, r interface{}) (interface{}, error) {
+	resp := r.(booking.CargosResponse)
+	cargos := make([]*pb.Cargo, 0, len(resp.Page.Cargos))
+	for _, c := range resp.Page.Cargos {
+		cargos = append(cargos, encodeCargo(c))
+	}
+	return &pb.ListCargosResponse{Cargos: cargos, NextCursor: resp.Page.NextCursor}, nil
+}
+
+func decodeListLocationsRequest(_ context.Context, _ interface{}) (interface{}, error) {
+	return booking.LocationsRequest{}, nil
+}
+
+func encodeListLocationsResponse(_ context.Context, r interface{}) (interface{}, error) {
+	resp := r.(booking.LocationsResponse)
+	locations := make([]*pb.Location, 0, len(resp.Locations))
+	for _, l := range resp.Locations {
+		locations = append(locations, &pb.Location{Locode: l.UNLocode, Name: l.Name})
+	}
+	return &pb.ListLocationsResponse{Locations: locations}, nil
+}
+
+func encodeCargo(c booking.Cargo) *pb.Cargo {
+	result := &pb.Cargo{
+		TrackingId:      c.TrackingID,
+		Origin:          c.Origin,
+		Destination:     c.Destination,
+		ArrivalDeadline: fromTime(c.ArrivalDeadline),
+		Misrouted:       c.Misrouted,
+		Routed:          c.Routed,
+		Legs:            encodeLegs(c.Legs),
+		MaxLegs:         int32(c.MaxLegs),
+		AvoidLocations:  c.AvoidLocations,
+	}
+	if c.EarliestDeparture != nil {
+		result.EarliestDeparture = fromTime(*c.EarliestDeparture)
+	}
+	if c.Price != nil {
+		result.Price = encodeMoney(*c.Price)
+	}
+	return result
+}
+
+func encodeMoney(m shipping.Money) *pb.Money {
+	return &pb.Money{Amount: m.Amount, Currency: m.Currency}
+}
+
+func encodeLegs(legs []shipping.Leg) []*pb.Leg {
+	var result []*pb.Leg
+	for _, l := range legs {
+		result = append(result, &pb.Leg{
+			VoyageNumber: string(l.VoyageNumber),
+			From:         string(l.LoadLocation),
+			To:           string(l.UnloadLocation),
+			LoadTime:     fromTime(l.LoadTime),
+			UnloadTime:   fromTime(l.UnloadTime),
+		})
+	}
+	return result
+}
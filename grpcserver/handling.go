@@ -0,0 +1,64 @@
+package grpcserver
+
+import (
+	"context"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/auth"
+	"github.com/marcusolsson/goddd/handling"
+	"github.com/marcusolsson/goddd/pb"
+)
+
+type handlingServer struct {
+	pb.UnimplementedHandlingServiceServer
+
+	registerHandlingEvent kitgrpc.Handler
+}
+
+func newHandlingServer(e handling.Endpoints, opts []kitgrpc.ServerOption) pb.HandlingServiceServer {
+	return &handlingServer{
+		registerHandlingEvent: kitgrpc.NewServer(e.RegisterHandlingEventEndpoint, decodeRegisterHandlingEventRequest, encodeRegisterHandlingEventResponse, opts...),
+	}
+}
+
+func (s *handlingServer) RegisterHandlingEvent(ctx context.Context, req *pb.RegisterHandlingEventRequest) (*pb.RegisterHandlingEventResponse, error) {
+	if p, ok := auth.FromContext(ctx); ok && !p.CanHandleAt(shipping.UNLocode(req.Location)) {
+		return nil, encodeError(auth.ErrPermissionDenied.WithFields(shipping.FieldError{
+			Name:   "location",
+			Reason: "is not one of your locations",
+		}))
+	}
+
+	_, resp, err := s.registerHandlingEvent.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.RegisterHandlingEventResponse), nil
+}
+
+// eventTypes maps protobuf event types to domain event types. Unknown types
+// become shipping.NotHandled, which the service rejects.
+var eventTypes = map[pb.HandlingEventType]shipping.HandlingEventType{
+	pb.HandlingEventType_HANDLING_EVENT_TYPE_LOAD:    shipping.Load,
+	pb.HandlingEventType_HANDLING_EVENT_TYPE_UNLOAD:  shipping.Unload,
+	pb.HandlingEventType_HANDLING_EVENT_TYPE_RECEIVE: shipping.Receive,
+	pb.HandlingEventType_HANDLING_EVENT_TYPE_CLAIM:   shipping.Claim,
+	pb.HandlingEventType_HANDLING_EVENT_TYPE_CUSTOMS: shipping.Customs,
+}
+
+func decodeRegisterHandlingEventRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.RegisterHandlingEventRequest)
+	return handling.RegisterHandlingEventRequest{
+		CompletionTime: toTime(req.CompletionTime),
+		ID:             shipping.TrackingID(req.TrackingId),
+		VoyageNumber:   shipping.VoyageNumber(req.VoyageNumber),
+		Location:       shipping.UNLocode(req.Location),
+		EventType:      eventTypes[req.EventType],
+	}, nil
+}
+
+func encodeRegisterHandlingEventResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return &pb.RegisterHandlingEventResponse{}, nil
+}
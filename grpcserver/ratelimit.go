@@ -0,0 +1,118 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/marcusolsson/goddd/auth"
+	"github.com/marcusolsson/goddd/pb"
+	"github.com/marcusolsson/goddd/ratelimit"
+)
+
+// serviceRouters holds the HTTP router whose rate limit applies to each
+// service.
+var serviceRouters = map[string]string{
+	pb.BookingService_ServiceDesc.ServiceName:  "booking",
+	pb.TrackingService_ServiceDesc.ServiceName: "tracking",
+	pb.HandlingService_ServiceDesc.ServiceName: "handling",
+}
+
+// clientKey identifies the client of a call by its kind and a key. An empty
+// kind means the call isn't limited.
+type clientKey func(ctx context.Context) (kind, key string)
+
+// rateLimit returns an interceptor that applies the rate limit of the router
+// of each service, if any, to each client as identified by client. Calls
+// from clients that exceed their rate are rejected with ResourceExhausted and
+// a RetryInfo detail telling them when to retry.
+func (c *config) rateLimit(client clientKey) grpc.UnaryServerInterceptor {
+	limiters := make(map[string]*ratelimit.Limiter)
+	for service, router := range serviceRouters {
+		if limit, ok := c.rateLimits[router]; ok && limit.Rate > 0 {
+			limiters[service] = ratelimit.NewLimiter(limit)
+		}
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		service := serviceName(info.FullMethod)
+
+		l, ok := limiters[service]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		kind, key := client(ctx)
+		if kind == "" {
+			return handler(ctx, req)
+		}
+
+		if d := l.Reserve(kind + ":" + key); d > 0 {
+			if c.throttled != nil {
+				c.throttled.With("router", serviceRouters[service], "client", kind).Add(1)
+			}
+			return nil, rateLimited(d)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitAuth returns an interceptor that applies the Auth rate limit, if
+// any, to each IP address before calls are authenticated.
+func (c *config) rateLimitAuth() grpc.UnaryServerInterceptor {
+	limit, ok := c.rateLimits[ratelimit.Auth]
+	if !ok || limit.Rate == 0 {
+		return nil
+	}
+
+	l := ratelimit.NewLimiter(limit)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		kind, key := byIP(ctx)
+		if d := l.Reserve(kind + ":" + key); d > 0 {
+			if c.throttled != nil {
+				c.throttled.With("router", ratelimit.Auth, "client", kind).Add(1)
+			}
+			return nil, rateLimited(d)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// rateLimited returns the status of a call that was throttled for d.
+func rateLimited(d time.Duration) error {
+	st := status.Convert(encodeError(ratelimit.ErrRateLimited))
+	if withRetry, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(d)}); err == nil {
+		st = withRetry
+	}
+	return st.Err()
+}
+
+// byIP identifies clients by IP address.
+func byIP(ctx context.Context) (kind, key string) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "ip", ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return "ip", host
+}
+
+// byClient identifies clients by the principal they authenticated as, or by
+// IP address if they didn't.
+func byClient(ctx context.Context) (kind, key string) {
+	if p, ok := auth.FromContext(ctx); ok && !p.Anonymous {
+		return "principal", p.Subject
+	}
+	return byIP(ctx)
+}
@@ -0,0 +1,164 @@
+// Package grpcserver provides a gRPC transport for the booking, tracking and
+// handling services. The API is defined by the protocol buffers in package pb.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/auth"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/handling"
+	"github.com/marcusolsson/goddd/pb"
+	"github.com/marcusolsson/goddd/ratelimit"
+	"github.com/marcusolsson/goddd/tracking"
+)
+
+// errorDomain identifies this system in the ErrorInfo detail of a status.
+const errorDomain = "goddd"
+
+type config struct {
+	authenticator  auth.Authenticator
+	anonymousRoles []auth.Role
+
+	rateLimits ratelimit.Limits
+	throttled  metrics.Counter
+}
+
+// Option configures optional features of the server.
+type Option func(*config)
+
+// WithAuthentication requires clients to authenticate with a, and authorizes
+// them by role, as the HTTP server does: booking requires RoleAdmin, handling
+// requires RoleHandler at the location of the event and tracking requires
+// RolePublic. Clients without credentials are given the anonymous roles.
+func WithAuthentication(a auth.Authenticator, anonymous ...auth.Role) Option {
+	return func(c *config) {
+		c.authenticator = a
+		c.anonymousRoles = anonymous
+	}
+}
+
+// WithRateLimits limits the rate of calls of each client to the services,
+// with the limits of the corresponding HTTP routers: booking, tracking and
+// handling. Clients are limited by principal once authenticated, and by IP
+// address otherwise. The ratelimit.Auth limit applies by IP address to all
+// calls before authentication. Throttled calls are counted by throttled,
+// which may be nil.
+func WithRateLimits(limits ratelimit.Limits, throttled metrics.Counter) Option {
+	return func(c *config) {
+		c.rateLimits = limits
+		c.throttled = throttled
+	}
+}
+
+// New returns a gRPC server that serves the given services.
+func New(bs booking.Service, ts tracking.Service, hs handling.Service, logger kitlog.Logger, options ...Option) *grpc.Server {
+	var c config
+	for _, opt := range options {
+		opt(&c)
+	}
+
+	var interceptors []grpc.UnaryServerInterceptor
+	if limitAuth := c.rateLimitAuth(); limitAuth != nil {
+		interceptors = append(interceptors, limitAuth)
+	}
+	if c.authenticator != nil {
+		interceptors = append(interceptors, c.authenticate, bookingActor)
+	}
+	interceptors = append(interceptors, c.rateLimit(byClient))
+
+	opts := []kitgrpc.ServerOption{
+		kitgrpc.ServerErrorLogger(logger),
+	}
+
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	pb.RegisterBookingServiceServer(s, newBookingServer(booking.MakeEndpoints(bs), opts))
+	pb.RegisterTrackingServiceServer(s, newTrackingServer(tracking.MakeEndpoints(ts), opts))
+	pb.RegisterHandlingServiceServer(s, newHandlingServer(handling.MakeEndpoints(hs), opts))
+	return s
+}
+
+// grpcCodes maps error codes to gRPC status codes. Codes not listed here are
+// considered invalid arguments.
+var grpcCodes = map[shipping.ErrorCode]codes.Code{
+	shipping.CodeInvalidArgument:       codes.InvalidArgument,
+	shipping.CodeInvalidCursor:         codes.InvalidArgument,
+	shipping.CodeUnknownCargo:          codes.NotFound,
+	shipping.CodeUnknownLocation:       codes.NotFound,
+	shipping.CodeUnknownVoyage:         codes.NotFound,
+	shipping.CodeUnknownRouteCandidate: codes.NotFound,
+	shipping.CodeStaleRouteCandidate:   codes.FailedPrecondition,
+	shipping.CodeNoRate:                codes.FailedPrecondition,
+	shipping.CodeCargoReceived:         codes.FailedPrecondition,
+	shipping.CodeUnauthenticated:       codes.Unauthenticated,
+	shipping.CodePermissionDenied:      codes.PermissionDenied,
+	shipping.CodeRoutingUnavailable:    codes.Unavailable,
+	shipping.CodeRateLimited:           codes.ResourceExhausted,
+}
+
+// encodeError translates err into a gRPC status error. Domain errors carry
+// their code in an ErrorInfo detail, and their fields in a BadRequest detail.
+func encodeError(err error) error {
+	var e *shipping.Error
+	switch {
+	case errors.As(err, &e):
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	code, ok := grpcCodes[e.Code]
+	if !ok {
+		code = codes.InvalidArgument
+	}
+
+	st := status.New(code, e.Message)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: string(e.Code), Domain: errorDomain}}
+	if len(e.Fields) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, f := range e.Fields {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.Name,
+				Description: f.Reason,
+			})
+		}
+		details = append(details, br)
+	}
+
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+func toTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func fromTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,539 @@
+package grpcserver
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/auth"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/handling"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/mock"
+	"github.com/marcusolsson/goddd/pb"
+	"github.com/marcusolsson/goddd/pricing"
+	"github.com/marcusolsson/goddd/ratelimit"
+	"github.com/marcusolsson/goddd/tracking"
+)
+
+type nopEventHandler struct{}
+
+func (nopEventHandler) CargoWasHandled(context.Context, shipping.HandlingEvent) {}
+
+// dial starts a server for services backed by in-memory repositories and
+// returns a connection to it.
+func dial(t *testing.T, opts ...Option) (*grpc.ClientConn, func()) {
+	var (
+		cargos    = inmem.NewCargoRepository()
+		locations = inmem.NewLocationRepository()
+		voyages   = inmem.NewVoyageRepository()
+		events    = inmem.NewHandlingEventRepository()
+	)
+
+	factory := shipping.HandlingEventFactory{
+		CargoRepository:    cargos,
+		VoyageRepository:   voyages,
+		LocationRepository: locations,
+	}
+
+	// Routes load the day after the earliest departure and unload the day
+	// before the arrival deadline.
+	rs := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			return []shipping.Itinerary{{Legs: []shipping.Leg{
+				shipping.NewLeg("V100", rs.Origin, rs.Destination, rs.EarliestDeparture.AddDate(0, 0, 1), rs.ArrivalDeadline.AddDate(0, 0, -1)),
+			}}}, nil
+		},
+	}
+
+	s := New(
+		booking.NewService(cargos, locations, events, rs, inmem.NewRouteCandidateRepository(), inmem.NewAmendmentRepository(),
+			booking.WithTariff(pricing.SampleTariff())),
+		tracking.NewService(cargos, events),
+		handling.NewService(events, factory, nopEventHandler{}),
+		log.NewLogfmtLogger(ioutil.Discard),
+		opts...,
+	)
+
+	lis := bufconn.Listen(1024 * 1024)
+	go s.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn, func() {
+		conn.Close()
+		s.Stop()
+	}
+}
+
+func TestBookAndTrack(t *testing.T) {
+	conn, stop := dial(t)
+	defer stop()
+
+	ctx := context.Background()
+
+	bc := pb.NewBookingServiceClient(conn)
+
+	deadline := time.Date(2009, time.March, 18, 12, 0, 0, 0, time.UTC)
+
+	booked, err := bc.BookNewCargo(ctx, &pb.BookNewCargoRequest{
+		Origin:            "SESTO",
+		Destination:       "AUMEL",
+		ArrivalDeadline:   timestamppb.New(deadline),
+		EarliestDeparture: timestamppb.New(deadline.AddDate(0, 0, -21)),
+		MaxLegs:           3,
+		AvoidLocations:    []string{"NLRTM"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := bc.LoadCargo(ctx, &pb.LoadCargoRequest{TrackingId: booked.TrackingId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Cargo.ArrivalDeadline.AsTime(); !got.Equal(deadline) {
+		t.Errorf("ArrivalDeadline = %v; want = %v", got, deadline)
+	}
+	if got, want := loaded.Cargo.EarliestDeparture.AsTime(), deadline.AddDate(0, 0, -21); !got.Equal(want) {
+		t.Errorf("EarliestDeparture = %v; want = %v", got, want)
+	}
+	if loaded.Cargo.MaxLegs != 3 {
+		t.Errorf("MaxLegs = %d; want = %d", loaded.Cargo.MaxLegs, 3)
+	}
+	if len(loaded.Cargo.AvoidLocations) != 1 || loaded.Cargo.AvoidLocations[0] != "NLRTM" {
+		t.Errorf("AvoidLocations = %v; want = [NLRTM]", loaded.Cargo.AvoidLocations)
+	}
+
+	hc := pb.NewHandlingServiceClient(conn)
+	if _, err := hc.RegisterHandlingEvent(ctx, &pb.RegisterHandlingEventRequest{
+		CompletionTime: timestamppb.New(deadline.AddDate(0, 0, -10)),
+		TrackingId:     booked.TrackingId,
+		Location:       "SESTO",
+		EventType:      pb.HandlingEventType_HANDLING_EVENT_TYPE_RECEIVE,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tc := pb.NewTrackingServiceClient(conn)
+	tracked, err := tc.Track(ctx, &pb.TrackRequest{TrackingId: booked.TrackingId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tracked.Cargo.Destination != "AUMEL" {
+		t.Errorf("Destination = %s; want = %s", tracked.Cargo.Destination, "AUMEL")
+	}
+	if len(tracked.Cargo.Events) != 1 {
+		t.Errorf("len(Events) = %d; want = %d", len(tracked.Cargo.Events), 1)
+	}
+
+	listed, err := bc.ListCargos(ctx, &pb.ListCargosRequest{RoutingStatus: pb.RoutingStatus_ROUTING_STATUS_NOT_ROUTED})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.Cargos) != 1 || listed.Cargos[0].TrackingId != booked.TrackingId {
+		t.Errorf("Cargos = %v; want [%s]", listed.Cargos, booked.TrackingId)
+	}
+}
+
+func TestQuoteAndAssignRouteCandidate(t *testing.T) {
+	conn, stop := dial(t)
+	defer stop()
+
+	ctx := context.Background()
+
+	bc := pb.NewBookingServiceClient(conn)
+
+	deadline := time.Date(2009, time.March, 18, 12, 0, 0, 0, time.UTC)
+
+	booked, err := bc.BookNewCargo(ctx, &pb.BookNewCargoRequest{
+		Origin:            "SESTO",
+		Destination:       "AUMEL",
+		ArrivalDeadline:   timestamppb.New(deadline),
+		EarliestDeparture: timestamppb.New(deadline.AddDate(0, 0, -21)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bc.RequestPossibleRoutesForCargo(ctx, &pb.RequestPossibleRoutesForCargoRequest{
+		TrackingId: booked.TrackingId,
+		Strategy:   "unknown",
+	}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("code = %s; want = %s", status.Code(err), codes.InvalidArgument)
+	}
+
+	routes, err := bc.RequestPossibleRoutesForCargo(ctx, &pb.RequestPossibleRoutesForCargoRequest{
+		TrackingId: booked.TrackingId,
+		Strategy:   shipping.StrategyFastest,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes.Routes) != 1 {
+		t.Fatalf("len(Routes) = %d; want = %d", len(routes.Routes), 1)
+	}
+
+	candidate := routes.Routes[0]
+	if candidate.Id == "" {
+		t.Error("Id is empty")
+	}
+	if candidate.ExpiresAt == nil {
+		t.Error("ExpiresAt is unset")
+	}
+	if len(candidate.Costs) == 0 {
+		t.Error("Costs is empty")
+	}
+	var score float64
+	for _, c := range candidate.Costs {
+		score += c.Score
+	}
+	if candidate.Score != score {
+		t.Errorf("Score = %v; want = %v", candidate.Score, score)
+	}
+
+	if _, err := bc.AssignCargoToRouteCandidate(ctx, &pb.AssignCargoToRouteCandidateRequest{
+		TrackingId:  booked.TrackingId,
+		CandidateId: "unknown",
+	}); status.Code(err) != codes.NotFound {
+		t.Errorf("code = %s; want = %s", status.Code(err), codes.NotFound)
+	}
+
+	quoted, err := bc.QuoteRoute(ctx, &pb.QuoteRouteRequest{
+		TrackingId:  booked.TrackingId,
+		CandidateId: candidate.Id,
+		Size:        2,
+		Currency:    "EUR",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total int64
+	for _, l := range quoted.Quote.Lines {
+		total += l.Amount.Amount
+	}
+	if quoted.Quote.Total.Amount != total || quoted.Quote.Total.Currency != "EUR" {
+		t.Errorf("Total = %v; want = %d EUR", quoted.Quote.Total, total)
+	}
+
+	if _, err := bc.AssignCargoToRouteCandidate(ctx, &pb.AssignCargoToRouteCandidateRequest{
+		TrackingId:  booked.TrackingId,
+		CandidateId: candidate.Id,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := bc.LoadCargo(ctx, &pb.LoadCargoRequest{TrackingId: booked.TrackingId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Cargo.Routed {
+		t.Error("Routed = false; want = true")
+	}
+	if len(loaded.Cargo.Legs) != 1 || loaded.Cargo.Legs[0].VoyageNumber != candidate.Legs[0].VoyageNumber {
+		t.Errorf("Legs = %v; want = %v", loaded.Cargo.Legs, candidate.Legs)
+	}
+	if p := loaded.Cargo.Price; p.GetAmount() != quoted.Quote.Total.Amount || p.GetCurrency() != "EUR" {
+		t.Errorf("Price = %v; want = %v", p, quoted.Quote.Total)
+	}
+
+	history, err := bc.History(ctx, &pb.HistoryRequest{TrackingId: booked.TrackingId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Amendments) != 1 {
+		t.Fatalf("len(Amendments) = %d; want = %d", len(history.Amendments), 1)
+	}
+	if a := history.Amendments[0]; a.Type != pb.AmendmentType_AMENDMENT_TYPE_ROUTE_ASSIGNED || len(a.Previous.Legs) != 0 || len(a.New.Legs) != 1 {
+		t.Errorf("Amendments[0] = %v; want route assigned", a)
+	}
+}
+
+func TestChangeArrivalDeadlineAndOrigin(t *testing.T) {
+	conn, stop := dial(t)
+	defer stop()
+
+	ctx := context.Background()
+
+	bc := pb.NewBookingServiceClient(conn)
+
+	deadline := time.Date(2009, time.March, 18, 12, 0, 0, 0, time.UTC)
+
+	booked, err := bc.BookNewCargo(ctx, &pb.BookNewCargoRequest{
+		Origin:          "SESTO",
+		Destination:     "AUMEL",
+		ArrivalDeadline: timestamppb.New(deadline),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	later := time.Now().AddDate(1, 0, 0).Truncate(time.Second)
+	if _, err := bc.ChangeArrivalDeadline(ctx, &pb.ChangeArrivalDeadlineRequest{
+		TrackingId:      booked.TrackingId,
+		ArrivalDeadline: timestamppb.New(later),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bc.ChangeOrigin(ctx, &pb.ChangeOriginRequest{
+		TrackingId: booked.TrackingId,
+		Origin:     "CNHKG",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := bc.LoadCargo(ctx, &pb.LoadCargoRequest{TrackingId: booked.TrackingId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Cargo.ArrivalDeadline.AsTime(); !got.Equal(later) {
+		t.Errorf("ArrivalDeadline = %v; want = %v", got, later)
+	}
+	if loaded.Cargo.Origin != "CNHKG" {
+		t.Errorf("Origin = %s; want = %s", loaded.Cargo.Origin, "CNHKG")
+	}
+
+	if _, err := pb.NewHandlingServiceClient(conn).RegisterHandlingEvent(ctx, &pb.RegisterHandlingEventRequest{
+		CompletionTime: timestamppb.New(deadline.AddDate(0, 0, -10)),
+		TrackingId:     booked.TrackingId,
+		Location:       "CNHKG",
+		EventType:      pb.HandlingEventType_HANDLING_EVENT_TYPE_RECEIVE,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The origin can't change once the cargo has been received.
+	_, err = bc.ChangeOrigin(ctx, &pb.ChangeOriginRequest{
+		TrackingId: booked.TrackingId,
+		Origin:     "SESTO",
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("code = %s; want = %s", status.Code(err), codes.FailedPrecondition)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	conn, stop := dial(t)
+	defer stop()
+
+	ctx := context.Background()
+
+	_, err := pb.NewTrackingServiceClient(conn).Track(ctx, &pb.TrackRequest{TrackingId: "no_such_id"})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("status.Code(err) = %v; want = %v", got, codes.NotFound)
+	}
+
+	_, err = pb.NewBookingServiceClient(conn).BookNewCargo(ctx, &pb.BookNewCargoRequest{Origin: "SESTO"})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("st.Code() = %v; want = %v", st.Code(), codes.InvalidArgument)
+	}
+
+	var (
+		reason string
+		fields []string
+	)
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			reason = d.Reason
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+		}
+	}
+	if reason != string(shipping.CodeInvalidArgument) {
+		t.Errorf("reason = %s; want = %s", reason, shipping.CodeInvalidArgument)
+	}
+	if len(fields) != 2 || fields[0] != "destination" || fields[1] != "arrival_deadline" {
+		t.Errorf("fields = %v; want = [destination arrival_deadline]", fields)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	keys := auth.NewAPIKeys(map[string]auth.Principal{
+		"admin":   {Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}},
+		"handler": {Subject: "stockholm", Roles: []auth.Role{auth.RoleHandler}, Locations: []shipping.UNLocode{shipping.SESTO}},
+	})
+
+	conn, stop := dial(t, WithAuthentication(keys, auth.RolePublic))
+	defer stop()
+
+	withKey := func(key string) context.Context {
+		ctx := context.Background()
+		if key == "" {
+			return ctx
+		}
+		return metadata.AppendToOutgoingContext(ctx, "x-api-key", key)
+	}
+
+	var (
+		bc = pb.NewBookingServiceClient(conn)
+		tc = pb.NewTrackingServiceClient(conn)
+		hc = pb.NewHandlingServiceClient(conn)
+	)
+
+	book := func(key string) error {
+		_, err := bc.BookNewCargo(withKey(key), &pb.BookNewCargoRequest{
+			Origin:          "SESTO",
+			Destination:     "AUMEL",
+			ArrivalDeadline: timestamppb.New(time.Date(2009, time.March, 18, 12, 0, 0, 0, time.UTC)),
+		})
+		return err
+	}
+	track := func(key string) error {
+		_, err := tc.Track(withKey(key), &pb.TrackRequest{TrackingId: "no_such_id"})
+		return err
+	}
+	handle := func(key, location string) error {
+		_, err := hc.RegisterHandlingEvent(withKey(key), &pb.RegisterHandlingEventRequest{
+			CompletionTime: timestamppb.Now(),
+			TrackingId:     "no_such_id",
+			Location:       location,
+			EventType:      pb.HandlingEventType_HANDLING_EVENT_TYPE_RECEIVE,
+		})
+		return err
+	}
+
+	for _, tt := range []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"anonymous booking", book(""), codes.Unauthenticated},
+		{"invalid key", book("wrong"), codes.Unauthenticated},
+		{"handler booking", book("handler"), codes.PermissionDenied},
+		{"admin booking", book("admin"), codes.OK},
+		{"anonymous tracking", track(""), codes.NotFound},
+		{"anonymous handling", handle("", "SESTO"), codes.Unauthenticated},
+		{"handler at other location", handle("handler", "AUMEL"), codes.PermissionDenied},
+		{"handler at own location", handle("handler", "SESTO"), codes.NotFound},
+		{"admin handling", handle("admin", "AUMEL"), codes.NotFound},
+	} {
+		if got := status.Code(tt.err); got != tt.want {
+			t.Errorf("%s: status.Code(err) = %v; want = %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	conn, stop := dial(t, WithRateLimits(ratelimit.Limits{
+		"tracking": {Rate: 0.001, Burst: 2},
+	}, nil))
+	defer stop()
+
+	ctx := context.Background()
+
+	tc := pb.NewTrackingServiceClient(conn)
+	for i := 0; i < 2; i++ {
+		_, err := tc.Track(ctx, &pb.TrackRequest{TrackingId: "no_such_id"})
+		if got := status.Code(err); got != codes.NotFound {
+			t.Errorf("status.Code(err) = %v; want = %v", got, codes.NotFound)
+		}
+	}
+
+	_, err := tc.Track(metadata.AppendToOutgoingContext(ctx, "x-api-key", "key"), &pb.TrackRequest{TrackingId: "no_such_id"})
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("st.Code() = %v; want = %v", st.Code(), codes.ResourceExhausted)
+	}
+
+	var retry *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if d, ok := d.(*errdetails.RetryInfo); ok {
+			retry = d
+		}
+	}
+	if retry == nil || retry.RetryDelay.AsDuration() <= 0 {
+		t.Errorf("RetryInfo = %v; want a retry delay", retry)
+	}
+
+	// Other services have their own limits.
+	if _, err := pb.NewBookingServiceClient(conn).ListLocations(ctx, &pb.ListLocationsRequest{}); err != nil {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRateLimitBeforeAuthentication(t *testing.T) {
+	keys := auth.NewAPIKeys(map[string]auth.Principal{
+		"admin": {Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}},
+	})
+
+	conn, stop := dial(t,
+		WithAuthentication(keys),
+		WithRateLimits(ratelimit.Limits{ratelimit.Auth: {Rate: 0.001, Burst: 2}}, nil),
+	)
+	defer stop()
+
+	bc := pb.NewBookingServiceClient(conn)
+	list := func(key string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+		_, err := bc.ListLocations(ctx, &pb.ListLocationsRequest{})
+		return err
+	}
+
+	for _, key := range []string{"guess1", "guess2"} {
+		if got := status.Code(list(key)); got != codes.Unauthenticated {
+			t.Errorf("status.Code(err) = %v; want = %v", got, codes.Unauthenticated)
+		}
+	}
+	if got := status.Code(list("admin")); got != codes.ResourceExhausted {
+		t.Errorf("status.Code(err) = %v; want = %v", got, codes.ResourceExhausted)
+	}
+}
+
+func TestAmendmentActor(t *testing.T) {
+	keys := auth.NewAPIKeys(map[string]auth.Principal{
+		"admin": {Subject: "booking-desk", Roles: []auth.Role{auth.RoleAdmin}},
+	})
+
+	conn, stop := dial(t, WithAuthentication(keys))
+	defer stop()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "admin")
+
+	bc := pb.NewBookingServiceClient(conn)
+
+	booked, err := bc.BookNewCargo(ctx, &pb.BookNewCargoRequest{
+		Origin:          "SESTO",
+		Destination:     "AUMEL",
+		ArrivalDeadline: timestamppb.New(time.Date(2009, time.March, 18, 12, 0, 0, 0, time.UTC)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bc.ChangeDestination(ctx, &pb.ChangeDestinationRequest{TrackingId: booked.TrackingId, Destination: "CNHKG"}); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := bc.History(ctx, &pb.HistoryRequest{TrackingId: booked.TrackingId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Amendments) != 1 || history.Amendments[0].Actor != "booking-desk" {
+		t.Errorf("amendments = %v; want one by booking-desk", history.Amendments)
+	}
+}
@@ -0,0 +1,60 @@
+package grpcserver
+
+import (
+	"context"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+
+	"github.com/marcusolsson/goddd/pb"
+	"github.com/marcusolsson/goddd/tracking"
+)
+
+type trackingServer struct {
+	pb.UnimplementedTrackingServiceServer
+
+	track kitgrpc.Handler
+}
+
+func newTrackingServer(e tracking.Endpoints, opts []kitgrpc.ServerOption) pb.TrackingServiceServer {
+	return &trackingServer{
+		track: kitgrpc.NewServer(e.TrackEndpoint, decodeTrackRequest, encodeTrackResponse, opts...),
+	}
+}
+
+func (s *trackingServer) Track(ctx context.Context, req *pb.TrackRequest) (*pb.TrackResponse, error) {
+	_, resp, err := s.track.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return resp.(*pb.TrackResponse), nil
+}
+
+func decodeTrackRequest(_ context.Context, r interface{}) (interface{}, error) {
+	req := r.(*pb.TrackRequest)
+	return tracking.TrackRequest{ID: req.TrackingId}, nil
+}
+
+func encodeTrackResponse(_ context.Context, r interface{}) (interface{}, error) {
+	c := r.(tracking.TrackResponse).Cargo
+
+	var events []*pb.TrackedCargo_Event
+	for _, e := range c.Events {
+		events = append(events, &pb.TrackedCargo_Event{
+			Description: e.Description,
+			Expected:    e.Expected,
+		})
+	}
+
+	return &pb.TrackResponse{
+		Cargo: &pb.TrackedCargo{
+			TrackingId:           c.TrackingID,
+			StatusText:           c.StatusText,
+			Origin:               c.Origin,
+			Destination:          c.Destination,
+			Eta:                  fromTime(c.ETA),
+			NextExpectedActivity: c.NextExpectedActivity,
+			ArrivalDeadline:      fromTime(c.ArrivalDeadline),
+			Events:               events,
+		},
+	}, nil
+}
@@ -9,6 +9,7 @@ package shipping
 // It would make sense not having the cargo package depend on handling.
 
 import (
+	"context"
 	"errors"
 	"time"
 )
@@ -77,8 +78,8 @@ func (h HandlingHistory) MostRecentlyCompletedEvent() (HandlingEvent, error) {
 
 // HandlingEventRepository provides access a handling event store.
 type HandlingEventRepository interface {
-	Store(e HandlingEvent)
-	QueryHandlingHistory(TrackingID) HandlingHistory
+	Store(ctx context.Context, e HandlingEvent)
+	QueryHandlingHistory(context.Context, TrackingID) HandlingHistory
 }
 
 // HandlingEventFactory creates handling events.
@@ -89,21 +90,21 @@ type HandlingEventFactory struct {
 }
 
 // CreateHandlingEvent creates a validated handling event.
-func (f *HandlingEventFactory) CreateHandlingEvent(registered time.Time, completed time.Time, id TrackingID,
+func (f *HandlingEventFactory) CreateHandlingEvent(ctx context.Context, registered time.Time, completed time.Time, id TrackingID,
 	voyageNumber VoyageNumber, unLocode UNLocode, eventType HandlingEventType) (HandlingEvent, error) {
 
-	if _, err := f.CargoRepository.Find(id); err != nil {
+	if _, err := f.CargoRepository.Find(ctx, id); err != nil {
 		return HandlingEvent{}, err
 	}
 
-	if _, err := f.VoyageRepository.Find(voyageNumber); err != nil {
+	if _, err := f.VoyageRepository.Find(ctx, voyageNumber); err != nil {
 		// TODO: This is pretty ugly, but when creating a Receive event, the voyage number is not known.
 		if len(voyageNumber) > 0 {
 			return HandlingEvent{}, err
 		}
 	}
 
-	if _, err := f.LocationRepository.Find(unLocode); err != nil {
+	if _, err := f.LocationRepository.Find(ctx, unLocode); err != nil {
 		return HandlingEvent{}, err
 	}
 
@@ -0,0 +1,48 @@
+package handling
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// Endpoints collects the endpoints of a handling service, to be exposed by a
+// transport.
+type Endpoints struct {
+	RegisterHandlingEventEndpoint endpoint.Endpoint
+}
+
+// MakeEndpoints returns the endpoints of s.
+func MakeEndpoints(s Service) Endpoints {
+	return Endpoints{
+		RegisterHandlingEventEndpoint: makeRegisterHandlingEventEndpoint(s),
+	}
+}
+
+// RegisterHandlingEventRequest is the request of the RegisterHandlingEvent
+// endpoint.
+type RegisterHandlingEventRequest struct {
+	CompletionTime time.Time
+	ID             shipping.TrackingID
+	VoyageNumber   shipping.VoyageNumber
+	Location       shipping.UNLocode
+	EventType      shipping.HandlingEventType
+}
+
+// RegisterHandlingEventResponse is the response of the
+// RegisterHandlingEvent endpoint.
+type RegisterHandlingEventResponse struct{}
+
+func makeRegisterHandlingEventEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RegisterHandlingEventRequest)
+		err := s.RegisterHandlingEvent(ctx, req.CompletionTime, req.ID, req.VoyageNumber, req.Location, req.EventType)
+		if err != nil {
+			return nil, err
+		}
+		return RegisterHandlingEventResponse{}, nil
+	}
+}
@@ -1,6 +1,7 @@
 package handling
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-kit/kit/metrics"
@@ -23,7 +24,7 @@ func NewInstrumentingService(counter metrics.Counter, latency metrics.Histogram,
 	}
 }
 
-func (s *instrumentingService) RegisterHandlingEvent(completed time.Time, id shipping.TrackingID, voyageNumber shipping.VoyageNumber,
+func (s *instrumentingService) RegisterHandlingEvent(ctx context.Context, completed time.Time, id shipping.TrackingID, voyageNumber shipping.VoyageNumber,
 	loc shipping.UNLocode, eventType shipping.HandlingEventType) error {
 
 	defer func(begin time.Time) {
@@ -31,5 +32,5 @@ func (s *instrumentingService) RegisterHandlingEvent(completed time.Time, id shi
 		s.requestLatency.With("method", "register_incident").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return s.next.RegisterHandlingEvent(completed, id, voyageNumber, loc, eventType)
+	return s.next.RegisterHandlingEvent(ctx, completed, id, voyageNumber, loc, eventType)
 }
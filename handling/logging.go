@@ -1,6 +1,7 @@
 package handling
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-kit/kit/log"
@@ -18,7 +19,7 @@ func NewLoggingService(logger log.Logger, s Service) Service {
 	return &loggingService{logger, s}
 }
 
-func (s *loggingService) RegisterHandlingEvent(completed time.Time, id shipping.TrackingID, voyageNumber shipping.VoyageNumber,
+func (s *loggingService) RegisterHandlingEvent(ctx context.Context, completed time.Time, id shipping.TrackingID, voyageNumber shipping.VoyageNumber,
 	unLocode shipping.UNLocode, eventType shipping.HandlingEventType) (err error) {
 	defer func(begin time.Time) {
 		s.logger.Log(
@@ -32,5 +33,5 @@ func (s *loggingService) RegisterHandlingEvent(completed time.Time, id shipping.
 			"err", err,
 		)
 	}(time.Now())
-	return s.next.RegisterHandlingEvent(completed, id, voyageNumber, unLocode, eventType)
+	return s.next.RegisterHandlingEvent(ctx, completed, id, voyageNumber, unLocode, eventType)
 }
@@ -3,7 +3,7 @@
 package handling
 
 import (
-	"errors"
+	"context"
 	"time"
 
 	shipping "github.com/marcusolsson/goddd"
@@ -11,18 +11,34 @@ import (
 )
 
 // ErrInvalidArgument is returned when one or more arguments are invalid.
-var ErrInvalidArgument = errors.New("invalid argument")
+var ErrInvalidArgument = shipping.NewError(shipping.CodeInvalidArgument, "invalid argument")
+
+// errRequired describes a required field that is missing.
+func errRequired(name string) shipping.FieldError {
+	return shipping.FieldError{Name: name, Reason: "is required"}
+}
 
 // EventHandler provides a means of subscribing to registered handling events.
 type EventHandler interface {
-	CargoWasHandled(shipping.HandlingEvent)
+	CargoWasHandled(context.Context, shipping.HandlingEvent)
+}
+
+// EventHandlers is an EventHandler that notifies each of its handlers, in
+// order.
+type EventHandlers []EventHandler
+
+// CargoWasHandled notifies each of the handlers.
+func (hs EventHandlers) CargoWasHandled(ctx context.Context, e shipping.HandlingEvent) {
+	for _, h := range hs {
+		h.CargoWasHandled(ctx, e)
+	}
 }
 
 // Service provides handling operations.
 type Service interface {
 	// RegisterHandlingEvent registers a handling event in the system, and
 	// notifies interested parties that a cargo has been handled.
-	RegisterHandlingEvent(completed time.Time, id shipping.TrackingID, voyageNumber shipping.VoyageNumber,
+	RegisterHandlingEvent(ctx context.Context, completed time.Time, id shipping.TrackingID, voyageNumber shipping.VoyageNumber,
 		unLocode shipping.UNLocode, eventType shipping.HandlingEventType) error
 }
 
@@ -32,19 +48,32 @@ type service struct {
 	handlingEventHandler    EventHandler
 }
 
-func (s *service) RegisterHandlingEvent(completed time.Time, id shipping.TrackingID, voyageNumber shipping.VoyageNumber,
+func (s *service) RegisterHandlingEvent(ctx context.Context, completed time.Time, id shipping.TrackingID, voyageNumber shipping.VoyageNumber,
 	loc shipping.UNLocode, eventType shipping.HandlingEventType) error {
-	if completed.IsZero() || id == "" || loc == "" || eventType == shipping.NotHandled {
-		return ErrInvalidArgument
+	var fields []shipping.FieldError
+	if completed.IsZero() {
+		fields = append(fields, errRequired("completion_time"))
+	}
+	if id == "" {
+		fields = append(fields, errRequired("tracking_id"))
+	}
+	if loc == "" {
+		fields = append(fields, errRequired("location"))
+	}
+	if eventType == shipping.NotHandled {
+		fields = append(fields, errRequired("event_type"))
+	}
+	if len(fields) > 0 {
+		return ErrInvalidArgument.WithFields(fields...)
 	}
 
-	e, err := s.handlingEventFactory.CreateHandlingEvent(time.Now(), completed, id, voyageNumber, loc, eventType)
+	e, err := s.handlingEventFactory.CreateHandlingEvent(ctx, time.Now(), completed, id, voyageNumber, loc, eventType)
 	if err != nil {
 		return err
 	}
 
-	s.handlingEventRepository.Store(e)
-	s.handlingEventHandler.CargoWasHandled(e)
+	s.handlingEventRepository.Store(ctx, e)
+	s.handlingEventHandler.CargoWasHandled(ctx, e)
 
 	return nil
 }
@@ -62,8 +91,8 @@ type handlingEventHandler struct {
 	InspectionService inspection.Service
 }
 
-func (h *handlingEventHandler) CargoWasHandled(event shipping.HandlingEvent) {
-	h.InspectionService.InspectCargo(event.TrackingID)
+func (h *handlingEventHandler) CargoWasHandled(ctx context.Context, event shipping.HandlingEvent) {
+	h.InspectionService.InspectCargo(ctx, event.TrackingID)
 }
 
 // NewEventHandler returns a new instance of a EventHandler.
@@ -1,6 +1,7 @@
 package handling
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -12,11 +13,13 @@ type stubEventHandler struct {
 	events []interface{}
 }
 
-func (h *stubEventHandler) CargoWasHandled(e shipping.HandlingEvent) {
+func (h *stubEventHandler) CargoWasHandled(_ context.Context, e shipping.HandlingEvent) {
 	h.events = append(h.events, e)
 }
 
 func TestRegisterHandlingEvent(t *testing.T) {
+	ctx := context.Background()
+
 	var cargos mock.CargoRepository
 	cargos.StoreFn = func(c *shipping.Cargo) error {
 		return nil
@@ -58,17 +61,17 @@ func TestRegisterHandlingEvent(t *testing.T) {
 
 	var err error
 
-	err = cargos.Store(shipping.NewCargo(id, shipping.RouteSpecification{}))
+	err = cargos.Store(ctx, shipping.NewCargo(id, shipping.RouteSpecification{}))
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = s.RegisterHandlingEvent(completed, id, voyage, shipping.SESTO, shipping.Load)
+	err = s.RegisterHandlingEvent(ctx, completed, id, voyage, shipping.SESTO, shipping.Load)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	err = s.RegisterHandlingEvent(completed, "no_such_id", voyage, shipping.SESTO, shipping.Load)
+	err = s.RegisterHandlingEvent(ctx, completed, "no_such_id", voyage, shipping.SESTO, shipping.Load)
 	if err != shipping.ErrUnknownCargo {
 		t.Errorf("err = %s; want = %s", err, shipping.ErrUnknownCargo)
 	}
@@ -0,0 +1,40 @@
+package handling
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+type tracingService struct {
+	tracer trace.Tracer
+	next   Service
+}
+
+// NewTracingService returns a new instance of a tracing Service.
+func NewTracingService(tracer trace.Tracer, s Service) Service {
+	return &tracingService{tracer, s}
+}
+
+func (s *tracingService) RegisterHandlingEvent(ctx context.Context, completed time.Time, id shipping.TrackingID, voyageNumber shipping.VoyageNumber,
+	unLocode shipping.UNLocode, eventType shipping.HandlingEventType) (err error) {
+	ctx, span := s.tracer.Start(ctx, "handling.RegisterHandlingEvent", trace.WithAttributes(
+		attribute.String("tracking_id", string(id)),
+		attribute.String("location", string(unLocode)),
+		attribute.String("voyage", string(voyageNumber)),
+		attribute.String("event_type", eventType.String()),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+	return s.next.RegisterHandlingEvent(ctx, completed, id, voyageNumber, unLocode, eventType)
+}
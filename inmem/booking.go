@@ -0,0 +1,134 @@
+package inmem
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/pricing"
+)
+
+// candidateRetention is how long route candidates are kept after they
+// expire, so that assigning them fails as stale rather than unknown.
+const candidateRetention = time.Hour
+
+type routeCandidateRepository struct {
+	mtx        sync.RWMutex
+	candidates map[booking.RouteCandidateID]*booking.RouteCandidate
+}
+
+func (r *routeCandidateRepository) Store(_ context.Context, c *booking.RouteCandidate) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	// Forget candidates that can no longer be assigned, so that the
+	// repository doesn't grow with every request for routes.
+	cutoff := time.Now().Add(-candidateRetention)
+	for id, val := range r.candidates {
+		if val.ExpiresAt.Before(cutoff) {
+			delete(r.candidates, id)
+		}
+	}
+
+	r.candidates[c.ID] = copyRouteCandidate(c)
+	return nil
+}
+
+func (r *routeCandidateRepository) Find(_ context.Context, id booking.RouteCandidateID) (*booking.RouteCandidate, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	if c, ok := r.candidates[id]; ok {
+		return copyRouteCandidate(c), nil
+	}
+	return nil, booking.ErrUnknownRouteCandidate
+}
+
+// NewRouteCandidateRepository returns a new instance of a in-memory route
+// candidate repository.
+func NewRouteCandidateRepository() booking.RouteCandidateRepository {
+	return &routeCandidateRepository{
+		candidates: make(map[booking.RouteCandidateID]*booking.RouteCandidate),
+	}
+}
+
+type amendmentRepository struct {
+	mtx        sync.RWMutex
+	amendments map[shipping.TrackingID][]*booking.Amendment
+}
+
+func (r *amendmentRepository) Store(_ context.Context, a *booking.Amendment) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	as := r.amendments[a.TrackingID]
+	for i, val := range as {
+		if val.ID == a.ID {
+			as[i] = copyAmendment(a)
+			return nil
+		}
+	}
+	r.amendments[a.TrackingID] = append(as, copyAmendment(a))
+	return nil
+}
+
+func (r *amendmentRepository) Remove(_ context.Context, id booking.AmendmentID) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for trackingID, as := range r.amendments {
+		for i, val := range as {
+			if val.ID == id {
+				r.amendments[trackingID] = append(as[:i:i], as[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (r *amendmentRepository) FindByTrackingID(_ context.Context, id shipping.TrackingID) []*booking.Amendment {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	as := make([]*booking.Amendment, 0, len(r.amendments[id]))
+	for _, val := range r.amendments[id] {
+		as = append(as, copyAmendment(val))
+	}
+	return as
+}
+
+// NewAmendmentRepository returns a new instance of a in-memory booking
+// amendment repository.
+func NewAmendmentRepository() booking.AmendmentRepository {
+	return &amendmentRepository{
+		amendments: make(map[shipping.TrackingID][]*booking.Amendment),
+	}
+}
+
+func copyRouteCandidate(c *booking.RouteCandidate) *booking.RouteCandidate {
+	cc := *c
+	cc.Itinerary = copyItinerary(c.Itinerary)
+	cc.Costs = append([]shipping.Cost(nil), c.Costs...)
+	if c.Quote != nil {
+		q := *c.Quote
+		q.Lines = append([]pricing.Line(nil), c.Quote.Lines...)
+		cc.Quote = &q
+	}
+	return &cc
+}
+
+func copyAmendment(a *booking.Amendment) *booking.Amendment {
+	c := *a
+	c.Previous = copyTerms(a.Previous)
+	c.New = copyTerms(a.New)
+	return &c
+}
+
+func copyTerms(t booking.Terms) booking.Terms {
+	c := t
+	c.Legs = append([]shipping.Leg(nil), t.Legs...)
+	if t.Price != nil {
+		p := *t.Price
+		c.Price = &p
+	}
+	return c
+}
@@ -2,6 +2,7 @@
 package inmem
 
 import (
+	"context"
 	"sync"
 
 	shipping "github.com/marcusolsson/goddd"
@@ -12,32 +13,36 @@ type cargoRepository struct {
 	cargos map[shipping.TrackingID]*shipping.Cargo
 }
 
-func (r *cargoRepository) Store(c *shipping.Cargo) error {
+func (r *cargoRepository) Store(_ context.Context, c *shipping.Cargo) error {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
-	r.cargos[c.TrackingID] = c
+	r.cargos[c.TrackingID] = copyCargo(c)
 	return nil
 }
 
-func (r *cargoRepository) Find(id shipping.TrackingID) (*shipping.Cargo, error) {
+func (r *cargoRepository) Find(_ context.Context, id shipping.TrackingID) (*shipping.Cargo, error) {
 	r.mtx.RLock()
 	defer r.mtx.RUnlock()
 	if val, ok := r.cargos[id]; ok {
-		return val, nil
+		return copyCargo(val), nil
 	}
 	return nil, shipping.ErrUnknownCargo
 }
 
-func (r *cargoRepository) FindAll() []*shipping.Cargo {
+func (r *cargoRepository) FindAll(_ context.Context) []*shipping.Cargo {
 	r.mtx.RLock()
 	defer r.mtx.RUnlock()
 	c := make([]*shipping.Cargo, 0, len(r.cargos))
 	for _, val := range r.cargos {
-		c = append(c, val)
+		c = append(c, copyCargo(val))
 	}
 	return c
 }
 
+func (r *cargoRepository) Query(ctx context.Context, q shipping.CargoQuery) (shipping.CargoPage, error) {
+	return q.Apply(r.FindAll(ctx))
+}
+
 // NewCargoRepository returns a new instance of a in-memory cargo repository.
 func NewCargoRepository() shipping.CargoRepository {
 	return &cargoRepository{
@@ -46,20 +51,35 @@ func NewCargoRepository() shipping.CargoRepository {
 }
 
 type locationRepository struct {
+	mtx       sync.RWMutex
 	locations map[shipping.UNLocode]*shipping.Location
 }
 
-func (r *locationRepository) Find(locode shipping.UNLocode) (*shipping.Location, error) {
+func (r *locationRepository) Store(_ context.Context, l *shipping.Location) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	c := *l
+	r.locations[l.UNLocode] = &c
+	return nil
+}
+
+func (r *locationRepository) Find(_ context.Context, locode shipping.UNLocode) (*shipping.Location, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
 	if l, ok := r.locations[locode]; ok {
-		return l, nil
+		c := *l
+		return &c, nil
 	}
 	return nil, shipping.ErrUnknownLocation
 }
 
-func (r *locationRepository) FindAll() []*shipping.Location {
+func (r *locationRepository) FindAll(_ context.Context) []*shipping.Location {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
 	l := make([]*shipping.Location, 0, len(r.locations))
 	for _, val := range r.locations {
-		l = append(l, val)
+		c := *val
+		l = append(l, &c)
 	}
 	return l
 }
@@ -81,17 +101,37 @@ func NewLocationRepository() shipping.LocationRepository {
 }
 
 type voyageRepository struct {
+	mtx     sync.RWMutex
 	voyages map[shipping.VoyageNumber]*shipping.Voyage
 }
 
-func (r *voyageRepository) Find(voyageNumber shipping.VoyageNumber) (*shipping.Voyage, error) {
+func (r *voyageRepository) Store(_ context.Context, v *shipping.Voyage) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.voyages[v.VoyageNumber] = copyVoyage(v)
+	return nil
+}
+
+func (r *voyageRepository) Find(_ context.Context, voyageNumber shipping.VoyageNumber) (*shipping.Voyage, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
 	if v, ok := r.voyages[voyageNumber]; ok {
-		return v, nil
+		return copyVoyage(v), nil
 	}
 
 	return nil, shipping.ErrUnknownVoyage
 }
 
+func (r *voyageRepository) FindAll(_ context.Context) []*shipping.Voyage {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	v := make([]*shipping.Voyage, 0, len(r.voyages))
+	for _, val := range r.voyages {
+		v = append(v, copyVoyage(val))
+	}
+	return v
+}
+
 // NewVoyageRepository returns a new instance of a in-memory voyage repository.
 func NewVoyageRepository() shipping.VoyageRepository {
 	r := &voyageRepository{
@@ -116,7 +156,7 @@ type handlingEventRepository struct {
 	events map[shipping.TrackingID][]shipping.HandlingEvent
 }
 
-func (r *handlingEventRepository) Store(e shipping.HandlingEvent) {
+func (r *handlingEventRepository) Store(_ context.Context, e shipping.HandlingEvent) {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
 	// Make array if it's the first event with this tracking ID.
@@ -126,10 +166,12 @@ func (r *handlingEventRepository) Store(e shipping.HandlingEvent) {
 	r.events[e.TrackingID] = append(r.events[e.TrackingID], e)
 }
 
-func (r *handlingEventRepository) QueryHandlingHistory(id shipping.TrackingID) shipping.HandlingHistory {
+func (r *handlingEventRepository) QueryHandlingHistory(_ context.Context, id shipping.TrackingID) shipping.HandlingHistory {
 	r.mtx.RLock()
 	defer r.mtx.RUnlock()
-	return shipping.HandlingHistory{HandlingEvents: r.events[id]}
+	events := make([]shipping.HandlingEvent, len(r.events[id]))
+	copy(events, r.events[id])
+	return shipping.HandlingHistory{HandlingEvents: events}
 }
 
 // NewHandlingEventRepository returns a new instance of a in-memory handling event repository.
@@ -138,3 +180,36 @@ func NewHandlingEventRepository() shipping.HandlingEventRepository {
 		events: make(map[shipping.TrackingID][]shipping.HandlingEvent),
 	}
 }
+
+// copyCargo returns a deep copy of c, so that callers can't modify the stored
+// cargo without going through Store.
+func copyCargo(c *shipping.Cargo) *shipping.Cargo {
+	cc := *c
+	cc.RouteSpecification = copyRouteSpecification(c.RouteSpecification)
+	cc.Itinerary = copyItinerary(c.Itinerary)
+	cc.Delivery.Itinerary = copyItinerary(c.Delivery.Itinerary)
+	cc.Delivery.RouteSpecification = copyRouteSpecification(c.Delivery.RouteSpecification)
+	return &cc
+}
+
+func copyRouteSpecification(rs shipping.RouteSpecification) shipping.RouteSpecification {
+	if rs.Constraints.AvoidLocations != nil {
+		rs.Constraints.AvoidLocations = append([]shipping.UNLocode(nil), rs.Constraints.AvoidLocations...)
+	}
+	return rs
+}
+
+func copyItinerary(i shipping.Itinerary) shipping.Itinerary {
+	if i.Legs == nil {
+		return i
+	}
+	legs := make([]shipping.Leg, len(i.Legs))
+	copy(legs, i.Legs)
+	return shipping.Itinerary{Legs: legs}
+}
+
+func copyVoyage(v *shipping.Voyage) *shipping.Voyage {
+	movements := make([]shipping.CarrierMovement, len(v.Schedule.CarrierMovements))
+	copy(movements, v.Schedule.CarrierMovements)
+	return shipping.NewVoyage(v.VoyageNumber, shipping.Schedule{CarrierMovements: movements})
+}
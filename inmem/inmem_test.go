@@ -0,0 +1,52 @@
+package inmem
+
+import (
+	"testing"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/repotest"
+)
+
+func TestCargoRepository(t *testing.T) {
+	repotest.TestCargoRepository(t, NewCargoRepository)
+}
+
+func TestLocationRepository(t *testing.T) {
+	repotest.TestLocationRepository(t, NewLocationRepository(), []*shipping.Location{
+		shipping.Stockholm,
+		shipping.Melbourne,
+		shipping.Hongkong,
+		shipping.Tokyo,
+		shipping.Rotterdam,
+		shipping.Hamburg,
+	})
+}
+
+func TestVoyageRepository(t *testing.T) {
+	repotest.TestVoyageRepository(t, NewVoyageRepository(), []*shipping.Voyage{
+		shipping.V100,
+		shipping.V300,
+		shipping.V400,
+		shipping.V0100S,
+	})
+}
+
+func TestHandlingEventRepository(t *testing.T) {
+	repotest.TestHandlingEventRepository(t, NewHandlingEventRepository)
+}
+
+func TestSubscriptionRepository(t *testing.T) {
+	repotest.TestSubscriptionRepository(t, NewSubscriptionRepository)
+}
+
+func TestDeliveryRepository(t *testing.T) {
+	repotest.TestDeliveryRepository(t, NewDeliveryRepository)
+}
+
+func TestRouteCandidateRepository(t *testing.T) {
+	repotest.TestRouteCandidateRepository(t, NewRouteCandidateRepository)
+}
+
+func TestAmendmentRepository(t *testing.T) {
+	repotest.TestAmendmentRepository(t, NewAmendmentRepository)
+}
@@ -0,0 +1,121 @@
+package inmem
+
+import (
+	"context"
+	"sync"
+
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+type subscriptionRepository struct {
+	mtx           sync.RWMutex
+	subscriptions map[webhook.SubscriptionID]*webhook.Subscription
+}
+
+func (r *subscriptionRepository) Store(_ context.Context, s *webhook.Subscription) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.subscriptions[s.ID] = copySubscription(s)
+	return nil
+}
+
+func (r *subscriptionRepository) Find(_ context.Context, id webhook.SubscriptionID) (*webhook.Subscription, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	if s, ok := r.subscriptions[id]; ok {
+		return copySubscription(s), nil
+	}
+	return nil, webhook.ErrUnknownSubscription
+}
+
+func (r *subscriptionRepository) FindAll(_ context.Context) []*webhook.Subscription {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	s := make([]*webhook.Subscription, 0, len(r.subscriptions))
+	for _, val := range r.subscriptions {
+		s = append(s, copySubscription(val))
+	}
+	return s
+}
+
+func (r *subscriptionRepository) Remove(_ context.Context, id webhook.SubscriptionID) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.subscriptions[id]; !ok {
+		return webhook.ErrUnknownSubscription
+	}
+	delete(r.subscriptions, id)
+	return nil
+}
+
+// NewSubscriptionRepository returns a new instance of a in-memory webhook
+// subscription repository.
+func NewSubscriptionRepository() webhook.SubscriptionRepository {
+	return &subscriptionRepository{
+		subscriptions: make(map[webhook.SubscriptionID]*webhook.Subscription),
+	}
+}
+
+type deliveryRepository struct {
+	mtx        sync.RWMutex
+	deliveries map[webhook.SubscriptionID][]*webhook.Delivery
+}
+
+func (r *deliveryRepository) Store(_ context.Context, d *webhook.Delivery) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	ds := r.deliveries[d.Subscription]
+	for i, val := range ds {
+		if val.ID == d.ID {
+			ds[i] = copyDelivery(d)
+			return nil
+		}
+	}
+	r.deliveries[d.Subscription] = append(ds, copyDelivery(d))
+	return nil
+}
+
+func (r *deliveryRepository) FindBySubscription(_ context.Context, id webhook.SubscriptionID) []*webhook.Delivery {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	ds := make([]*webhook.Delivery, 0, len(r.deliveries[id]))
+	for _, val := range r.deliveries[id] {
+		ds = append(ds, copyDelivery(val))
+	}
+	return ds
+}
+
+func (r *deliveryRepository) FindPending(_ context.Context) []*webhook.Delivery {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	var ds []*webhook.Delivery
+	for _, vals := range r.deliveries {
+		for _, val := range vals {
+			if val.Status == webhook.Pending {
+				ds = append(ds, copyDelivery(val))
+			}
+		}
+	}
+	return ds
+}
+
+// NewDeliveryRepository returns a new instance of a in-memory webhook
+// delivery repository.
+func NewDeliveryRepository() webhook.DeliveryRepository {
+	return &deliveryRepository{
+		deliveries: make(map[webhook.SubscriptionID][]*webhook.Delivery),
+	}
+}
+
+func copySubscription(s *webhook.Subscription) *webhook.Subscription {
+	c := *s
+	c.Events = append([]webhook.EventType(nil), s.Events...)
+	return &c
+}
+
+func copyDelivery(d *webhook.Delivery) *webhook.Delivery {
+	c := *d
+	c.Payload = append([]byte(nil), d.Payload...)
+	c.Attempts = append([]webhook.Attempt(nil), d.Attempts...)
+	return &c
+}
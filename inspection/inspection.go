@@ -2,13 +2,33 @@
 package inspection
 
 import (
+	"context"
+
 	shipping "github.com/marcusolsson/goddd"
 )
 
 // EventHandler provides means of subscribing to inspection events.
 type EventHandler interface {
-	CargoWasMisdirected(*shipping.Cargo)
-	CargoHasArrived(*shipping.Cargo)
+	CargoWasMisdirected(context.Context, *shipping.Cargo)
+	CargoHasArrived(context.Context, *shipping.Cargo)
+}
+
+// EventHandlers is an EventHandler that notifies each of its handlers, in
+// order.
+type EventHandlers []EventHandler
+
+// CargoWasMisdirected notifies each of the handlers.
+func (hs EventHandlers) CargoWasMisdirected(ctx context.Context, c *shipping.Cargo) {
+	for _, h := range hs {
+		h.CargoWasMisdirected(ctx, c)
+	}
+}
+
+// CargoHasArrived notifies each of the handlers.
+func (hs EventHandlers) CargoHasArrived(ctx context.Context, c *shipping.Cargo) {
+	for _, h := range hs {
+		h.CargoHasArrived(ctx, c)
+	}
 }
 
 // Service provides cargo inspection operations.
@@ -16,7 +36,7 @@ type Service interface {
 	// InspectCargo inspects cargo and send relevant notifications to
 	// interested parties, for example if a cargo has been misdirected, or
 	// unloaded at the final destination.
-	InspectCargo(id shipping.TrackingID)
+	InspectCargo(ctx context.Context, id shipping.TrackingID)
 }
 
 type service struct {
@@ -26,25 +46,29 @@ type service struct {
 }
 
 // TODO: Should be transactional
-func (s *service) InspectCargo(id shipping.TrackingID) {
-	c, err := s.cargos.Find(id)
+func (s *service) InspectCargo(ctx context.Context, id shipping.TrackingID) {
+	c, err := s.cargos.Find(ctx, id)
 	if err != nil {
 		return
 	}
 
-	h := s.events.QueryHandlingHistory(id)
+	h := s.events.QueryHandlingHistory(ctx, id)
 
 	c.DeriveDeliveryProgress(h)
 
+	// Store the re-derived delivery first, so that handlers reading the
+	// cargo see it.
+	if err := s.cargos.Store(ctx, c); err != nil {
+		return
+	}
+
 	if c.Delivery.IsMisdirected {
-		s.handler.CargoWasMisdirected(c)
+		s.handler.CargoWasMisdirected(ctx, c)
 	}
 
 	if c.Delivery.IsUnloadedAtDestination {
-		s.handler.CargoHasArrived(c)
+		s.handler.CargoHasArrived(ctx, c)
 	}
-
-	s.cargos.Store(c)
 }
 
 // NewService creates a inspection service with necessary dependencies.
@@ -1,6 +1,7 @@
 package inspection
 
 import (
+	"context"
 	"testing"
 
 	shipping "github.com/marcusolsson/goddd"
@@ -10,15 +11,17 @@ type stubEventHandler struct {
 	events []interface{}
 }
 
-func (h *stubEventHandler) CargoWasMisdirected(c *shipping.Cargo) {
+func (h *stubEventHandler) CargoWasMisdirected(_ context.Context, c *shipping.Cargo) {
 	h.events = append(h.events, c)
 }
 
-func (h *stubEventHandler) CargoHasArrived(c *shipping.Cargo) {
+func (h *stubEventHandler) CargoHasArrived(_ context.Context, c *shipping.Cargo) {
 	h.events = append(h.events, c)
 }
 
 func TestInspectMisdirectedCargo(t *testing.T) {
+	ctx := context.Background()
+
 	var cargos mockCargoRepository
 
 	events := mockHandlingEventRepository{
@@ -42,25 +45,25 @@ func TestInspectMisdirectedCargo(t *testing.T) {
 		{VoyageNumber: voyage, LoadLocation: shipping.AUMEL, UnloadLocation: shipping.CNHKG},
 	}})
 
-	if err := cargos.Store(c); err != nil {
+	if err := cargos.Store(ctx, c); err != nil {
 		t.Fatal(err)
 	}
 
-	storeEvent(&events, id, voyage, shipping.Receive, shipping.SESTO)
-	storeEvent(&events, id, voyage, shipping.Load, shipping.SESTO)
-	storeEvent(&events, id, voyage, shipping.Unload, shipping.USNYC)
+	storeEvent(ctx, &events, id, voyage, shipping.Receive, shipping.SESTO)
+	storeEvent(ctx, &events, id, voyage, shipping.Load, shipping.SESTO)
+	storeEvent(ctx, &events, id, voyage, shipping.Unload, shipping.USNYC)
 
 	if len(handler.events) != 0 {
 		t.Errorf("no events should be handled")
 	}
 
-	s.InspectCargo(id)
+	s.InspectCargo(ctx, id)
 
 	if len(handler.events) != 1 {
 		t.Errorf("1 event should be handled")
 	}
 
-	s.InspectCargo("no_such_id")
+	s.InspectCargo(ctx, "no_such_id")
 
 	// no events was published
 	if len(handler.events) != 1 {
@@ -69,6 +72,8 @@ func TestInspectMisdirectedCargo(t *testing.T) {
 }
 
 func TestInspectUnloadedCargo(t *testing.T) {
+	ctx := context.Background()
+
 	var cargos mockCargoRepository
 
 	events := mockHandlingEventRepository{
@@ -96,26 +101,26 @@ func TestInspectUnloadedCargo(t *testing.T) {
 		{VoyageNumber: voyage, LoadLocation: shipping.AUMEL, UnloadLocation: shipping.CNHKG},
 	}})
 
-	cargos.Store(unloadedCargo)
+	cargos.Store(ctx, unloadedCargo)
 
-	storeEvent(&events, id, voyage, shipping.Receive, shipping.SESTO)
-	storeEvent(&events, id, voyage, shipping.Load, shipping.SESTO)
-	storeEvent(&events, id, voyage, shipping.Unload, shipping.AUMEL)
-	storeEvent(&events, id, voyage, shipping.Load, shipping.AUMEL)
-	storeEvent(&events, id, voyage, shipping.Unload, shipping.CNHKG)
+	storeEvent(ctx, &events, id, voyage, shipping.Receive, shipping.SESTO)
+	storeEvent(ctx, &events, id, voyage, shipping.Load, shipping.SESTO)
+	storeEvent(ctx, &events, id, voyage, shipping.Unload, shipping.AUMEL)
+	storeEvent(ctx, &events, id, voyage, shipping.Load, shipping.AUMEL)
+	storeEvent(ctx, &events, id, voyage, shipping.Unload, shipping.CNHKG)
 
 	if len(handler.events) != 0 {
 		t.Errorf("len(handler.events) = %d; want = %d", len(handler.events), 0)
 	}
 
-	s.InspectCargo(id)
+	s.InspectCargo(ctx, id)
 
 	if len(handler.events) != 1 {
 		t.Errorf("len(handler.events) = %d; want = %d", len(handler.events), 1)
 	}
 }
 
-func storeEvent(r shipping.HandlingEventRepository, id shipping.TrackingID, voyageNumber shipping.VoyageNumber, typ shipping.HandlingEventType, loc shipping.UNLocode) {
+func storeEvent(ctx context.Context, r shipping.HandlingEventRepository, id shipping.TrackingID, voyageNumber shipping.VoyageNumber, typ shipping.HandlingEventType, loc shipping.UNLocode) {
 	e := shipping.HandlingEvent{
 		TrackingID: id,
 		Activity: shipping.HandlingActivity{
@@ -125,40 +130,44 @@ func storeEvent(r shipping.HandlingEventRepository, id shipping.TrackingID, voya
 		},
 	}
 
-	r.Store(e)
+	r.Store(ctx, e)
 }
 
 type mockCargoRepository struct {
 	cargo *shipping.Cargo
 }
 
-func (r *mockCargoRepository) Store(c *shipping.Cargo) error {
+func (r *mockCargoRepository) Store(_ context.Context, c *shipping.Cargo) error {
 	r.cargo = c
 	return nil
 }
 
-func (r *mockCargoRepository) Find(id shipping.TrackingID) (*shipping.Cargo, error) {
+func (r *mockCargoRepository) Find(_ context.Context, id shipping.TrackingID) (*shipping.Cargo, error) {
 	if r.cargo != nil {
 		return r.cargo, nil
 	}
 	return nil, shipping.ErrUnknownCargo
 }
 
-func (r *mockCargoRepository) FindAll() []*shipping.Cargo {
+func (r *mockCargoRepository) FindAll(_ context.Context) []*shipping.Cargo {
 	return []*shipping.Cargo{r.cargo}
 }
 
+func (r *mockCargoRepository) Query(_ context.Context, q shipping.CargoQuery) (shipping.CargoPage, error) {
+	return q.Apply([]*shipping.Cargo{r.cargo})
+}
+
 type mockHandlingEventRepository struct {
 	events map[shipping.TrackingID][]shipping.HandlingEvent
 }
 
-func (r *mockHandlingEventRepository) Store(e shipping.HandlingEvent) {
+func (r *mockHandlingEventRepository) Store(_ context.Context, e shipping.HandlingEvent) {
 	if _, ok := r.events[e.TrackingID]; !ok {
 		r.events[e.TrackingID] = make([]shipping.HandlingEvent, 0)
 	}
 	r.events[e.TrackingID] = append(r.events[e.TrackingID], e)
 }
 
-func (r *mockHandlingEventRepository) QueryHandlingHistory(id shipping.TrackingID) shipping.HandlingHistory {
+func (r *mockHandlingEventRepository) QueryHandlingHistory(_ context.Context, id shipping.TrackingID) shipping.HandlingHistory {
 	return shipping.HandlingHistory{HandlingEvents: r.events[id]}
 }
@@ -1,6 +1,8 @@
 package shipping
 
-import "errors"
+import (
+	"context"
+)
 
 // UNLocode is the United Nations location code that uniquely identifies a
 // particular location.
@@ -17,10 +19,11 @@ type Location struct {
 }
 
 // ErrUnknownLocation is used when a location could not be found.
-var ErrUnknownLocation = errors.New("unknown location")
+var ErrUnknownLocation = NewError(CodeUnknownLocation, "unknown location")
 
 // LocationRepository provides access a location store.
 type LocationRepository interface {
-	Find(locode UNLocode) (*Location, error)
-	FindAll() []*Location
+	Store(ctx context.Context, location *Location) error
+	Find(ctx context.Context, locode UNLocode) (*Location, error)
+	FindAll(ctx context.Context) []*Location
 }
@@ -0,0 +1,273 @@
+// Package metrics provides a Prometheus collector for the state of the
+// shipping domain, as opposed to the request metrics of the services.
+package metrics
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+const namespace = "shipping"
+
+// loadTimeout bounds reading every cargo from the repository the first time
+// the collector is scraped.
+const loadTimeout = 10 * time.Second
+
+// DefaultAtRiskWindow is how close to its arrival deadline a cargo that isn't
+// routed is considered at risk.
+const DefaultAtRiskWindow = 72 * time.Hour
+
+var (
+	cargosDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "cargos"),
+		"Number of booked cargos by routing and transport status.",
+		[]string{"routing_status", "transport_status"}, nil,
+	)
+	misdirectedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "cargos_misdirected"),
+		"Number of cargos that have been handled outside of their itinerary.",
+		nil, nil,
+	)
+	overdueDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "cargos_overdue"),
+		"Number of undelivered cargos past their arrival deadline.",
+		nil, nil,
+	)
+	atRiskDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "cargos_at_risk"),
+		"Number of undelivered cargos at risk of missing their arrival deadline.",
+		nil, nil,
+	)
+)
+
+// Collector collects metrics about cargos and their handling. The cargo
+// gauges are computed from the delivery of each cargo, which is read from
+// the cargo repository once, on the first scrape, and then kept up to date
+// as cargos are booked, amended and handled. Changes that bypass the booking
+// and handling services, such as an archive import or another instance
+// sharing the repository, aren't seen until the process restarts. Handling
+// events, routing failures and route cache lookups are counted as they
+// happen.
+type Collector struct {
+	// AtRiskWindow is how close to its arrival deadline a cargo that
+	// isn't routed is considered at risk.
+	AtRiskWindow time.Duration
+
+	cargos shipping.CargoRepository
+
+	loadMtx sync.Mutex
+	loaded  bool
+
+	mtx        sync.Mutex
+	deliveries map[shipping.TrackingID]shipping.Delivery
+
+	handlingEvents  *prometheus.CounterVec
+	routingFailures *prometheus.CounterVec
+	cacheLookups    *prometheus.CounterVec
+
+	now func() time.Time
+}
+
+// NewCollector returns a new Collector of metrics about the cargos in
+// cargos.
+func NewCollector(cargos shipping.CargoRepository) *Collector {
+	return &Collector{
+		AtRiskWindow: DefaultAtRiskWindow,
+		cargos:       cargos,
+		deliveries:   make(map[shipping.TrackingID]shipping.Delivery),
+		handlingEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "handling_events_total",
+			Help:      "Number of registered handling events by type and location.",
+		}, []string{"type", "location"}),
+		routingFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "routing_failures_total",
+			Help:      "Number of failed calls to the routing service by reason.",
+		}, []string{"reason"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "route_cache_lookups_total",
+			Help:      "Number of lookups in the route cache by result.",
+		}, []string{"result"}),
+		now: time.Now,
+	}
+}
+
+// CargoWasHandled counts a registered handling event and updates the
+// delivery of the handled cargo. It makes the collector a
+// handling.EventHandler.
+func (c *Collector) CargoWasHandled(ctx context.Context, e shipping.HandlingEvent) {
+	c.handlingEvents.WithLabelValues(labelValue(e.Activity.Type.String()), string(e.Activity.Location)).Inc()
+
+	cargo, err := c.cargos.Find(ctx, e.TrackingID)
+	if err != nil {
+		return
+	}
+	c.update(cargo)
+}
+
+// CargoWasAmended updates the delivery of a booked or amended cargo. It makes
+// the collector a booking.EventHandler.
+func (c *Collector) CargoWasAmended(_ context.Context, cargo *shipping.Cargo) {
+	c.update(cargo)
+}
+
+// update records the current delivery of cargo.
+func (c *Collector) update(cargo *shipping.Cargo) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.deliveries[cargo.TrackingID] = cargo.Delivery
+}
+
+// snapshot returns the delivery of every known cargo, reading them from the
+// repository unless that has already been done.
+func (c *Collector) snapshot() []shipping.Delivery {
+	c.load()
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	ds := make([]shipping.Delivery, 0, len(c.deliveries))
+	for _, d := range c.deliveries {
+		ds = append(ds, d)
+	}
+	return ds
+}
+
+// load reads the delivery of every cargo from the repository, unless that
+// has already been done. The hooks aren't held up while it does.
+func (c *Collector) load() {
+	c.loadMtx.Lock()
+	defer c.loadMtx.Unlock()
+
+	if c.loaded {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout)
+	defer cancel()
+
+	cargos := c.cargos.FindAll(ctx)
+
+	// Try again on the next scrape if the cargos couldn't all be read in
+	// time.
+	if ctx.Err() != nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, cargo := range cargos {
+		// Deliveries reported by the hooks are at least as recent.
+		if _, ok := c.deliveries[cargo.TrackingID]; !ok {
+			c.deliveries[cargo.TrackingID] = cargo.Delivery
+		}
+	}
+	c.loaded = true
+}
+
+// RoutingFailures returns the counter of failed calls to the routing
+// service, to be labeled with a "reason".
+func (c *Collector) RoutingFailures() metrics.Counter {
+	return kitprometheus.NewCounter(c.routingFailures)
+}
+
+// RouteCacheLookups returns the counter of lookups in the route cache, to be
+// labeled with a "result".
+func (c *Collector) RouteCacheLookups() metrics.Counter {
+	return kitprometheus.NewCounter(c.cacheLookups)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cargosDesc
+	ch <- misdirectedDesc
+	ch <- overdueDesc
+	ch <- atRiskDesc
+	c.handlingEvents.Describe(ch)
+	c.routingFailures.Describe(ch)
+	c.cacheLookups.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	type status struct {
+		routing   shipping.RoutingStatus
+		transport shipping.TransportStatus
+	}
+
+	var (
+		now                          = c.now()
+		statuses                     = make(map[status]int)
+		misdirected, overdue, atRisk int
+	)
+	for _, d := range c.snapshot() {
+		statuses[status{d.RoutingStatus, d.TransportStatus}]++
+
+		if d.IsMisdirected {
+			misdirected++
+		}
+
+		switch {
+		case isDelivered(d):
+		case isOverdue(d, now):
+			overdue++
+		case c.isAtRisk(d, now):
+			atRisk++
+		}
+	}
+
+	for s, n := range statuses {
+		ch <- prometheus.MustNewConstMetric(cargosDesc, prometheus.GaugeValue, float64(n),
+			labelValue(s.routing.String()), labelValue(s.transport.String()))
+	}
+	ch <- prometheus.MustNewConstMetric(misdirectedDesc, prometheus.GaugeValue, float64(misdirected))
+	ch <- prometheus.MustNewConstMetric(overdueDesc, prometheus.GaugeValue, float64(overdue))
+	ch <- prometheus.MustNewConstMetric(atRiskDesc, prometheus.GaugeValue, float64(atRisk))
+
+	c.handlingEvents.Collect(ch)
+	c.routingFailures.Collect(ch)
+	c.cacheLookups.Collect(ch)
+}
+
+// isDelivered returns whether the cargo has reached its destination.
+func isDelivered(d shipping.Delivery) bool {
+	return d.IsUnloadedAtDestination || d.TransportStatus == shipping.Claimed
+}
+
+// isOverdue returns whether the arrival deadline has passed.
+func isOverdue(d shipping.Delivery, now time.Time) bool {
+	deadline := d.RouteSpecification.ArrivalDeadline
+	return !deadline.IsZero() && now.After(deadline)
+}
+
+// isAtRisk returns whether the cargo is expected to miss its arrival
+// deadline: it has strayed from its itinerary, the itinerary arrives too
+// late, or it still isn't routed with little time left.
+func (c *Collector) isAtRisk(d shipping.Delivery, now time.Time) bool {
+	deadline := d.RouteSpecification.ArrivalDeadline
+	switch {
+	case d.IsMisdirected:
+		return true
+	case d.RoutingStatus != shipping.Routed:
+		return !deadline.IsZero() && deadline.Sub(now) < c.AtRiskWindow
+	default:
+		return !deadline.IsZero() && d.ETA.After(deadline)
+	}
+}
+
+// labelValue turns a status such as "Not routed" into "not_routed".
+func labelValue(s string) string {
+	return strings.Replace(strings.ToLower(s), " ", "_", -1)
+}
@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/inmem"
+)
+
+func TestCollector(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2009, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cargos := inmem.NewCargoRepository()
+
+	store := func(id shipping.TrackingID, deadline time.Time, d shipping.Delivery) {
+		c := shipping.NewCargo(id, shipping.RouteSpecification{
+			Origin:          shipping.SESTO,
+			Destination:     shipping.FIHEL,
+			ArrivalDeadline: deadline,
+		})
+		d.RouteSpecification = c.RouteSpecification
+		c.Delivery = d
+		if err := cargos.Store(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// On track.
+	store("A", now.AddDate(0, 0, 30), shipping.Delivery{
+		RoutingStatus:   shipping.Routed,
+		TransportStatus: shipping.OnboardCarrier,
+		ETA:             now.AddDate(0, 0, 20),
+	})
+	// Past its deadline.
+	store("B", now.AddDate(0, 0, -1), shipping.Delivery{
+		RoutingStatus:   shipping.Routed,
+		TransportStatus: shipping.InPort,
+		ETA:             now.AddDate(0, 0, -2),
+	})
+	// Delivered late, which is no longer overdue.
+	store("C", now.AddDate(0, 0, -1), shipping.Delivery{
+		RoutingStatus:   shipping.Routed,
+		TransportStatus: shipping.Claimed,
+	})
+	// Misdirected.
+	store("D", now.AddDate(0, 0, 30), shipping.Delivery{
+		RoutingStatus:   shipping.Routed,
+		TransportStatus: shipping.InPort,
+		IsMisdirected:   true,
+	})
+	// Not routed with a day left.
+	store("E", now.AddDate(0, 0, 1), shipping.Delivery{
+		RoutingStatus:   shipping.NotRouted,
+		TransportStatus: shipping.NotReceived,
+	})
+	// Not routed with plenty of time left.
+	store("F", now.AddDate(0, 0, 30), shipping.Delivery{
+		RoutingStatus:   shipping.NotRouted,
+		TransportStatus: shipping.NotReceived,
+	})
+
+	c := NewCollector(cargos)
+	c.now = func() time.Time { return now }
+
+	c.CargoWasHandled(ctx, shipping.HandlingEvent{TrackingID: "A", Activity: shipping.HandlingActivity{Type: shipping.Receive, Location: shipping.SESTO}})
+	c.CargoWasHandled(ctx, shipping.HandlingEvent{TrackingID: "B", Activity: shipping.HandlingActivity{Type: shipping.Receive, Location: shipping.SESTO}})
+	c.CargoWasHandled(ctx, shipping.HandlingEvent{TrackingID: "A", Activity: shipping.HandlingActivity{Type: shipping.Load, Location: shipping.SESTO}})
+	c.RoutingFailures().With("reason", "circuit_open").Add(1)
+	c.RouteCacheLookups().With("result", "hit").Add(2)
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		labels map[string]string
+		want   float64
+	}{
+		{"shipping_cargos", map[string]string{"routing_status": "routed", "transport_status": "in_port"}, 2},
+		{"shipping_cargos", map[string]string{"routing_status": "not_routed", "transport_status": "not_received"}, 2},
+		{"shipping_cargos", map[string]string{"routing_status": "routed", "transport_status": "claimed"}, 1},
+		{"shipping_cargos_misdirected", nil, 1},
+		{"shipping_cargos_overdue", nil, 1},
+		{"shipping_cargos_at_risk", nil, 2},
+		{"shipping_handling_events_total", map[string]string{"type": "receive", "location": "SESTO"}, 2},
+		{"shipping_handling_events_total", map[string]string{"type": "load", "location": "SESTO"}, 1},
+		{"shipping_routing_failures_total", map[string]string{"reason": "circuit_open"}, 1},
+		{"shipping_route_cache_lookups_total", map[string]string{"result": "hit"}, 2},
+	} {
+		got, ok := value(families, tt.name, tt.labels)
+		if !ok {
+			t.Errorf("%s%v not found", tt.name, tt.labels)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s%v = %v; want = %v", tt.name, tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestCollectorHooks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2009, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cargos := &countingCargoRepository{CargoRepository: inmem.NewCargoRepository()}
+
+	newCargo := func(id shipping.TrackingID) *shipping.Cargo {
+		return shipping.NewCargo(id, shipping.RouteSpecification{
+			Origin:          shipping.SESTO,
+			Destination:     shipping.FIHEL,
+			ArrivalDeadline: now.AddDate(0, 0, 30),
+		})
+	}
+
+	a := newCargo("A")
+	if err := cargos.Store(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCollector(cargos)
+	c.now = func() time.Time { return now }
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+
+	gauge := func(name string, labels map[string]string) float64 {
+		families, err := reg.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, _ := value(families, name, labels)
+		return v
+	}
+
+	notRouted := map[string]string{"routing_status": "not_routed", "transport_status": "not_received"}
+
+	if got := gauge("shipping_cargos", notRouted); got != 1 {
+		t.Errorf("shipping_cargos%v = %v; want = %v", notRouted, got, 1)
+	}
+
+	// Cargos stored behind the collector's back aren't seen until they are
+	// booked or amended.
+	b := newCargo("B")
+	if err := cargos.Store(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if got := gauge("shipping_cargos", notRouted); got != 1 {
+		t.Errorf("shipping_cargos%v = %v; want = %v", notRouted, got, 1)
+	}
+
+	c.CargoWasAmended(ctx, b)
+	if got := gauge("shipping_cargos", notRouted); got != 2 {
+		t.Errorf("shipping_cargos%v = %v; want = %v", notRouted, got, 2)
+	}
+
+	// Handling a cargo reads its new delivery from the repository.
+	a.Delivery.TransportStatus = shipping.InPort
+	a.Delivery.IsMisdirected = true
+	if err := cargos.Store(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	c.CargoWasHandled(ctx, shipping.HandlingEvent{TrackingID: "A", Activity: shipping.HandlingActivity{Type: shipping.Unload, Location: shipping.USNYC}})
+
+	if got := gauge("shipping_cargos_misdirected", nil); got != 1 {
+		t.Errorf("shipping_cargos_misdirected = %v; want = %v", got, 1)
+	}
+	if got := gauge("shipping_cargos_at_risk", nil); got != 1 {
+		t.Errorf("shipping_cargos_at_risk = %v; want = %v", got, 1)
+	}
+
+	if cargos.findAll != 1 {
+		t.Errorf("findAll = %d; want = %d", cargos.findAll, 1)
+	}
+}
+
+// countingCargoRepository counts the calls to FindAll.
+type countingCargoRepository struct {
+	shipping.CargoRepository
+	findAll int
+}
+
+func (r *countingCargoRepository) FindAll(ctx context.Context) []*shipping.Cargo {
+	r.findAll++
+	return r.CargoRepository.FindAll(ctx)
+}
+
+// value returns the value of the metric with the given name and labels.
+func value(families []*dto.MetricFamily, name string, labels map[string]string) (float64, bool) {
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range f.Metric {
+			if len(m.Label) != len(labels) {
+				continue
+			}
+			for _, l := range m.Label {
+				if labels[l.GetName()] != l.GetValue() {
+					continue metrics
+				}
+			}
+			switch {
+			case m.Gauge != nil:
+				return m.Gauge.GetValue(), true
+			case m.Counter != nil:
+				return m.Counter.GetValue(), true
+			}
+		}
+	}
+	return 0, false
+}
@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"context"
+
 	shipping "github.com/marcusolsson/goddd"
 )
 
@@ -14,28 +16,40 @@ type CargoRepository struct {
 
 	FindAllFn      func() []*shipping.Cargo
 	FindAllInvoked bool
+
+	QueryFn      func(shipping.CargoQuery) (shipping.CargoPage, error)
+	QueryInvoked bool
 }
 
 // Store calls the StoreFn.
-func (r *CargoRepository) Store(c *shipping.Cargo) error {
+func (r *CargoRepository) Store(_ context.Context, c *shipping.Cargo) error {
 	r.StoreInvoked = true
 	return r.StoreFn(c)
 }
 
 // Find calls the FindFn.
-func (r *CargoRepository) Find(id shipping.TrackingID) (*shipping.Cargo, error) {
+func (r *CargoRepository) Find(_ context.Context, id shipping.TrackingID) (*shipping.Cargo, error) {
 	r.FindInvoked = true
 	return r.FindFn(id)
 }
 
 // FindAll calls the FindAllFn.
-func (r *CargoRepository) FindAll() []*shipping.Cargo {
+func (r *CargoRepository) FindAll(_ context.Context) []*shipping.Cargo {
 	r.FindAllInvoked = true
 	return r.FindAllFn()
 }
 
+// Query calls the QueryFn.
+func (r *CargoRepository) Query(_ context.Context, q shipping.CargoQuery) (shipping.CargoPage, error) {
+	r.QueryInvoked = true
+	return r.QueryFn(q)
+}
+
 // LocationRepository is a mock location repository.
 type LocationRepository struct {
+	StoreFn      func(*shipping.Location) error
+	StoreInvoked bool
+
 	FindFn      func(shipping.UNLocode) (*shipping.Location, error)
 	FindInvoked bool
 
@@ -43,30 +57,54 @@ type LocationRepository struct {
 	FindAllInvoked bool
 }
 
+// Store calls the StoreFn.
+func (r *LocationRepository) Store(_ context.Context, l *shipping.Location) error {
+	r.StoreInvoked = true
+	return r.StoreFn(l)
+}
+
 // Find calls the FindFn.
-func (r *LocationRepository) Find(locode shipping.UNLocode) (*shipping.Location, error) {
+func (r *LocationRepository) Find(_ context.Context, locode shipping.UNLocode) (*shipping.Location, error) {
 	r.FindInvoked = true
 	return r.FindFn(locode)
 }
 
 // FindAll calls the FindAllFn.
-func (r *LocationRepository) FindAll() []*shipping.Location {
+func (r *LocationRepository) FindAll(_ context.Context) []*shipping.Location {
 	r.FindAllInvoked = true
 	return r.FindAllFn()
 }
 
 // VoyageRepository is a mock voyage repository.
 type VoyageRepository struct {
+	StoreFn      func(*shipping.Voyage) error
+	StoreInvoked bool
+
 	FindFn      func(shipping.VoyageNumber) (*shipping.Voyage, error)
 	FindInvoked bool
+
+	FindAllFn      func() []*shipping.Voyage
+	FindAllInvoked bool
+}
+
+// Store calls the StoreFn.
+func (r *VoyageRepository) Store(_ context.Context, v *shipping.Voyage) error {
+	r.StoreInvoked = true
+	return r.StoreFn(v)
 }
 
 // Find calls the FindFn.
-func (r *VoyageRepository) Find(number shipping.VoyageNumber) (*shipping.Voyage, error) {
+func (r *VoyageRepository) Find(_ context.Context, number shipping.VoyageNumber) (*shipping.Voyage, error) {
 	r.FindInvoked = true
 	return r.FindFn(number)
 }
 
+// FindAll calls the FindAllFn.
+func (r *VoyageRepository) FindAll(_ context.Context) []*shipping.Voyage {
+	r.FindAllInvoked = true
+	return r.FindAllFn()
+}
+
 // HandlingEventRepository is a mock handling events repository.
 type HandlingEventRepository struct {
 	StoreFn      func(shipping.HandlingEvent)
@@ -77,25 +115,25 @@ type HandlingEventRepository struct {
 }
 
 // Store calls the StoreFn.
-func (r *HandlingEventRepository) Store(e shipping.HandlingEvent) {
+func (r *HandlingEventRepository) Store(_ context.Context, e shipping.HandlingEvent) {
 	r.StoreInvoked = true
 	r.StoreFn(e)
 }
 
 // QueryHandlingHistory calls the QueryHandlingHistoryFn.
-func (r *HandlingEventRepository) QueryHandlingHistory(id shipping.TrackingID) shipping.HandlingHistory {
+func (r *HandlingEventRepository) QueryHandlingHistory(_ context.Context, id shipping.TrackingID) shipping.HandlingHistory {
 	r.QueryHandlingHistoryInvoked = true
 	return r.QueryHandlingHistoryFn(id)
 }
 
 // RoutingService provides a mock routing service.
 type RoutingService struct {
-	FetchRoutesFn      func(shipping.RouteSpecification) []shipping.Itinerary
+	FetchRoutesFn      func(shipping.RouteSpecification) ([]shipping.Itinerary, error)
 	FetchRoutesInvoked bool
 }
 
 // FetchRoutesForSpecification calls the FetchRoutesFn.
-func (s *RoutingService) FetchRoutesForSpecification(rs shipping.RouteSpecification) []shipping.Itinerary {
+func (s *RoutingService) FetchRoutesForSpecification(_ context.Context, rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
 	s.FetchRoutesInvoked = true
 	return s.FetchRoutesFn(rs)
 }
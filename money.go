@@ -0,0 +1,25 @@
+package shipping
+
+import "fmt"
+
+// Money is an amount in a currency. Amounts are held in minor units, e.g.
+// cents, to avoid rounding errors, and every currency is assumed to have two
+// decimals.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// IsZero reports whether m is the zero value, which means no amount at all
+// rather than zero in some currency.
+func (m Money) IsZero() bool {
+	return m == Money{}
+}
+
+func (m Money) String() string {
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, amount/100, amount%100, m.Currency)
+}
@@ -0,0 +1,18 @@
+package shipping
+
+import "testing"
+
+func TestMoneyString(t *testing.T) {
+	for _, tt := range []struct {
+		in   Money
+		want string
+	}{
+		{Money{Amount: 123456, Currency: "USD"}, "1234.56 USD"},
+		{Money{Amount: 5, Currency: "EUR"}, "0.05 EUR"},
+		{Money{Amount: -1050, Currency: "SEK"}, "-10.50 SEK"},
+	} {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q; want = %q", tt.in, got, tt.want)
+		}
+	}
+}
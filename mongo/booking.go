@@ -0,0 +1,158 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+)
+
+// candidateRetention is how long route candidates are kept after they
+// expire, so that assigning them fails as stale rather than unknown.
+const candidateRetention = time.Hour
+
+type routeCandidateRepository struct {
+	db      string
+	session *mgo.Session
+}
+
+func (r *routeCandidateRepository) Store(ctx context.Context, rc *booking.RouteCandidate) error {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("route_candidate")
+
+	_, err = c.Upsert(bson.M{"id": rc.ID}, bson.M{"$set": rc})
+
+	return err
+}
+
+func (r *routeCandidateRepository) Find(ctx context.Context, id booking.RouteCandidateID) (*booking.RouteCandidate, error) {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("route_candidate")
+
+	var result booking.RouteCandidate
+	if err := c.Find(bson.M{"id": id}).One(&result); err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, booking.ErrUnknownRouteCandidate
+		}
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// NewRouteCandidateRepository returns a new instance of a MongoDB route
+// candidate repository. MongoDB removes candidates a while after they
+// expire.
+func NewRouteCandidateRepository(db string, session *mgo.Session) (booking.RouteCandidateRepository, error) {
+	r := &routeCandidateRepository{
+		db:      db,
+		session: session,
+	}
+
+	sess := r.session.Copy()
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("route_candidate")
+
+	for _, index := range []mgo.Index{
+		{Key: []string{"id"}, Unique: true, DropDups: true, Background: true, Sparse: true},
+		{Key: []string{"expiresat"}, Background: true, ExpireAfter: candidateRetention},
+	} {
+		if err := c.EnsureIndex(index); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+type amendmentRepository struct {
+	db      string
+	session *mgo.Session
+}
+
+func (r *amendmentRepository) Store(ctx context.Context, a *booking.Amendment) error {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("booking_amendment")
+
+	_, err = c.Upsert(bson.M{"id": a.ID}, bson.M{"$set": a})
+
+	return err
+}
+
+func (r *amendmentRepository) Remove(ctx context.Context, id booking.AmendmentID) error {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("booking_amendment")
+
+	if err := c.Remove(bson.M{"id": id}); err != nil && err != mgo.ErrNotFound {
+		return err
+	}
+
+	return nil
+}
+
+func (r *amendmentRepository) FindByTrackingID(ctx context.Context, id shipping.TrackingID) []*booking.Amendment {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return []*booking.Amendment{}
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("booking_amendment")
+
+	var result []*booking.Amendment
+	if err := c.Find(bson.M{"trackingid": id}).Sort("time", "_id").All(&result); err != nil {
+		return []*booking.Amendment{}
+	}
+
+	return result
+}
+
+// NewAmendmentRepository returns a new instance of a MongoDB booking
+// amendment repository.
+func NewAmendmentRepository(db string, session *mgo.Session) (booking.AmendmentRepository, error) {
+	r := &amendmentRepository{
+		db:      db,
+		session: session,
+	}
+
+	sess := r.session.Copy()
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("booking_amendment")
+
+	for _, index := range []mgo.Index{
+		{Key: []string{"id"}, Unique: true, DropDups: true, Background: true, Sparse: true},
+		{Key: []string{"trackingid", "time"}, Background: true},
+	} {
+		if err := c.EnsureIndex(index); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
@@ -1,6 +1,10 @@
+// Package mongo provides MongoDB implementations of the domain repositories.
 package mongo
 
 import (
+	"context"
+	"time"
+
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 
@@ -12,19 +16,25 @@ type cargoRepository struct {
 	session *mgo.Session
 }
 
-func (r *cargoRepository) Store(cargo *shipping.Cargo) error {
-	sess := r.session.Copy()
+func (r *cargoRepository) Store(ctx context.Context, cargo *shipping.Cargo) error {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return err
+	}
 	defer sess.Close()
 
 	c := sess.DB(r.db).C("cargo")
 
-	_, err := c.Upsert(bson.M{"trackingid": cargo.TrackingID}, bson.M{"$set": cargo})
+	_, err = c.Upsert(bson.M{"trackingid": cargo.TrackingID}, bson.M{"$set": cargo})
 
 	return err
 }
 
-func (r *cargoRepository) Find(id shipping.TrackingID) (*shipping.Cargo, error) {
-	sess := r.session.Copy()
+func (r *cargoRepository) Find(ctx context.Context, id shipping.TrackingID) (*shipping.Cargo, error) {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return nil, err
+	}
 	defer sess.Close()
 
 	c := sess.DB(r.db).C("cargo")
@@ -40,8 +50,11 @@ func (r *cargoRepository) Find(id shipping.TrackingID) (*shipping.Cargo, error)
 	return &result, nil
 }
 
-func (r *cargoRepository) FindAll() []*shipping.Cargo {
-	sess := r.session.Copy()
+func (r *cargoRepository) FindAll(ctx context.Context) []*shipping.Cargo {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return []*shipping.Cargo{}
+	}
 	defer sess.Close()
 
 	c := sess.DB(r.db).C("cargo")
@@ -54,6 +67,93 @@ func (r *cargoRepository) FindAll() []*shipping.Cargo {
 	return result
 }
 
+func (r *cargoRepository) Query(ctx context.Context, q shipping.CargoQuery) (shipping.CargoPage, error) {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return shipping.CargoPage{}, err
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("cargo")
+
+	var conds []bson.M
+
+	if q.RoutingStatus != nil {
+		conds = append(conds, bson.M{"delivery.routingstatus": *q.RoutingStatus})
+	}
+	if q.Misrouted != nil {
+		op := "$ne"
+		if *q.Misrouted {
+			op = "$eq"
+		}
+		conds = append(conds, bson.M{"delivery.routingstatus": bson.M{op: shipping.Misrouted}})
+	}
+	if q.Origin != "" {
+		conds = append(conds, bson.M{"origin": q.Origin})
+	}
+	if q.Destination != "" {
+		conds = append(conds, bson.M{"routespecification.destination": q.Destination})
+	}
+	if !q.DeadlineAfter.IsZero() {
+		conds = append(conds, bson.M{"routespecification.arrivaldeadline": bson.M{"$gte": q.DeadlineAfter}})
+	}
+	if !q.DeadlineBefore.IsZero() {
+		conds = append(conds, bson.M{"routespecification.arrivaldeadline": bson.M{"$lt": q.DeadlineBefore}})
+	}
+
+	cmp, prefix := "$gt", ""
+	if q.Descending {
+		cmp, prefix = "$lt", "-"
+	}
+
+	sortFields := []string{prefix + "trackingid"}
+	if q.SortBy == shipping.SortByArrivalDeadline {
+		sortFields = []string{prefix + "routespecification.arrivaldeadline", prefix + "trackingid"}
+	}
+
+	if q.Cursor != "" {
+		cur, err := q.DecodeCursor()
+		if err != nil {
+			return shipping.CargoPage{}, err
+		}
+
+		switch q.SortBy {
+		case shipping.SortByArrivalDeadline:
+			conds = append(conds, bson.M{"$or": []bson.M{
+				{"routespecification.arrivaldeadline": bson.M{cmp: cur.Deadline}},
+				{"routespecification.arrivaldeadline": cur.Deadline, "trackingid": bson.M{cmp: cur.TrackingID}},
+			}})
+		default:
+			conds = append(conds, bson.M{"trackingid": bson.M{cmp: cur.TrackingID}})
+		}
+	}
+
+	filter := bson.M{}
+	if len(conds) > 0 {
+		filter["$and"] = conds
+	}
+
+	query := c.Find(filter).Sort(sortFields...)
+	if q.Limit > 0 {
+		// Fetch one extra cargo to find out whether there is a next page.
+		query = query.Limit(q.Limit + 1)
+	}
+
+	var result []*shipping.Cargo
+	if err := query.All(&result); err != nil {
+		return shipping.CargoPage{}, err
+	}
+
+	var page shipping.CargoPage
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+		page.NextCursor = q.NewCargoCursor(result[len(result)-1])
+	}
+	page.Cargos = result
+
+	return page, nil
+}
+
 // NewCargoRepository returns a new instance of a MongoDB cargo repository.
 func NewCargoRepository(db string, session *mgo.Session) (shipping.CargoRepository, error) {
 	r := &cargoRepository{
@@ -78,6 +178,18 @@ func NewCargoRepository(db string, session *mgo.Session) (shipping.CargoReposito
 		return nil, err
 	}
 
+	// Indexes used by Query.
+	for _, key := range [][]string{
+		{"routespecification.arrivaldeadline", "trackingid"},
+		{"delivery.routingstatus", "trackingid"},
+		{"origin"},
+		{"routespecification.destination"},
+	} {
+		if err := c.EnsureIndex(mgo.Index{Key: key, Background: true}); err != nil {
+			return nil, err
+		}
+	}
+
 	return r, nil
 }
 
@@ -86,8 +198,11 @@ type locationRepository struct {
 	session *mgo.Session
 }
 
-func (r *locationRepository) Find(locode shipping.UNLocode) (*shipping.Location, error) {
-	sess := r.session.Copy()
+func (r *locationRepository) Find(ctx context.Context, locode shipping.UNLocode) (*shipping.Location, error) {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return nil, err
+	}
 	defer sess.Close()
 
 	c := sess.DB(r.db).C("location")
@@ -103,8 +218,11 @@ func (r *locationRepository) Find(locode shipping.UNLocode) (*shipping.Location,
 	return &result, nil
 }
 
-func (r *locationRepository) FindAll() []*shipping.Location {
-	sess := r.session.Copy()
+func (r *locationRepository) FindAll(ctx context.Context) []*shipping.Location {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return []*shipping.Location{}
+	}
 	defer sess.Close()
 
 	c := sess.DB(r.db).C("location")
@@ -117,13 +235,16 @@ func (r *locationRepository) FindAll() []*shipping.Location {
 	return result
 }
 
-func (r *locationRepository) store(l *shipping.Location) error {
-	sess := r.session.Copy()
+func (r *locationRepository) Store(ctx context.Context, l *shipping.Location) error {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return err
+	}
 	defer sess.Close()
 
 	c := sess.DB(r.db).C("location")
 
-	_, err := c.Upsert(bson.M{"unlocode": l.UNLocode}, bson.M{"$set": l})
+	_, err = c.Upsert(bson.M{"unlocode": l.UNLocode}, bson.M{"$set": l})
 
 	return err
 }
@@ -162,7 +283,7 @@ func NewLocationRepository(db string, session *mgo.Session) (shipping.LocationRe
 	}
 
 	for _, l := range initial {
-		r.store(l)
+		r.Store(context.Background(), l)
 	}
 
 	return r, nil
@@ -173,8 +294,11 @@ type voyageRepository struct {
 	session *mgo.Session
 }
 
-func (r *voyageRepository) Find(voyageNumber shipping.VoyageNumber) (*shipping.Voyage, error) {
-	sess := r.session.Copy()
+func (r *voyageRepository) Find(ctx context.Context, voyageNumber shipping.VoyageNumber) (*shipping.Voyage, error) {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return nil, err
+	}
 	defer sess.Close()
 
 	c := sess.DB(r.db).C("voyage")
@@ -190,13 +314,33 @@ func (r *voyageRepository) Find(voyageNumber shipping.VoyageNumber) (*shipping.V
 	return &result, nil
 }
 
-func (r *voyageRepository) store(v *shipping.Voyage) error {
-	sess := r.session.Copy()
+func (r *voyageRepository) FindAll(ctx context.Context) []*shipping.Voyage {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return []*shipping.Voyage{}
+	}
 	defer sess.Close()
 
 	c := sess.DB(r.db).C("voyage")
 
-	_, err := c.Upsert(bson.M{"number": v.VoyageNumber}, bson.M{"$set": v})
+	var result []*shipping.Voyage
+	if err := c.Find(bson.M{}).All(&result); err != nil {
+		return []*shipping.Voyage{}
+	}
+
+	return result
+}
+
+func (r *voyageRepository) Store(ctx context.Context, v *shipping.Voyage) error {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("voyage")
+
+	_, err = c.Upsert(bson.M{"number": v.VoyageNumber}, bson.M{"$set": v})
 
 	return err
 }
@@ -237,7 +381,7 @@ func NewVoyageRepository(db string, session *mgo.Session) (shipping.VoyageReposi
 	}
 
 	for _, v := range initial {
-		r.store(v)
+		r.Store(context.Background(), v)
 	}
 
 	return r, nil
@@ -248,8 +392,11 @@ type handlingEventRepository struct {
 	session *mgo.Session
 }
 
-func (r *handlingEventRepository) Store(e shipping.HandlingEvent) {
-	sess := r.session.Copy()
+func (r *handlingEventRepository) Store(ctx context.Context, e shipping.HandlingEvent) {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return
+	}
 	defer sess.Close()
 
 	c := sess.DB(r.db).C("handling_event")
@@ -257,14 +404,19 @@ func (r *handlingEventRepository) Store(e shipping.HandlingEvent) {
 	_ = c.Insert(e)
 }
 
-func (r *handlingEventRepository) QueryHandlingHistory(id shipping.TrackingID) shipping.HandlingHistory {
-	sess := r.session.Copy()
+func (r *handlingEventRepository) QueryHandlingHistory(ctx context.Context, id shipping.TrackingID) shipping.HandlingHistory {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return shipping.HandlingHistory{}
+	}
 	defer sess.Close()
 
 	c := sess.DB(r.db).C("handling_event")
 
+	// Events are returned in the order they were stored. ObjectIds are
+	// increasing, which makes _id a cheap proxy for insertion order.
 	var result []shipping.HandlingEvent
-	_ = c.Find(bson.M{"trackingid": id}).All(&result)
+	_ = c.Find(bson.M{"trackingid": id}).Sort("_id").All(&result)
 
 	return shipping.HandlingHistory{HandlingEvents: result}
 }
@@ -276,3 +428,31 @@ func NewHandlingEventRepository(db string, session *mgo.Session) shipping.Handli
 		session: session,
 	}
 }
+
+// copySession returns a copy of session with its socket timeout bound by the
+// deadline of ctx. MongoDB operations in mgo are not context-aware, so this is
+// the closest we get to having a cancelled request abort a running query.
+func copySession(ctx context.Context, session *mgo.Session) (*mgo.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sess := session.Copy()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		sess.SetSocketTimeout(time.Until(deadline))
+	}
+
+	return sess, nil
+}
+
+// Ping checks that the database can be reached through session.
+func Ping(ctx context.Context, session *mgo.Session) error {
+	sess, err := copySession(ctx, session)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	return sess.Ping()
+}
@@ -0,0 +1,187 @@
+package mongo
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"gopkg.in/mgo.v2"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/repotest"
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+// The tests in this file require a running MongoDB instance and are skipped
+// unless MONGODB_URL is set.
+
+func dial(t *testing.T) *mgo.Session {
+	url := os.Getenv("MONGODB_URL")
+	if url == "" {
+		t.Skip("MONGODB_URL not set")
+	}
+
+	session, err := mgo.DialWithTimeout(url, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session.SetMode(mgo.Monotonic, true)
+
+	return session
+}
+
+// tempDB returns a function that names a new, empty database for every call,
+// and a function that drops all of them.
+func tempDB(session *mgo.Session) (func() string, func()) {
+	var dbs []string
+
+	newDB := func() string {
+		db := fmt.Sprintf("goddd_test_%d_%d", time.Now().UnixNano(), len(dbs))
+		dbs = append(dbs, db)
+		return db
+	}
+
+	dropAll := func() {
+		for _, db := range dbs {
+			session.DB(db).DropDatabase()
+		}
+	}
+
+	return newDB, dropAll
+}
+
+func TestCargoRepository(t *testing.T) {
+	session := dial(t)
+	defer session.Close()
+
+	newDB, dropAll := tempDB(session)
+	defer dropAll()
+
+	repotest.TestCargoRepository(t, func() shipping.CargoRepository {
+		r, err := NewCargoRepository(newDB(), session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	})
+}
+
+func TestLocationRepository(t *testing.T) {
+	session := dial(t)
+	defer session.Close()
+
+	newDB, dropAll := tempDB(session)
+	defer dropAll()
+
+	r, err := NewLocationRepository(newDB(), session)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repotest.TestLocationRepository(t, r, []*shipping.Location{
+		shipping.Stockholm,
+		shipping.Melbourne,
+		shipping.Hongkong,
+		shipping.Tokyo,
+		shipping.Rotterdam,
+		shipping.Hamburg,
+	})
+}
+
+func TestVoyageRepository(t *testing.T) {
+	session := dial(t)
+	defer session.Close()
+
+	newDB, dropAll := tempDB(session)
+	defer dropAll()
+
+	r, err := NewVoyageRepository(newDB(), session)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repotest.TestVoyageRepository(t, r, []*shipping.Voyage{
+		shipping.V100,
+		shipping.V300,
+		shipping.V400,
+		shipping.V0100S,
+	})
+}
+
+func TestHandlingEventRepository(t *testing.T) {
+	session := dial(t)
+	defer session.Close()
+
+	newDB, dropAll := tempDB(session)
+	defer dropAll()
+
+	repotest.TestHandlingEventRepository(t, func() shipping.HandlingEventRepository {
+		return NewHandlingEventRepository(newDB(), session)
+	})
+}
+
+func TestSubscriptionRepository(t *testing.T) {
+	session := dial(t)
+	defer session.Close()
+
+	newDB, dropAll := tempDB(session)
+	defer dropAll()
+
+	repotest.TestSubscriptionRepository(t, func() webhook.SubscriptionRepository {
+		r, err := NewSubscriptionRepository(newDB(), session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	})
+}
+
+func TestDeliveryRepository(t *testing.T) {
+	session := dial(t)
+	defer session.Close()
+
+	newDB, dropAll := tempDB(session)
+	defer dropAll()
+
+	repotest.TestDeliveryRepository(t, func() webhook.DeliveryRepository {
+		r, err := NewDeliveryRepository(newDB(), session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	})
+}
+
+func TestRouteCandidateRepository(t *testing.T) {
+	session := dial(t)
+	defer session.Close()
+
+	newDB, dropAll := tempDB(session)
+	defer dropAll()
+
+	repotest.TestRouteCandidateRepository(t, func() booking.RouteCandidateRepository {
+		r, err := NewRouteCandidateRepository(newDB(), session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	})
+}
+
+func TestAmendmentRepository(t *testing.T) {
+	session := dial(t)
+	defer session.Close()
+
+	newDB, dropAll := tempDB(session)
+	defer dropAll()
+
+	repotest.TestAmendmentRepository(t, func() booking.AmendmentRepository {
+		r, err := NewAmendmentRepository(newDB(), session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	})
+}
@@ -0,0 +1,183 @@
+package mongo
+
+import (
+	"context"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+type subscriptionRepository struct {
+	db      string
+	session *mgo.Session
+}
+
+func (r *subscriptionRepository) Store(ctx context.Context, s *webhook.Subscription) error {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("webhook_subscription")
+
+	_, err = c.Upsert(bson.M{"id": s.ID}, bson.M{"$set": s})
+
+	return err
+}
+
+func (r *subscriptionRepository) Find(ctx context.Context, id webhook.SubscriptionID) (*webhook.Subscription, error) {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("webhook_subscription")
+
+	var result webhook.Subscription
+	if err := c.Find(bson.M{"id": id}).One(&result); err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, webhook.ErrUnknownSubscription
+		}
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *subscriptionRepository) FindAll(ctx context.Context) []*webhook.Subscription {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return []*webhook.Subscription{}
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("webhook_subscription")
+
+	var result []*webhook.Subscription
+	if err := c.Find(bson.M{}).All(&result); err != nil {
+		return []*webhook.Subscription{}
+	}
+
+	return result
+}
+
+func (r *subscriptionRepository) Remove(ctx context.Context, id webhook.SubscriptionID) error {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("webhook_subscription")
+
+	if err := c.Remove(bson.M{"id": id}); err != nil {
+		if err == mgo.ErrNotFound {
+			return webhook.ErrUnknownSubscription
+		}
+		return err
+	}
+
+	return nil
+}
+
+// NewSubscriptionRepository returns a new instance of a MongoDB webhook
+// subscription repository.
+func NewSubscriptionRepository(db string, session *mgo.Session) (webhook.SubscriptionRepository, error) {
+	r := &subscriptionRepository{
+		db:      db,
+		session: session,
+	}
+
+	index := mgo.Index{
+		Key:        []string{"id"},
+		Unique:     true,
+		DropDups:   true,
+		Background: true,
+		Sparse:     true,
+	}
+
+	sess := r.session.Copy()
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("webhook_subscription")
+
+	if err := c.EnsureIndex(index); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+type deliveryRepository struct {
+	db      string
+	session *mgo.Session
+}
+
+func (r *deliveryRepository) Store(ctx context.Context, d *webhook.Delivery) error {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("webhook_delivery")
+
+	_, err = c.Upsert(bson.M{"id": d.ID}, bson.M{"$set": d})
+
+	return err
+}
+
+func (r *deliveryRepository) FindBySubscription(ctx context.Context, id webhook.SubscriptionID) []*webhook.Delivery {
+	return r.find(ctx, bson.M{"subscription": id})
+}
+
+func (r *deliveryRepository) FindPending(ctx context.Context) []*webhook.Delivery {
+	return r.find(ctx, bson.M{"status": webhook.Pending})
+}
+
+func (r *deliveryRepository) find(ctx context.Context, query bson.M) []*webhook.Delivery {
+	sess, err := copySession(ctx, r.session)
+	if err != nil {
+		return []*webhook.Delivery{}
+	}
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("webhook_delivery")
+
+	var result []*webhook.Delivery
+	if err := c.Find(query).Sort("created", "_id").All(&result); err != nil {
+		return []*webhook.Delivery{}
+	}
+
+	return result
+}
+
+// NewDeliveryRepository returns a new instance of a MongoDB webhook delivery
+// repository.
+func NewDeliveryRepository(db string, session *mgo.Session) (webhook.DeliveryRepository, error) {
+	r := &deliveryRepository{
+		db:      db,
+		session: session,
+	}
+
+	sess := r.session.Copy()
+	defer sess.Close()
+
+	c := sess.DB(r.db).C("webhook_delivery")
+
+	for _, index := range []mgo.Index{
+		{Key: []string{"id"}, Unique: true, DropDups: true, Background: true, Sparse: true},
+		{Key: []string{"subscription", "created"}, Background: true},
+		{Key: []string{"status"}, Background: true},
+	} {
+		if err := c.EnsureIndex(index); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,2954 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: shipping.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AmendmentType int32
+
+const (
+	AmendmentType_AMENDMENT_TYPE_UNSPECIFIED              AmendmentType = 0
+	AmendmentType_AMENDMENT_TYPE_DESTINATION_CHANGED      AmendmentType = 1
+	AmendmentType_AMENDMENT_TYPE_ARRIVAL_DEADLINE_CHANGED AmendmentType = 2
+	AmendmentType_AMENDMENT_TYPE_ORIGIN_CHANGED           AmendmentType = 3
+	AmendmentType_AMENDMENT_TYPE_ROUTE_ASSIGNED           AmendmentType = 4
+)
+
+// Enum value maps for AmendmentType.
+var (
+	AmendmentType_name = map[int32]string{
+		0: "AMENDMENT_TYPE_UNSPECIFIED",
+		1: "AMENDMENT_TYPE_DESTINATION_CHANGED",
+		2: "AMENDMENT_TYPE_ARRIVAL_DEADLINE_CHANGED",
+		3: "AMENDMENT_TYPE_ORIGIN_CHANGED",
+		4: "AMENDMENT_TYPE_ROUTE_ASSIGNED",
+	}
+	AmendmentType_value = map[string]int32{
+		"AMENDMENT_TYPE_UNSPECIFIED":              0,
+		"AMENDMENT_TYPE_DESTINATION_CHANGED":      1,
+		"AMENDMENT_TYPE_ARRIVAL_DEADLINE_CHANGED": 2,
+		"AMENDMENT_TYPE_ORIGIN_CHANGED":           3,
+		"AMENDMENT_TYPE_ROUTE_ASSIGNED":           4,
+	}
+)
+
+func (x AmendmentType) Enum() *AmendmentType {
+	p := new(AmendmentType)
+	*p = x
+	return p
+}
+
+func (x AmendmentType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AmendmentType) Descriptor() protoreflect.EnumDescriptor {
+	return file_shipping_proto_enumTypes[0].Descriptor()
+}
+
+func (AmendmentType) Type() protoreflect.EnumType {
+	return &file_shipping_proto_enumTypes[0]
+}
+
+func (x AmendmentType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AmendmentType.Descriptor instead.
+func (AmendmentType) EnumDescriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{0}
+}
+
+type RoutingStatus int32
+
+const (
+	RoutingStatus_ROUTING_STATUS_UNSPECIFIED RoutingStatus = 0
+	RoutingStatus_ROUTING_STATUS_NOT_ROUTED  RoutingStatus = 1
+	RoutingStatus_ROUTING_STATUS_MISROUTED   RoutingStatus = 2
+	RoutingStatus_ROUTING_STATUS_ROUTED      RoutingStatus = 3
+)
+
+// Enum value maps for RoutingStatus.
+var (
+	RoutingStatus_name = map[int32]string{
+		0: "ROUTING_STATUS_UNSPECIFIED",
+		1: "ROUTING_STATUS_NOT_ROUTED",
+		2: "ROUTING_STATUS_MISROUTED",
+		3: "ROUTING_STATUS_ROUTED",
+	}
+	RoutingStatus_value = map[string]int32{
+		"ROUTING_STATUS_UNSPECIFIED": 0,
+		"ROUTING_STATUS_NOT_ROUTED":  1,
+		"ROUTING_STATUS_MISROUTED":   2,
+		"ROUTING_STATUS_ROUTED":      3,
+	}
+)
+
+func (x RoutingStatus) Enum() *RoutingStatus {
+	p := new(RoutingStatus)
+	*p = x
+	return p
+}
+
+func (x RoutingStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RoutingStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_shipping_proto_enumTypes[1].Descriptor()
+}
+
+func (RoutingStatus) Type() protoreflect.EnumType {
+	return &file_shipping_proto_enumTypes[1]
+}
+
+func (x RoutingStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RoutingStatus.Descriptor instead.
+func (RoutingStatus) EnumDescriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{1}
+}
+
+type CargoSortKey int32
+
+const (
+	CargoSortKey_CARGO_SORT_KEY_TRACKING_ID      CargoSortKey = 0
+	CargoSortKey_CARGO_SORT_KEY_ARRIVAL_DEADLINE CargoSortKey = 1
+)
+
+// Enum value maps for CargoSortKey.
+var (
+	CargoSortKey_name = map[int32]string{
+		0: "CARGO_SORT_KEY_TRACKING_ID",
+		1: "CARGO_SORT_KEY_ARRIVAL_DEADLINE",
+	}
+	CargoSortKey_value = map[string]int32{
+		"CARGO_SORT_KEY_TRACKING_ID":      0,
+		"CARGO_SORT_KEY_ARRIVAL_DEADLINE": 1,
+	}
+)
+
+func (x CargoSortKey) Enum() *CargoSortKey {
+	p := new(CargoSortKey)
+	*p = x
+	return p
+}
+
+func (x CargoSortKey) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CargoSortKey) Descriptor() protoreflect.EnumDescriptor {
+	return file_shipping_proto_enumTypes[2].Descriptor()
+}
+
+func (CargoSortKey) Type() protoreflect.EnumType {
+	return &file_shipping_proto_enumTypes[2]
+}
+
+func (x CargoSortKey) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CargoSortKey.Descriptor instead.
+func (CargoSortKey) EnumDescriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{2}
+}
+
+type HandlingEventType int32
+
+const (
+	HandlingEventType_HANDLING_EVENT_TYPE_UNSPECIFIED HandlingEventType = 0
+	HandlingEventType_HANDLING_EVENT_TYPE_LOAD        HandlingEventType = 1
+	HandlingEventType_HANDLING_EVENT_TYPE_UNLOAD      HandlingEventType = 2
+	HandlingEventType_HANDLING_EVENT_TYPE_RECEIVE     HandlingEventType = 3
+	HandlingEventType_HANDLING_EVENT_TYPE_CLAIM       HandlingEventType = 4
+	HandlingEventType_HANDLING_EVENT_TYPE_CUSTOMS     HandlingEventType = 5
+)
+
+// Enum value maps for HandlingEventType.
+var (
+	HandlingEventType_name = map[int32]string{
+		0: "HANDLING_EVENT_TYPE_UNSPECIFIED",
+		1: "HANDLING_EVENT_TYPE_LOAD",
+		2: "HANDLING_EVENT_TYPE_UNLOAD",
+		3: "HANDLING_EVENT_TYPE_RECEIVE",
+		4: "HANDLING_EVENT_TYPE_CLAIM",
+		5: "HANDLING_EVENT_TYPE_CUSTOMS",
+	}
+	HandlingEventType_value = map[string]int32{
+		"HANDLING_EVENT_TYPE_UNSPECIFIED": 0,
+		"HANDLING_EVENT_TYPE_LOAD":        1,
+		"HANDLING_EVENT_TYPE_UNLOAD":      2,
+		"HANDLING_EVENT_TYPE_RECEIVE":     3,
+		"HANDLING_EVENT_TYPE_CLAIM":       4,
+		"HANDLING_EVENT_TYPE_CUSTOMS":     5,
+	}
+)
+
+func (x HandlingEventType) Enum() *HandlingEventType {
+	p := new(HandlingEventType)
+	*p = x
+	return p
+}
+
+func (x HandlingEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HandlingEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_shipping_proto_enumTypes[3].Descriptor()
+}
+
+func (HandlingEventType) Type() protoreflect.EnumType {
+	return &file_shipping_proto_enumTypes[3]
+}
+
+func (x HandlingEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HandlingEventType.Descriptor instead.
+func (HandlingEventType) EnumDescriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{3}
+}
+
+type Leg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VoyageNumber  string                 `protobuf:"bytes,1,opt,name=voyage_number,json=voyageNumber,proto3" json:"voyage_number,omitempty"`
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	LoadTime      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=load_time,json=loadTime,proto3" json:"load_time,omitempty"`
+	UnloadTime    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=unload_time,json=unloadTime,proto3" json:"unload_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Leg) Reset() {
+	*x = Leg{}
+	mi := &file_shipping_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Leg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Leg) ProtoMessage() {}
+
+func (x *Leg) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Leg.ProtoReflect.Descriptor instead.
+func (*Leg) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Leg) GetVoyageNumber() string {
+	if x != nil {
+		return x.VoyageNumber
+	}
+	return ""
+}
+
+func (x *Leg) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Leg) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *Leg) GetLoadTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LoadTime
+	}
+	return nil
+}
+
+func (x *Leg) GetUnloadTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UnloadTime
+	}
+	return nil
+}
+
+type Itinerary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Legs          []*Leg                 `protobuf:"bytes,1,rep,name=legs,proto3" json:"legs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Itinerary) Reset() {
+	*x = Itinerary{}
+	mi := &file_shipping_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Itinerary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Itinerary) ProtoMessage() {}
+
+func (x *Itinerary) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Itinerary.ProtoReflect.Descriptor instead.
+func (*Itinerary) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Itinerary) GetLegs() []*Leg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+type Cargo struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId        string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	Origin            string                 `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination       string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	ArrivalDeadline   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=arrival_deadline,json=arrivalDeadline,proto3" json:"arrival_deadline,omitempty"`
+	Misrouted         bool                   `protobuf:"varint,5,opt,name=misrouted,proto3" json:"misrouted,omitempty"`
+	Routed            bool                   `protobuf:"varint,6,opt,name=routed,proto3" json:"routed,omitempty"`
+	Legs              []*Leg                 `protobuf:"bytes,7,rep,name=legs,proto3" json:"legs,omitempty"`
+	EarliestDeparture *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=earliest_departure,json=earliestDeparture,proto3" json:"earliest_departure,omitempty"`
+	MaxLegs           int32                  `protobuf:"varint,9,opt,name=max_legs,json=maxLegs,proto3" json:"max_legs,omitempty"`
+	AvoidLocations    []string               `protobuf:"bytes,10,rep,name=avoid_locations,json=avoidLocations,proto3" json:"avoid_locations,omitempty"`
+	// Price quoted for the route the cargo is assigned to, if any.
+	Price         *Money `protobuf:"bytes,11,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cargo) Reset() {
+	*x = Cargo{}
+	mi := &file_shipping_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cargo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cargo) ProtoMessage() {}
+
+func (x *Cargo) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cargo.ProtoReflect.Descriptor instead.
+func (*Cargo) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Cargo) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+func (x *Cargo) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *Cargo) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *Cargo) GetArrivalDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArrivalDeadline
+	}
+	return nil
+}
+
+func (x *Cargo) GetMisrouted() bool {
+	if x != nil {
+		return x.Misrouted
+	}
+	return false
+}
+
+func (x *Cargo) GetRouted() bool {
+	if x != nil {
+		return x.Routed
+	}
+	return false
+}
+
+func (x *Cargo) GetLegs() []*Leg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *Cargo) GetEarliestDeparture() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EarliestDeparture
+	}
+	return nil
+}
+
+func (x *Cargo) GetMaxLegs() int32 {
+	if x != nil {
+		return x.MaxLegs
+	}
+	return 0
+}
+
+func (x *Cargo) GetAvoidLocations() []string {
+	if x != nil {
+		return x.AvoidLocations
+	}
+	return nil
+}
+
+func (x *Cargo) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+// Money is an amount in minor units, e.g. cents, of a currency.
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_shipping_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Money) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locode        string                 `protobuf:"bytes,1,opt,name=locode,proto3" json:"locode,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_shipping_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Location) GetLocode() string {
+	if x != nil {
+		return x.Locode
+	}
+	return ""
+}
+
+func (x *Location) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type BookNewCargoRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Origin          string                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination     string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	ArrivalDeadline *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=arrival_deadline,json=arrivalDeadline,proto3" json:"arrival_deadline,omitempty"`
+	// When the cargo is ready to leave its origin at the earliest. Unset
+	// means it can leave at any time.
+	EarliestDeparture *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=earliest_departure,json=earliestDeparture,proto3" json:"earliest_departure,omitempty"`
+	// Largest number of legs of a route. Zero means no limit.
+	MaxLegs int32 `protobuf:"varint,5,opt,name=max_legs,json=maxLegs,proto3" json:"max_legs,omitempty"`
+	// Locations where the cargo must not be handled.
+	AvoidLocations []string `protobuf:"bytes,6,rep,name=avoid_locations,json=avoidLocations,proto3" json:"avoid_locations,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BookNewCargoRequest) Reset() {
+	*x = BookNewCargoRequest{}
+	mi := &file_shipping_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookNewCargoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookNewCargoRequest) ProtoMessage() {}
+
+func (x *BookNewCargoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookNewCargoRequest.ProtoReflect.Descriptor instead.
+func (*BookNewCargoRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BookNewCargoRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *BookNewCargoRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *BookNewCargoRequest) GetArrivalDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArrivalDeadline
+	}
+	return nil
+}
+
+func (x *BookNewCargoRequest) GetEarliestDeparture() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EarliestDeparture
+	}
+	return nil
+}
+
+func (x *BookNewCargoRequest) GetMaxLegs() int32 {
+	if x != nil {
+		return x.MaxLegs
+	}
+	return 0
+}
+
+func (x *BookNewCargoRequest) GetAvoidLocations() []string {
+	if x != nil {
+		return x.AvoidLocations
+	}
+	return nil
+}
+
+type BookNewCargoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookNewCargoResponse) Reset() {
+	*x = BookNewCargoResponse{}
+	mi := &file_shipping_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookNewCargoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookNewCargoResponse) ProtoMessage() {}
+
+func (x *BookNewCargoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookNewCargoResponse.ProtoReflect.Descriptor instead.
+func (*BookNewCargoResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BookNewCargoResponse) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+type LoadCargoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadCargoRequest) Reset() {
+	*x = LoadCargoRequest{}
+	mi := &file_shipping_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadCargoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadCargoRequest) ProtoMessage() {}
+
+func (x *LoadCargoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadCargoRequest.ProtoReflect.Descriptor instead.
+func (*LoadCargoRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LoadCargoRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+type LoadCargoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cargo         *Cargo                 `protobuf:"bytes,1,opt,name=cargo,proto3" json:"cargo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadCargoResponse) Reset() {
+	*x = LoadCargoResponse{}
+	mi := &file_shipping_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadCargoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadCargoResponse) ProtoMessage() {}
+
+func (x *LoadCargoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadCargoResponse.ProtoReflect.Descriptor instead.
+func (*LoadCargoResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LoadCargoResponse) GetCargo() *Cargo {
+	if x != nil {
+		return x.Cargo
+	}
+	return nil
+}
+
+type RequestPossibleRoutesForCargoRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	// Ranking strategy: balanced, fastest, cheapest, fewest_transshipments
+	// or most_slack. Unset means balanced.
+	Strategy      string `protobuf:"bytes,2,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPossibleRoutesForCargoRequest) Reset() {
+	*x = RequestPossibleRoutesForCargoRequest{}
+	mi := &file_shipping_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPossibleRoutesForCargoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPossibleRoutesForCargoRequest) ProtoMessage() {}
+
+func (x *RequestPossibleRoutesForCargoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPossibleRoutesForCargoRequest.ProtoReflect.Descriptor instead.
+func (*RequestPossibleRoutesForCargoRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RequestPossibleRoutesForCargoRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+func (x *RequestPossibleRoutesForCargoRequest) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+// Cost is what a single criterion contributes to the score of a route.
+type Cost struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Criterion     string                 `protobuf:"bytes,1,opt,name=criterion,proto3" json:"criterion,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Weight        float64                `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Score         float64                `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cost) Reset() {
+	*x = Cost{}
+	mi := &file_shipping_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cost) ProtoMessage() {}
+
+func (x *Cost) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cost.ProtoReflect.Descriptor instead.
+func (*Cost) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Cost) GetCriterion() string {
+	if x != nil {
+		return x.Criterion
+	}
+	return ""
+}
+
+func (x *Cost) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Cost) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Cost) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// RouteCandidate is a route found for a cargo. Its legs are encoded as those
+// of an Itinerary.
+type RouteCandidate struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Legs      []*Leg                 `protobuf:"bytes,1,rep,name=legs,proto3" json:"legs,omitempty"`
+	Id        string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Score is the sum of the scores of the costs. Lower is better.
+	Score         float64 `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	Costs         []*Cost `protobuf:"bytes,5,rep,name=costs,proto3" json:"costs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteCandidate) Reset() {
+	*x = RouteCandidate{}
+	mi := &file_shipping_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteCandidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteCandidate) ProtoMessage() {}
+
+func (x *RouteCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteCandidate.ProtoReflect.Descriptor instead.
+func (*RouteCandidate) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RouteCandidate) GetLegs() []*Leg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *RouteCandidate) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RouteCandidate) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *RouteCandidate) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *RouteCandidate) GetCosts() []*Cost {
+	if x != nil {
+		return x.Costs
+	}
+	return nil
+}
+
+type RequestPossibleRoutesForCargoResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Routes, best first.
+	Routes        []*RouteCandidate `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPossibleRoutesForCargoResponse) Reset() {
+	*x = RequestPossibleRoutesForCargoResponse{}
+	mi := &file_shipping_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPossibleRoutesForCargoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPossibleRoutesForCargoResponse) ProtoMessage() {}
+
+func (x *RequestPossibleRoutesForCargoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPossibleRoutesForCargoResponse.ProtoReflect.Descriptor instead.
+func (*RequestPossibleRoutesForCargoResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RequestPossibleRoutesForCargoResponse) GetRoutes() []*RouteCandidate {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+type QuoteRouteRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId  string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	CandidateId string                 `protobuf:"bytes,2,opt,name=candidate_id,json=candidateId,proto3" json:"candidate_id,omitempty"`
+	// Units of cargo to carry.
+	Size int32 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// Currency of the quote. Unset means the currency of the tariff.
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteRouteRequest) Reset() {
+	*x = QuoteRouteRequest{}
+	mi := &file_shipping_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteRouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteRouteRequest) ProtoMessage() {}
+
+func (x *QuoteRouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteRouteRequest.ProtoReflect.Descriptor instead.
+func (*QuoteRouteRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *QuoteRouteRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+func (x *QuoteRouteRequest) GetCandidateId() string {
+	if x != nil {
+		return x.CandidateId
+	}
+	return ""
+}
+
+func (x *QuoteRouteRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *QuoteRouteRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Quote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          int32                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Lines         []*Quote_Line          `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`
+	Total         *Money                 `protobuf:"bytes,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quote) Reset() {
+	*x = Quote{}
+	mi := &file_shipping_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quote) ProtoMessage() {}
+
+func (x *Quote) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quote.ProtoReflect.Descriptor instead.
+func (*Quote) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Quote) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Quote) GetLines() []*Quote_Line {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *Quote) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+type QuoteRouteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Quote         *Quote                 `protobuf:"bytes,1,opt,name=quote,proto3" json:"quote,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteRouteResponse) Reset() {
+	*x = QuoteRouteResponse{}
+	mi := &file_shipping_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteRouteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteRouteResponse) ProtoMessage() {}
+
+func (x *QuoteRouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteRouteResponse.ProtoReflect.Descriptor instead.
+func (*QuoteRouteResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *QuoteRouteResponse) GetQuote() *Quote {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+type AssignCargoToRouteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	Route         *Itinerary             `protobuf:"bytes,2,opt,name=route,proto3" json:"route,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignCargoToRouteRequest) Reset() {
+	*x = AssignCargoToRouteRequest{}
+	mi := &file_shipping_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignCargoToRouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignCargoToRouteRequest) ProtoMessage() {}
+
+func (x *AssignCargoToRouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignCargoToRouteRequest.ProtoReflect.Descriptor instead.
+func (*AssignCargoToRouteRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AssignCargoToRouteRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+func (x *AssignCargoToRouteRequest) GetRoute() *Itinerary {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+type AssignCargoToRouteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignCargoToRouteResponse) Reset() {
+	*x = AssignCargoToRouteResponse{}
+	mi := &file_shipping_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignCargoToRouteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignCargoToRouteResponse) ProtoMessage() {}
+
+func (x *AssignCargoToRouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignCargoToRouteResponse.ProtoReflect.Descriptor instead.
+func (*AssignCargoToRouteResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{17}
+}
+
+type AssignCargoToRouteCandidateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	CandidateId   string                 `protobuf:"bytes,2,opt,name=candidate_id,json=candidateId,proto3" json:"candidate_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignCargoToRouteCandidateRequest) Reset() {
+	*x = AssignCargoToRouteCandidateRequest{}
+	mi := &file_shipping_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignCargoToRouteCandidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignCargoToRouteCandidateRequest) ProtoMessage() {}
+
+func (x *AssignCargoToRouteCandidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignCargoToRouteCandidateRequest.ProtoReflect.Descriptor instead.
+func (*AssignCargoToRouteCandidateRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AssignCargoToRouteCandidateRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+func (x *AssignCargoToRouteCandidateRequest) GetCandidateId() string {
+	if x != nil {
+		return x.CandidateId
+	}
+	return ""
+}
+
+type AssignCargoToRouteCandidateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignCargoToRouteCandidateResponse) Reset() {
+	*x = AssignCargoToRouteCandidateResponse{}
+	mi := &file_shipping_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignCargoToRouteCandidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignCargoToRouteCandidateResponse) ProtoMessage() {}
+
+func (x *AssignCargoToRouteCandidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignCargoToRouteCandidateResponse.ProtoReflect.Descriptor instead.
+func (*AssignCargoToRouteCandidateResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{19}
+}
+
+type ChangeDestinationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	Destination   string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeDestinationRequest) Reset() {
+	*x = ChangeDestinationRequest{}
+	mi := &file_shipping_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeDestinationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeDestinationRequest) ProtoMessage() {}
+
+func (x *ChangeDestinationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeDestinationRequest.ProtoReflect.Descriptor instead.
+func (*ChangeDestinationRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ChangeDestinationRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+func (x *ChangeDestinationRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+type ChangeDestinationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeDestinationResponse) Reset() {
+	*x = ChangeDestinationResponse{}
+	mi := &file_shipping_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeDestinationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeDestinationResponse) ProtoMessage() {}
+
+func (x *ChangeDestinationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeDestinationResponse.ProtoReflect.Descriptor instead.
+func (*ChangeDestinationResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{21}
+}
+
+type ChangeArrivalDeadlineRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId      string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	ArrivalDeadline *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=arrival_deadline,json=arrivalDeadline,proto3" json:"arrival_deadline,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangeArrivalDeadlineRequest) Reset() {
+	*x = ChangeArrivalDeadlineRequest{}
+	mi := &file_shipping_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeArrivalDeadlineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeArrivalDeadlineRequest) ProtoMessage() {}
+
+func (x *ChangeArrivalDeadlineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeArrivalDeadlineRequest.ProtoReflect.Descriptor instead.
+func (*ChangeArrivalDeadlineRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ChangeArrivalDeadlineRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+func (x *ChangeArrivalDeadlineRequest) GetArrivalDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArrivalDeadline
+	}
+	return nil
+}
+
+type ChangeArrivalDeadlineResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeArrivalDeadlineResponse) Reset() {
+	*x = ChangeArrivalDeadlineResponse{}
+	mi := &file_shipping_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeArrivalDeadlineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeArrivalDeadlineResponse) ProtoMessage() {}
+
+func (x *ChangeArrivalDeadlineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeArrivalDeadlineResponse.ProtoReflect.Descriptor instead.
+func (*ChangeArrivalDeadlineResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{23}
+}
+
+type ChangeOriginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	Origin        string                 `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeOriginRequest) Reset() {
+	*x = ChangeOriginRequest{}
+	mi := &file_shipping_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeOriginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeOriginRequest) ProtoMessage() {}
+
+func (x *ChangeOriginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeOriginRequest.ProtoReflect.Descriptor instead.
+func (*ChangeOriginRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ChangeOriginRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+func (x *ChangeOriginRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+type ChangeOriginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeOriginResponse) Reset() {
+	*x = ChangeOriginResponse{}
+	mi := &file_shipping_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeOriginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeOriginResponse) ProtoMessage() {}
+
+func (x *ChangeOriginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeOriginResponse.ProtoReflect.Descriptor instead.
+func (*ChangeOriginResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{25}
+}
+
+// Terms are the parts of a booking that amendments change.
+type Terms struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Origin          string                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination     string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	ArrivalDeadline *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=arrival_deadline,json=arrivalDeadline,proto3" json:"arrival_deadline,omitempty"`
+	Legs            []*Leg                 `protobuf:"bytes,4,rep,name=legs,proto3" json:"legs,omitempty"`
+	Price           *Money                 `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Terms) Reset() {
+	*x = Terms{}
+	mi := &file_shipping_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Terms) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Terms) ProtoMessage() {}
+
+func (x *Terms) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Terms.ProtoReflect.Descriptor instead.
+func (*Terms) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Terms) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *Terms) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *Terms) GetArrivalDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArrivalDeadline
+	}
+	return nil
+}
+
+func (x *Terms) GetLegs() []*Leg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *Terms) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+type Amendment struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type  AmendmentType          `protobuf:"varint,2,opt,name=type,proto3,enum=shipping.v1.AmendmentType" json:"type,omitempty"`
+	// Subject of the client that made the amendment. Empty if the client
+	// wasn't authenticated.
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	Previous      *Terms                 `protobuf:"bytes,5,opt,name=previous,proto3" json:"previous,omitempty"`
+	New           *Terms                 `protobuf:"bytes,6,opt,name=new,proto3" json:"new,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Amendment) Reset() {
+	*x = Amendment{}
+	mi := &file_shipping_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Amendment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Amendment) ProtoMessage() {}
+
+func (x *Amendment) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Amendment.ProtoReflect.Descriptor instead.
+func (*Amendment) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Amendment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Amendment) GetType() AmendmentType {
+	if x != nil {
+		return x.Type
+	}
+	return AmendmentType_AMENDMENT_TYPE_UNSPECIFIED
+}
+
+func (x *Amendment) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *Amendment) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Amendment) GetPrevious() *Terms {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+func (x *Amendment) GetNew() *Terms {
+	if x != nil {
+		return x.New
+	}
+	return nil
+}
+
+type HistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	mi := &file_shipping_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRequest) ProtoMessage() {}
+
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *HistoryRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+type HistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amendments    []*Amendment           `protobuf:"bytes,1,rep,name=amendments,proto3" json:"amendments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryResponse) Reset() {
+	*x = HistoryResponse{}
+	mi := &file_shipping_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryResponse) ProtoMessage() {}
+
+func (x *HistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryResponse.ProtoReflect.Descriptor instead.
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *HistoryResponse) GetAmendments() []*Amendment {
+	if x != nil {
+		return x.Amendments
+	}
+	return nil
+}
+
+type ListCargosRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filters. Unset filters match all cargos.
+	RoutingStatus  RoutingStatus          `protobuf:"varint,1,opt,name=routing_status,json=routingStatus,proto3,enum=shipping.v1.RoutingStatus" json:"routing_status,omitempty"`
+	Misrouted      *bool                  `protobuf:"varint,2,opt,name=misrouted,proto3,oneof" json:"misrouted,omitempty"`
+	Origin         string                 `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination    string                 `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`
+	DeadlineAfter  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=deadline_after,json=deadlineAfter,proto3" json:"deadline_after,omitempty"`
+	DeadlineBefore *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=deadline_before,json=deadlineBefore,proto3" json:"deadline_before,omitempty"`
+	SortBy         CargoSortKey           `protobuf:"varint,7,opt,name=sort_by,json=sortBy,proto3,enum=shipping.v1.CargoSortKey" json:"sort_by,omitempty"`
+	Descending     bool                   `protobuf:"varint,8,opt,name=descending,proto3" json:"descending,omitempty"`
+	// Cursor is the next_cursor of the previous page.
+	Cursor        string `protobuf:"bytes,9,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32  `protobuf:"varint,10,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCargosRequest) Reset() {
+	*x = ListCargosRequest{}
+	mi := &file_shipping_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCargosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCargosRequest) ProtoMessage() {}
+
+func (x *ListCargosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCargosRequest.ProtoReflect.Descriptor instead.
+func (*ListCargosRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListCargosRequest) GetRoutingStatus() RoutingStatus {
+	if x != nil {
+		return x.RoutingStatus
+	}
+	return RoutingStatus_ROUTING_STATUS_UNSPECIFIED
+}
+
+func (x *ListCargosRequest) GetMisrouted() bool {
+	if x != nil && x.Misrouted != nil {
+		return *x.Misrouted
+	}
+	return false
+}
+
+func (x *ListCargosRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *ListCargosRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *ListCargosRequest) GetDeadlineAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeadlineAfter
+	}
+	return nil
+}
+
+func (x *ListCargosRequest) GetDeadlineBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeadlineBefore
+	}
+	return nil
+}
+
+func (x *ListCargosRequest) GetSortBy() CargoSortKey {
+	if x != nil {
+		return x.SortBy
+	}
+	return CargoSortKey_CARGO_SORT_KEY_TRACKING_ID
+}
+
+func (x *ListCargosRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *ListCargosRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListCargosRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListCargosResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cargos        []*Cargo               `protobuf:"bytes,1,rep,name=cargos,proto3" json:"cargos,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCargosResponse) Reset() {
+	*x = ListCargosResponse{}
+	mi := &file_shipping_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCargosResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCargosResponse) ProtoMessage() {}
+
+func (x *ListCargosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCargosResponse.ProtoReflect.Descriptor instead.
+func (*ListCargosResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListCargosResponse) GetCargos() []*Cargo {
+	if x != nil {
+		return x.Cargos
+	}
+	return nil
+}
+
+func (x *ListCargosResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ListLocationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLocationsRequest) Reset() {
+	*x = ListLocationsRequest{}
+	mi := &file_shipping_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLocationsRequest) ProtoMessage() {}
+
+func (x *ListLocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLocationsRequest.ProtoReflect.Descriptor instead.
+func (*ListLocationsRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{32}
+}
+
+type ListLocationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locations     []*Location            `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLocationsResponse) Reset() {
+	*x = ListLocationsResponse{}
+	mi := &file_shipping_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLocationsResponse) ProtoMessage() {}
+
+func (x *ListLocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLocationsResponse.ProtoReflect.Descriptor instead.
+func (*ListLocationsResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListLocationsResponse) GetLocations() []*Location {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+type TrackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackRequest) Reset() {
+	*x = TrackRequest{}
+	mi := &file_shipping_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackRequest) ProtoMessage() {}
+
+func (x *TrackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackRequest.ProtoReflect.Descriptor instead.
+func (*TrackRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *TrackRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+type TrackedCargo struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId           string                 `protobuf:"bytes,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	StatusText           string                 `protobuf:"bytes,2,opt,name=status_text,json=statusText,proto3" json:"status_text,omitempty"`
+	Origin               string                 `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination          string                 `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`
+	Eta                  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=eta,proto3" json:"eta,omitempty"`
+	NextExpectedActivity string                 `protobuf:"bytes,6,opt,name=next_expected_activity,json=nextExpectedActivity,proto3" json:"next_expected_activity,omitempty"`
+	ArrivalDeadline      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=arrival_deadline,json=arrivalDeadline,proto3" json:"arrival_deadline,omitempty"`
+	Events               []*TrackedCargo_Event  `protobuf:"bytes,8,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *TrackedCargo) Reset() {
+	*x = TrackedCargo{}
+	mi := &file_shipping_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackedCargo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackedCargo) ProtoMessage() {}
+
+func (x *TrackedCargo) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackedCargo.ProtoReflect.Descriptor instead.
+func (*TrackedCargo) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *TrackedCargo) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+func (x *TrackedCargo) GetStatusText() string {
+	if x != nil {
+		return x.StatusText
+	}
+	return ""
+}
+
+func (x *TrackedCargo) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *TrackedCargo) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *TrackedCargo) GetEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Eta
+	}
+	return nil
+}
+
+func (x *TrackedCargo) GetNextExpectedActivity() string {
+	if x != nil {
+		return x.NextExpectedActivity
+	}
+	return ""
+}
+
+func (x *TrackedCargo) GetArrivalDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArrivalDeadline
+	}
+	return nil
+}
+
+func (x *TrackedCargo) GetEvents() []*TrackedCargo_Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type TrackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cargo         *TrackedCargo          `protobuf:"bytes,1,opt,name=cargo,proto3" json:"cargo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackResponse) Reset() {
+	*x = TrackResponse{}
+	mi := &file_shipping_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackResponse) ProtoMessage() {}
+
+func (x *TrackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackResponse.ProtoReflect.Descriptor instead.
+func (*TrackResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *TrackResponse) GetCargo() *TrackedCargo {
+	if x != nil {
+		return x.Cargo
+	}
+	return nil
+}
+
+type RegisterHandlingEventRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CompletionTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=completion_time,json=completionTime,proto3" json:"completion_time,omitempty"`
+	TrackingId     string                 `protobuf:"bytes,2,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	// Voyage number. Not needed for events that don't involve a voyage.
+	VoyageNumber  string            `protobuf:"bytes,3,opt,name=voyage_number,json=voyageNumber,proto3" json:"voyage_number,omitempty"`
+	Location      string            `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	EventType     HandlingEventType `protobuf:"varint,5,opt,name=event_type,json=eventType,proto3,enum=shipping.v1.HandlingEventType" json:"event_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterHandlingEventRequest) Reset() {
+	*x = RegisterHandlingEventRequest{}
+	mi := &file_shipping_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterHandlingEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterHandlingEventRequest) ProtoMessage() {}
+
+func (x *RegisterHandlingEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterHandlingEventRequest.ProtoReflect.Descriptor instead.
+func (*RegisterHandlingEventRequest) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RegisterHandlingEventRequest) GetCompletionTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletionTime
+	}
+	return nil
+}
+
+func (x *RegisterHandlingEventRequest) GetTrackingId() string {
+	if x != nil {
+		return x.TrackingId
+	}
+	return ""
+}
+
+func (x *RegisterHandlingEventRequest) GetVoyageNumber() string {
+	if x != nil {
+		return x.VoyageNumber
+	}
+	return ""
+}
+
+func (x *RegisterHandlingEventRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *RegisterHandlingEventRequest) GetEventType() HandlingEventType {
+	if x != nil {
+		return x.EventType
+	}
+	return HandlingEventType_HANDLING_EVENT_TYPE_UNSPECIFIED
+}
+
+type RegisterHandlingEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterHandlingEventResponse) Reset() {
+	*x = RegisterHandlingEventResponse{}
+	mi := &file_shipping_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterHandlingEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterHandlingEventResponse) ProtoMessage() {}
+
+func (x *RegisterHandlingEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterHandlingEventResponse.ProtoReflect.Descriptor instead.
+func (*RegisterHandlingEventResponse) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{38}
+}
+
+type Quote_Line struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Description   string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quote_Line) Reset() {
+	*x = Quote_Line{}
+	mi := &file_shipping_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quote_Line) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quote_Line) ProtoMessage() {}
+
+func (x *Quote_Line) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quote_Line.ProtoReflect.Descriptor instead.
+func (*Quote_Line) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{14, 0}
+}
+
+func (x *Quote_Line) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Quote_Line) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+type TrackedCargo_Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Description   string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Expected      bool                   `protobuf:"varint,2,opt,name=expected,proto3" json:"expected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackedCargo_Event) Reset() {
+	*x = TrackedCargo_Event{}
+	mi := &file_shipping_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackedCargo_Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackedCargo_Event) ProtoMessage() {}
+
+func (x *TrackedCargo_Event) ProtoReflect() protoreflect.Message {
+	mi := &file_shipping_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackedCargo_Event.ProtoReflect.Descriptor instead.
+func (*TrackedCargo_Event) Descriptor() ([]byte, []int) {
+	return file_shipping_proto_rawDescGZIP(), []int{35, 0}
+}
+
+func (x *TrackedCargo_Event) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TrackedCargo_Event) GetExpected() bool {
+	if x != nil {
+		return x.Expected
+	}
+	return false
+}
+
+var File_shipping_proto protoreflect.FileDescriptor
+
+const file_shipping_proto_rawDesc = "" +
+	"\n" +
+	"\x0eshipping.proto\x12\vshipping.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc4\x01\n" +
+	"\x03Leg\x12#\n" +
+	"\rvoyage_number\x18\x01 \x01(\tR\fvoyageNumber\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x127\n" +
+	"\tload_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bloadTime\x12;\n" +
+	"\vunload_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"unloadTime\"1\n" +
+	"\tItinerary\x12$\n" +
+	"\x04legs\x18\x01 \x03(\v2\x10.shipping.v1.LegR\x04legs\"\xbe\x03\n" +
+	"\x05Cargo\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\x12\x16\n" +
+	"\x06origin\x18\x02 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x03 \x01(\tR\vdestination\x12E\n" +
+	"\x10arrival_deadline\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0farrivalDeadline\x12\x1c\n" +
+	"\tmisrouted\x18\x05 \x01(\bR\tmisrouted\x12\x16\n" +
+	"\x06routed\x18\x06 \x01(\bR\x06routed\x12$\n" +
+	"\x04legs\x18\a \x03(\v2\x10.shipping.v1.LegR\x04legs\x12I\n" +
+	"\x12earliest_departure\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x11earliestDeparture\x12\x19\n" +
+	"\bmax_legs\x18\t \x01(\x05R\amaxLegs\x12'\n" +
+	"\x0favoid_locations\x18\n" +
+	" \x03(\tR\x0eavoidLocations\x12(\n" +
+	"\x05price\x18\v \x01(\v2\x12.shipping.v1.MoneyR\x05price\";\n" +
+	"\x05Money\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"6\n" +
+	"\bLocation\x12\x16\n" +
+	"\x06locode\x18\x01 \x01(\tR\x06locode\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\xa5\x02\n" +
+	"\x13BookNewCargoRequest\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12E\n" +
+	"\x10arrival_deadline\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0farrivalDeadline\x12I\n" +
+	"\x12earliest_departure\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x11earliestDeparture\x12\x19\n" +
+	"\bmax_legs\x18\x05 \x01(\x05R\amaxLegs\x12'\n" +
+	"\x0favoid_locations\x18\x06 \x03(\tR\x0eavoidLocations\"7\n" +
+	"\x14BookNewCargoResponse\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\"3\n" +
+	"\x10LoadCargoRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\"=\n" +
+	"\x11LoadCargoResponse\x12(\n" +
+	"\x05cargo\x18\x01 \x01(\v2\x12.shipping.v1.CargoR\x05cargo\"c\n" +
+	"$RequestPossibleRoutesForCargoRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\x12\x1a\n" +
+	"\bstrategy\x18\x02 \x01(\tR\bstrategy\"h\n" +
+	"\x04Cost\x12\x1c\n" +
+	"\tcriterion\x18\x01 \x01(\tR\tcriterion\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x01R\x06weight\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\"\xc0\x01\n" +
+	"\x0eRouteCandidate\x12$\n" +
+	"\x04legs\x18\x01 \x03(\v2\x10.shipping.v1.LegR\x04legs\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\x12'\n" +
+	"\x05costs\x18\x05 \x03(\v2\x11.shipping.v1.CostR\x05costs\"\\\n" +
+	"%RequestPossibleRoutesForCargoResponse\x123\n" +
+	"\x06routes\x18\x01 \x03(\v2\x1b.shipping.v1.RouteCandidateR\x06routes\"\x87\x01\n" +
+	"\x11QuoteRouteRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\x12!\n" +
+	"\fcandidate_id\x18\x02 \x01(\tR\vcandidateId\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"\xca\x01\n" +
+	"\x05Quote\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x05R\x04size\x12-\n" +
+	"\x05lines\x18\x02 \x03(\v2\x17.shipping.v1.Quote.LineR\x05lines\x12(\n" +
+	"\x05total\x18\x03 \x01(\v2\x12.shipping.v1.MoneyR\x05total\x1aT\n" +
+	"\x04Line\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12*\n" +
+	"\x06amount\x18\x02 \x01(\v2\x12.shipping.v1.MoneyR\x06amount\">\n" +
+	"\x12QuoteRouteResponse\x12(\n" +
+	"\x05quote\x18\x01 \x01(\v2\x12.shipping.v1.QuoteR\x05quote\"j\n" +
+	"\x19AssignCargoToRouteRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\x12,\n" +
+	"\x05route\x18\x02 \x01(\v2\x16.shipping.v1.ItineraryR\x05route\"\x1c\n" +
+	"\x1aAssignCargoToRouteResponse\"h\n" +
+	"\"AssignCargoToRouteCandidateRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\x12!\n" +
+	"\fcandidate_id\x18\x02 \x01(\tR\vcandidateId\"%\n" +
+	"#AssignCargoToRouteCandidateResponse\"]\n" +
+	"\x18ChangeDestinationRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\"\x1b\n" +
+	"\x19ChangeDestinationResponse\"\x86\x01\n" +
+	"\x1cChangeArrivalDeadlineRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\x12E\n" +
+	"\x10arrival_deadline\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0farrivalDeadline\"\x1f\n" +
+	"\x1dChangeArrivalDeadlineResponse\"N\n" +
+	"\x13ChangeOriginRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\x12\x16\n" +
+	"\x06origin\x18\x02 \x01(\tR\x06origin\"\x16\n" +
+	"\x14ChangeOriginResponse\"\xd8\x01\n" +
+	"\x05Terms\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12E\n" +
+	"\x10arrival_deadline\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0farrivalDeadline\x12$\n" +
+	"\x04legs\x18\x04 \x03(\v2\x10.shipping.v1.LegR\x04legs\x12(\n" +
+	"\x05price\x18\x05 \x01(\v2\x12.shipping.v1.MoneyR\x05price\"\xe7\x01\n" +
+	"\tAmendment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1a.shipping.v1.AmendmentTypeR\x04type\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12.\n" +
+	"\x04time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12.\n" +
+	"\bprevious\x18\x05 \x01(\v2\x12.shipping.v1.TermsR\bprevious\x12$\n" +
+	"\x03new\x18\x06 \x01(\v2\x12.shipping.v1.TermsR\x03new\"1\n" +
+	"\x0eHistoryRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\"I\n" +
+	"\x0fHistoryResponse\x126\n" +
+	"\n" +
+	"amendments\x18\x01 \x03(\v2\x16.shipping.v1.AmendmentR\n" +
+	"amendments\"\xcb\x03\n" +
+	"\x11ListCargosRequest\x12A\n" +
+	"\x0erouting_status\x18\x01 \x01(\x0e2\x1a.shipping.v1.RoutingStatusR\rroutingStatus\x12!\n" +
+	"\tmisrouted\x18\x02 \x01(\bH\x00R\tmisrouted\x88\x01\x01\x12\x16\n" +
+	"\x06origin\x18\x03 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x04 \x01(\tR\vdestination\x12A\n" +
+	"\x0edeadline_after\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\rdeadlineAfter\x12C\n" +
+	"\x0fdeadline_before\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0edeadlineBefore\x122\n" +
+	"\asort_by\x18\a \x01(\x0e2\x19.shipping.v1.CargoSortKeyR\x06sortBy\x12\x1e\n" +
+	"\n" +
+	"descending\x18\b \x01(\bR\n" +
+	"descending\x12\x16\n" +
+	"\x06cursor\x18\t \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\n" +
+	" \x01(\x05R\x05limitB\f\n" +
+	"\n" +
+	"_misrouted\"a\n" +
+	"\x12ListCargosResponse\x12*\n" +
+	"\x06cargos\x18\x01 \x03(\v2\x12.shipping.v1.CargoR\x06cargos\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\x16\n" +
+	"\x14ListLocationsRequest\"L\n" +
+	"\x15ListLocationsResponse\x123\n" +
+	"\tlocations\x18\x01 \x03(\v2\x15.shipping.v1.LocationR\tlocations\"/\n" +
+	"\fTrackRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\"\xb5\x03\n" +
+	"\fTrackedCargo\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\tR\n" +
+	"trackingId\x12\x1f\n" +
+	"\vstatus_text\x18\x02 \x01(\tR\n" +
+	"statusText\x12\x16\n" +
+	"\x06origin\x18\x03 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x04 \x01(\tR\vdestination\x12,\n" +
+	"\x03eta\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x03eta\x124\n" +
+	"\x16next_expected_activity\x18\x06 \x01(\tR\x14nextExpectedActivity\x12E\n" +
+	"\x10arrival_deadline\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x0farrivalDeadline\x127\n" +
+	"\x06events\x18\b \x03(\v2\x1f.shipping.v1.TrackedCargo.EventR\x06events\x1aE\n" +
+	"\x05Event\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bexpected\x18\x02 \x01(\bR\bexpected\"@\n" +
+	"\rTrackResponse\x12/\n" +
+	"\x05cargo\x18\x01 \x01(\v2\x19.shipping.v1.TrackedCargoR\x05cargo\"\x84\x02\n" +
+	"\x1cRegisterHandlingEventRequest\x12C\n" +
+	"\x0fcompletion_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x0ecompletionTime\x12\x1f\n" +
+	"\vtracking_id\x18\x02 \x01(\tR\n" +
+	"trackingId\x12#\n" +
+	"\rvoyage_number\x18\x03 \x01(\tR\fvoyageNumber\x12\x1a\n" +
+	"\blocation\x18\x04 \x01(\tR\blocation\x12=\n" +
+	"\n" +
+	"event_type\x18\x05 \x01(\x0e2\x1e.shipping.v1.HandlingEventTypeR\teventType\"\x1f\n" +
+	"\x1dRegisterHandlingEventResponse*\xca\x01\n" +
+	"\rAmendmentType\x12\x1e\n" +
+	"\x1aAMENDMENT_TYPE_UNSPECIFIED\x10\x00\x12&\n" +
+	"\"AMENDMENT_TYPE_DESTINATION_CHANGED\x10\x01\x12+\n" +
+	"'AMENDMENT_TYPE_ARRIVAL_DEADLINE_CHANGED\x10\x02\x12!\n" +
+	"\x1dAMENDMENT_TYPE_ORIGIN_CHANGED\x10\x03\x12!\n" +
+	"\x1dAMENDMENT_TYPE_ROUTE_ASSIGNED\x10\x04*\x87\x01\n" +
+	"\rRoutingStatus\x12\x1e\n" +
+	"\x1aROUTING_STATUS_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19ROUTING_STATUS_NOT_ROUTED\x10\x01\x12\x1c\n" +
+	"\x18ROUTING_STATUS_MISROUTED\x10\x02\x12\x19\n" +
+	"\x15ROUTING_STATUS_ROUTED\x10\x03*S\n" +
+	"\fCargoSortKey\x12\x1e\n" +
+	"\x1aCARGO_SORT_KEY_TRACKING_ID\x10\x00\x12#\n" +
+	"\x1fCARGO_SORT_KEY_ARRIVAL_DEADLINE\x10\x01*\xd7\x01\n" +
+	"\x11HandlingEventType\x12#\n" +
+	"\x1fHANDLING_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18HANDLING_EVENT_TYPE_LOAD\x10\x01\x12\x1e\n" +
+	"\x1aHANDLING_EVENT_TYPE_UNLOAD\x10\x02\x12\x1f\n" +
+	"\x1bHANDLING_EVENT_TYPE_RECEIVE\x10\x03\x12\x1d\n" +
+	"\x19HANDLING_EVENT_TYPE_CLAIM\x10\x04\x12\x1f\n" +
+	"\x1bHANDLING_EVENT_TYPE_CUSTOMS\x10\x052\x89\t\n" +
+	"\x0eBookingService\x12S\n" +
+	"\fBookNewCargo\x12 .shipping.v1.BookNewCargoRequest\x1a!.shipping.v1.BookNewCargoResponse\x12J\n" +
+	"\tLoadCargo\x12\x1d.shipping.v1.LoadCargoRequest\x1a\x1e.shipping.v1.LoadCargoResponse\x12\x86\x01\n" +
+	"\x1dRequestPossibleRoutesForCargo\x121.shipping.v1.RequestPossibleRoutesForCargoRequest\x1a2.shipping.v1.RequestPossibleRoutesForCargoResponse\x12M\n" +
+	"\n" +
+	"QuoteRoute\x12\x1e.shipping.v1.QuoteRouteRequest\x1a\x1f.shipping.v1.QuoteRouteResponse\x12e\n" +
+	"\x12AssignCargoToRoute\x12&.shipping.v1.AssignCargoToRouteRequest\x1a'.shipping.v1.AssignCargoToRouteResponse\x12\x80\x01\n" +
+	"\x1bAssignCargoToRouteCandidate\x12/.shipping.v1.AssignCargoToRouteCandidateRequest\x1a0.shipping.v1.AssignCargoToRouteCandidateResponse\x12b\n" +
+	"\x11ChangeDestination\x12%.shipping.v1.ChangeDestinationRequest\x1a&.shipping.v1.ChangeDestinationResponse\x12n\n" +
+	"\x15ChangeArrivalDeadline\x12).shipping.v1.ChangeArrivalDeadlineRequest\x1a*.shipping.v1.ChangeArrivalDeadlineResponse\x12S\n" +
+	"\fChangeOrigin\x12 .shipping.v1.ChangeOriginRequest\x1a!.shipping.v1.ChangeOriginResponse\x12D\n" +
+	"\aHistory\x12\x1b.shipping.v1.HistoryRequest\x1a\x1c.shipping.v1.HistoryResponse\x12M\n" +
+	"\n" +
+	"ListCargos\x12\x1e.shipping.v1.ListCargosRequest\x1a\x1f.shipping.v1.ListCargosResponse\x12V\n" +
+	"\rListLocations\x12!.shipping.v1.ListLocationsRequest\x1a\".shipping.v1.ListLocationsResponse2Q\n" +
+	"\x0fTrackingService\x12>\n" +
+	"\x05Track\x12\x19.shipping.v1.TrackRequest\x1a\x1a.shipping.v1.TrackResponse2\x81\x01\n" +
+	"\x0fHandlingService\x12n\n" +
+	"\x15RegisterHandlingEvent\x12).shipping.v1.RegisterHandlingEventRequest\x1a*.shipping.v1.RegisterHandlingEventResponseB\"Z github.com/marcusolsson/goddd/pbb\x06proto3"
+
+var (
+	file_shipping_proto_rawDescOnce sync.Once
+	file_shipping_proto_rawDescData []byte
+)
+
+func file_shipping_proto_rawDescGZIP() []byte {
+	file_shipping_proto_rawDescOnce.Do(func() {
+		file_shipping_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_shipping_proto_rawDesc), len(file_shipping_proto_rawDesc)))
+	})
+	return file_shipping_proto_rawDescData
+}
+
+var file_shipping_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_shipping_proto_msgTypes = make([]protoimpl.MessageInfo, 41)
+var file_shipping_proto_goTypes = []any{
+	(AmendmentType)(0),                            // 0: shipping.v1.AmendmentType
+	(RoutingStatus)(0),                            // 1: shipping.v1.RoutingStatus
+	(CargoSortKey)(0),                             // 2: shipping.v1.CargoSortKey
+	(HandlingEventType)(0),                        // 3: shipping.v1.HandlingEventType
+	(*Leg)(nil),                                   // 4: shipping.v1.Leg
+	(*Itinerary)(nil),                             // 5: shipping.v1.Itinerary
+	(*Cargo)(nil),                                 // 6: shipping.v1.Cargo
+	(*Money)(nil),                                 // 7: shipping.v1.Money
+	(*Location)(nil),                              // 8: shipping.v1.Location
+	(*BookNewCargoRequest)(nil),                   // 9: shipping.v1.BookNewCargoRequest
+	(*BookNewCargoResponse)(nil),                  // 10: shipping.v1.BookNewCargoResponse
+	(*LoadCargoRequest)(nil),                      // 11: shipping.v1.LoadCargoRequest
+	(*LoadCargoResponse)(nil),                     // 12: shipping.v1.LoadCargoResponse
+	(*RequestPossibleRoutesForCargoRequest)(nil),  // 13: shipping.v1.RequestPossibleRoutesForCargoRequest
+	(*Cost)(nil),                                  // 14: shipping.v1.Cost
+	(*RouteCandidate)(nil),                        // 15: shipping.v1.RouteCandidate
+	(*RequestPossibleRoutesForCargoResponse)(nil), // 16: shipping.v1.RequestPossibleRoutesForCargoResponse
+	(*QuoteRouteRequest)(nil),                     // 17: shipping.v1.QuoteRouteRequest
+	(*Quote)(nil),                                 // 18: shipping.v1.Quote
+	(*QuoteRouteResponse)(nil),                    // 19: shipping.v1.QuoteRouteResponse
+	(*AssignCargoToRouteRequest)(nil),             // 20: shipping.v1.AssignCargoToRouteRequest
+	(*AssignCargoToRouteResponse)(nil),            // 21: shipping.v1.AssignCargoToRouteResponse
+	(*AssignCargoToRouteCandidateRequest)(nil),    // 22: shipping.v1.AssignCargoToRouteCandidateRequest
+	(*AssignCargoToRouteCandidateResponse)(nil),   // 23: shipping.v1.AssignCargoToRouteCandidateResponse
+	(*ChangeDestinationRequest)(nil),              // 24: shipping.v1.ChangeDestinationRequest
+	(*ChangeDestinationResponse)(nil),             // 25: shipping.v1.ChangeDestinationResponse
+	(*ChangeArrivalDeadlineRequest)(nil),          // 26: shipping.v1.ChangeArrivalDeadlineRequest
+	(*ChangeArrivalDeadlineResponse)(nil),         // 27: shipping.v1.ChangeArrivalDeadlineResponse
+	(*ChangeOriginRequest)(nil),                   // 28: shipping.v1.ChangeOriginRequest
+	(*ChangeOriginResponse)(nil),                  // 29: shipping.v1.ChangeOriginResponse
+	(*Terms)(nil),                                 // 30: shipping.v1.Terms
+	(*Amendment)(nil),                             // 31: shipping.v1.Amendment
+	(*HistoryRequest)(nil),                        // 32: shipping.v1.HistoryRequest
+	(*HistoryResponse)(nil),                       // 33: shipping.v1.HistoryResponse
+	(*ListCargosRequest)(nil),                     // 34: shipping.v1.ListCargosRequest
+	(*ListCargosResponse)(nil),                    // 35: shipping.v1.ListCargosResponse
+	(*ListLocationsRequest)(nil),                  // 36: shipping.v1.ListLocationsRequest
+	(*ListLocationsResponse)(nil),                 // 37: shipping.v1.ListLocationsResponse
+	(*TrackRequest)(nil),                          // 38: shipping.v1.TrackRequest
+	(*TrackedCargo)(nil),                          // 39: shipping.v1.TrackedCargo
+	(*TrackResponse)(nil),                         // 40: shipping.v1.TrackResponse
+	(*RegisterHandlingEventRequest)(nil),          // 41: shipping.v1.RegisterHandlingEventRequest
+	(*RegisterHandlingEventResponse)(nil),         // 42: shipping.v1.RegisterHandlingEventResponse
+	(*Quote_Line)(nil),                            // 43: shipping.v1.Quote.Line
+	(*TrackedCargo_Event)(nil),                    // 44: shipping.v1.TrackedCargo.Event
+	(*timestamppb.Timestamp)(nil),                 // 45: google.protobuf.Timestamp
+}
+var file_shipping_proto_depIdxs = []int32{
+	45, // 0: shipping.v1.Leg.load_time:type_name -> google.protobuf.Timestamp
+	45, // 1: shipping.v1.Leg.unload_time:type_name -> google.protobuf.Timestamp
+	4,  // 2: shipping.v1.Itinerary.legs:type_name -> shipping.v1.Leg
+	45, // 3: shipping.v1.Cargo.arrival_deadline:type_name -> google.protobuf.Timestamp
+	4,  // 4: shipping.v1.Cargo.legs:type_name -> shipping.v1.Leg
+	45, // 5: shipping.v1.Cargo.earliest_departure:type_name -> google.protobuf.Timestamp
+	7,  // 6: shipping.v1.Cargo.price:type_name -> shipping.v1.Money
+	45, // 7: shipping.v1.BookNewCargoRequest.arrival_deadline:type_name -> google.protobuf.Timestamp
+	45, // 8: shipping.v1.BookNewCargoRequest.earliest_departure:type_name -> google.protobuf.Timestamp
+	6,  // 9: shipping.v1.LoadCargoResponse.cargo:type_name -> shipping.v1.Cargo
+	4,  // 10: shipping.v1.RouteCandidate.legs:type_name -> shipping.v1.Leg
+	45, // 11: shipping.v1.RouteCandidate.expires_at:type_name -> google.protobuf.Timestamp
+	14, // 12: shipping.v1.RouteCandidate.costs:type_name -> shipping.v1.Cost
+	15, // 13: shipping.v1.RequestPossibleRoutesForCargoResponse.routes:type_name -> shipping.v1.RouteCandidate
+	43, // 14: shipping.v1.Quote.lines:type_name -> shipping.v1.Quote.Line
+	7,  // 15: shipping.v1.Quote.total:type_name -> shipping.v1.Money
+	18, // 16: shipping.v1.QuoteRouteResponse.quote:type_name -> shipping.v1.Quote
+	5,  // 17: shipping.v1.AssignCargoToRouteRequest.route:type_name -> shipping.v1.Itinerary
+	45, // 18: shipping.v1.ChangeArrivalDeadlineRequest.arrival_deadline:type_name -> google.protobuf.Timestamp
+	45, // 19: shipping.v1.Terms.arrival_deadline:type_name -> google.protobuf.Timestamp
+	4,  // 20: shipping.v1.Terms.legs:type_name -> shipping.v1.Leg
+	7,  // 21: shipping.v1.Terms.price:type_name -> shipping.v1.Money
+	0,  // 22: shipping.v1.Amendment.type:type_name -> shipping.v1.AmendmentType
+	45, // 23: shipping.v1.Amendment.time:type_name -> google.protobuf.Timestamp
+	30, // 24: shipping.v1.Amendment.previous:type_name -> shipping.v1.Terms
+	30, // 25: shipping.v1.Amendment.new:type_name -> shipping.v1.Terms
+	31, // 26: shipping.v1.HistoryResponse.amendments:type_name -> shipping.v1.Amendment
+	1,  // 27: shipping.v1.ListCargosRequest.routing_status:type_name -> shipping.v1.RoutingStatus
+	45, // 28: shipping.v1.ListCargosRequest.deadline_after:type_name -> google.protobuf.Timestamp
+	45, // 29: shipping.v1.ListCargosRequest.deadline_before:type_name -> google.protobuf.Timestamp
+	2,  // 30: shipping.v1.ListCargosRequest.sort_by:type_name -> shipping.v1.CargoSortKey
+	6,  // 31: shipping.v1.ListCargosResponse.cargos:type_name -> shipping.v1.Cargo
+	8,  // 32: shipping.v1.ListLocationsResponse.locations:type_name -> shipping.v1.Location
+	45, // 33: shipping.v1.TrackedCargo.eta:type_name -> google.protobuf.Timestamp
+	45, // 34: shipping.v1.TrackedCargo.arrival_deadline:type_name -> google.protobuf.Timestamp
+	44, // 35: shipping.v1.TrackedCargo.events:type_name -> shipping.v1.TrackedCargo.Event
+	39, // 36: shipping.v1.TrackResponse.cargo:type_name -> shipping.v1.TrackedCargo
+	45, // 37: shipping.v1.RegisterHandlingEventRequest.completion_time:type_name -> google.protobuf.Timestamp
+	3,  // 38: shipping.v1.RegisterHandlingEventRequest.event_type:type_name -> shipping.v1.HandlingEventType
+	7,  // 39: shipping.v1.Quote.Line.amount:type_name -> shipping.v1.Money
+	9,  // 40: shipping.v1.BookingService.BookNewCargo:input_type -> shipping.v1.BookNewCargoRequest
+	11, // 41: shipping.v1.BookingService.LoadCargo:input_type -> shipping.v1.LoadCargoRequest
+	13, // 42: shipping.v1.BookingService.RequestPossibleRoutesForCargo:input_type -> shipping.v1.RequestPossibleRoutesForCargoRequest
+	17, // 43: shipping.v1.BookingService.QuoteRoute:input_type -> shipping.v1.QuoteRouteRequest
+	20, // 44: shipping.v1.BookingService.AssignCargoToRoute:input_type -> shipping.v1.AssignCargoToRouteRequest
+	22, // 45: shipping.v1.BookingService.AssignCargoToRouteCandidate:input_type -> shipping.v1.AssignCargoToRouteCandidateRequest
+	24, // 46: shipping.v1.BookingService.ChangeDestination:input_type -> shipping.v1.ChangeDestinationRequest
+	26, // 47: shipping.v1.BookingService.ChangeArrivalDeadline:input_type -> shipping.v1.ChangeArrivalDeadlineRequest
+	28, // 48: shipping.v1.BookingService.ChangeOrigin:input_type -> shipping.v1.ChangeOriginRequest
+	32, // 49: shipping.v1.BookingService.History:input_type -> shipping.v1.HistoryRequest
+	34, // 50: shipping.v1.BookingService.ListCargos:input_type -> shipping.v1.ListCargosRequest
+	36, // 51: shipping.v1.BookingService.ListLocations:input_type -> shipping.v1.ListLocationsRequest
+	38, // 52: shipping.v1.TrackingService.Track:input_type -> shipping.v1.TrackRequest
+	41, // 53: shipping.v1.HandlingService.RegisterHandlingEvent:input_type -> shipping.v1.RegisterHandlingEventRequest
+	10, // 54: shipping.v1.BookingService.BookNewCargo:output_type -> shipping.v1.BookNewCargoResponse
+	12, // 55: shipping.v1.BookingService.LoadCargo:output_type -> shipping.v1.LoadCargoResponse
+	16, // 56: shipping.v1.BookingService.RequestPossibleRoutesForCargo:output_type -> shipping.v1.RequestPossibleRoutesForCargoResponse
+	19, // 57: shipping.v1.BookingService.QuoteRoute:output_type -> shipping.v1.QuoteRouteResponse
+	21, // 58: shipping.v1.BookingService.AssignCargoToRoute:output_type -> shipping.v1.AssignCargoToRouteResponse
+	23, // 59: shipping.v1.BookingService.AssignCargoToRouteCandidate:output_type -> shipping.v1.AssignCargoToRouteCandidateResponse
+	25, // 60: shipping.v1.BookingService.ChangeDestination:output_type -> shipping.v1.ChangeDestinationResponse
+	27, // 61: shipping.v1.BookingService.ChangeArrivalDeadline:output_type -> shipping.v1.ChangeArrivalDeadlineResponse
+	29, // 62: shipping.v1.BookingService.ChangeOrigin:output_type -> shipping.v1.ChangeOriginResponse
+	33, // 63: shipping.v1.BookingService.History:output_type -> shipping.v1.HistoryResponse
+	35, // 64: shipping.v1.BookingService.ListCargos:output_type -> shipping.v1.ListCargosResponse
+	37, // 65: shipping.v1.BookingService.ListLocations:output_type -> shipping.v1.ListLocationsResponse
+	40, // 66: shipping.v1.TrackingService.Track:output_type -> shipping.v1.TrackResponse
+	42, // 67: shipping.v1.HandlingService.RegisterHandlingEvent:output_type -> shipping.v1.RegisterHandlingEventResponse
+	54, // [54:68] is the sub-list for method output_type
+	40, // [40:54] is the sub-list for method input_type
+	40, // [40:40] is the sub-list for extension type_name
+	40, // [40:40] is the sub-list for extension extendee
+	0,  // [0:40] is the sub-list for field type_name
+}
+
+func init() { file_shipping_proto_init() }
+func file_shipping_proto_init() {
+	if File_shipping_proto != nil {
+		return
+	}
+	file_shipping_proto_msgTypes[30].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shipping_proto_rawDesc), len(file_shipping_proto_rawDesc)),
+			NumEnums:      4,
+			NumMessages:   41,
+			NumExtensions: 0,
+			NumServices:   3,
+		},
+		GoTypes:           file_shipping_proto_goTypes,
+		DependencyIndexes: file_shipping_proto_depIdxs,
+		EnumInfos:         file_shipping_proto_enumTypes,
+		MessageInfos:      file_shipping_proto_msgTypes,
+	}.Build()
+	File_shipping_proto = out.File
+	file_shipping_proto_goTypes = nil
+	file_shipping_proto_depIdxs = nil
+}
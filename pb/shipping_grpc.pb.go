@@ -0,0 +1,799 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: shipping.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BookingService_BookNewCargo_FullMethodName                  = "/shipping.v1.BookingService/BookNewCargo"
+	BookingService_LoadCargo_FullMethodName                     = "/shipping.v1.BookingService/LoadCargo"
+	BookingService_RequestPossibleRoutesForCargo_FullMethodName = "/shipping.v1.BookingService/RequestPossibleRoutesForCargo"
+	BookingService_QuoteRoute_FullMethodName                    = "/shipping.v1.BookingService/QuoteRoute"
+	BookingService_AssignCargoToRoute_FullMethodName            = "/shipping.v1.BookingService/AssignCargoToRoute"
+	BookingService_AssignCargoToRouteCandidate_FullMethodName   = "/shipping.v1.BookingService/AssignCargoToRouteCandidate"
+	BookingService_ChangeDestination_FullMethodName             = "/shipping.v1.BookingService/ChangeDestination"
+	BookingService_ChangeArrivalDeadline_FullMethodName         = "/shipping.v1.BookingService/ChangeArrivalDeadline"
+	BookingService_ChangeOrigin_FullMethodName                  = "/shipping.v1.BookingService/ChangeOrigin"
+	BookingService_History_FullMethodName                       = "/shipping.v1.BookingService/History"
+	BookingService_ListCargos_FullMethodName                    = "/shipping.v1.BookingService/ListCargos"
+	BookingService_ListLocations_FullMethodName                 = "/shipping.v1.BookingService/ListLocations"
+)
+
+// BookingServiceClient is the client API for BookingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BookingService is used by views facing an administrator.
+type BookingServiceClient interface {
+	// BookNewCargo registers a new cargo, not yet routed.
+	BookNewCargo(ctx context.Context, in *BookNewCargoRequest, opts ...grpc.CallOption) (*BookNewCargoResponse, error)
+	// LoadCargo returns a booked cargo.
+	LoadCargo(ctx context.Context, in *LoadCargoRequest, opts ...grpc.CallOption) (*LoadCargoResponse, error)
+	// RequestPossibleRoutesForCargo returns the routes that satisfy the route
+	// specification of a cargo, ranked by a strategy. The routes can be
+	// assigned by ID until they expire.
+	RequestPossibleRoutesForCargo(ctx context.Context, in *RequestPossibleRoutesForCargoRequest, opts ...grpc.CallOption) (*RequestPossibleRoutesForCargoResponse, error)
+	// QuoteRoute prices carrying a cargo along a route returned by
+	// RequestPossibleRoutesForCargo for that cargo. The quoted total becomes
+	// the price of the cargo if it's assigned to the route.
+	QuoteRoute(ctx context.Context, in *QuoteRouteRequest, opts ...grpc.CallOption) (*QuoteRouteResponse, error)
+	// AssignCargoToRoute assigns a cargo to a route.
+	AssignCargoToRoute(ctx context.Context, in *AssignCargoToRouteRequest, opts ...grpc.CallOption) (*AssignCargoToRouteResponse, error)
+	// AssignCargoToRouteCandidate assigns a cargo to a route returned by
+	// RequestPossibleRoutesForCargo for that cargo.
+	AssignCargoToRouteCandidate(ctx context.Context, in *AssignCargoToRouteCandidateRequest, opts ...grpc.CallOption) (*AssignCargoToRouteCandidateResponse, error)
+	// ChangeDestination changes the destination of a cargo.
+	ChangeDestination(ctx context.Context, in *ChangeDestinationRequest, opts ...grpc.CallOption) (*ChangeDestinationResponse, error)
+	// ChangeArrivalDeadline changes the arrival deadline of a cargo.
+	ChangeArrivalDeadline(ctx context.Context, in *ChangeArrivalDeadlineRequest, opts ...grpc.CallOption) (*ChangeArrivalDeadlineResponse, error)
+	// ChangeOrigin changes where a cargo is picked up. It fails once the cargo
+	// has been received.
+	ChangeOrigin(ctx context.Context, in *ChangeOriginRequest, opts ...grpc.CallOption) (*ChangeOriginResponse, error)
+	// History returns the amendments made to the booking of a cargo, oldest
+	// first.
+	History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
+	// ListCargos returns a page of booked cargos.
+	ListCargos(ctx context.Context, in *ListCargosRequest, opts ...grpc.CallOption) (*ListCargosResponse, error)
+	// ListLocations returns all registered locations.
+	ListLocations(ctx context.Context, in *ListLocationsRequest, opts ...grpc.CallOption) (*ListLocationsResponse, error)
+}
+
+type bookingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBookingServiceClient(cc grpc.ClientConnInterface) BookingServiceClient {
+	return &bookingServiceClient{cc}
+}
+
+func (c *bookingServiceClient) BookNewCargo(ctx context.Context, in *BookNewCargoRequest, opts ...grpc.CallOption) (*BookNewCargoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BookNewCargoResponse)
+	err := c.cc.Invoke(ctx, BookingService_BookNewCargo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) LoadCargo(ctx context.Context, in *LoadCargoRequest, opts ...grpc.CallOption) (*LoadCargoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoadCargoResponse)
+	err := c.cc.Invoke(ctx, BookingService_LoadCargo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) RequestPossibleRoutesForCargo(ctx context.Context, in *RequestPossibleRoutesForCargoRequest, opts ...grpc.CallOption) (*RequestPossibleRoutesForCargoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestPossibleRoutesForCargoResponse)
+	err := c.cc.Invoke(ctx, BookingService_RequestPossibleRoutesForCargo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) QuoteRoute(ctx context.Context, in *QuoteRouteRequest, opts ...grpc.CallOption) (*QuoteRouteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuoteRouteResponse)
+	err := c.cc.Invoke(ctx, BookingService_QuoteRoute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) AssignCargoToRoute(ctx context.Context, in *AssignCargoToRouteRequest, opts ...grpc.CallOption) (*AssignCargoToRouteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignCargoToRouteResponse)
+	err := c.cc.Invoke(ctx, BookingService_AssignCargoToRoute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) AssignCargoToRouteCandidate(ctx context.Context, in *AssignCargoToRouteCandidateRequest, opts ...grpc.CallOption) (*AssignCargoToRouteCandidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignCargoToRouteCandidateResponse)
+	err := c.cc.Invoke(ctx, BookingService_AssignCargoToRouteCandidate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) ChangeDestination(ctx context.Context, in *ChangeDestinationRequest, opts ...grpc.CallOption) (*ChangeDestinationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeDestinationResponse)
+	err := c.cc.Invoke(ctx, BookingService_ChangeDestination_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) ChangeArrivalDeadline(ctx context.Context, in *ChangeArrivalDeadlineRequest, opts ...grpc.CallOption) (*ChangeArrivalDeadlineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeArrivalDeadlineResponse)
+	err := c.cc.Invoke(ctx, BookingService_ChangeArrivalDeadline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) ChangeOrigin(ctx context.Context, in *ChangeOriginRequest, opts ...grpc.CallOption) (*ChangeOriginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeOriginResponse)
+	err := c.cc.Invoke(ctx, BookingService_ChangeOrigin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, BookingService_History_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) ListCargos(ctx context.Context, in *ListCargosRequest, opts ...grpc.CallOption) (*ListCargosResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCargosResponse)
+	err := c.cc.Invoke(ctx, BookingService_ListCargos_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookingServiceClient) ListLocations(ctx context.Context, in *ListLocationsRequest, opts ...grpc.CallOption) (*ListLocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLocationsResponse)
+	err := c.cc.Invoke(ctx, BookingService_ListLocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BookingServiceServer is the server API for BookingService service.
+// All implementations must embed UnimplementedBookingServiceServer
+// for forward compatibility.
+//
+// BookingService is used by views facing an administrator.
+type BookingServiceServer interface {
+	// BookNewCargo registers a new cargo, not yet routed.
+	BookNewCargo(context.Context, *BookNewCargoRequest) (*BookNewCargoResponse, error)
+	// LoadCargo returns a booked cargo.
+	LoadCargo(context.Context, *LoadCargoRequest) (*LoadCargoResponse, error)
+	// RequestPossibleRoutesForCargo returns the routes that satisfy the route
+	// specification of a cargo, ranked by a strategy. The routes can be
+	// assigned by ID until they expire.
+	RequestPossibleRoutesForCargo(context.Context, *RequestPossibleRoutesForCargoRequest) (*RequestPossibleRoutesForCargoResponse, error)
+	// QuoteRoute prices carrying a cargo along a route returned by
+	// RequestPossibleRoutesForCargo for that cargo. The quoted total becomes
+	// the price of the cargo if it's assigned to the route.
+	QuoteRoute(context.Context, *QuoteRouteRequest) (*QuoteRouteResponse, error)
+	// AssignCargoToRoute assigns a cargo to a route.
+	AssignCargoToRoute(context.Context, *AssignCargoToRouteRequest) (*AssignCargoToRouteResponse, error)
+	// AssignCargoToRouteCandidate assigns a cargo to a route returned by
+	// RequestPossibleRoutesForCargo for that cargo.
+	AssignCargoToRouteCandidate(context.Context, *AssignCargoToRouteCandidateRequest) (*AssignCargoToRouteCandidateResponse, error)
+	// ChangeDestination changes the destination of a cargo.
+	ChangeDestination(context.Context, *ChangeDestinationRequest) (*ChangeDestinationResponse, error)
+	// ChangeArrivalDeadline changes the arrival deadline of a cargo.
+	ChangeArrivalDeadline(context.Context, *ChangeArrivalDeadlineRequest) (*ChangeArrivalDeadlineResponse, error)
+	// ChangeOrigin changes where a cargo is picked up. It fails once the cargo
+	// has been received.
+	ChangeOrigin(context.Context, *ChangeOriginRequest) (*ChangeOriginResponse, error)
+	// History returns the amendments made to the booking of a cargo, oldest
+	// first.
+	History(context.Context, *HistoryRequest) (*HistoryResponse, error)
+	// ListCargos returns a page of booked cargos.
+	ListCargos(context.Context, *ListCargosRequest) (*ListCargosResponse, error)
+	// ListLocations returns all registered locations.
+	ListLocations(context.Context, *ListLocationsRequest) (*ListLocationsResponse, error)
+	mustEmbedUnimplementedBookingServiceServer()
+}
+
+// UnimplementedBookingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBookingServiceServer struct{}
+
+func (UnimplementedBookingServiceServer) BookNewCargo(context.Context, *BookNewCargoRequest) (*BookNewCargoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BookNewCargo not implemented")
+}
+func (UnimplementedBookingServiceServer) LoadCargo(context.Context, *LoadCargoRequest) (*LoadCargoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LoadCargo not implemented")
+}
+func (UnimplementedBookingServiceServer) RequestPossibleRoutesForCargo(context.Context, *RequestPossibleRoutesForCargoRequest) (*RequestPossibleRoutesForCargoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RequestPossibleRoutesForCargo not implemented")
+}
+func (UnimplementedBookingServiceServer) QuoteRoute(context.Context, *QuoteRouteRequest) (*QuoteRouteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method QuoteRoute not implemented")
+}
+func (UnimplementedBookingServiceServer) AssignCargoToRoute(context.Context, *AssignCargoToRouteRequest) (*AssignCargoToRouteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AssignCargoToRoute not implemented")
+}
+func (UnimplementedBookingServiceServer) AssignCargoToRouteCandidate(context.Context, *AssignCargoToRouteCandidateRequest) (*AssignCargoToRouteCandidateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AssignCargoToRouteCandidate not implemented")
+}
+func (UnimplementedBookingServiceServer) ChangeDestination(context.Context, *ChangeDestinationRequest) (*ChangeDestinationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ChangeDestination not implemented")
+}
+func (UnimplementedBookingServiceServer) ChangeArrivalDeadline(context.Context, *ChangeArrivalDeadlineRequest) (*ChangeArrivalDeadlineResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ChangeArrivalDeadline not implemented")
+}
+func (UnimplementedBookingServiceServer) ChangeOrigin(context.Context, *ChangeOriginRequest) (*ChangeOriginResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ChangeOrigin not implemented")
+}
+func (UnimplementedBookingServiceServer) History(context.Context, *HistoryRequest) (*HistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method History not implemented")
+}
+func (UnimplementedBookingServiceServer) ListCargos(context.Context, *ListCargosRequest) (*ListCargosResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListCargos not implemented")
+}
+func (UnimplementedBookingServiceServer) ListLocations(context.Context, *ListLocationsRequest) (*ListLocationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListLocations not implemented")
+}
+func (UnimplementedBookingServiceServer) mustEmbedUnimplementedBookingServiceServer() {}
+func (UnimplementedBookingServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeBookingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BookingServiceServer will
+// result in compilation errors.
+type UnsafeBookingServiceServer interface {
+	mustEmbedUnimplementedBookingServiceServer()
+}
+
+func RegisterBookingServiceServer(s grpc.ServiceRegistrar, srv BookingServiceServer) {
+	// If the following call panics, it indicates UnimplementedBookingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BookingService_ServiceDesc, srv)
+}
+
+func _BookingService_BookNewCargo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookNewCargoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).BookNewCargo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_BookNewCargo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).BookNewCargo(ctx, req.(*BookNewCargoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_LoadCargo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadCargoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).LoadCargo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_LoadCargo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).LoadCargo(ctx, req.(*LoadCargoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_RequestPossibleRoutesForCargo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestPossibleRoutesForCargoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).RequestPossibleRoutesForCargo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_RequestPossibleRoutesForCargo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).RequestPossibleRoutesForCargo(ctx, req.(*RequestPossibleRoutesForCargoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_QuoteRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuoteRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).QuoteRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_QuoteRoute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).QuoteRoute(ctx, req.(*QuoteRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_AssignCargoToRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignCargoToRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).AssignCargoToRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_AssignCargoToRoute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).AssignCargoToRoute(ctx, req.(*AssignCargoToRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_AssignCargoToRouteCandidate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignCargoToRouteCandidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).AssignCargoToRouteCandidate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_AssignCargoToRouteCandidate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).AssignCargoToRouteCandidate(ctx, req.(*AssignCargoToRouteCandidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_ChangeDestination_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeDestinationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).ChangeDestination(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_ChangeDestination_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).ChangeDestination(ctx, req.(*ChangeDestinationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_ChangeArrivalDeadline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeArrivalDeadlineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).ChangeArrivalDeadline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_ChangeArrivalDeadline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).ChangeArrivalDeadline(ctx, req.(*ChangeArrivalDeadlineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_ChangeOrigin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeOriginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).ChangeOrigin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_ChangeOrigin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).ChangeOrigin(ctx, req.(*ChangeOriginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).History(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_History_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).History(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_ListCargos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCargosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).ListCargos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_ListCargos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).ListCargos(ctx, req.(*ListCargosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookingService_ListLocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookingServiceServer).ListLocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookingService_ListLocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookingServiceServer).ListLocations(ctx, req.(*ListLocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BookingService_ServiceDesc is the grpc.ServiceDesc for BookingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BookingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shipping.v1.BookingService",
+	HandlerType: (*BookingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BookNewCargo",
+			Handler:    _BookingService_BookNewCargo_Handler,
+		},
+		{
+			MethodName: "LoadCargo",
+			Handler:    _BookingService_LoadCargo_Handler,
+		},
+		{
+			MethodName: "RequestPossibleRoutesForCargo",
+			Handler:    _BookingService_RequestPossibleRoutesForCargo_Handler,
+		},
+		{
+			MethodName: "QuoteRoute",
+			Handler:    _BookingService_QuoteRoute_Handler,
+		},
+		{
+			MethodName: "AssignCargoToRoute",
+			Handler:    _BookingService_AssignCargoToRoute_Handler,
+		},
+		{
+			MethodName: "AssignCargoToRouteCandidate",
+			Handler:    _BookingService_AssignCargoToRouteCandidate_Handler,
+		},
+		{
+			MethodName: "ChangeDestination",
+			Handler:    _BookingService_ChangeDestination_Handler,
+		},
+		{
+			MethodName: "ChangeArrivalDeadline",
+			Handler:    _BookingService_ChangeArrivalDeadline_Handler,
+		},
+		{
+			MethodName: "ChangeOrigin",
+			Handler:    _BookingService_ChangeOrigin_Handler,
+		},
+		{
+			MethodName: "History",
+			Handler:    _BookingService_History_Handler,
+		},
+		{
+			MethodName: "ListCargos",
+			Handler:    _BookingService_ListCargos_Handler,
+		},
+		{
+			MethodName: "ListLocations",
+			Handler:    _BookingService_ListLocations_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "shipping.proto",
+}
+
+const (
+	TrackingService_Track_FullMethodName = "/shipping.v1.TrackingService/Track"
+)
+
+// TrackingServiceClient is the client API for TrackingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TrackingService is used by views facing the end-user.
+type TrackingServiceClient interface {
+	// Track returns the tracking status of a cargo.
+	Track(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*TrackResponse, error)
+}
+
+type trackingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrackingServiceClient(cc grpc.ClientConnInterface) TrackingServiceClient {
+	return &trackingServiceClient{cc}
+}
+
+func (c *trackingServiceClient) Track(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*TrackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrackResponse)
+	err := c.cc.Invoke(ctx, TrackingService_Track_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrackingServiceServer is the server API for TrackingService service.
+// All implementations must embed UnimplementedTrackingServiceServer
+// for forward compatibility.
+//
+// TrackingService is used by views facing the end-user.
+type TrackingServiceServer interface {
+	// Track returns the tracking status of a cargo.
+	Track(context.Context, *TrackRequest) (*TrackResponse, error)
+	mustEmbedUnimplementedTrackingServiceServer()
+}
+
+// UnimplementedTrackingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTrackingServiceServer struct{}
+
+func (UnimplementedTrackingServiceServer) Track(context.Context, *TrackRequest) (*TrackResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Track not implemented")
+}
+func (UnimplementedTrackingServiceServer) mustEmbedUnimplementedTrackingServiceServer() {}
+func (UnimplementedTrackingServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeTrackingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrackingServiceServer will
+// result in compilation errors.
+type UnsafeTrackingServiceServer interface {
+	mustEmbedUnimplementedTrackingServiceServer()
+}
+
+func RegisterTrackingServiceServer(s grpc.ServiceRegistrar, srv TrackingServiceServer) {
+	// If the following call panics, it indicates UnimplementedTrackingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TrackingService_ServiceDesc, srv)
+}
+
+func _TrackingService_Track_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingServiceServer).Track(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingService_Track_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingServiceServer).Track(ctx, req.(*TrackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TrackingService_ServiceDesc is the grpc.ServiceDesc for TrackingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TrackingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shipping.v1.TrackingService",
+	HandlerType: (*TrackingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Track",
+			Handler:    _TrackingService_Track_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "shipping.proto",
+}
+
+const (
+	HandlingService_RegisterHandlingEvent_FullMethodName = "/shipping.v1.HandlingService/RegisterHandlingEvent"
+)
+
+// HandlingServiceClient is the client API for HandlingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// HandlingService is used by views facing the people handling the cargo
+// along its route.
+type HandlingServiceClient interface {
+	// RegisterHandlingEvent registers that a cargo has been handled.
+	RegisterHandlingEvent(ctx context.Context, in *RegisterHandlingEventRequest, opts ...grpc.CallOption) (*RegisterHandlingEventResponse, error)
+}
+
+type handlingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHandlingServiceClient(cc grpc.ClientConnInterface) HandlingServiceClient {
+	return &handlingServiceClient{cc}
+}
+
+func (c *handlingServiceClient) RegisterHandlingEvent(ctx context.Context, in *RegisterHandlingEventRequest, opts ...grpc.CallOption) (*RegisterHandlingEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterHandlingEventResponse)
+	err := c.cc.Invoke(ctx, HandlingService_RegisterHandlingEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HandlingServiceServer is the server API for HandlingService service.
+// All implementations must embed UnimplementedHandlingServiceServer
+// for forward compatibility.
+//
+// HandlingService is used by views facing the people handling the cargo
+// along its route.
+type HandlingServiceServer interface {
+	// RegisterHandlingEvent registers that a cargo has been handled.
+	RegisterHandlingEvent(context.Context, *RegisterHandlingEventRequest) (*RegisterHandlingEventResponse, error)
+	mustEmbedUnimplementedHandlingServiceServer()
+}
+
+// UnimplementedHandlingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHandlingServiceServer struct{}
+
+func (UnimplementedHandlingServiceServer) RegisterHandlingEvent(context.Context, *RegisterHandlingEventRequest) (*RegisterHandlingEventResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RegisterHandlingEvent not implemented")
+}
+func (UnimplementedHandlingServiceServer) mustEmbedUnimplementedHandlingServiceServer() {}
+func (UnimplementedHandlingServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeHandlingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HandlingServiceServer will
+// result in compilation errors.
+type UnsafeHandlingServiceServer interface {
+	mustEmbedUnimplementedHandlingServiceServer()
+}
+
+func RegisterHandlingServiceServer(s grpc.ServiceRegistrar, srv HandlingServiceServer) {
+	// If the following call panics, it indicates UnimplementedHandlingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&HandlingService_ServiceDesc, srv)
+}
+
+func _HandlingService_RegisterHandlingEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterHandlingEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlingServiceServer).RegisterHandlingEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HandlingService_RegisterHandlingEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlingServiceServer).RegisterHandlingEvent(ctx, req.(*RegisterHandlingEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HandlingService_ServiceDesc is the grpc.ServiceDesc for HandlingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HandlingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shipping.v1.HandlingService",
+	HandlerType: (*HandlingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterHandlingEvent",
+			Handler:    _HandlingService_RegisterHandlingEvent_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "shipping.proto",
+}
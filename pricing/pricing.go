@@ -0,0 +1,277 @@
+// Package pricing provides a tariff engine that quotes the price of carrying
+// cargo along an itinerary.
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// ErrInvalidArgument is returned when one or more arguments are invalid.
+var ErrInvalidArgument = shipping.NewError(shipping.CodeInvalidArgument, "invalid argument")
+
+// ErrNoRate is returned when a tariff has no base rate for one or more legs
+// of an itinerary.
+var ErrNoRate = shipping.NewError(shipping.CodeNoRate, "no rate")
+
+// Rate is the base rate of carrying one unit of cargo, i.e. one twenty-foot
+// equivalent unit (TEU), on a leg. Empty fields match any leg, so a rate may
+// apply to a voyage, a lane, a lane on a voyage or every leg.
+type Rate struct {
+	Voyage shipping.VoyageNumber `json:"voyage,omitempty"`
+	From   shipping.UNLocode     `json:"from,omitempty"`
+	To     shipping.UNLocode     `json:"to,omitempty"`
+	Amount int64                 `json:"amount"`
+}
+
+func (r Rate) matches(l shipping.Leg) bool {
+	return (r.Voyage == "" || r.Voyage == l.VoyageNumber) &&
+		(r.From == "" || r.From == l.LoadLocation) &&
+		(r.To == "" || r.To == l.UnloadLocation)
+}
+
+// specificity ranks rates that match the same leg. Locations weigh more than
+// the voyage, so a rate for a lane wins over a rate for a voyage.
+func (r Rate) specificity() int {
+	var n int
+	if r.Voyage != "" {
+		n++
+	}
+	if r.From != "" {
+		n += 2
+	}
+	if r.To != "" {
+		n += 2
+	}
+	return n
+}
+
+// Surcharge is added on top of the base freight. It's a percentage of the
+// freight, an amount per unit of cargo, or both.
+type Surcharge struct {
+	Name    string  `json:"name"`
+	Percent float64 `json:"percent,omitempty"`
+	Amount  int64   `json:"amount,omitempty"`
+
+	// Location limits the surcharge to the legs that load or unload there.
+	// It's then charged once per such leg, as a percentage of the freight
+	// of that leg.
+	Location shipping.UNLocode `json:"location,omitempty"`
+}
+
+// Tariff holds the rates and surcharges of a carrier, in a single currency.
+type Tariff struct {
+	Currency   string      `json:"currency"`
+	Rates      []Rate      `json:"rates"`
+	Surcharges []Surcharge `json:"surcharges,omitempty"`
+
+	// ExchangeRates lists the other currencies that quotes may be given
+	// in, as the number of units of each that one unit of Currency buys.
+	ExchangeRates map[string]float64 `json:"exchange_rates,omitempty"`
+}
+
+// Line is a single charge of a quote.
+type Line struct {
+	Description string         `json:"description"`
+	Amount      shipping.Money `json:"amount"`
+}
+
+// Quote is the price of carrying cargo of a given size along an itinerary,
+// broken down into lines that add up to the total.
+type Quote struct {
+	Size  int            `json:"size"`
+	Lines []Line         `json:"lines"`
+	Total shipping.Money `json:"total"`
+}
+
+// Quote prices carrying size units of cargo along itinerary, in currency. An
+// empty currency means the currency of the tariff. Each leg is charged the
+// most specific rate that matches it; rates that are equally specific are
+// tried in order.
+func (t *Tariff) Quote(itinerary shipping.Itinerary, size int, currency string) (Quote, error) {
+	var fields []shipping.FieldError
+	if len(itinerary.Legs) == 0 {
+		fields = append(fields, shipping.FieldError{Name: "route", Reason: "must have at least one leg"})
+	}
+	if size < 1 {
+		fields = append(fields, shipping.FieldError{Name: "size", Reason: "must be positive"})
+	}
+
+	exchangeRate := 1.0
+	switch {
+	case currency == "":
+		currency = t.Currency
+	case currency != t.Currency:
+		r, ok := t.ExchangeRates[currency]
+		if !ok {
+			fields = append(fields, shipping.FieldError{Name: "currency", Reason: "is not supported"})
+		}
+		exchangeRate = r
+	}
+
+	if len(fields) > 0 {
+		return Quote{}, ErrInvalidArgument.WithFields(fields...)
+	}
+
+	freight := make([]int64, len(itinerary.Legs))
+	var missing []shipping.FieldError
+	for i, l := range itinerary.Legs {
+		r, ok := t.rateFor(l)
+		if !ok {
+			missing = append(missing, shipping.FieldError{
+				Name:   fmt.Sprintf("legs[%d]", i),
+				Reason: fmt.Sprintf("no rate for voyage %s from %s to %s", l.VoyageNumber, l.LoadLocation, l.UnloadLocation),
+			})
+			continue
+		}
+		freight[i] = r.Amount * int64(size)
+	}
+	if len(missing) > 0 {
+		return Quote{}, ErrNoRate.WithFields(missing...)
+	}
+
+	q := Quote{Size: size, Lines: []Line{}, Total: shipping.Money{Currency: currency}}
+	add := func(description string, amount int64) {
+		amount = round(float64(amount) * exchangeRate)
+		q.Lines = append(q.Lines, Line{
+			Description: description,
+			Amount:      shipping.Money{Amount: amount, Currency: currency},
+		})
+		q.Total.Amount += amount
+	}
+
+	var total int64
+	for i, l := range itinerary.Legs {
+		add(fmt.Sprintf("Freight %s %s-%s", l.VoyageNumber, l.LoadLocation, l.UnloadLocation), freight[i])
+		total += freight[i]
+	}
+
+	for _, s := range t.Surcharges {
+		if s.Location == "" {
+			add(s.Name, s.charge(total, size))
+			continue
+		}
+
+		var (
+			amount  int64
+			applies bool
+		)
+		for i, l := range itinerary.Legs {
+			if l.LoadLocation == s.Location || l.UnloadLocation == s.Location {
+				amount += s.charge(freight[i], size)
+				applies = true
+			}
+		}
+		if applies {
+			add(fmt.Sprintf("%s %s", s.Name, s.Location), amount)
+		}
+	}
+
+	return q, nil
+}
+
+// LegCost returns the freight of carrying one unit of cargo along l, in
+// minor units of the currency of the tariff, without surcharges. It's meant
+// for ranking routes by cost, so legs without a rate cost as much as the most
+// expensive rate rather than failing.
+func (t *Tariff) LegCost(l shipping.Leg) float64 {
+	if r, ok := t.rateFor(l); ok {
+		return float64(r.Amount)
+	}
+
+	var max int64
+	for _, r := range t.Rates {
+		if r.Amount > max {
+			max = r.Amount
+		}
+	}
+	return float64(max)
+}
+
+func (t *Tariff) rateFor(l shipping.Leg) (Rate, bool) {
+	var (
+		best  Rate
+		found bool
+	)
+	for _, r := range t.Rates {
+		if r.matches(l) && (!found || r.specificity() > best.specificity()) {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
+
+func (s Surcharge) charge(freight int64, size int) int64 {
+	return round(float64(freight)*s.Percent/100) + s.Amount*int64(size)
+}
+
+// round rounds half away from zero to whole minor units.
+func round(f float64) int64 {
+	return int64(math.Round(f))
+}
+
+// Validate reports the first problem found with the tariff, if any.
+func (t *Tariff) Validate() error {
+	if t.Currency == "" {
+		return errors.New("currency is required")
+	}
+	for i, r := range t.Rates {
+		if r.Amount < 0 {
+			return fmt.Errorf("rates[%d]: amount must not be negative", i)
+		}
+	}
+	for i, s := range t.Surcharges {
+		if s.Name == "" {
+			return fmt.Errorf("surcharges[%d]: name is required", i)
+		}
+	}
+	for c, r := range t.ExchangeRates {
+		if r <= 0 {
+			return fmt.Errorf("exchange_rates[%s]: must be positive", c)
+		}
+	}
+	return nil
+}
+
+// LoadTariff reads a tariff in JSON from r and validates it.
+func LoadTariff(r io.Reader) (*Tariff, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var t Tariff
+	if err := dec.Decode(&t); err != nil {
+		return nil, err
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SampleTariff returns a tariff that covers the sample voyages.
+func SampleTariff() *Tariff {
+	return &Tariff{
+		Currency: "USD",
+		Rates: []Rate{
+			{Amount: 120000},
+			{Voyage: shipping.V100.VoyageNumber, Amount: 180000},
+			{Voyage: shipping.V300.VoyageNumber, Amount: 150000},
+			{Voyage: shipping.V400.VoyageNumber, Amount: 60000},
+			{From: shipping.DEHAM, To: shipping.SESTO, Amount: 45000},
+		},
+		Surcharges: []Surcharge{
+			{Name: "Bunker adjustment", Percent: 12.5},
+			{Name: "Terminal handling", Location: shipping.CNHKG, Amount: 9500},
+			{Name: "Terminal handling", Location: shipping.NLRTM, Amount: 11000},
+		},
+		ExchangeRates: map[string]float64{
+			"EUR": 0.92,
+			"SEK": 10.6,
+		},
+	}
+}
@@ -0,0 +1,182 @@
+package pricing
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+func TestTariffQuote(t *testing.T) {
+	tariff := &Tariff{
+		Currency: "USD",
+		Rates: []Rate{
+			{Amount: 1000},
+			{Voyage: "V100", Amount: 2000},
+			{From: shipping.JNTKO, To: shipping.USNYC, Amount: 1500},
+			{Voyage: "V100", From: shipping.JNTKO, To: shipping.USNYC, Amount: 1800},
+		},
+		Surcharges: []Surcharge{
+			{Name: "Bunker adjustment", Percent: 10},
+			{Name: "Terminal handling", Location: shipping.JNTKO, Amount: 50},
+			{Name: "Terminal handling", Location: shipping.AUMEL, Amount: 70},
+		},
+		ExchangeRates: map[string]float64{"EUR": 0.5},
+	}
+
+	itinerary := shipping.Itinerary{Legs: []shipping.Leg{
+		{VoyageNumber: "V100", LoadLocation: shipping.CNHKG, UnloadLocation: shipping.JNTKO},
+		{VoyageNumber: "V100", LoadLocation: shipping.JNTKO, UnloadLocation: shipping.USNYC},
+		{VoyageNumber: "V200", LoadLocation: shipping.USNYC, UnloadLocation: shipping.SESTO},
+	}}
+
+	q, err := tariff.Quote(itinerary, 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usd := func(amount int64) shipping.Money { return shipping.Money{Amount: amount, Currency: "USD"} }
+	want := Quote{
+		Size: 2,
+		Lines: []Line{
+			{"Freight V100 CNHKG-JNTKO", usd(4000)},
+			{"Freight V100 JNTKO-USNYC", usd(3600)},
+			{"Freight V200 USNYC-SESTO", usd(2000)},
+			{"Bunker adjustment", usd(960)},
+			{"Terminal handling JNTKO", usd(200)},
+		},
+		Total: usd(10760),
+	}
+	if !reflect.DeepEqual(q, want) {
+		t.Errorf("q = %+v; want = %+v", q, want)
+	}
+
+	q, err = tariff.Quote(itinerary, 2, "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (shipping.Money{Amount: 5380, Currency: "EUR"}); q.Total != want {
+		t.Errorf("q.Total = %v; want = %v", q.Total, want)
+	}
+	var sum int64
+	for _, l := range q.Lines {
+		sum += l.Amount.Amount
+	}
+	if sum != q.Total.Amount {
+		t.Errorf("sum of lines = %d; want = %d", sum, q.Total.Amount)
+	}
+}
+
+func TestTariffLegCost(t *testing.T) {
+	tariff := &Tariff{
+		Currency: "USD",
+		Rates: []Rate{
+			{Voyage: "V100", Amount: 2000},
+			{Voyage: "V100", From: shipping.JNTKO, To: shipping.USNYC, Amount: 1800},
+			{Voyage: "V200", Amount: 2500},
+		},
+	}
+
+	for _, tt := range []struct {
+		leg  shipping.Leg
+		want float64
+	}{
+		{shipping.Leg{VoyageNumber: "V100", LoadLocation: shipping.CNHKG, UnloadLocation: shipping.JNTKO}, 2000},
+		{shipping.Leg{VoyageNumber: "V100", LoadLocation: shipping.JNTKO, UnloadLocation: shipping.USNYC}, 1800},
+		{shipping.Leg{VoyageNumber: "V300", LoadLocation: shipping.USNYC, UnloadLocation: shipping.SESTO}, 2500},
+	} {
+		if got := tariff.LegCost(tt.leg); got != tt.want {
+			t.Errorf("LegCost(%s) = %v; want = %v", tt.leg.VoyageNumber, got, tt.want)
+		}
+	}
+}
+
+func TestTariffQuoteErrors(t *testing.T) {
+	tariff := &Tariff{
+		Currency: "USD",
+		Rates:    []Rate{{Voyage: "V100", Amount: 1000}},
+	}
+
+	itinerary := shipping.Itinerary{Legs: []shipping.Leg{
+		{VoyageNumber: "V100", LoadLocation: shipping.CNHKG, UnloadLocation: shipping.JNTKO},
+		{VoyageNumber: "V200", LoadLocation: shipping.JNTKO, UnloadLocation: shipping.USNYC},
+	}}
+
+	for _, tt := range []struct {
+		name      string
+		itinerary shipping.Itinerary
+		size      int
+		currency  string
+		want      error
+		fields    []string
+	}{
+		{"NoLegs", shipping.Itinerary{}, 1, "", ErrInvalidArgument, []string{"route"}},
+		{"NoSize", itinerary, 0, "", ErrInvalidArgument, []string{"size"}},
+		{"UnknownCurrency", itinerary, 1, "SEK", ErrInvalidArgument, []string{"currency"}},
+		{"NoRate", itinerary, 1, "", ErrNoRate, []string{"legs[1]"}},
+	} {
+		_, err := tariff.Quote(tt.itinerary, tt.size, tt.currency)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v; want = %v", tt.name, err, tt.want)
+			continue
+		}
+
+		var fields []string
+		for _, f := range err.(*shipping.Error).Fields {
+			fields = append(fields, f.Name)
+		}
+		if !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("%s: fields = %v; want = %v", tt.name, fields, tt.fields)
+		}
+	}
+}
+
+func TestLoadTariff(t *testing.T) {
+	tariff, err := LoadTariff(strings.NewReader(`{
+		"currency": "EUR",
+		"rates": [{"voyage": "V100", "amount": 150000}],
+		"surcharges": [{"name": "Bunker adjustment", "percent": 12.5}],
+		"exchange_rates": {"USD": 1.08}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tariff.Currency != "EUR" || len(tariff.Rates) != 1 || len(tariff.Surcharges) != 1 || tariff.ExchangeRates["USD"] != 1.08 {
+		t.Errorf("tariff = %+v", tariff)
+	}
+
+	for _, tt := range []struct {
+		name string
+		in   string
+	}{
+		{"Malformed", `{`},
+		{"UnknownField", `{"currency": "EUR", "rate": []}`},
+		{"NoCurrency", `{"rates": []}`},
+		{"NegativeRate", `{"currency": "EUR", "rates": [{"amount": -1}]}`},
+		{"UnnamedSurcharge", `{"currency": "EUR", "surcharges": [{"percent": 5}]}`},
+		{"ZeroExchangeRate", `{"currency": "EUR", "exchange_rates": {"USD": 0}}`},
+	} {
+		if _, err := LoadTariff(strings.NewReader(tt.in)); err == nil {
+			t.Errorf("%s: err = nil; want an error", tt.name)
+		}
+	}
+}
+
+func TestSampleTariffCoversSampleVoyages(t *testing.T) {
+	tariff := SampleTariff()
+	if err := tariff.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []*shipping.Voyage{shipping.V100, shipping.V300, shipping.V400} {
+		var legs []shipping.Leg
+		for _, m := range v.Schedule.CarrierMovements {
+			legs = append(legs, shipping.Leg{VoyageNumber: v.VoyageNumber, LoadLocation: m.DepartureLocation, UnloadLocation: m.ArrivalLocation})
+		}
+		if _, err := tariff.Quote(shipping.Itinerary{Legs: legs}, 1, ""); err != nil {
+			t.Errorf("%s: %v", v.VoyageNumber, err)
+		}
+	}
+}
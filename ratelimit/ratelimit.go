@@ -0,0 +1,125 @@
+// Package ratelimit limits the rate of requests of each client with token
+// buckets.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// ErrRateLimited is returned to clients that exceed their rate.
+var ErrRateLimited = shipping.NewError(shipping.CodeRateLimited, "too many requests")
+
+// sweepInterval is how often clients that have been idle long enough to
+// refill their bucket are forgotten.
+const sweepInterval = time.Minute
+
+// Limit is the rate at which a single client may make requests, as a token
+// bucket that's refilled with Rate tokens per second and holds up to Burst
+// tokens. The zero value doesn't limit anything.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Parse parses a rate limit written as requests per second, optionally
+// followed by a colon and the burst size, such as "5" or "0.5:10". The burst
+// defaults to the rate, rounded up.
+func Parse(s string) (Limit, error) {
+	parts := strings.SplitN(s, ":", 2)
+
+	r, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || r < 0 {
+		return Limit{}, fmt.Errorf("invalid rate %q", parts[0])
+	}
+
+	burst := int(math.Ceil(r))
+	if len(parts) == 2 {
+		burst, err = strconv.Atoi(parts[1])
+		if err != nil || burst < 1 {
+			return Limit{}, fmt.Errorf("invalid burst %q", parts[1])
+		}
+	}
+
+	return Limit{Rate: r, Burst: burst}, nil
+}
+
+func (l Limit) String() string {
+	if l.Rate == 0 {
+		return "unlimited"
+	}
+	return strconv.FormatFloat(l.Rate, 'f', -1, 64) + ":" + strconv.Itoa(l.Burst)
+}
+
+// Auth names the limit on requests by IP address before they're
+// authenticated, which spans all parts of the API. It keeps clients from
+// guessing credentials at an unlimited rate, and should be looser than the
+// limits of the parts, since clients behind a shared address share it.
+const Auth = "auth"
+
+// Limits holds the rate limits of each part of the API, by its name:
+// booking, tracking, handling or webhooks, and the Auth limit.
+type Limits map[string]Limit
+
+// Limiter limits the rate of requests of each client separately.
+type Limiter struct {
+	limit Limit
+
+	mtx       sync.Mutex
+	clients   map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+// NewLimiter returns a limiter that gives each client its own bucket.
+func NewLimiter(limit Limit) *Limiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &Limiter{
+		limit:     limit,
+		clients:   make(map[string]*rate.Limiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// Reserve takes a token from the bucket of the client, and returns how long
+// it has to wait for one if it's empty.
+func (l *Limiter) Reserve(client string) time.Duration {
+	now := time.Now()
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if now.Sub(l.lastSweep) > sweepInterval {
+		for k, lim := range l.clients {
+			if lim.TokensAt(now) >= float64(l.limit.Burst) {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	lim, ok := l.clients[client]
+	if !ok {
+		lim = rate.NewLimiter(rate.Limit(l.limit.Rate), l.limit.Burst)
+		l.clients[client] = lim
+	}
+
+	res := lim.ReserveN(now, 1)
+	if !res.OK() {
+		return time.Duration(math.MaxInt64)
+	}
+	if d := res.DelayFrom(now); d > 0 {
+		res.CancelAt(now)
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want Limit
+		err  bool
+	}{
+		{"5", Limit{Rate: 5, Burst: 5}, false},
+		{"0.5", Limit{Rate: 0.5, Burst: 1}, false},
+		{"2:10", Limit{Rate: 2, Burst: 10}, false},
+		{"0", Limit{}, false},
+		{"fast", Limit{}, true},
+		{"1:0", Limit{}, true},
+	} {
+		got, err := Parse(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("Parse(%q) err = %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v; want = %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(Limit{Rate: 0.001, Burst: 2})
+
+	for i := 0; i < 2; i++ {
+		if d := l.Reserve("a"); d != 0 {
+			t.Errorf("Reserve(a) = %v; want = 0", d)
+		}
+	}
+	if d := l.Reserve("a"); d <= 0 {
+		t.Errorf("Reserve(a) = %v; want > 0", d)
+	}
+	if d := l.Reserve("b"); d != 0 {
+		t.Errorf("Reserve(b) = %v; want = 0", d)
+	}
+}
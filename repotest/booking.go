@@ -0,0 +1,327 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/pricing"
+)
+
+// TestRouteCandidateRepository runs the conformance suite for route
+// candidate repositories. newRepo is called once per test and must return an
+// empty repository.
+func TestRouteCandidateRepository(t *testing.T, newRepo func() booking.RouteCandidateRepository) {
+	t.Run("FindUnknown", func(t *testing.T) {
+		r := newRepo()
+
+		if _, err := r.Find(context.Background(), "no_such_id"); err != booking.ErrUnknownRouteCandidate {
+			t.Errorf("err = %v; want = %v", err, booking.ErrUnknownRouteCandidate)
+		}
+	})
+
+	t.Run("StoreAndFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		want := newRouteCandidate("c1")
+		if err := r.Store(ctx, want); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := r.Find(ctx, want.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkRouteCandidate(t, got, want)
+	})
+
+	t.Run("FindExpired", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		// Candidates that have just expired must still be found, so that
+		// they can be told apart from unknown ones.
+		want := newRouteCandidate("c1")
+		want.ExpiresAt = time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+		r.Store(ctx, want)
+		r.Store(ctx, newRouteCandidate("c2"))
+
+		got, err := r.Find(ctx, want.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkRouteCandidate(t, got, want)
+	})
+
+	t.Run("CopyOnFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		want := newRouteCandidate("c1")
+		if err := r.Store(ctx, want); err != nil {
+			t.Fatal(err)
+		}
+
+		got, _ := r.Find(ctx, want.ID)
+		got.Legs[0].VoyageNumber = "ZZZZZ"
+		got.Costs[0].Score = 0
+		got.Quote.Lines[0].Description = "changed"
+
+		got, _ = r.Find(ctx, want.ID)
+		checkRouteCandidate(t, got, want)
+	})
+
+	t.Run("ConcurrentAccess", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				c := newRouteCandidate(booking.RouteCandidateID(fmt.Sprintf("c%d", i)))
+				r.Store(ctx, c)
+				r.Find(ctx, c.ID)
+			}(i)
+		}
+		wg.Wait()
+
+		for i := 0; i < concurrency; i++ {
+			id := booking.RouteCandidateID(fmt.Sprintf("c%d", i))
+			if _, err := r.Find(ctx, id); err != nil {
+				t.Errorf("Find(%s) err = %v", id, err)
+			}
+		}
+	})
+}
+
+func newRouteCandidate(id booking.RouteCandidateID) *booking.RouteCandidate {
+	return &booking.RouteCandidate{
+		ID:         id,
+		TrackingID: "ABC123",
+		// Repositories may forget expired candidates, so these expire
+		// relative to now rather than on a fixed day.
+		ExpiresAt: time.Now().Add(time.Hour).Truncate(time.Millisecond),
+		RouteCandidate: shipping.RouteCandidate{
+			Itinerary: shipping.Itinerary{Legs: []shipping.Leg{
+				{
+					VoyageNumber:   "V100",
+					LoadLocation:   shipping.SESTO,
+					UnloadLocation: shipping.FIHEL,
+					LoadTime:       day(1),
+					UnloadTime:     day(2),
+				},
+			}},
+			Score: 24,
+			Costs: []shipping.Cost{
+				{Criterion: shipping.CriterionTransitTime, Value: 24, Weight: 1, Score: 24},
+			},
+		},
+		Quote: &pricing.Quote{
+			Size: 1,
+			Lines: []pricing.Line{
+				{Description: "Freight V100 SESTO-FIHEL", Amount: shipping.Money{Amount: 60000, Currency: "USD"}},
+			},
+			Total: shipping.Money{Amount: 60000, Currency: "USD"},
+		},
+	}
+}
+
+func checkRouteCandidate(t *testing.T, got, want *booking.RouteCandidate) {
+	t.Helper()
+
+	if got.ID != want.ID {
+		t.Errorf("ID = %s; want = %s", got.ID, want.ID)
+	}
+	if got.TrackingID != want.TrackingID {
+		t.Errorf("TrackingID = %s; want = %s", got.TrackingID, want.TrackingID)
+	}
+	if !got.ExpiresAt.Equal(want.ExpiresAt) {
+		t.Errorf("ExpiresAt = %v; want = %v", got.ExpiresAt, want.ExpiresAt)
+	}
+	if len(got.Legs) != len(want.Legs) {
+		t.Fatalf("len(Legs) = %d; want = %d", len(got.Legs), len(want.Legs))
+	}
+	for i := range want.Legs {
+		g, w := got.Legs[i], want.Legs[i]
+		if g.VoyageNumber != w.VoyageNumber || g.LoadLocation != w.LoadLocation || g.UnloadLocation != w.UnloadLocation ||
+			!g.LoadTime.Equal(w.LoadTime) || !g.UnloadTime.Equal(w.UnloadTime) {
+			t.Errorf("Legs[%d] = %v; want = %v", i, g, w)
+		}
+	}
+	if got.Score != want.Score {
+		t.Errorf("Score = %v; want = %v", got.Score, want.Score)
+	}
+	if len(got.Costs) != len(want.Costs) || (len(want.Costs) > 0 && got.Costs[0] != want.Costs[0]) {
+		t.Errorf("Costs = %v; want = %v", got.Costs, want.Costs)
+	}
+	if !reflect.DeepEqual(got.Quote, want.Quote) {
+		t.Errorf("Quote = %+v; want = %+v", got.Quote, want.Quote)
+	}
+}
+
+// TestAmendmentRepository runs the conformance suite for booking amendment
+// repositories. newRepo is called once per test and must return an empty
+// repository.
+func TestAmendmentRepository(t *testing.T, newRepo func() booking.AmendmentRepository) {
+	t.Run("FindUnknown", func(t *testing.T) {
+		r := newRepo()
+
+		if got := r.FindByTrackingID(context.Background(), "no_such_id"); len(got) != 0 {
+			t.Errorf("len(amendments) = %d; want = %d", len(got), 0)
+		}
+	})
+
+	t.Run("StoreAndFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		first := newAmendment("a1", "ABC123", day(1))
+		second := newAmendment("a2", "ABC123", day(2))
+		for _, a := range []*booking.Amendment{first, newAmendment("a3", "DEF456", day(1)), second} {
+			if err := r.Store(ctx, a); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got := r.FindByTrackingID(ctx, "ABC123")
+		if len(got) != 2 {
+			t.Fatalf("len(amendments) = %d; want = %d", len(got), 2)
+		}
+		checkAmendment(t, got[0], first)
+		checkAmendment(t, got[1], second)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		r.Store(ctx, newAmendment("a1", "ABC123", day(1)))
+		r.Store(ctx, newAmendment("a2", "ABC123", day(2)))
+
+		if err := r.Remove(ctx, "a1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Remove(ctx, "a1"); err != nil {
+			t.Errorf("err = %v; want = nil", err)
+		}
+
+		got := r.FindByTrackingID(ctx, "ABC123")
+		if len(got) != 1 || got[0].ID != "a2" {
+			t.Errorf("FindByTrackingID() = %v; want = [a2]", got)
+		}
+	})
+
+	t.Run("CopyOnFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		want := newAmendment("a1", "ABC123", day(1))
+		if err := r.Store(ctx, want); err != nil {
+			t.Fatal(err)
+		}
+
+		got := r.FindByTrackingID(ctx, want.TrackingID)
+		got[0].New.Legs[0].VoyageNumber = "ZZZZZ"
+		got[0].New.Price.Amount = 0
+
+		got = r.FindByTrackingID(ctx, want.TrackingID)
+		checkAmendment(t, got[0], want)
+	})
+
+	t.Run("ConcurrentAccess", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				a := newAmendment(booking.AmendmentID(fmt.Sprintf("a%d", i)), "ABC123", day(1))
+				r.Store(ctx, a)
+				r.FindByTrackingID(ctx, a.TrackingID)
+			}(i)
+		}
+		wg.Wait()
+
+		if got := r.FindByTrackingID(ctx, "ABC123"); len(got) != concurrency {
+			t.Errorf("len(amendments) = %d; want = %d", len(got), concurrency)
+		}
+	})
+}
+
+func newAmendment(id booking.AmendmentID, trackingID shipping.TrackingID, t time.Time) *booking.Amendment {
+	return &booking.Amendment{
+		ID:         id,
+		TrackingID: trackingID,
+		Type:       booking.RouteAssigned,
+		Actor:      "alice",
+		Time:       t,
+		Previous: booking.Terms{
+			Origin:          shipping.SESTO,
+			Destination:     shipping.FIHEL,
+			ArrivalDeadline: day(10),
+		},
+		New: booking.Terms{
+			Origin:          shipping.SESTO,
+			Destination:     shipping.FIHEL,
+			ArrivalDeadline: day(10),
+			Legs: []shipping.Leg{
+				{VoyageNumber: "V100", LoadLocation: shipping.SESTO, UnloadLocation: shipping.FIHEL, LoadTime: day(1), UnloadTime: day(2)},
+			},
+			Price: &shipping.Money{Amount: 60000, Currency: "USD"},
+		},
+	}
+}
+
+func checkAmendment(t *testing.T, got, want *booking.Amendment) {
+	t.Helper()
+
+	if got.ID != want.ID || got.TrackingID != want.TrackingID || got.Type != want.Type || got.Actor != want.Actor {
+		t.Errorf("amendment = %+v; want = %+v", got, want)
+	}
+	if !got.Time.Equal(want.Time) {
+		t.Errorf("Time = %v; want = %v", got.Time, want.Time)
+	}
+	checkTerms(t, "Previous", got.Previous, want.Previous)
+	checkTerms(t, "New", got.New, want.New)
+}
+
+func checkTerms(t *testing.T, name string, got, want booking.Terms) {
+	t.Helper()
+
+	if got.Origin != want.Origin || got.Destination != want.Destination || !got.ArrivalDeadline.Equal(want.ArrivalDeadline) {
+		t.Errorf("%s = %+v; want = %+v", name, got, want)
+	}
+	if len(got.Legs) != len(want.Legs) {
+		t.Fatalf("len(%s.Legs) = %d; want = %d", name, len(got.Legs), len(want.Legs))
+	}
+	for i := range want.Legs {
+		g, w := got.Legs[i], want.Legs[i]
+		if g.VoyageNumber != w.VoyageNumber || g.LoadLocation != w.LoadLocation || g.UnloadLocation != w.UnloadLocation ||
+			!g.LoadTime.Equal(w.LoadTime) || !g.UnloadTime.Equal(w.UnloadTime) {
+			t.Errorf("%s.Legs[%d] = %v; want = %v", name, i, g, w)
+		}
+	}
+	if !reflect.DeepEqual(got.Price, want.Price) {
+		t.Errorf("%s.Price = %v; want = %v", name, got.Price, want.Price)
+	}
+}
@@ -0,0 +1,798 @@
+// Package repotest provides a conformance suite for implementations of the
+// domain repositories.
+//
+// Each implementation is expected to behave the same way, regardless of the
+// underlying storage: unknown entities are reported using the domain errors,
+// returned entities are copies that can be modified freely by the caller,
+// handling histories are returned in the order they were stored, and
+// repositories can be used from multiple goroutines at once.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// concurrency is the number of goroutines used when testing concurrent access.
+const concurrency = 10
+
+// TestCargoRepository runs the conformance suite for cargo repositories.
+// newRepo is called once per test and must return an empty repository.
+func TestCargoRepository(t *testing.T, newRepo func() shipping.CargoRepository) {
+	t.Run("FindUnknown", func(t *testing.T) {
+		r := newRepo()
+
+		if _, err := r.Find(context.Background(), "no_such_id"); err != shipping.ErrUnknownCargo {
+			t.Errorf("err = %v; want = %v", err, shipping.ErrUnknownCargo)
+		}
+	})
+
+	t.Run("StoreAndFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		want := newRoutedCargo("ABC123")
+		if err := r.Store(ctx, want); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := r.Find(ctx, want.TrackingID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkCargo(t, got, want)
+	})
+
+	t.Run("StoreReplaces", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		c := newRoutedCargo("ABC123")
+		if err := r.Store(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+
+		c.SpecifyNewRoute(shipping.RouteSpecification{
+			Origin:          shipping.SESTO,
+			Destination:     shipping.AUMEL,
+			ArrivalDeadline: c.RouteSpecification.ArrivalDeadline,
+		})
+		if err := r.Store(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := r.Find(ctx, c.TrackingID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkCargo(t, got, c)
+
+		if n := len(r.FindAll(ctx)); n != 1 {
+			t.Errorf("len(FindAll()) = %d; want = %d", n, 1)
+		}
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		if n := len(r.FindAll(ctx)); n != 0 {
+			t.Errorf("len(FindAll()) = %d; want = %d", n, 0)
+		}
+
+		want := map[shipping.TrackingID]*shipping.Cargo{
+			"ABC123": newRoutedCargo("ABC123"),
+			"FTL456": newRoutedCargo("FTL456"),
+		}
+		for _, c := range want {
+			if err := r.Store(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		all := r.FindAll(ctx)
+		if len(all) != len(want) {
+			t.Fatalf("len(FindAll()) = %d; want = %d", len(all), len(want))
+		}
+		for _, c := range all {
+			w, ok := want[c.TrackingID]
+			if !ok {
+				t.Errorf("unexpected cargo %s", c.TrackingID)
+				continue
+			}
+			checkCargo(t, c, w)
+		}
+	})
+
+	t.Run("CopyOnStore", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		c := newRoutedCargo("ABC123")
+		if err := r.Store(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+
+		want := newRoutedCargo("ABC123")
+
+		c.Origin = shipping.JNTKO
+		c.RouteSpecification.Destination = shipping.JNTKO
+		c.Itinerary.Legs[0].VoyageNumber = "XX000"
+
+		got, err := r.Find(ctx, c.TrackingID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkCargo(t, got, want)
+	})
+
+	t.Run("CopyOnFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		if err := r.Store(ctx, newRoutedCargo("ABC123")); err != nil {
+			t.Fatal(err)
+		}
+
+		want := newRoutedCargo("ABC123")
+
+		c, err := r.Find(ctx, want.TrackingID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c.Origin = shipping.JNTKO
+		c.RouteSpecification.Destination = shipping.JNTKO
+		c.Itinerary.Legs[0].VoyageNumber = "XX000"
+
+		for _, c := range r.FindAll(ctx) {
+			c.Origin = shipping.JNTKO
+			c.Itinerary.Legs[0].VoyageNumber = "XX000"
+		}
+
+		got, err := r.Find(ctx, want.TrackingID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkCargo(t, got, want)
+	})
+
+	t.Run("QueryFilter", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		for _, c := range queryCargos() {
+			if err := r.Store(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		routed := shipping.Routed
+		misrouted := true
+
+		for _, tt := range []struct {
+			name string
+			q    shipping.CargoQuery
+			want []shipping.TrackingID
+		}{
+			{"All", shipping.CargoQuery{}, []shipping.TrackingID{"A1", "A2", "A3", "A4", "A5"}},
+			{"RoutingStatus", shipping.CargoQuery{RoutingStatus: &routed}, []shipping.TrackingID{"A1", "A3"}},
+			{"Misrouted", shipping.CargoQuery{Misrouted: &misrouted}, []shipping.TrackingID{"A4"}},
+			{"Origin", shipping.CargoQuery{Origin: shipping.SESTO}, []shipping.TrackingID{"A4", "A5"}},
+			{"Destination", shipping.CargoQuery{Destination: shipping.SESTO}, []shipping.TrackingID{"A1", "A2", "A3"}},
+			{"Deadline", shipping.CargoQuery{
+				DeadlineAfter:  queryDeadline(2),
+				DeadlineBefore: queryDeadline(4),
+			}, []shipping.TrackingID{"A2", "A3"}},
+			{"SortByDeadline", shipping.CargoQuery{SortBy: shipping.SortByArrivalDeadline}, []shipping.TrackingID{"A1", "A2", "A3", "A5", "A4"}},
+			{"Descending", shipping.CargoQuery{Descending: true, Destination: shipping.SESTO}, []shipping.TrackingID{"A3", "A2", "A1"}},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				page, err := r.Query(ctx, tt.q)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkTrackingIDs(t, page.Cargos, tt.want)
+				if page.NextCursor != "" {
+					t.Errorf("NextCursor = %q; want none", page.NextCursor)
+				}
+			})
+		}
+	})
+
+	t.Run("QueryPagination", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		for _, c := range queryCargos() {
+			if err := r.Store(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, tt := range []struct {
+			name string
+			q    shipping.CargoQuery
+			want []shipping.TrackingID
+		}{
+			{"ByTrackingID", shipping.CargoQuery{Limit: 2}, []shipping.TrackingID{"A1", "A2", "A3", "A4", "A5"}},
+			{"ByDeadline", shipping.CargoQuery{Limit: 2, SortBy: shipping.SortByArrivalDeadline}, []shipping.TrackingID{"A1", "A2", "A3", "A5", "A4"}},
+			{"ByDeadlineDescending", shipping.CargoQuery{Limit: 2, SortBy: shipping.SortByArrivalDeadline, Descending: true}, []shipping.TrackingID{"A4", "A5", "A3", "A2", "A1"}},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				var got []*shipping.Cargo
+
+				q := tt.q
+				for i := 0; ; i++ {
+					if i > len(tt.want) {
+						t.Fatal("too many pages")
+					}
+
+					page, err := r.Query(ctx, q)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(page.Cargos) > q.Limit {
+						t.Fatalf("len(Cargos) = %d; want <= %d", len(page.Cargos), q.Limit)
+					}
+					got = append(got, page.Cargos...)
+
+					if page.NextCursor == "" {
+						break
+					}
+					q.Cursor = page.NextCursor
+				}
+
+				checkTrackingIDs(t, got, tt.want)
+			})
+		}
+	})
+
+	t.Run("QueryInvalidCursor", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		for _, c := range queryCargos() {
+			if err := r.Store(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if _, err := r.Query(ctx, shipping.CargoQuery{Cursor: "not a cursor"}); err != shipping.ErrInvalidCursor {
+			t.Errorf("err = %v; want = %v", err, shipping.ErrInvalidCursor)
+		}
+
+		page, err := r.Query(ctx, shipping.CargoQuery{Limit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// A cursor can't be used with another sort order.
+		q := shipping.CargoQuery{Cursor: page.NextCursor, SortBy: shipping.SortByArrivalDeadline}
+		if _, err := r.Query(ctx, q); err != shipping.ErrInvalidCursor {
+			t.Errorf("err = %v; want = %v", err, shipping.ErrInvalidCursor)
+		}
+	})
+
+	t.Run("ConcurrentAccess", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		shared := newRoutedCargo("SHARED")
+		if err := r.Store(ctx, shared); err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				c := newRoutedCargo(shipping.TrackingID(fmt.Sprintf("C%03d", i)))
+				if err := r.Store(ctx, c); err != nil {
+					t.Error(err)
+				}
+
+				s, err := r.Find(ctx, shared.TrackingID)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				s.Itinerary.Legs[0].VoyageNumber = shipping.VoyageNumber(fmt.Sprintf("V%03d", i))
+				if err := r.Store(ctx, s); err != nil {
+					t.Error(err)
+				}
+
+				r.FindAll(ctx)
+			}(i)
+		}
+		wg.Wait()
+
+		if n := len(r.FindAll(ctx)); n != concurrency+1 {
+			t.Errorf("len(FindAll()) = %d; want = %d", n, concurrency+1)
+		}
+	})
+}
+
+// TestLocationRepository runs the conformance suite for location
+// repositories. The repository must contain exactly the locations in want.
+// The last test stores an additional location in r.
+func TestLocationRepository(t *testing.T, r shipping.LocationRepository, want []*shipping.Location) {
+	t.Run("FindUnknown", func(t *testing.T) {
+		if _, err := r.Find(context.Background(), "ZZZZZ"); err != shipping.ErrUnknownLocation {
+			t.Errorf("err = %v; want = %v", err, shipping.ErrUnknownLocation)
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		for _, w := range want {
+			got, err := r.Find(context.Background(), w.UNLocode)
+			if err != nil {
+				t.Errorf("Find(%s): %v", w.UNLocode, err)
+				continue
+			}
+			if *got != *w {
+				t.Errorf("Find(%s) = %v; want = %v", w.UNLocode, *got, *w)
+			}
+		}
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		all := r.FindAll(context.Background())
+		if len(all) != len(want) {
+			t.Fatalf("len(FindAll()) = %d; want = %d", len(all), len(want))
+		}
+
+		byCode := make(map[shipping.UNLocode]shipping.Location)
+		for _, w := range want {
+			byCode[w.UNLocode] = *w
+		}
+		for _, l := range all {
+			if w, ok := byCode[l.UNLocode]; !ok || *l != w {
+				t.Errorf("unexpected location %v", *l)
+			}
+		}
+	})
+
+	t.Run("CopyOnFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		if len(want) == 0 {
+			t.Skip("no locations")
+		}
+
+		w := *want[0]
+
+		l, err := r.Find(ctx, w.UNLocode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.Name = "Atlantis"
+
+		for _, l := range r.FindAll(ctx) {
+			l.Name = "Atlantis"
+		}
+
+		got, err := r.Find(ctx, w.UNLocode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *got != w {
+			t.Errorf("Find(%s) = %v; want = %v", w.UNLocode, *got, w)
+		}
+	})
+
+	t.Run("ConcurrentAccess", func(t *testing.T) {
+		ctx := context.Background()
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, w := range want {
+					if _, err := r.Find(ctx, w.UNLocode); err != nil {
+						t.Error(err)
+					}
+				}
+				r.FindAll(ctx)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("Store", func(t *testing.T) {
+		ctx := context.Background()
+
+		l := &shipping.Location{UNLocode: "SEGOT", Name: "Gothenburg"}
+		if err := r.Store(ctx, l); err != nil {
+			t.Fatal(err)
+		}
+
+		l.Name = "Atlantis"
+
+		got, err := r.Find(ctx, "SEGOT")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Name != "Gothenburg" {
+			t.Errorf("Name = %s; want = %s", got.Name, "Gothenburg")
+		}
+
+		if n := len(r.FindAll(ctx)); n != len(want)+1 {
+			t.Errorf("len(FindAll()) = %d; want = %d", n, len(want)+1)
+		}
+	})
+}
+
+// TestVoyageRepository runs the conformance suite for voyage repositories.
+// The repository must contain at least the voyages in want. The last test
+// stores an additional voyage in r.
+func TestVoyageRepository(t *testing.T, r shipping.VoyageRepository, want []*shipping.Voyage) {
+	t.Run("FindUnknown", func(t *testing.T) {
+		if _, err := r.Find(context.Background(), "XX000"); err != shipping.ErrUnknownVoyage {
+			t.Errorf("err = %v; want = %v", err, shipping.ErrUnknownVoyage)
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		for _, w := range want {
+			got, err := r.Find(context.Background(), w.VoyageNumber)
+			if err != nil {
+				t.Errorf("Find(%s): %v", w.VoyageNumber, err)
+				continue
+			}
+			checkVoyage(t, got, w)
+		}
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		all := make(map[shipping.VoyageNumber]*shipping.Voyage)
+		for _, v := range r.FindAll(context.Background()) {
+			all[v.VoyageNumber] = v
+		}
+
+		for _, w := range want {
+			got, ok := all[w.VoyageNumber]
+			if !ok {
+				t.Errorf("missing voyage %s", w.VoyageNumber)
+				continue
+			}
+			checkVoyage(t, got, w)
+		}
+	})
+
+	t.Run("CopyOnFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		var w *shipping.Voyage
+		for _, v := range want {
+			if len(v.Schedule.CarrierMovements) > 0 {
+				w = v
+				break
+			}
+		}
+		if w == nil {
+			t.Skip("no voyages with a schedule")
+		}
+
+		// Keep a copy, in case the repository hands out the voyage in want.
+		w = &shipping.Voyage{
+			VoyageNumber: w.VoyageNumber,
+			Schedule: shipping.Schedule{
+				CarrierMovements: append([]shipping.CarrierMovement(nil), w.Schedule.CarrierMovements...),
+			},
+		}
+
+		v, err := r.Find(ctx, w.VoyageNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.Schedule.CarrierMovements[0].ArrivalLocation = "ZZZZZ"
+
+		got, err := r.Find(ctx, w.VoyageNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkVoyage(t, got, w)
+	})
+
+	t.Run("ConcurrentAccess", func(t *testing.T) {
+		ctx := context.Background()
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, w := range want {
+					if _, err := r.Find(ctx, w.VoyageNumber); err != nil {
+						t.Error(err)
+					}
+				}
+				r.FindAll(ctx)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("Store", func(t *testing.T) {
+		ctx := context.Background()
+
+		v := shipping.NewVoyage("V999", shipping.Schedule{CarrierMovements: []shipping.CarrierMovement{
+			{DepartureLocation: shipping.SESTO, ArrivalLocation: shipping.FIHEL},
+		}})
+		if err := r.Store(ctx, v); err != nil {
+			t.Fatal(err)
+		}
+
+		v.Schedule.CarrierMovements[0].ArrivalLocation = "ZZZZZ"
+
+		got, err := r.Find(ctx, "V999")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkVoyage(t, got, shipping.NewVoyage("V999", shipping.Schedule{CarrierMovements: []shipping.CarrierMovement{
+			{DepartureLocation: shipping.SESTO, ArrivalLocation: shipping.FIHEL},
+		}}))
+	})
+}
+
+// TestHandlingEventRepository runs the conformance suite for handling event
+// repositories. newRepo is called once per test and must return an empty
+// repository.
+func TestHandlingEventRepository(t *testing.T, newRepo func() shipping.HandlingEventRepository) {
+	t.Run("QueryUnknown", func(t *testing.T) {
+		r := newRepo()
+
+		h := r.QueryHandlingHistory(context.Background(), "no_such_id")
+		if n := len(h.HandlingEvents); n != 0 {
+			t.Errorf("len(HandlingEvents) = %d; want = %d", n, 0)
+		}
+	})
+
+	t.Run("Ordering", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		want := sampleEvents("ABC123")
+		for _, e := range want {
+			r.Store(ctx, e)
+			r.Store(ctx, shipping.HandlingEvent{TrackingID: "FTL456", Activity: e.Activity})
+		}
+
+		got := r.QueryHandlingHistory(ctx, "ABC123").HandlingEvents
+		checkEvents(t, got, want)
+
+		last, err := shipping.HandlingHistory{HandlingEvents: got}.MostRecentlyCompletedEvent()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if last != want[len(want)-1] {
+			t.Errorf("MostRecentlyCompletedEvent() = %v; want = %v", last, want[len(want)-1])
+		}
+	})
+
+	t.Run("CopyOnQuery", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		want := sampleEvents("ABC123")
+		for _, e := range want {
+			r.Store(ctx, e)
+		}
+
+		h := r.QueryHandlingHistory(ctx, "ABC123")
+		h.HandlingEvents[0].Activity.Location = "ZZZZZ"
+		_ = append(h.HandlingEvents[:1], shipping.HandlingEvent{TrackingID: "ABC123"})
+
+		got := r.QueryHandlingHistory(ctx, "ABC123").HandlingEvents
+		checkEvents(t, got, want)
+	})
+
+	t.Run("ConcurrentAccess", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		events := sampleEvents("ABC123")
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, e := range events {
+					r.Store(ctx, e)
+					r.QueryHandlingHistory(ctx, e.TrackingID)
+				}
+			}()
+		}
+		wg.Wait()
+
+		h := r.QueryHandlingHistory(ctx, "ABC123")
+		if n, want := len(h.HandlingEvents), concurrency*len(events); n != want {
+			t.Errorf("len(HandlingEvents) = %d; want = %d", n, want)
+		}
+	})
+}
+
+func newRoutedCargo(id shipping.TrackingID) *shipping.Cargo {
+	c := shipping.NewCargo(id, shipping.RouteSpecification{
+		Origin:            shipping.CNHKG,
+		Destination:       shipping.SESTO,
+		ArrivalDeadline:   time.Date(2009, time.March, 18, 12, 0, 0, 0, time.UTC),
+		EarliestDeparture: time.Date(2009, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Constraints: shipping.RouteConstraints{
+			MaxLegs:        3,
+			AvoidLocations: []shipping.UNLocode{shipping.NLRTM},
+		},
+	})
+
+	c.AssignToRoute(shipping.Itinerary{Legs: []shipping.Leg{
+		shipping.NewLeg("V100", shipping.CNHKG, shipping.USNYC,
+			time.Date(2009, time.March, 3, 12, 0, 0, 0, time.UTC),
+			time.Date(2009, time.March, 9, 12, 0, 0, 0, time.UTC)),
+		shipping.NewLeg("V400", shipping.USNYC, shipping.SESTO,
+			time.Date(2009, time.March, 10, 12, 0, 0, 0, time.UTC),
+			time.Date(2009, time.March, 15, 12, 0, 0, 0, time.UTC)),
+	}})
+
+	return c
+}
+
+func queryDeadline(day int) time.Time {
+	return time.Date(2009, time.March, day, 12, 0, 0, 0, time.UTC)
+}
+
+// queryCargos returns cargos used for testing queries. Ordered by arrival
+// deadline, they are A1, A2, A3, A5 and A4, where A2 and A3 share deadline.
+func queryCargos() []*shipping.Cargo {
+	a1 := newRoutedCargo("A1")
+	a1.SpecifyNewRoute(shipping.RouteSpecification{Origin: shipping.CNHKG, Destination: shipping.SESTO, ArrivalDeadline: queryDeadline(1)})
+
+	a2 := shipping.NewCargo("A2", shipping.RouteSpecification{Origin: shipping.CNHKG, Destination: shipping.SESTO, ArrivalDeadline: queryDeadline(3)})
+
+	a3 := newRoutedCargo("A3")
+	a3.SpecifyNewRoute(shipping.RouteSpecification{Origin: shipping.CNHKG, Destination: shipping.SESTO, ArrivalDeadline: queryDeadline(3)})
+
+	a4 := newRoutedCargo("A4")
+	a4.Origin = shipping.SESTO
+	a4.SpecifyNewRoute(shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL, ArrivalDeadline: queryDeadline(9)})
+
+	a5 := shipping.NewCargo("A5", shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL, ArrivalDeadline: queryDeadline(5)})
+
+	return []*shipping.Cargo{a3, a5, a1, a4, a2}
+}
+
+func checkTrackingIDs(t *testing.T, got []*shipping.Cargo, want []shipping.TrackingID) {
+	t.Helper()
+
+	var ids []shipping.TrackingID
+	for _, c := range got {
+		ids = append(ids, c.TrackingID)
+	}
+
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("cargos = %v; want = %v", ids, want)
+	}
+}
+
+func sampleEvents(id shipping.TrackingID) []shipping.HandlingEvent {
+	return []shipping.HandlingEvent{
+		{TrackingID: id, Activity: shipping.HandlingActivity{Type: shipping.Receive, Location: shipping.CNHKG}},
+		{TrackingID: id, Activity: shipping.HandlingActivity{Type: shipping.Load, Location: shipping.CNHKG, VoyageNumber: "V100"}},
+		{TrackingID: id, Activity: shipping.HandlingActivity{Type: shipping.Unload, Location: shipping.USNYC, VoyageNumber: "V100"}},
+		{TrackingID: id, Activity: shipping.HandlingActivity{Type: shipping.Load, Location: shipping.USNYC, VoyageNumber: "V400"}},
+		{TrackingID: id, Activity: shipping.HandlingActivity{Type: shipping.Unload, Location: shipping.SESTO, VoyageNumber: "V400"}},
+	}
+}
+
+// checkCargo compares the parts of a cargo that every implementation must
+// preserve. Times are compared using Equal, since storage backends are free
+// to change the location of a time.
+func checkCargo(t *testing.T, got, want *shipping.Cargo) {
+	t.Helper()
+
+	if got.TrackingID != want.TrackingID {
+		t.Errorf("TrackingID = %s; want = %s", got.TrackingID, want.TrackingID)
+	}
+	if got.Origin != want.Origin {
+		t.Errorf("Origin = %s; want = %s", got.Origin, want.Origin)
+	}
+	if got.RouteSpecification.Origin != want.RouteSpecification.Origin {
+		t.Errorf("RouteSpecification.Origin = %s; want = %s",
+			got.RouteSpecification.Origin, want.RouteSpecification.Origin)
+	}
+	if got.RouteSpecification.Destination != want.RouteSpecification.Destination {
+		t.Errorf("RouteSpecification.Destination = %s; want = %s",
+			got.RouteSpecification.Destination, want.RouteSpecification.Destination)
+	}
+	if !got.RouteSpecification.ArrivalDeadline.Equal(want.RouteSpecification.ArrivalDeadline) {
+		t.Errorf("RouteSpecification.ArrivalDeadline = %s; want = %s",
+			got.RouteSpecification.ArrivalDeadline, want.RouteSpecification.ArrivalDeadline)
+	}
+	if !got.RouteSpecification.EarliestDeparture.Equal(want.RouteSpecification.EarliestDeparture) {
+		t.Errorf("RouteSpecification.EarliestDeparture = %s; want = %s",
+			got.RouteSpecification.EarliestDeparture, want.RouteSpecification.EarliestDeparture)
+	}
+	if got.RouteSpecification.Constraints.MaxLegs != want.RouteSpecification.Constraints.MaxLegs {
+		t.Errorf("RouteSpecification.Constraints.MaxLegs = %d; want = %d",
+			got.RouteSpecification.Constraints.MaxLegs, want.RouteSpecification.Constraints.MaxLegs)
+	}
+	if g, w := got.RouteSpecification.Constraints.AvoidLocations, want.RouteSpecification.Constraints.AvoidLocations; !reflect.DeepEqual(g, w) {
+		t.Errorf("RouteSpecification.Constraints.AvoidLocations = %v; want = %v", g, w)
+	}
+	if len(got.Itinerary.Legs) != len(want.Itinerary.Legs) {
+		t.Fatalf("len(Itinerary.Legs) = %d; want = %d", len(got.Itinerary.Legs), len(want.Itinerary.Legs))
+	}
+	for i := range want.Itinerary.Legs {
+		g, w := got.Itinerary.Legs[i], want.Itinerary.Legs[i]
+		if g.VoyageNumber != w.VoyageNumber || g.LoadLocation != w.LoadLocation || g.UnloadLocation != w.UnloadLocation ||
+			!g.LoadTime.Equal(w.LoadTime) || !g.UnloadTime.Equal(w.UnloadTime) {
+			t.Errorf("Itinerary.Legs[%d] = %v; want = %v", i, g, w)
+		}
+	}
+	if got.Delivery.RoutingStatus != want.Delivery.RoutingStatus {
+		t.Errorf("Delivery.RoutingStatus = %v; want = %v", got.Delivery.RoutingStatus, want.Delivery.RoutingStatus)
+	}
+	if got.Delivery.TransportStatus != want.Delivery.TransportStatus {
+		t.Errorf("Delivery.TransportStatus = %v; want = %v", got.Delivery.TransportStatus, want.Delivery.TransportStatus)
+	}
+}
+
+func checkVoyage(t *testing.T, got, want *shipping.Voyage) {
+	t.Helper()
+
+	if got.VoyageNumber != want.VoyageNumber {
+		t.Errorf("VoyageNumber = %s; want = %s", got.VoyageNumber, want.VoyageNumber)
+	}
+	if len(got.Schedule.CarrierMovements) != len(want.Schedule.CarrierMovements) {
+		t.Fatalf("len(CarrierMovements) = %d; want = %d",
+			len(got.Schedule.CarrierMovements), len(want.Schedule.CarrierMovements))
+	}
+	for i := range want.Schedule.CarrierMovements {
+		g, w := got.Schedule.CarrierMovements[i], want.Schedule.CarrierMovements[i]
+		if g.DepartureLocation != w.DepartureLocation || g.ArrivalLocation != w.ArrivalLocation ||
+			!g.DepartureTime.Equal(w.DepartureTime) || !g.ArrivalTime.Equal(w.ArrivalTime) {
+			t.Errorf("CarrierMovements[%d] = %v; want = %v", i, g, w)
+		}
+	}
+}
+
+func checkEvents(t *testing.T, got, want []shipping.HandlingEvent) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("len(HandlingEvents) = %d; want = %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("HandlingEvents[%d] = %v; want = %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+// TestSubscriptionRepository runs the conformance suite for webhook
+// subscription repositories. newRepo is called once per test and must return
+// an empty repository.
+func TestSubscriptionRepository(t *testing.T, newRepo func() webhook.SubscriptionRepository) {
+	t.Run("FindUnknown", func(t *testing.T) {
+		r := newRepo()
+
+		if _, err := r.Find(context.Background(), "no_such_id"); err != webhook.ErrUnknownSubscription {
+			t.Errorf("err = %v; want = %v", err, webhook.ErrUnknownSubscription)
+		}
+	})
+
+	t.Run("StoreAndFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		want := newSubscription("s1")
+		if err := r.Store(ctx, want); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := r.Find(ctx, want.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkSubscription(t, got, want)
+	})
+
+	t.Run("CopyOnFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		want := newSubscription("s1")
+		if err := r.Store(ctx, want); err != nil {
+			t.Fatal(err)
+		}
+
+		got, _ := r.Find(ctx, want.ID)
+		got.Events[0] = "ZZZZZ"
+
+		got, _ = r.Find(ctx, want.ID)
+		checkSubscription(t, got, want)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		r.Store(ctx, newSubscription("s1"))
+		r.Store(ctx, newSubscription("s2"))
+
+		if err := r.Remove(ctx, "s1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Remove(ctx, "s1"); err != webhook.ErrUnknownSubscription {
+			t.Errorf("err = %v; want = %v", err, webhook.ErrUnknownSubscription)
+		}
+
+		all := r.FindAll(ctx)
+		if len(all) != 1 || all[0].ID != "s2" {
+			t.Errorf("FindAll() = %v; want = [s2]", all)
+		}
+	})
+
+	t.Run("ConcurrentAccess", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s := newSubscription(webhook.SubscriptionID(fmt.Sprintf("s%d", i)))
+				r.Store(ctx, s)
+				r.Find(ctx, s.ID)
+				r.FindAll(ctx)
+			}(i)
+		}
+		wg.Wait()
+
+		if n := len(r.FindAll(ctx)); n != concurrency {
+			t.Errorf("len(FindAll()) = %d; want = %d", n, concurrency)
+		}
+	})
+}
+
+// TestDeliveryRepository runs the conformance suite for webhook delivery
+// repositories. newRepo is called once per test and must return an empty
+// repository.
+func TestDeliveryRepository(t *testing.T, newRepo func() webhook.DeliveryRepository) {
+	t.Run("FindBySubscription", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		for i, id := range []webhook.DeliveryID{"d1", "d2", "d3"} {
+			r.Store(ctx, newDelivery(id, "s1", i))
+			r.Store(ctx, newDelivery(id+"x", "s2", i))
+		}
+
+		checkDeliveryIDs(t, r.FindBySubscription(ctx, "s1"), []webhook.DeliveryID{"d1", "d2", "d3"})
+		checkDeliveryIDs(t, r.FindBySubscription(ctx, "s3"), nil)
+	})
+
+	t.Run("StoreReplaces", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		d := newDelivery("d1", "s1", 0)
+		r.Store(ctx, d)
+
+		d.Status = webhook.Delivered
+		d.Attempts = append(d.Attempts, webhook.Attempt{Time: day(1), StatusCode: 200})
+		r.Store(ctx, d)
+
+		got := r.FindBySubscription(ctx, "s1")
+		checkDeliveryIDs(t, got, []webhook.DeliveryID{"d1"})
+		if got[0].Status != webhook.Delivered {
+			t.Errorf("Status = %s; want = %s", got[0].Status, webhook.Delivered)
+		}
+		if n := len(got[0].Attempts); n != 1 {
+			t.Errorf("len(Attempts) = %d; want = %d", n, 1)
+		}
+		if string(got[0].Payload) != string(d.Payload) {
+			t.Errorf("Payload = %s; want = %s", got[0].Payload, d.Payload)
+		}
+	})
+
+	t.Run("FindPending", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		for i, status := range []webhook.DeliveryStatus{webhook.Pending, webhook.Delivered, webhook.Failed, webhook.Pending} {
+			d := newDelivery(webhook.DeliveryID(fmt.Sprintf("d%d", i)), "s1", i)
+			d.Status = status
+			r.Store(ctx, d)
+		}
+
+		checkDeliveryIDs(t, r.FindPending(ctx), []webhook.DeliveryID{"d0", "d3"})
+	})
+
+	t.Run("CopyOnFind", func(t *testing.T) {
+		ctx := context.Background()
+
+		r := newRepo()
+
+		r.Store(ctx, newDelivery("d1", "s1", 0))
+
+		got := r.FindPending(ctx)
+		got[0].Status = webhook.Failed
+		got[0].Payload[0] = 'x'
+
+		got = r.FindBySubscription(ctx, "s1")
+		if got[0].Status != webhook.Pending {
+			t.Errorf("Status = %s; want = %s", got[0].Status, webhook.Pending)
+		}
+		if got[0].Payload[0] != '{' {
+			t.Errorf("Payload = %s", got[0].Payload)
+		}
+	})
+}
+
+func newSubscription(id webhook.SubscriptionID) *webhook.Subscription {
+	return &webhook.Subscription{
+		ID:       id,
+		URL:      "http://example.com/hook",
+		Customer: "acme",
+		Cargo:    "ABC123",
+		Events:   []webhook.EventType{webhook.CargoArrived, webhook.CargoMisdirected},
+		Created:  day(0),
+		Secret:   "secret",
+	}
+}
+
+func newDelivery(id webhook.DeliveryID, sub webhook.SubscriptionID, n int) *webhook.Delivery {
+	return &webhook.Delivery{
+		ID:           id,
+		Subscription: sub,
+		Event:        webhook.CargoArrived,
+		Payload:      []byte(`{"id":"` + string(id) + `"}`),
+		Status:       webhook.Pending,
+		NextAttempt:  day(n),
+		Created:      day(n),
+	}
+}
+
+func day(n int) time.Time {
+	return time.Date(2017, time.March, 1+n, 0, 0, 0, 0, time.UTC)
+}
+
+func checkSubscription(t *testing.T, got, want *webhook.Subscription) {
+	t.Helper()
+
+	if got.ID != want.ID || got.URL != want.URL || got.Customer != want.Customer || got.Cargo != want.Cargo || got.Secret != want.Secret {
+		t.Errorf("got = %+v; want = %+v", got, want)
+	}
+	if !got.Created.Equal(want.Created) {
+		t.Errorf("Created = %v; want = %v", got.Created, want.Created)
+	}
+	if fmt.Sprint(got.Events) != fmt.Sprint(want.Events) {
+		t.Errorf("Events = %v; want = %v", got.Events, want.Events)
+	}
+}
+
+func checkDeliveryIDs(t *testing.T, got []*webhook.Delivery, want []webhook.DeliveryID) {
+	t.Helper()
+
+	var ids []webhook.DeliveryID
+	for _, d := range got {
+		ids = append(ids, d.ID)
+	}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("IDs = %v; want = %v", ids, want)
+	}
+}
@@ -1,8 +1,15 @@
 package shipping
 
+import "context"
+
 // RoutingService is a domain service for routing cargos.
 type RoutingService interface {
 	// FetchRoutesForSpecification finds all possible routes that satisfy a
-	// given specification.
-	FetchRoutesForSpecification(rs RouteSpecification) []Itinerary
+	// given specification. Finding no routes isn't an error, while failing
+	// to ask is, and should wrap ErrRoutingUnavailable.
+	FetchRoutesForSpecification(ctx context.Context, rs RouteSpecification) ([]Itinerary, error)
 }
+
+// ErrRoutingUnavailable is returned when routes can't be fetched at the
+// moment, for example when the routing service is down.
+var ErrRoutingUnavailable = NewError(CodeRoutingUnavailable, "routing service unavailable")
@@ -0,0 +1,291 @@
+package routing
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// Defaults of a Cache.
+const (
+	DefaultCacheTTL  = 5 * time.Minute
+	DefaultCacheSize = 1000
+)
+
+// CacheOption configures optional features of a Cache.
+type CacheOption func(*Cache)
+
+// WithCacheTTL keeps routes for d rather than DefaultCacheTTL.
+func WithCacheTTL(d time.Duration) CacheOption {
+	return func(c *Cache) { c.ttl = d }
+}
+
+// WithCacheSize keeps the routes of at most n route specifications rather
+// than DefaultCacheSize. The least recently used are evicted first.
+func WithCacheSize(n int) CacheOption {
+	return func(c *Cache) { c.size = n }
+}
+
+// WithCacheCounter counts the lookups in the cache with lookups, labeled with
+// the "result" of the lookup: hit or miss.
+func WithCacheCounter(lookups metrics.Counter) CacheOption {
+	return func(c *Cache) { c.lookups = lookups }
+}
+
+// Cache holds the routes found for route specifications. Routes are kept
+// until they expire, are evicted to make room for others, or one of the
+// voyages they use changes. Expired routes are kept until they're replaced
+// or evicted, so that Fallback can serve them.
+type Cache struct {
+	ttl     time.Duration
+	size    int
+	lookups metrics.Counter
+
+	mtx      sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List
+	byVoyage map[shipping.VoyageNumber]map[string]struct{}
+
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	key         string
+	itineraries []shipping.Itinerary
+	voyages     []shipping.VoyageNumber
+	expires     time.Time
+}
+
+// NewCache returns a new, empty route cache.
+func NewCache(opts ...CacheOption) *Cache {
+	c := &Cache{
+		ttl:      DefaultCacheTTL,
+		size:     DefaultCacheSize,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		byVoyage: make(map[shipping.VoyageNumber]map[string]struct{}),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Len returns the number of route specifications with cached routes,
+// including those that have expired but not yet been removed.
+func (c *Cache) Len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.lru.Len()
+}
+
+// InvalidateVoyage removes the routes that use voyage v.
+func (c *Cache) InvalidateVoyage(v shipping.VoyageNumber) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for key := range c.byVoyage[v] {
+		c.remove(c.entries[key])
+	}
+}
+
+// get returns the routes cached under key. Expired routes are only returned
+// if stale is set.
+func (c *Cache) get(key string, stale bool) ([]shipping.Itinerary, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*cacheEntry)
+	if !stale && !c.now().Before(e.expires) {
+		return nil, false
+	}
+
+	c.lru.MoveToFront(el)
+
+	return copyItineraries(e.itineraries), true
+}
+
+func (c *Cache) put(key string, itineraries []shipping.Itinerary) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+
+	e := &cacheEntry{
+		key:         key,
+		itineraries: copyItineraries(itineraries),
+		voyages:     voyagesOf(itineraries),
+		expires:     c.now().Add(c.ttl),
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	for _, v := range e.voyages {
+		if c.byVoyage[v] == nil {
+			c.byVoyage[v] = make(map[string]struct{})
+		}
+		c.byVoyage[v][key] = struct{}{}
+	}
+
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove removes el from the cache. c.mtx must be held.
+func (c *Cache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*cacheEntry)
+	delete(c.entries, e.key)
+	for _, v := range e.voyages {
+		delete(c.byVoyage[v], e.key)
+		if len(c.byVoyage[v]) == 0 {
+			delete(c.byVoyage, v)
+		}
+	}
+}
+
+func (c *Cache) count(result string) {
+	if c.lookups != nil {
+		c.lookups.With("result", result).Add(1)
+	}
+}
+
+// cacheKey identifies a route specification. Every part of the specification
+// may change the routes found, so every part is in the key.
+func cacheKey(rs shipping.RouteSpecification) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%d|%v",
+		rs.Origin, rs.Destination,
+		formatCacheTime(rs.ArrivalDeadline), formatCacheTime(rs.EarliestDeparture),
+		rs.Constraints.MaxLegs, rs.Constraints.AvoidLocations)
+}
+
+func formatCacheTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// voyagesOf returns the distinct voyages used by itineraries.
+func voyagesOf(itineraries []shipping.Itinerary) []shipping.VoyageNumber {
+	seen := make(map[shipping.VoyageNumber]bool)
+	var voyages []shipping.VoyageNumber
+	for _, it := range itineraries {
+		for _, l := range it.Legs {
+			if !seen[l.VoyageNumber] {
+				seen[l.VoyageNumber] = true
+				voyages = append(voyages, l.VoyageNumber)
+			}
+		}
+	}
+	return voyages
+}
+
+func copyItineraries(itineraries []shipping.Itinerary) []shipping.Itinerary {
+	if itineraries == nil {
+		return nil
+	}
+	c := make([]shipping.Itinerary, len(itineraries))
+	for i, it := range itineraries {
+		c[i] = shipping.Itinerary{Legs: append([]shipping.Leg(nil), it.Legs...)}
+	}
+	return c
+}
+
+type cachingService struct {
+	cache *Cache
+	shipping.RoutingService
+}
+
+func (s cachingService) FetchRoutesForSpecification(ctx context.Context, rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+	key := cacheKey(rs)
+
+	if itineraries, ok := s.cache.get(key, false); ok {
+		s.cache.count("hit")
+		return itineraries, nil
+	}
+	s.cache.count("miss")
+
+	var stale bool
+	itineraries, err := s.RoutingService.FetchRoutesForSpecification(context.WithValue(ctx, staleKey{}, &stale), rs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Routes served by the fallback keep their expiry.
+	if !stale {
+		s.cache.put(key, itineraries)
+	}
+
+	return itineraries, nil
+}
+
+// staleKey marks the context of a call through the caching middleware, so
+// that the fallback can tell it that the routes it served are stale.
+type staleKey struct{}
+
+type fallbackService struct {
+	cache *Cache
+}
+
+func (s fallbackService) FetchRoutesForSpecification(ctx context.Context, rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+	itineraries, ok := s.cache.get(cacheKey(rs), true)
+	if !ok {
+		return nil, fmt.Errorf("%w: no cached routes", shipping.ErrRoutingUnavailable)
+	}
+	if stale, ok := ctx.Value(staleKey{}).(*bool); ok {
+		*stale = true
+	}
+	return itineraries, nil
+}
+
+// Fallback returns a routing service that serves the routes in c, whether
+// they've expired or not, and fails with shipping.ErrRoutingUnavailable for
+// route specifications without cached routes. It's meant as the fallback of
+// a proxy that's wrapped by the caching middleware of c, for when the
+// routing service can't be reached; the routes it serves aren't given a new
+// expiry.
+func (c *Cache) Fallback() shipping.RoutingService {
+	return fallbackService{c}
+}
+
+// NewCachingMiddleware returns a new instance of a caching middleware, which
+// keeps the routes found by the service it decorates in c. Failed calls
+// aren't cached.
+func NewCachingMiddleware(c *Cache) ServiceMiddleware {
+	return func(next shipping.RoutingService) shipping.RoutingService {
+		return cachingService{c, next}
+	}
+}
+
+type invalidatingVoyageRepository struct {
+	cache *Cache
+	shipping.VoyageRepository
+}
+
+func (r invalidatingVoyageRepository) Store(ctx context.Context, v *shipping.Voyage) error {
+	if err := r.VoyageRepository.Store(ctx, v); err != nil {
+		return err
+	}
+	r.cache.InvalidateVoyage(v.VoyageNumber)
+	return nil
+}
+
+// NewInvalidatingVoyageRepository returns a voyage repository that removes
+// the routes that use a voyage from c whenever the voyage is stored in r.
+// Routes that a new voyage would open up are only found once the cached
+// routes expire.
+func NewInvalidatingVoyageRepository(r shipping.VoyageRepository, c *Cache) shipping.VoyageRepository {
+	return invalidatingVoyageRepository{c, r}
+}
@@ -0,0 +1,265 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/mock"
+)
+
+func TestCachingMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	next := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			calls++
+			return []shipping.Itinerary{
+				{Legs: []shipping.Leg{{VoyageNumber: "V100", LoadLocation: rs.Origin, UnloadLocation: rs.Destination}}},
+			}, nil
+		},
+	}
+
+	lookups := &countingCounter{counts: make(map[string]float64)}
+	cache := NewCache(WithCacheCounter(lookups))
+	rs := NewCachingMiddleware(cache)(next)
+
+	spec := shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.CNHKG}
+
+	first, err := rs.FetchRoutesForSpecification(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the routes returned must not change the cached ones.
+	first[0].Legs[0].VoyageNumber = "ZZZZZ"
+
+	second, err := rs.FetchRoutesForSpecification(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d; want = %d", calls, 1)
+	}
+	if got := second[0].Legs[0].VoyageNumber; got != "V100" {
+		t.Errorf("VoyageNumber = %s; want = %s", got, "V100")
+	}
+
+	// Any part of the specification makes a different key.
+	spec.Constraints.MaxLegs = 2
+	if _, err := rs.FetchRoutesForSpecification(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d; want = %d", calls, 2)
+	}
+
+	if lookups.counts["hit"] != 1 || lookups.counts["miss"] != 2 {
+		t.Errorf("lookups = %v; want 1 hit and 2 misses", lookups.counts)
+	}
+}
+
+func TestCachingMiddlewareExpires(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	next := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			calls++
+			return nil, nil
+		},
+	}
+
+	now := time.Date(2009, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	cache := NewCache(WithCacheTTL(time.Minute))
+	cache.now = func() time.Time { return now }
+	rs := NewCachingMiddleware(cache)(next)
+
+	spec := shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.CNHKG}
+
+	rs.FetchRoutesForSpecification(ctx, spec)
+	now = now.Add(59 * time.Second)
+	rs.FetchRoutesForSpecification(ctx, spec)
+	if calls != 1 {
+		t.Errorf("calls = %d; want = %d", calls, 1)
+	}
+
+	now = now.Add(time.Second)
+	rs.FetchRoutesForSpecification(ctx, spec)
+	if calls != 2 {
+		t.Errorf("calls = %d; want = %d", calls, 2)
+	}
+}
+
+func TestCacheFallback(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	var down bool
+	next := func(fallback shipping.RoutingService) shipping.RoutingService {
+		return routingServiceFunc(func(ctx context.Context, rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			if down {
+				return fallback.FetchRoutesForSpecification(ctx, rs)
+			}
+			calls++
+			return []shipping.Itinerary{{Legs: []shipping.Leg{{VoyageNumber: "V100"}}}}, nil
+		})
+	}
+
+	now := time.Date(2009, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	cache := NewCache(WithCacheTTL(time.Minute))
+	cache.now = func() time.Time { return now }
+
+	spec := shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.CNHKG}
+	other := shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL}
+
+	cached := cache.Fallback()
+	rs := NewCachingMiddleware(cache)(next(cached))
+
+	if _, err := cached.FetchRoutesForSpecification(ctx, spec); !errors.Is(err, shipping.ErrRoutingUnavailable) {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrRoutingUnavailable)
+	}
+
+	rs.FetchRoutesForSpecification(ctx, spec)
+
+	// Expired routes are served while the routing service is down, without
+	// being cached again.
+	now = now.Add(time.Hour)
+	down = true
+
+	itineraries, err := rs.FetchRoutesForSpecification(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(itineraries) != 1 {
+		t.Errorf("len(itineraries) = %d; want = %d", len(itineraries), 1)
+	}
+	if _, err := rs.FetchRoutesForSpecification(ctx, other); !errors.Is(err, shipping.ErrRoutingUnavailable) {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrRoutingUnavailable)
+	}
+
+	down = false
+	rs.FetchRoutesForSpecification(ctx, spec)
+	if calls != 2 {
+		t.Errorf("calls = %d; want = %d", calls, 2)
+	}
+}
+
+func TestCachingMiddlewareEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+
+	var calls []shipping.UNLocode
+	next := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			calls = append(calls, rs.Destination)
+			return nil, nil
+		},
+	}
+
+	cache := NewCache(WithCacheSize(2))
+	rs := NewCachingMiddleware(cache)(next)
+
+	fetch := func(dest shipping.UNLocode) {
+		rs.FetchRoutesForSpecification(ctx, shipping.RouteSpecification{Origin: shipping.SESTO, Destination: dest})
+	}
+
+	fetch(shipping.CNHKG)
+	fetch(shipping.AUMEL)
+	fetch(shipping.CNHKG)
+	fetch(shipping.USNYC) // Evicts AUMEL.
+	fetch(shipping.CNHKG)
+	fetch(shipping.AUMEL)
+
+	want := []shipping.UNLocode{shipping.CNHKG, shipping.AUMEL, shipping.USNYC, shipping.AUMEL}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v; want = %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("calls = %v; want = %v", calls, want)
+			break
+		}
+	}
+	if n := cache.Len(); n != 2 {
+		t.Errorf("Len() = %d; want = %d", n, 2)
+	}
+}
+
+func TestCachingMiddlewareSkipsErrors(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	next := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			calls++
+			return nil, shipping.ErrRoutingUnavailable
+		},
+	}
+
+	rs := NewCachingMiddleware(NewCache())(next)
+
+	spec := shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.CNHKG}
+	for i := 0; i < 2; i++ {
+		if _, err := rs.FetchRoutesForSpecification(ctx, spec); !errors.Is(err, shipping.ErrRoutingUnavailable) {
+			t.Errorf("err = %v; want = %v", err, shipping.ErrRoutingUnavailable)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d; want = %d", calls, 2)
+	}
+}
+
+func TestInvalidatingVoyageRepository(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	next := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			calls++
+			voyage := shipping.VoyageNumber("V100")
+			if rs.Destination == shipping.AUMEL {
+				voyage = "V200"
+			}
+			return []shipping.Itinerary{
+				{Legs: []shipping.Leg{{VoyageNumber: voyage, LoadLocation: rs.Origin, UnloadLocation: rs.Destination}}},
+			}, nil
+		},
+	}
+
+	cache := NewCache()
+	rs := NewCachingMiddleware(cache)(next)
+
+	voyages := NewInvalidatingVoyageRepository(&mock.VoyageRepository{
+		StoreFn: func(*shipping.Voyage) error { return nil },
+	}, cache)
+
+	toHongkong := shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.CNHKG}
+	toMelbourne := shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL}
+
+	rs.FetchRoutesForSpecification(ctx, toHongkong)
+	rs.FetchRoutesForSpecification(ctx, toMelbourne)
+
+	if err := voyages.Store(ctx, shipping.NewVoyage("V100", shipping.Schedule{})); err != nil {
+		t.Fatal(err)
+	}
+	if n := cache.Len(); n != 1 {
+		t.Errorf("Len() = %d; want = %d", n, 1)
+	}
+
+	rs.FetchRoutesForSpecification(ctx, toHongkong)
+	rs.FetchRoutesForSpecification(ctx, toMelbourne)
+	if calls != 3 {
+		t.Errorf("calls = %d; want = %d", calls, 3)
+	}
+}
+
+type routingServiceFunc func(ctx context.Context, rs shipping.RouteSpecification) ([]shipping.Itinerary, error)
+
+func (f routingServiceFunc) FetchRoutesForSpecification(ctx context.Context, rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+	return f(ctx, rs)
+}
@@ -0,0 +1,140 @@
+package routing
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/sd"
+)
+
+// FileInstancer yields the instances of the routing service listed in a
+// file, one per line. Blank lines and lines starting with # are ignored. The
+// file is checked for changes on a fixed schedule and re-read when it has
+// changed.
+type FileInstancer struct {
+	path   string
+	logger log.Logger
+
+	mtx      sync.Mutex
+	state    sd.Event
+	modTime  time.Time
+	size     int64
+	channels map[chan<- sd.Event]struct{}
+
+	quit chan struct{}
+}
+
+// NewFileInstancer returns an instancer of the instances listed in the file
+// at path, which is checked for changes every interval.
+func NewFileInstancer(path string, interval time.Duration, logger log.Logger) *FileInstancer {
+	i := &FileInstancer{
+		path:     path,
+		logger:   logger,
+		channels: make(map[chan<- sd.Event]struct{}),
+		quit:     make(chan struct{}),
+	}
+
+	i.refresh()
+
+	go i.loop(time.NewTicker(interval))
+
+	return i
+}
+
+func (i *FileInstancer) loop(t *time.Ticker) {
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			i.refresh()
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+// refresh re-reads the file if it has changed since it was last read.
+func (i *FileInstancer) refresh() {
+	fi, err := os.Stat(i.path)
+	if err != nil {
+		i.logger.Log("path", i.path, "err", err)
+		i.update(sd.Event{Err: err}, time.Time{}, 0)
+		return
+	}
+
+	i.mtx.Lock()
+	unchanged := i.state.Err == nil && fi.ModTime().Equal(i.modTime) && fi.Size() == i.size
+	i.mtx.Unlock()
+	if unchanged {
+		return
+	}
+
+	b, err := ioutil.ReadFile(i.path)
+	if err != nil {
+		i.logger.Log("path", i.path, "err", err)
+		i.update(sd.Event{Err: err}, time.Time{}, 0)
+		return
+	}
+
+	instances := parseInstances(b)
+	i.logger.Log("path", i.path, "instances", len(instances))
+	i.update(sd.Event{Instances: instances}, fi.ModTime(), fi.Size())
+}
+
+// update notifies the registered channels of event, unless it's what they
+// were last notified of. Endpointers keep using the instances last read when
+// notified of an error.
+func (i *FileInstancer) update(event sd.Event, modTime time.Time, size int64) {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+
+	i.modTime, i.size = modTime, size
+
+	if reflect.DeepEqual(i.state, event) {
+		return
+	}
+	i.state = event
+	for ch := range i.channels {
+		ch <- event
+	}
+}
+
+func parseInstances(b []byte) []string {
+	var instances []string
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		instances = append(instances, line)
+	}
+	return instances
+}
+
+// Register implements sd.Instancer.
+func (i *FileInstancer) Register(ch chan<- sd.Event) {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	i.channels[ch] = struct{}{}
+	ch <- i.state
+}
+
+// Deregister implements sd.Instancer.
+func (i *FileInstancer) Deregister(ch chan<- sd.Event) {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	delete(i.channels, ch)
+}
+
+// Stop implements sd.Instancer. It stops checking the file for changes.
+func (i *FileInstancer) Stop() {
+	close(i.quit)
+}
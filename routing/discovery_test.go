@@ -0,0 +1,78 @@
+package routing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/sd"
+)
+
+func TestFileInstancer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "routing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "instances")
+	write := func(s string, mod time.Time) {
+		if err := ioutil.WriteFile(path, []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mod := time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC)
+	write("# pathfinders\nhttp://a:7878\n\nb:7878\n", mod)
+
+	// Refreshed by hand rather than on schedule.
+	i := NewFileInstancer(path, time.Hour, log.NewNopLogger())
+	defer i.Stop()
+
+	ch := make(chan sd.Event, 1)
+	i.Register(ch)
+	defer i.Deregister(ch)
+
+	checkInstances(t, <-ch, "http://a:7878", "b:7878")
+
+	// Unchanged files aren't read again.
+	i.refresh()
+	select {
+	case e := <-ch:
+		t.Errorf("unexpected event %v", e)
+	default:
+	}
+
+	write("http://c:7878\n", mod.Add(time.Second))
+	i.refresh()
+	checkInstances(t, <-ch, "http://c:7878")
+
+	os.Remove(path)
+	i.refresh()
+	if e := <-ch; e.Err == nil {
+		t.Errorf("err = nil; want an error for a missing file")
+	}
+}
+
+func checkInstances(t *testing.T, e sd.Event, want ...string) {
+	t.Helper()
+
+	if e.Err != nil {
+		t.Fatal(e.Err)
+	}
+	if len(e.Instances) != len(want) {
+		t.Fatalf("instances = %v; want = %v", e.Instances, want)
+	}
+	for i := range want {
+		if e.Instances[i] != want[i] {
+			t.Errorf("instances = %v; want = %v", e.Instances, want)
+			break
+		}
+	}
+}
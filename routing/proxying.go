@@ -3,30 +3,59 @@ package routing
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/afex/hystrix-go/hystrix"
 	"github.com/go-kit/kit/circuitbreaker"
 	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/sd"
+	"github.com/go-kit/kit/sd/lb"
 	kithttp "github.com/go-kit/kit/transport/http"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
 	shipping "github.com/marcusolsson/goddd"
 )
 
 type proxyService struct {
-	context.Context
 	FetchRoutesEndpoint endpoint.Endpoint
-	shipping.RoutingService
+	failures            metrics.Counter
+
+	// fallback, if not nil, is asked for routes while the circuit breaker
+	// is open.
+	fallback shipping.RoutingService
 }
 
-func (s proxyService) FetchRoutesForSpecification(rs shipping.RouteSpecification) []shipping.Itinerary {
-	response, err := s.FetchRoutesEndpoint(s.Context, fetchRoutesRequest{
-		From: string(rs.Origin),
-		To:   string(rs.Destination),
+func (s proxyService) FetchRoutesForSpecification(ctx context.Context, rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+	response, err := s.FetchRoutesEndpoint(ctx, fetchRoutesRequest{
+		From:              string(rs.Origin),
+		To:                string(rs.Destination),
+		Deadline:          rs.ArrivalDeadline,
+		EarliestDeparture: rs.EarliestDeparture,
+		MaxLegs:           rs.Constraints.MaxLegs,
+		Avoid:             rs.Constraints.AvoidLocations,
 	})
 	if err != nil {
-		return []shipping.Itinerary{}
+		if s.failures != nil {
+			s.failures.With("reason", failureReason(err)).Add(1)
+		}
+		if err == hystrix.ErrCircuitOpen && s.fallback != nil {
+			return s.fallback.FetchRoutesForSpecification(ctx, rs)
+		}
+		return nil, fmt.Errorf("%w: %v", shipping.ErrRoutingUnavailable, err)
 	}
 
 	resp := response.(fetchRoutesResponse)
@@ -44,28 +73,204 @@ func (s proxyService) FetchRoutesForSpecification(rs shipping.RouteSpecification
 			})
 		}
 
-		itineraries = append(itineraries, shipping.Itinerary{Legs: legs})
+		// The routing service may not honor every part of the
+		// specification, so make sure that it does.
+		if it := (shipping.Itinerary{Legs: legs}); rs.IsFullySatisfiedBy(it) {
+			itineraries = append(itineraries, it)
+		}
 	}
 
-	return itineraries
+	return itineraries, nil
+}
+
+// failureReason classifies a failed call to the routing service.
+func failureReason(err error) string {
+	if re, ok := err.(lb.RetryError); ok {
+		err = re.Final
+	}
+	switch {
+	case err == hystrix.ErrCircuitOpen:
+		return "circuit_open"
+	case err == hystrix.ErrTimeout, errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	return "error"
+}
+
+// circuitName names the circuit breaker guarding the routing service.
+const circuitName = "fetch-routes"
+
+// ErrCircuitOpen is returned by CheckCircuit when the routing service has
+// failed too often to be called.
+var ErrCircuitOpen = errors.New("routing service circuit is open")
+
+// CheckCircuit returns ErrCircuitOpen if the circuit breaker guarding the
+// routing service is open.
+func CheckCircuit(_ context.Context) error {
+	c, _, err := hystrix.GetCircuit(circuitName)
+	if err != nil {
+		return err
+	}
+	if c.IsOpen() {
+		return ErrCircuitOpen
+	}
+	return nil
 }
 
 // ServiceMiddleware defines a middleware for a routing service.
 type ServiceMiddleware func(shipping.RoutingService) shipping.RoutingService
 
-// NewProxyingMiddleware returns a new instance of a proxying middleware.
-func NewProxyingMiddleware(ctx context.Context, proxyURL string) ServiceMiddleware {
-	return func(next shipping.RoutingService) shipping.RoutingService {
-		var e endpoint.Endpoint
-		e = makeFetchRoutesEndpoint(ctx, proxyURL)
-		e = circuitbreaker.Hystrix("fetch-routes")(e)
-		return proxyService{ctx, e, next}
+// ProxyOption configures optional features of a proxying middleware.
+type ProxyOption func(*proxyOptions)
+
+type proxyOptions struct {
+	tracer   trace.Tracer
+	failures metrics.Counter
+	logger   log.Logger
+	timeout  time.Duration
+	retries  int
+}
+
+// DefaultTimeout is how long a call to the routing service may take unless
+// configured otherwise.
+const DefaultTimeout = time.Second
+
+// WithTracer traces the calls to the routing service with t, and propagates
+// the trace to it using the global propagator.
+func WithTracer(t trace.Tracer) ProxyOption {
+	return func(o *proxyOptions) { o.tracer = t }
+}
+
+// WithFailureCounter counts the failed calls to the routing service with c,
+// labeled with the "reason" of the failure: circuit_open, timeout or error.
+func WithFailureCounter(c metrics.Counter) ProxyOption {
+	return func(o *proxyOptions) { o.failures = c }
+}
+
+// WithLogger logs changes to the instances of the routing service, and
+// instances that can't be used, to logger.
+func WithLogger(logger log.Logger) ProxyOption {
+	return func(o *proxyOptions) { o.logger = logger }
+}
+
+// WithTimeout gives up on a call to an instance of the routing service after
+// d.
+func WithTimeout(d time.Duration) ProxyOption {
+	return func(o *proxyOptions) { o.timeout = d }
+}
+
+// WithRetries retries a failed call to the routing service up to n times,
+// each on the next instance, unless the circuit breaker has opened.
+func WithRetries(n int) ProxyOption {
+	return func(o *proxyOptions) { o.retries = n }
+}
+
+// NewProxyingMiddleware returns a new instance of a proxying middleware for
+// the routing service at proxyURL. It falls back to the service it
+// decorates, if not nil, while the circuit breaker guarding the routing
+// service is open.
+func NewProxyingMiddleware(proxyURL string, opts ...ProxyOption) (ServiceMiddleware, error) {
+	if _, err := parseURL(proxyURL); err != nil {
+		return nil, err
+	}
+
+	o := newProxyOptions(opts)
+
+	e, _, err := o.factory(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.middleware(sd.FixedEndpointer{e}), nil
+}
+
+// NewBalancingMiddleware is like NewProxyingMiddleware, but balances the
+// calls over the instances of the routing service found by instancer, in
+// turn. Instances are either URLs or, as found by DNS SRV records, host:port
+// pairs served over HTTP.
+func NewBalancingMiddleware(instancer sd.Instancer, opts ...ProxyOption) ServiceMiddleware {
+	o := newProxyOptions(opts)
+	return o.middleware(sd.NewEndpointer(instancer, o.factory, o.logger))
+}
+
+func newProxyOptions(opts []ProxyOption) proxyOptions {
+	o := proxyOptions{timeout: DefaultTimeout, logger: log.NewNopLogger()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// factory returns the endpoint of an instance of the routing service.
+func (o proxyOptions) factory(instance string) (endpoint.Endpoint, io.Closer, error) {
+	e, err := makeFetchRoutesEndpoint(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+	e = timeoutEndpoint(o.timeout)(e)
+	if o.tracer != nil {
+		e = traceEndpoint(o.tracer, instance)(e)
+	}
+	return e, nil, nil
+}
+
+// middleware returns a middleware that calls the endpoints of endpointer in
+// turn, retrying on the next one if a call fails.
+func (o proxyOptions) middleware(endpointer sd.Endpointer) ServiceMiddleware {
+	// Every attempt may take the full timeout.
+	timeout := o.timeout * time.Duration(o.retries+1)
+
+	e := lb.RetryWithCallback(timeout, lb.NewRoundRobin(endpointer), func(n int, err error) (bool, error) {
+		return n <= o.retries, nil
+	})
+
+	hystrix.ConfigureCommand(circuitName, hystrix.CommandConfig{
+		Timeout: int(timeout / time.Millisecond),
+	})
+	e = circuitbreaker.Hystrix(circuitName)(e)
+
+	return func(fallback shipping.RoutingService) shipping.RoutingService {
+		return proxyService{e, o.failures, fallback}
+	}
+}
+
+// timeoutEndpoint cancels calls that take longer than d.
+func timeoutEndpoint(d time.Duration) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+
+// traceEndpoint starts a client span for every call to the routing service.
+func traceEndpoint(tracer trace.Tracer, proxyURL string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			ctx, span := tracer.Start(ctx, "routing.FetchRoutes", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+				attribute.String("url.full", proxyURL),
+			))
+			defer func() {
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+				}
+				span.End()
+			}()
+			return next(ctx, request)
+		}
 	}
 }
 
 type fetchRoutesRequest struct {
-	From string
-	To   string
+	From              string
+	To                string
+	Deadline          time.Time
+	EarliestDeparture time.Time
+	MaxLegs           int
+	Avoid             []shipping.UNLocode
 }
 
 type fetchRoutesResponse struct {
@@ -80,22 +285,65 @@ type fetchRoutesResponse struct {
 	} `json:"paths"`
 }
 
-func makeFetchRoutesEndpoint(ctx context.Context, instance string) endpoint.Endpoint {
+// instanceURL returns the URL of the paths resource of an instance of the
+// routing service, given as a URL or a host:port pair.
+func instanceURL(instance string) (*url.URL, error) {
+	if !strings.Contains(instance, "://") {
+		if _, _, err := net.SplitHostPort(instance); err == nil {
+			instance = "http://" + instance
+		}
+	}
+	return parseURL(instance)
+}
+
+// parseURL returns the URL of the paths resource of the routing service at
+// instance.
+func parseURL(instance string) (*url.URL, error) {
 	u, err := url.Parse(instance)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("routing service URL: %v", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("routing service URL: %q is not a HTTP URL", instance)
 	}
 	if u.Path == "" {
 		u.Path = "/paths"
 	}
+	return u, nil
+}
+
+func makeFetchRoutesEndpoint(instance string) (endpoint.Endpoint, error) {
+	u, err := instanceURL(instance)
+	if err != nil {
+		return nil, err
+	}
 	return kithttp.NewClient(
 		"GET", u,
 		encodeFetchRoutesRequest,
 		decodeFetchRoutesResponse,
-	).Endpoint()
+		kithttp.ClientBefore(injectTraceContext),
+		kithttp.ClientAfter(recordStatusCode),
+	).Endpoint(), nil
+}
+
+// injectTraceContext propagates the trace of ctx, if any, to the routing
+// service.
+func injectTraceContext(ctx context.Context, r *http.Request) context.Context {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+	return ctx
+}
+
+// recordStatusCode adds the status code of the response to the span of ctx,
+// if any.
+func recordStatusCode(ctx context.Context, resp *http.Response) context.Context {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	return ctx
 }
 
 func decodeFetchRoutesResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
 	var response fetchRoutesResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return nil, err
@@ -109,6 +357,18 @@ func encodeFetchRoutesRequest(_ context.Context, r *http.Request, request interf
 	vals := r.URL.Query()
 	vals.Add("from", req.From)
 	vals.Add("to", req.To)
+	if !req.Deadline.IsZero() {
+		vals.Add("deadline", req.Deadline.UTC().Format(time.RFC3339))
+	}
+	if !req.EarliestDeparture.IsZero() {
+		vals.Add("earliest_departure", req.EarliestDeparture.UTC().Format(time.RFC3339))
+	}
+	if req.MaxLegs > 0 {
+		vals.Add("max_legs", strconv.Itoa(req.MaxLegs))
+	}
+	for _, l := range req.Avoid {
+		vals.Add("avoid", string(l))
+	}
 	r.URL.RawQuery = vals.Encode()
 
 	return nil
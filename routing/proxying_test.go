@@ -0,0 +1,327 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/afex/hystrix-go/hystrix"
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/sd"
+	"github.com/go-kit/kit/sd/lb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/mock"
+)
+
+func TestProxyPropagatesTrace(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	tracer := tp.Tracer("test")
+
+	var traceparent, query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"paths": [{"edges": [{"origin": "SESTO", "destination": "CNHKG", "voyage": "V100"}]}]}`))
+	}))
+	defer ts.Close()
+
+	proxy, err := NewProxyingMiddleware(ts.URL, WithTracer(tracer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := proxy(nil)
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	itineraries, err := rs.FetchRoutesForSpecification(ctx, shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.CNHKG,
+	})
+	parent.End()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(itineraries) != 1 {
+		t.Fatalf("len(itineraries) = %d; want = %d", len(itineraries), 1)
+	}
+	if query != "from=SESTO&to=CNHKG" {
+		t.Errorf("query = %q; want = %q", query, "from=SESTO&to=CNHKG")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("len(spans) = %d; want = %d", len(ended), 2)
+	}
+	call := ended[0]
+
+	if call.Name() != "routing.FetchRoutes" {
+		t.Errorf("call.Name() = %q; want = %q", call.Name(), "routing.FetchRoutes")
+	}
+	if call.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("call span isn't a child of the parent span")
+	}
+
+	// The routing service continues the trace from the call span.
+	want := "00-" + call.SpanContext().TraceID().String() + "-" + call.SpanContext().SpanID().String()
+	if !strings.HasPrefix(traceparent, want) {
+		t.Errorf("traceparent = %q; want prefix %q", traceparent, want)
+	}
+}
+
+func TestProxySendsRouteSpecification(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"paths": [
+			{"edges": [{"origin": "SESTO", "destination": "CNHKG", "voyage": "V100", "departure": "2009-03-02T12:00:00Z", "arrival": "2009-03-10T12:00:00Z"}]},
+			{"edges": [{"origin": "SESTO", "destination": "CNHKG", "voyage": "V200", "departure": "2009-03-02T12:00:00Z", "arrival": "2009-03-20T12:00:00Z"}]},
+			{"edges": [{"origin": "SESTO", "destination": "CNHKG", "voyage": "V300", "departure": "2009-02-27T12:00:00Z", "arrival": "2009-03-10T12:00:00Z"}]},
+			{"edges": [
+				{"origin": "SESTO", "destination": "DEHAM", "voyage": "V400", "departure": "2009-03-02T12:00:00Z", "arrival": "2009-03-04T12:00:00Z"},
+				{"origin": "DEHAM", "destination": "CNHKG", "voyage": "V400", "departure": "2009-03-04T12:00:00Z", "arrival": "2009-03-10T12:00:00Z"}
+			]},
+			{"edges": [{"origin": "SESTO", "destination": "USNYC", "voyage": "V500", "departure": "2009-03-02T12:00:00Z", "arrival": "2009-03-10T12:00:00Z"}]}
+		]}`))
+	}))
+	defer ts.Close()
+
+	proxy, err := NewProxyingMiddleware(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	itineraries, err := proxy(nil).FetchRoutesForSpecification(context.Background(), shipping.RouteSpecification{
+		Origin:            shipping.SESTO,
+		Destination:       shipping.CNHKG,
+		ArrivalDeadline:   time.Date(2009, time.March, 15, 12, 0, 0, 0, time.UTC),
+		EarliestDeparture: time.Date(2009, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Constraints: shipping.RouteConstraints{
+			MaxLegs:        2,
+			AvoidLocations: []shipping.UNLocode{shipping.DEHAM, shipping.FIHEL},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := url.Values{
+		"from":               {"SESTO"},
+		"to":                 {"CNHKG"},
+		"deadline":           {"2009-03-15T12:00:00Z"},
+		"earliest_departure": {"2009-03-01T12:00:00Z"},
+		"max_legs":           {"2"},
+		"avoid":              {"DEHAM", "FIHEL"},
+	}
+	if query.Encode() != want.Encode() {
+		t.Errorf("query = %q; want = %q", query.Encode(), want.Encode())
+	}
+
+	// Only the first route arrives in time, departs late enough, avoids
+	// DEHAM and ends up in the right place.
+	if len(itineraries) != 1 {
+		t.Fatalf("len(itineraries) = %d; want = %d", len(itineraries), 1)
+	}
+	if v := itineraries[0].Legs[0].VoyageNumber; v != "V100" {
+		t.Errorf("VoyageNumber = %s; want = %s", v, "V100")
+	}
+}
+
+func TestProxyCountsFailures(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`not json`))
+	}))
+	defer ts.Close()
+
+	failures := &countingCounter{counts: make(map[string]float64)}
+
+	proxy, err := NewProxyingMiddleware(ts.URL, WithFailureCounter(failures))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := proxy(nil)
+
+	if _, err := rs.FetchRoutesForSpecification(context.Background(), shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.CNHKG,
+	}); !errors.Is(err, shipping.ErrRoutingUnavailable) {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrRoutingUnavailable)
+	}
+	if failures.counts["error"] != 1 {
+		t.Errorf("failures = %v; want one error", failures.counts)
+	}
+}
+
+func TestProxyRetries(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"paths": [{"edges": [{"origin": "SESTO", "destination": "CNHKG", "voyage": "V100"}]}]}`))
+	}))
+	defer ts.Close()
+
+	proxy, err := NewProxyingMiddleware(ts.URL, WithRetries(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	itineraries, err := proxy(nil).FetchRoutesForSpecification(context.Background(), shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.CNHKG,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(itineraries) != 1 {
+		t.Errorf("len(itineraries) = %d; want = %d", len(itineraries), 1)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d; want = %d", calls, 3)
+	}
+}
+
+func TestProxyTimeout(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	proxy, err := NewProxyingMiddleware(ts.URL, WithTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := proxy(nil).FetchRoutesForSpecification(context.Background(), shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.CNHKG,
+	}); !errors.Is(err, shipping.ErrRoutingUnavailable) {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrRoutingUnavailable)
+	}
+}
+
+func TestProxyFallsBackWhenCircuitIsOpen(t *testing.T) {
+	fallback := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			return []shipping.Itinerary{{}}, nil
+		},
+	}
+
+	open := func(context.Context, interface{}) (interface{}, error) {
+		return nil, hystrix.ErrCircuitOpen
+	}
+
+	itineraries, err := proxyService{FetchRoutesEndpoint: open, fallback: fallback}.FetchRoutesForSpecification(context.Background(), shipping.RouteSpecification{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fallback.FetchRoutesInvoked {
+		t.Errorf("fallback wasn't invoked")
+	}
+	if len(itineraries) != 1 {
+		t.Errorf("len(itineraries) = %d; want = %d", len(itineraries), 1)
+	}
+
+	// Without a fallback, the routing service is unavailable.
+	if _, err := (proxyService{FetchRoutesEndpoint: open}).FetchRoutesForSpecification(context.Background(), shipping.RouteSpecification{}); !errors.Is(err, shipping.ErrRoutingUnavailable) {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrRoutingUnavailable)
+	}
+}
+
+func TestNewProxyingMiddlewareRejectsBadURL(t *testing.T) {
+	for _, u := range []string{"", "localhost:7878", "ftp://example.com", "http://%zz"} {
+		if _, err := NewProxyingMiddleware(u); err == nil {
+			t.Errorf("NewProxyingMiddleware(%q) = nil error; want an error", u)
+		}
+	}
+}
+
+func TestBalancingMiddleware(t *testing.T) {
+	var healthy, broken int
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		healthy++
+		w.Write([]byte(`{"paths": [{"edges": [{"origin": "SESTO", "destination": "CNHKG", "voyage": "V100"}]}]}`))
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		broken++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	// Instances found by DNS SRV records have no scheme.
+	instancer := sd.FixedInstancer{ok.URL, strings.TrimPrefix(failing.URL, "http://")}
+
+	rs := NewBalancingMiddleware(instancer, WithRetries(1))(nil)
+
+	// The endpointer learns about the instances in the background.
+	waitForEndpoints(t, rs)
+	healthy, broken = 0, 0
+
+	for i := 0; i < 4; i++ {
+		itineraries, err := rs.FetchRoutesForSpecification(context.Background(), shipping.RouteSpecification{
+			Origin:      shipping.SESTO,
+			Destination: shipping.CNHKG,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(itineraries) != 1 {
+			t.Errorf("len(itineraries) = %d; want = %d", len(itineraries), 1)
+		}
+	}
+
+	// The calls were spread over both instances, and the ones that reached
+	// the failing instance were retried on the other one.
+	if healthy != 4 || broken == 0 {
+		t.Errorf("healthy, broken = %d, %d; want 4 healthy and some broken", healthy, broken)
+	}
+}
+
+func waitForEndpoints(t *testing.T, rs shipping.RoutingService) {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		if _, err := rs.FetchRoutesForSpecification(context.Background(), shipping.RouteSpecification{}); err == nil || !strings.Contains(err.Error(), lb.ErrNoEndpoints.Error()) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("no endpoints")
+}
+
+// countingCounter is a metrics.Counter that counts by the value of its only
+// label.
+type countingCounter struct {
+	value  string
+	counts map[string]float64
+}
+
+func (c *countingCounter) With(labelValues ...string) metrics.Counter {
+	if len(labelValues) == 2 {
+		return &countingCounter{labelValues[1], c.counts}
+	}
+	return c
+}
+
+func (c *countingCounter) Add(delta float64) { c.counts[c.value] += delta }
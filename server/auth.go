@@ -0,0 +1,95 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/marcusolsson/goddd/auth"
+	"github.com/marcusolsson/goddd/booking"
+)
+
+// authenticate identifies the client of each request and adds its principal
+// to the request context. Clients without credentials are given the
+// anonymous roles, if any. Requests are passed on as is when authentication
+// isn't enabled.
+func (s *Server) authenticate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Authenticator == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+
+		p, err := s.Authenticator.Authenticate(ctx, credentials(r))
+		switch {
+		case err == auth.ErrNoCredentials:
+			if len(s.AnonymousRoles) == 0 {
+				h.ServeHTTP(w, r)
+				return
+			}
+			p = &auth.Principal{Subject: "anonymous", Roles: s.AnonymousRoles, Anonymous: true}
+		case err != nil:
+			w.Header().Set("WWW-Authenticate", `Bearer realm="goddd"`)
+			encodeError(ctx, err, w)
+			return
+		}
+
+		h.ServeHTTP(w, r.WithContext(auth.NewContext(ctx, p)))
+	})
+}
+
+// require only lets through requests from principals with any of the given
+// roles. It has no effect when authentication isn't enabled.
+func (s *Server) require(roles ...auth.Role) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s.Authenticator == nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+
+			// Anonymous clients are asked to authenticate rather than
+			// being denied outright.
+			p, ok := auth.FromContext(ctx)
+			if !ok || (p.Anonymous && !p.HasRole(roles...)) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="goddd"`)
+				encodeError(ctx, auth.ErrUnauthenticated, w)
+				return
+			}
+			if !p.HasRole(roles...) {
+				encodeError(ctx, auth.ErrPermissionDenied, w)
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// credentials returns the credentials of r. API keys are given in the
+// X-API-Key header, and bearer tokens in the Authorization header.
+func credentials(r *http.Request) auth.Credentials {
+	var c auth.Credentials
+
+	c.APIKey = r.Header.Get("X-API-Key")
+
+	if v := r.Header.Get("Authorization"); len(v) > 7 && strings.EqualFold(v[:7], "Bearer ") {
+		c.BearerToken = strings.TrimSpace(v[7:])
+	}
+
+	return c
+}
+
+// bookingActor passes the subject of the authenticated client on to the
+// booking service, as the actor of the changes it makes.
+func bookingActor(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := auth.FromContext(r.Context()); ok && !p.Anonymous {
+			r = r.WithContext(booking.NewActorContext(r.Context(), p.Subject))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/auth"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/handling"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/tracking"
+)
+
+func TestAuthorization(t *testing.T) {
+	var (
+		cargos    = inmem.NewCargoRepository()
+		locations = inmem.NewLocationRepository()
+		voyages   = inmem.NewVoyageRepository()
+		events    = inmem.NewHandlingEventRepository()
+	)
+
+	cargos.Store(context.Background(), shipping.NewCargo("ABC123", shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.AUMEL,
+	}))
+
+	bs := booking.NewService(cargos, locations, events, nil, nil, nil)
+	ts := tracking.NewService(cargos, events)
+	hs := handling.NewService(events, shipping.HandlingEventFactory{
+		CargoRepository:    cargos,
+		VoyageRepository:   voyages,
+		LocationRepository: locations,
+	}, handling.EventHandlers{})
+
+	keys := auth.NewAPIKeys(map[string]auth.Principal{
+		"admin":   {Subject: "admin", Roles: []auth.Role{auth.RoleAdmin}},
+		"handler": {Subject: "stockholm", Roles: []auth.Role{auth.RoleHandler}, Locations: []shipping.UNLocode{shipping.SESTO}},
+	})
+
+	h := New(bs, ts, hs, log.NewLogfmtLogger(ioutil.Discard), WithAuthentication(keys, auth.RolePublic))
+
+	incident := func(location string) string {
+		return `{"completion_time": "2017-01-01T00:00:00Z", "tracking_id": "ABC123", "location": "` + location + `", "event_type": "Receive"}`
+	}
+
+	for _, tt := range []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		key    string
+		status int
+	}{
+		{"anonymous booking", "GET", "/booking/v1/cargos", "", "", http.StatusUnauthorized},
+		{"anonymous tracking", "GET", "/tracking/v1/cargos/ABC123", "", "", http.StatusOK},
+		{"invalid key", "GET", "/tracking/v1/cargos/ABC123", "", "wrong", http.StatusUnauthorized},
+		{"handler booking", "GET", "/booking/v1/cargos", "", "handler", http.StatusForbidden},
+		{"admin booking", "GET", "/booking/v1/cargos", "", "admin", http.StatusOK},
+		{"anonymous handling", "POST", "/handling/v1/incidents", incident("SESTO"), "", http.StatusUnauthorized},
+		{"handler at own location", "POST", "/handling/v1/incidents", incident("SESTO"), "handler", http.StatusOK},
+		{"handler elsewhere", "POST", "/handling/v1/incidents", incident("AUMEL"), "handler", http.StatusForbidden},
+	} {
+		req, _ := http.NewRequest(tt.method, "http://example.com"+tt.path, strings.NewReader(tt.body))
+		if tt.key != "" {
+			req.Header.Set("X-API-Key", tt.key)
+		}
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("%s: rec.Code = %d; want = %d (%s)", tt.name, rec.Code, tt.status, rec.Body)
+		}
+		if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: missing WWW-Authenticate", tt.name)
+		}
+	}
+}
+
+func TestAllowedOrigins(t *testing.T) {
+	h := New(nil, nil, nil, log.NewLogfmtLogger(ioutil.Discard), WithAllowedOrigins("https://example.com"))
+
+	for origin, want := range map[string]string{
+		"https://example.com": "https://example.com",
+		"https://evil.com":    "",
+	} {
+		req, _ := http.NewRequest("OPTIONS", "http://localhost/booking/v1/cargos", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != want {
+			t.Errorf("Access-Control-Allow-Origin = %q; want = %q", got, want)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package server
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi"
@@ -11,6 +13,7 @@ import (
 
 	shipping "github.com/marcusolsson/goddd"
 	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/pricing"
 )
 
 type bookingHandler struct {
@@ -27,35 +30,49 @@ func (h *bookingHandler) router() chi.Router {
 		r.Get("/", h.listCargos)
 		r.Route("/{trackingID}", func(r chi.Router) {
 			r.Get("/", h.loadCargo)
+			r.Get("/history", h.history)
 			r.Get("/request_routes", h.requestRoutes)
+			r.Post("/quote", h.quoteRoute)
 			r.Post("/assign_to_route", h.assignToRoute)
 			r.Post("/change_destination", h.changeDestination)
+			r.Post("/change_arrival_deadline", h.changeArrivalDeadline)
+			r.Post("/change_origin", h.changeOrigin)
 		})
 
 	})
 	r.Get("/locations", h.listLocations)
 
-	r.Method("GET", "/docs", http.StripPrefix("/booking/v1/docs", http.FileServer(http.Dir("booking/docs"))))
-
 	return r
 }
 
 func (h *bookingHandler) bookCargo(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 
 	var request struct {
-		Origin          shipping.UNLocode
-		Destination     shipping.UNLocode
-		ArrivalDeadline time.Time
+		Origin            shipping.UNLocode   `json:"origin"`
+		Destination       shipping.UNLocode   `json:"destination"`
+		ArrivalDeadline   time.Time           `json:"arrival_deadline"`
+		EarliestDeparture time.Time           `json:"earliest_departure"`
+		MaxLegs           int                 `json:"max_legs"`
+		AvoidLocations    []shipping.UNLocode `json:"avoid_locations"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+	if err := decodeRequest(r, &request); err != nil {
 		h.logger.Log("error", err)
 		encodeError(ctx, err, w)
 		return
 	}
 
-	id, err := h.s.BookNewCargo(request.Origin, request.Destination, request.ArrivalDeadline)
+	id, err := h.s.BookNewCargo(ctx, shipping.RouteSpecification{
+		Origin:            request.Origin,
+		Destination:       request.Destination,
+		ArrivalDeadline:   request.ArrivalDeadline,
+		EarliestDeparture: request.EarliestDeparture,
+		Constraints: shipping.RouteConstraints{
+			MaxLegs:        request.MaxLegs,
+			AvoidLocations: request.AvoidLocations,
+		},
+	})
 	if err != nil {
 		encodeError(ctx, err, w)
 		return
@@ -76,11 +93,11 @@ func (h *bookingHandler) bookCargo(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *bookingHandler) loadCargo(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 
 	trackingID := shipping.TrackingID(chi.URLParam(r, "trackingID"))
 
-	c, err := h.s.LoadCargo(trackingID)
+	c, err := h.s.LoadCargo(ctx, trackingID)
 	if err != nil {
 		encodeError(ctx, err, w)
 		return
@@ -100,17 +117,83 @@ func (h *bookingHandler) loadCargo(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *bookingHandler) history(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	trackingID := shipping.TrackingID(chi.URLParam(r, "trackingID"))
+
+	amendments, err := h.s.History(ctx, trackingID)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+
+	var response = struct {
+		Amendments []booking.Amendment `json:"amendments"`
+	}{
+		Amendments: amendments,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Log("error", err)
+		encodeError(ctx, err, w)
+		return
+	}
+}
+
 func (h *bookingHandler) requestRoutes(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
+
+	trackingID := shipping.TrackingID(chi.URLParam(r, "trackingID"))
+
+	candidates, err := h.s.RequestPossibleRoutesForCargo(ctx, trackingID, r.URL.Query().Get("strategy"))
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+
+	var response = struct {
+		Routes []booking.RouteCandidate `json:"routes"`
+	}{
+		Routes: candidates,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Log("error", err)
+		encodeError(ctx, err, w)
+		return
+	}
+}
+
+func (h *bookingHandler) quoteRoute(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
 	trackingID := shipping.TrackingID(chi.URLParam(r, "trackingID"))
 
-	itin := h.s.RequestPossibleRoutesForCargo(trackingID)
+	var request struct {
+		CandidateID booking.RouteCandidateID `json:"candidate_id"`
+		Size        int                      `json:"size"`
+		Currency    string                   `json:"currency"`
+	}
+
+	if err := decodeRequest(r, &request); err != nil {
+		h.logger.Log("error", err)
+		encodeError(ctx, err, w)
+		return
+	}
+
+	q, err := h.s.QuoteRoute(ctx, trackingID, request.CandidateID, request.Size, request.Currency)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
 
 	var response = struct {
-		Routes []shipping.Itinerary `json:"routes"`
+		Quote pricing.Quote `json:"quote"`
 	}{
-		Routes: itin,
+		Quote: q,
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -122,21 +205,32 @@ func (h *bookingHandler) requestRoutes(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *bookingHandler) assignToRoute(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 
 	trackingID := shipping.TrackingID(chi.URLParam(r, "trackingID"))
 
 	var request struct {
-		Itinerary shipping.Itinerary `json:"route"`
+		Itinerary   *shipping.Itinerary      `json:"route"`
+		CandidateID booking.RouteCandidateID `json:"candidate_id"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+	if err := decodeRequest(r, &request); err != nil {
 		h.logger.Log("error", err)
 		encodeError(ctx, err, w)
 		return
 	}
 
-	err := h.s.AssignCargoToRoute(trackingID, request.Itinerary)
+	var err error
+	switch {
+	case request.Itinerary != nil && request.CandidateID != "":
+		err = invalidParam("candidate_id", "must not be given along with route")
+	case request.CandidateID != "":
+		err = h.s.AssignCargoToRouteCandidate(ctx, trackingID, request.CandidateID)
+	case request.Itinerary != nil:
+		err = h.s.AssignCargoToRoute(ctx, trackingID, *request.Itinerary)
+	default:
+		err = invalidParam("candidate_id", "either route or candidate_id is required")
+	}
 	if err != nil {
 		encodeError(ctx, err, w)
 		return
@@ -144,7 +238,7 @@ func (h *bookingHandler) assignToRoute(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *bookingHandler) changeDestination(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 
 	trackingID := shipping.TrackingID(chi.URLParam(r, "trackingID"))
 
@@ -152,13 +246,57 @@ func (h *bookingHandler) changeDestination(w http.ResponseWriter, r *http.Reques
 		Destination shipping.UNLocode `json:"destination"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+	if err := decodeRequest(r, &request); err != nil {
 		h.logger.Log("error", err)
 		encodeError(ctx, err, w)
 		return
 	}
 
-	err := h.s.ChangeDestination(trackingID, request.Destination)
+	err := h.s.ChangeDestination(ctx, trackingID, request.Destination)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+}
+
+func (h *bookingHandler) changeArrivalDeadline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	trackingID := shipping.TrackingID(chi.URLParam(r, "trackingID"))
+
+	var request struct {
+		ArrivalDeadline time.Time `json:"arrival_deadline"`
+	}
+
+	if err := decodeRequest(r, &request); err != nil {
+		h.logger.Log("error", err)
+		encodeError(ctx, err, w)
+		return
+	}
+
+	err := h.s.ChangeArrivalDeadline(ctx, trackingID, request.ArrivalDeadline)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+}
+
+func (h *bookingHandler) changeOrigin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	trackingID := shipping.TrackingID(chi.URLParam(r, "trackingID"))
+
+	var request struct {
+		Origin shipping.UNLocode `json:"origin"`
+	}
+
+	if err := decodeRequest(r, &request); err != nil {
+		h.logger.Log("error", err)
+		encodeError(ctx, err, w)
+		return
+	}
+
+	err := h.s.ChangeOrigin(ctx, trackingID, request.Origin)
 	if err != nil {
 		encodeError(ctx, err, w)
 		return
@@ -166,14 +304,26 @@ func (h *bookingHandler) changeDestination(w http.ResponseWriter, r *http.Reques
 }
 
 func (h *bookingHandler) listCargos(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
+
+	q, err := decodeCargoQuery(r.URL.Query())
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
 
-	cs := h.s.Cargos()
+	page, err := h.s.Cargos(ctx, q)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
 
 	var response = struct {
-		Cargos []booking.Cargo `json:"cargos"`
+		Cargos     []booking.Cargo `json:"cargos"`
+		NextCursor string          `json:"next_cursor,omitempty"`
 	}{
-		Cargos: cs,
+		Cargos:     page.Cargos,
+		NextCursor: page.NextCursor,
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -185,9 +335,9 @@ func (h *bookingHandler) listCargos(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *bookingHandler) listLocations(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 
-	ls := h.s.Locations()
+	ls := h.s.Locations(ctx)
 
 	var response = struct {
 		Locations []booking.Location `json:"locations"`
@@ -202,3 +352,78 @@ func (h *bookingHandler) listLocations(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// decodeCargoQuery parses the query parameters used to filter, sort and
+// paginate the cargo listing.
+func decodeCargoQuery(vals url.Values) (shipping.CargoQuery, error) {
+	var q shipping.CargoQuery
+
+	if v := vals.Get("routing_status"); v != "" {
+		s, ok := routingStatuses[v]
+		if !ok {
+			return q, invalidParam("routing_status", "must be one of not_routed, misrouted, routed")
+		}
+		q.RoutingStatus = &s
+	}
+
+	if v := vals.Get("misrouted"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, invalidParam("misrouted", "must be a boolean")
+		}
+		q.Misrouted = &b
+	}
+
+	q.Origin = shipping.UNLocode(vals.Get("origin"))
+	q.Destination = shipping.UNLocode(vals.Get("destination"))
+
+	for param, t := range map[string]*time.Time{
+		"deadline_after":  &q.DeadlineAfter,
+		"deadline_before": &q.DeadlineBefore,
+	} {
+		if v := vals.Get(param); v != "" {
+			d, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return q, invalidParam(param, "must be a RFC 3339 timestamp")
+			}
+			*t = d
+		}
+	}
+
+	if v := vals.Get("sort"); v != "" {
+		if strings.HasPrefix(v, "-") {
+			q.Descending = true
+			v = v[1:]
+		}
+		switch v {
+		case "tracking_id":
+			q.SortBy = shipping.SortByTrackingID
+		case "arrival_deadline":
+			q.SortBy = shipping.SortByArrivalDeadline
+		default:
+			return q, invalidParam("sort", "must be one of tracking_id, arrival_deadline, optionally prefixed with -")
+		}
+	}
+
+	if v := vals.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return q, invalidParam("limit", "must be a positive integer")
+		}
+		q.Limit = n
+	}
+
+	q.Cursor = vals.Get("cursor")
+
+	return q, nil
+}
+
+func invalidParam(name, reason string) error {
+	return booking.ErrInvalidArgument.WithFields(shipping.FieldError{Name: name, Reason: reason})
+}
+
+var routingStatuses = map[string]shipping.RoutingStatus{
+	"not_routed": shipping.NotRouted,
+	"misrouted":  shipping.Misrouted,
+	"routed":     shipping.Routed,
+}
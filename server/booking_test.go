@@ -0,0 +1,415 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/auth"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/mock"
+	"github.com/marcusolsson/goddd/pricing"
+)
+
+func TestListCargos(t *testing.T) {
+	ctx := context.Background()
+
+	cargos := inmem.NewCargoRepository()
+
+	for i, id := range []shipping.TrackingID{"A1", "A2", "A3"} {
+		c := shipping.NewCargo(id, shipping.RouteSpecification{
+			Origin:          shipping.SESTO,
+			Destination:     shipping.AUMEL,
+			ArrivalDeadline: time.Date(2009, time.March, 3-i, 12, 0, 0, 0, time.UTC),
+		})
+		if err := cargos.Store(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := booking.NewService(cargos, nil, nil, nil, nil, nil)
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	var ids []string
+
+	url := "http://example.com/booking/v1/cargos?sort=arrival_deadline&limit=2&routing_status=not_routed"
+	for url != "" {
+		req, _ := http.NewRequest("GET", url, nil)
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("rec.Code = %d; want = %d", rec.Code, http.StatusOK)
+		}
+
+		var response struct {
+			Cargos     []booking.Cargo `json:"cargos"`
+			NextCursor string          `json:"next_cursor"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, c := range response.Cargos {
+			ids = append(ids, c.TrackingID)
+		}
+
+		url = ""
+		if response.NextCursor != "" {
+			url = "http://example.com/booking/v1/cargos?sort=arrival_deadline&limit=2&routing_status=not_routed&cursor=" + response.NextCursor
+		}
+	}
+
+	if want := []string{"A3", "A2", "A1"}; len(ids) != len(want) || ids[0] != want[0] || ids[1] != want[1] || ids[2] != want[2] {
+		t.Errorf("ids = %v; want = %v", ids, want)
+	}
+}
+
+func TestListCargosInvalidQuery(t *testing.T) {
+	s := booking.NewService(inmem.NewCargoRepository(), nil, nil, nil, nil, nil)
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	for _, query := range []string{
+		"routing_status=lost",
+		"misrouted=maybe",
+		"deadline_after=yesterday",
+		"sort=weight",
+		"limit=0",
+		"cursor=xyz",
+	} {
+		req, _ := http.NewRequest("GET", "http://example.com/booking/v1/cargos?"+query, nil)
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: rec.Code = %d; want = %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestAssignToRouteCandidate(t *testing.T) {
+	ctx := context.Background()
+
+	cargos := inmem.NewCargoRepository()
+	for _, id := range []shipping.TrackingID{"ABC123", "DEF456"} {
+		cargos.Store(ctx, shipping.NewCargo(id, shipping.RouteSpecification{
+			Origin:      shipping.SESTO,
+			Destination: shipping.AUMEL,
+		}))
+	}
+
+	rs := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			return []shipping.Itinerary{
+				{Legs: []shipping.Leg{{VoyageNumber: "V100", LoadLocation: rs.Origin, UnloadLocation: rs.Destination}}},
+			}, nil
+		},
+	}
+
+	s := booking.NewService(cargos, nil, nil, rs, inmem.NewRouteCandidateRepository(), inmem.NewAmendmentRepository())
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	req, _ := http.NewRequest("GET", "http://example.com/booking/v1/cargos/ABC123/request_routes", nil)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	var response struct {
+		Routes []booking.RouteCandidate `json:"routes"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Routes) != 1 || response.Routes[0].ID == "" {
+		t.Fatalf("routes = %+v; want one with an ID", response.Routes)
+	}
+	id := string(response.Routes[0].ID)
+
+	for _, tt := range []struct {
+		trackingID string
+		body       string
+		status     int
+	}{
+		{"ABC123", `{}`, http.StatusBadRequest},
+		{"ABC123", `{"candidate_id":"` + id + `","route":{"legs":[]}}`, http.StatusBadRequest},
+		{"ABC123", `{"candidate_id":"no_such_id"}`, http.StatusUnprocessableEntity},
+		{"DEF456", `{"candidate_id":"` + id + `"}`, http.StatusUnprocessableEntity},
+		{"ABC123", `{"candidate_id":"` + id + `"}`, http.StatusOK},
+	} {
+		req, _ := http.NewRequest("POST", "http://example.com/booking/v1/cargos/"+tt.trackingID+"/assign_to_route", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("%s %s: rec.Code = %d; want = %d", tt.trackingID, tt.body, rec.Code, tt.status)
+		}
+	}
+
+	c, _ := cargos.Find(ctx, "ABC123")
+	if c.Delivery.RoutingStatus != shipping.Routed {
+		t.Errorf("RoutingStatus = %s; want = %s", c.Delivery.RoutingStatus, shipping.Routed)
+	}
+}
+
+func TestQuoteRoute(t *testing.T) {
+	ctx := context.Background()
+
+	cargos := inmem.NewCargoRepository()
+	cargos.Store(ctx, shipping.NewCargo("ABC123", shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.AUMEL,
+	}))
+
+	rs := &mock.RoutingService{
+		FetchRoutesFn: func(rs shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			return []shipping.Itinerary{
+				{Legs: []shipping.Leg{{VoyageNumber: "V100", LoadLocation: rs.Origin, UnloadLocation: rs.Destination}}},
+			}, nil
+		},
+	}
+
+	tariff := &pricing.Tariff{
+		Currency: "USD",
+		Rates:    []pricing.Rate{{Voyage: "V100", Amount: 100000}},
+	}
+
+	s := booking.NewService(cargos, nil, nil, rs, inmem.NewRouteCandidateRepository(), inmem.NewAmendmentRepository(), booking.WithTariff(tariff))
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	routes, err := s.RequestPossibleRoutesForCargo(ctx, "ABC123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := string(routes[0].ID)
+
+	for _, tt := range []struct {
+		body   string
+		status int
+	}{
+		{`{"candidate_id":"` + id + `"}`, http.StatusBadRequest},
+		{`{"candidate_id":"` + id + `","size":1,"currency":"SEK"}`, http.StatusBadRequest},
+		{`{"candidate_id":"no_such_id","size":1}`, http.StatusUnprocessableEntity},
+		{`{"candidate_id":"` + id + `","size":2}`, http.StatusOK},
+	} {
+		req, _ := http.NewRequest("POST", "http://example.com/booking/v1/cargos/ABC123/quote", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("%s: rec.Code = %d; want = %d", tt.body, rec.Code, tt.status)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		var response struct {
+			Quote pricing.Quote `json:"quote"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if want := (shipping.Money{Amount: 200000, Currency: "USD"}); response.Quote.Total != want {
+			t.Errorf("Total = %v; want = %v", response.Quote.Total, want)
+		}
+	}
+
+	if err := s.AssignCargoToRouteCandidate(ctx, "ABC123", routes[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest("GET", "http://example.com/booking/v1/cargos/ABC123", nil)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	var response struct {
+		Cargo booking.Cargo `json:"cargo"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if want := (shipping.Money{Amount: 200000, Currency: "USD"}); response.Cargo.Price == nil || *response.Cargo.Price != want {
+		t.Errorf("Price = %v; want = %v", response.Cargo.Price, want)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+
+	cargos := inmem.NewCargoRepository()
+	cargos.Store(ctx, shipping.NewCargo("ABC123", shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.AUMEL,
+	}))
+
+	locations := inmem.NewLocationRepository()
+	locations.Store(ctx, shipping.Helsinki)
+
+	s := booking.NewService(cargos, locations, nil, nil, inmem.NewRouteCandidateRepository(), inmem.NewAmendmentRepository())
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	if err := s.ChangeDestination(booking.NewActorContext(ctx, "alice"), "ABC123", shipping.FIHEL); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		trackingID string
+		status     int
+		want       int
+	}{
+		{"ABC123", http.StatusOK, 1},
+		{"DEF456", http.StatusNotFound, 0},
+	} {
+		req, _ := http.NewRequest("GET", "http://example.com/booking/v1/cargos/"+tt.trackingID+"/history", nil)
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("%s: rec.Code = %d; want = %d", tt.trackingID, rec.Code, tt.status)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		var response struct {
+			Amendments []booking.Amendment `json:"amendments"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Amendments) != tt.want {
+			t.Fatalf("%s: len(amendments) = %d; want = %d", tt.trackingID, len(response.Amendments), tt.want)
+		}
+
+		a := response.Amendments[0]
+		if a.Type != booking.DestinationChanged || a.Actor != "alice" || a.Previous.Destination != shipping.AUMEL || a.New.Destination != shipping.FIHEL {
+			t.Errorf("amendment = %+v; want destination changed from AUMEL to FIHEL by alice", a)
+		}
+	}
+}
+
+func TestAmendmentActor(t *testing.T) {
+	ctx := context.Background()
+
+	cargos := inmem.NewCargoRepository()
+	cargos.Store(ctx, shipping.NewCargo("ABC123", shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.AUMEL,
+	}))
+
+	locations := inmem.NewLocationRepository()
+	locations.Store(ctx, shipping.Helsinki)
+
+	amendments := inmem.NewAmendmentRepository()
+
+	s := booking.NewService(cargos, locations, nil, nil, inmem.NewRouteCandidateRepository(), amendments)
+
+	keys := auth.NewAPIKeys(map[string]auth.Principal{
+		"secret": {Subject: "booking-desk", Roles: []auth.Role{auth.RoleAdmin}},
+	})
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard), WithAuthentication(keys))
+
+	req, _ := http.NewRequest("POST", "http://example.com/booking/v1/cargos/ABC123/change_destination", strings.NewReader(`{"destination":"FIHEL"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "secret")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rec.Code = %d; want = %d", rec.Code, http.StatusOK)
+	}
+
+	as := amendments.FindByTrackingID(ctx, "ABC123")
+	if len(as) != 1 || as[0].Actor != "booking-desk" {
+		t.Errorf("amendments = %+v; want one by booking-desk", as)
+	}
+}
+
+func TestChangeArrivalDeadlineAndOrigin(t *testing.T) {
+	ctx := context.Background()
+
+	cargos := inmem.NewCargoRepository()
+	for _, id := range []shipping.TrackingID{"ABC123", "DEF456"} {
+		cargos.Store(ctx, shipping.NewCargo(id, shipping.RouteSpecification{
+			Origin:          shipping.SESTO,
+			Destination:     shipping.AUMEL,
+			ArrivalDeadline: time.Date(2015, time.November, 10, 23, 0, 0, 0, time.UTC),
+		}))
+	}
+
+	locations := inmem.NewLocationRepository()
+
+	events := inmem.NewHandlingEventRepository()
+	events.Store(ctx, shipping.HandlingEvent{
+		TrackingID: "DEF456",
+		Activity:   shipping.HandlingActivity{Type: shipping.Receive, Location: shipping.SESTO},
+	})
+
+	s := booking.NewService(cargos, locations, events, nil, inmem.NewRouteCandidateRepository(), inmem.NewAmendmentRepository())
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	later := time.Now().AddDate(1, 0, 0).Truncate(time.Second).UTC()
+	changeDeadline := `{"arrival_deadline":"` + later.Format(time.RFC3339) + `"}`
+
+	for _, tt := range []struct {
+		trackingID string
+		action     string
+		body       string
+		status     int
+	}{
+		{"ABC123", "change_arrival_deadline", `{}`, http.StatusBadRequest},
+		{"ABC123", "change_arrival_deadline", `{"arrival_deadline":"tomorrow"}`, http.StatusBadRequest},
+		{"ABC123", "change_arrival_deadline", `{"arrival_deadline":"2015-11-17T23:00:00Z"}`, http.StatusBadRequest},
+		{"GHI789", "change_arrival_deadline", changeDeadline, http.StatusNotFound},
+		{"ABC123", "change_arrival_deadline", changeDeadline, http.StatusOK},
+		{"ABC123", "change_origin", `{}`, http.StatusBadRequest},
+		{"GHI789", "change_origin", `{"origin":"DEHAM"}`, http.StatusNotFound},
+		{"ABC123", "change_origin", `{"origin":"XXXXX"}`, http.StatusUnprocessableEntity},
+		{"DEF456", "change_origin", `{"origin":"DEHAM"}`, http.StatusConflict},
+		{"ABC123", "change_origin", `{"origin":"DEHAM"}`, http.StatusOK},
+	} {
+		req, _ := http.NewRequest("POST", "http://example.com/booking/v1/cargos/"+tt.trackingID+"/"+tt.action, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("%s %s %s: rec.Code = %d; want = %d", tt.trackingID, tt.action, tt.body, rec.Code, tt.status)
+		}
+	}
+
+	c, _ := cargos.Find(ctx, "ABC123")
+	if !c.RouteSpecification.ArrivalDeadline.Equal(later) {
+		t.Errorf("ArrivalDeadline = %v; want = %v", c.RouteSpecification.ArrivalDeadline, later)
+	}
+	if c.RouteSpecification.Origin != shipping.DEHAM {
+		t.Errorf("Origin = %s; want = %s", c.RouteSpecification.Origin, shipping.DEHAM)
+	}
+}
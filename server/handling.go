@@ -1,8 +1,6 @@
 package server
 
 import (
-	"context"
-	"encoding/json"
 	"net/http"
 	"time"
 
@@ -10,6 +8,7 @@ import (
 	kitlog "github.com/go-kit/kit/log"
 
 	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/auth"
 	"github.com/marcusolsson/goddd/handling"
 )
 
@@ -22,12 +21,11 @@ type handlingHandler struct {
 func (h *handlingHandler) router() chi.Router {
 	r := chi.NewRouter()
 	r.Post("/incidents", h.registerIncident)
-	r.Method("GET", "/docs", http.StripPrefix("/handling/v1/docs", http.FileServer(http.Dir("handling/docs"))))
 	return r
 }
 
 func (h *handlingHandler) registerIncident(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 
 	var request struct {
 		CompletionTime time.Time `json:"completion_time"`
@@ -37,18 +35,36 @@ func (h *handlingHandler) registerIncident(w http.ResponseWriter, r *http.Reques
 		EventType      string    `json:"event_type"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+	if err := decodeRequest(r, &request); err != nil {
 		h.logger.Log("error", err)
 		encodeError(ctx, err, w)
 		return
 	}
 
+	eventType := stringToEventType(request.EventType)
+	if request.EventType != "" && eventType == shipping.NotHandled {
+		encodeError(ctx, handling.ErrInvalidArgument.WithFields(shipping.FieldError{
+			Name:   "event_type",
+			Reason: "must be one of Receive, Load, Unload, Customs, Claim",
+		}), w)
+		return
+	}
+
+	if p, ok := auth.FromContext(ctx); ok && !p.CanHandleAt(shipping.UNLocode(request.Location)) {
+		encodeError(ctx, auth.ErrPermissionDenied.WithFields(shipping.FieldError{
+			Name:   "location",
+			Reason: "is not one of your locations",
+		}), w)
+		return
+	}
+
 	err := h.s.RegisterHandlingEvent(
+		ctx,
 		request.CompletionTime,
 		shipping.TrackingID(request.TrackingID),
 		shipping.VoyageNumber(request.VoyageNumber),
 		shipping.UNLocode(request.Location),
-		stringToEventType(request.EventType),
+		eventType,
 	)
 	if err != nil {
 		encodeError(ctx, err, w)
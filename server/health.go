@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// healthCheckTimeout bounds the time spent on each readiness check.
+const healthCheckTimeout = 5 * time.Second
+
+// HealthCheck reports whether a dependency of the server is usable.
+type HealthCheck func(ctx context.Context) error
+
+// WithReadinessChecks makes /readyz report the given checks, by name. The
+// server is only ready if all of them pass.
+func WithReadinessChecks(checks map[string]HealthCheck) Option {
+	return func(s *Server) { s.ReadinessChecks = checks }
+}
+
+// Drain prepares the server for shutting down: /readyz starts failing so
+// that load balancers stop sending requests, and open streams are closed so
+// that they don't hold up http.Server.Shutdown. Requests in flight are left
+// to finish.
+func (s *Server) Drain() {
+	s.drainOnce.Do(func() { close(s.draining) })
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// healthz reports that the process is alive.
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// readyz reports whether the server is ready to serve requests, by running
+// the readiness checks at the same time.
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	select {
+	case <-s.draining:
+		writeHealth(w, http.StatusServiceUnavailable, healthResponse{Status: "draining"})
+		return
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		status = http.StatusOK
+		resp   = healthResponse{Status: "ok", Checks: make(map[string]string)}
+	)
+
+	for name, check := range s.ReadinessChecks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+
+			result := "ok"
+			err := check(ctx)
+			if err != nil {
+				result = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Checks[name] = result
+			if err != nil {
+				status = http.StatusServiceUnavailable
+				resp.Status = "unavailable"
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	writeHealth(w, status, resp)
+}
+
+func writeHealth(w http.ResponseWriter, status int, resp healthResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/tracking"
+)
+
+func TestHealth(t *testing.T) {
+	var routingErr error
+
+	s := New(nil, nil, nil, log.NewLogfmtLogger(ioutil.Discard), WithReadinessChecks(map[string]HealthCheck{
+		"repository": func(context.Context) error { return nil },
+		"routing":    func(context.Context) error { return routingErr },
+	}))
+
+	get := func(path string) (int, healthResponse) {
+		req, _ := http.NewRequest("GET", "http://example.com"+path, nil)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+
+		var resp healthResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, resp
+	}
+
+	if code, _ := get("/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz: code = %d; want = %d", code, http.StatusOK)
+	}
+	if code, resp := get("/readyz"); code != http.StatusOK || resp.Checks["routing"] != "ok" {
+		t.Errorf("/readyz: code = %d, resp = %+v; want = %d", code, resp, http.StatusOK)
+	}
+
+	routingErr = errors.New("circuit open")
+
+	code, resp := get("/readyz")
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz: code = %d; want = %d", code, http.StatusServiceUnavailable)
+	}
+	if resp.Checks["routing"] != "circuit open" || resp.Checks["repository"] != "ok" {
+		t.Errorf("/readyz: checks = %v", resp.Checks)
+	}
+
+	routingErr = nil
+	s.Drain()
+
+	if code, resp := get("/readyz"); code != http.StatusServiceUnavailable || resp.Status != "draining" {
+		t.Errorf("/readyz: code = %d, status = %s; want = %d, draining", code, resp.Status, http.StatusServiceUnavailable)
+	}
+	if code, _ := get("/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz: code = %d; want = %d", code, http.StatusOK)
+	}
+}
+
+func TestDrainClosesStreams(t *testing.T) {
+	cargos := inmem.NewCargoRepository()
+	cargos.Store(context.Background(), shipping.NewCargo("TEST", shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.FIHEL,
+	}))
+
+	s := New(nil, tracking.NewService(cargos, inmem.NewHandlingEventRepository()), nil,
+		log.NewLogfmtLogger(ioutil.Discard), WithTrackingUpdates(tracking.NewBroker()))
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/tracking/v1/cargos/TEST/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	done := make(chan struct{})
+	go func() {
+		ioutil.ReadAll(resp.Body)
+		close(done)
+	}()
+
+	s.Drain()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream wasn't closed")
+	}
+}
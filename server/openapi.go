@@ -0,0 +1,616 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/marcusolsson/goddd/booking"
+)
+
+// document is an OpenAPI 3 document. Only the parts of the specification
+// needed to describe this API are supported.
+type document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       info                  `json:"info"`
+	Paths      map[string]pathItem   `json:"paths"`
+	Components components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// pathItem maps lower-case HTTP methods to operations.
+type pathItem map[string]*operation
+
+type operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []parameter         `json:"parameters,omitempty"`
+	RequestBody *requestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type components struct {
+	Schemas         map[string]*schema        `json:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes,omitempty"`
+}
+
+type securityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+type schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Minimum     *int               `json:"minimum,omitempty"`
+	Maximum     *int               `json:"maximum,omitempty"`
+	MinItems    int                `json:"minItems,omitempty"`
+	Items       *schema            `json:"items,omitempty"`
+	Properties  map[string]*schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+
+	// AdditionalProperties is only ever false. Nil allows any properties.
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
+}
+
+const (
+	mediaTypeJSON    = "application/json"
+	mediaTypeProblem = "application/problem+json"
+
+	mediaTypeEventStream = "text/event-stream"
+	schemaPrefix         = "#/components/schemas/"
+)
+
+func ref(name string) *schema { return &schema{Ref: schemaPrefix + name} }
+
+func str(description string) *schema { return &schema{Type: "string", Description: description} }
+
+func dateTime(description string) *schema {
+	return &schema{Type: "string", Format: "date-time", Description: description}
+}
+
+func arrayOf(s *schema) *schema { return &schema{Type: "array", Items: s} }
+
+func intPtr(n int) *int { return &n }
+
+// object returns a schema for an object with the given properties, of which
+// required must be present.
+func object(props map[string]*schema, required ...string) *schema {
+	return &schema{Type: "object", Properties: props, Required: required}
+}
+
+// closedObject is like object but doesn't allow any other properties. It's
+// used for request bodies to catch misspelled properties.
+func closedObject(props map[string]*schema, required ...string) *schema {
+	closed := false
+	s := object(props, required...)
+	s.AdditionalProperties = &closed
+	return s
+}
+
+func pathParam(name, description string) parameter {
+	return parameter{Name: name, In: "path", Description: description, Required: true, Schema: str("")}
+}
+
+func queryParam(name, description string, s *schema) parameter {
+	return parameter{Name: name, In: "query", Description: description, Schema: s}
+}
+
+func jsonBody(s *schema) *requestBody {
+	return &requestBody{Required: true, Content: map[string]mediaType{mediaTypeJSON: {Schema: s}}}
+}
+
+func success(description string, s *schema) response {
+	if s == nil {
+		return response{Description: description}
+	}
+	return response{Description: description, Content: map[string]mediaType{mediaTypeJSON: {Schema: s}}}
+}
+
+// responses returns the given successful response together with problem
+// responses for each of the error statuses, and those that any operation
+// may respond with.
+func responses(success response, statuses ...int) map[string]response {
+	m := map[string]response{"200": success}
+	for _, s := range append(statuses, http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusGatewayTimeout) {
+		m[strconv.Itoa(s)] = response{
+			Description: http.StatusText(s),
+			Content:     map[string]mediaType{mediaTypeProblem: {Schema: ref("Problem")}},
+		}
+	}
+	return m
+}
+
+var (
+	trackingIDParam     = pathParam("trackingID", "The tracking ID of the cargo.")
+	subscriptionIDParam = pathParam("subscriptionID", "The ID of the webhook subscription.")
+)
+
+var eventTypes = &schema{Type: "string", Enum: []string{"cargo.misdirected", "cargo.arrived"}}
+
+// spec describes the API served by Server. TestSpecMatchesRoutes makes sure
+// that it stays in sync with the routes.
+var spec = &document{
+	OpenAPI: "3.0.0",
+	Info: info{
+		Title:       "Cargo shipping",
+		Description: "Booking, tracking and handling of cargos.",
+		Version:     "v1",
+	},
+	Paths: map[string]pathItem{
+		"/booking/v1/cargos": {
+			"get": {
+				OperationID: "listCargos",
+				Summary:     "Booked cargos, one page at a time.",
+				Tags:        []string{"booking"},
+				Parameters: []parameter{
+					queryParam("routing_status", "Only list cargos with the given routing status.",
+						&schema{Type: "string", Enum: []string{"not_routed", "misrouted", "routed"}}),
+					queryParam("misrouted", "Only list cargos that are (or aren't) misrouted.", &schema{Type: "boolean"}),
+					queryParam("origin", "Only list cargos with the given origin.", str("")),
+					queryParam("destination", "Only list cargos with the given destination.", str("")),
+					queryParam("deadline_after", "Only list cargos with an arrival deadline at or after the given time.", dateTime("")),
+					queryParam("deadline_before", "Only list cargos with an arrival deadline before the given time.", dateTime("")),
+					queryParam("sort", "Sort order. Prefix with - for descending order.",
+						&schema{Type: "string", Enum: []string{"tracking_id", "-tracking_id", "arrival_deadline", "-arrival_deadline"}}),
+					queryParam("limit", "Maximum number of cargos in the page.",
+						&schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(booking.MaxPageSize)}),
+					queryParam("cursor", "The next_cursor of the previous page.", str("")),
+				},
+				Responses: responses(success("A page of cargos.", object(map[string]*schema{
+					"cargos":      arrayOf(ref("Cargo")),
+					"next_cursor": str("Cursor for the next page. Missing on the last page."),
+				}, "cargos")), http.StatusBadRequest),
+			},
+			"post": {
+				OperationID: "bookCargo",
+				Summary:     "Book a new cargo.",
+				Tags:        []string{"booking"},
+				RequestBody: jsonBody(closedObject(map[string]*schema{
+					"origin":             str("UN/LOCODE of the origin."),
+					"destination":        str("UN/LOCODE of the destination."),
+					"arrival_deadline":   dateTime(""),
+					"earliest_departure": dateTime("When the cargo is ready to leave its origin at the earliest."),
+					"max_legs":           {Type: "integer", Minimum: intPtr(0), Description: "Largest number of legs of a route. Zero means no limit."},
+					"avoid_locations":    arrayOf(str("UN/LOCODE of a location the cargo must not be handled at.")),
+				}, "origin", "destination", "arrival_deadline")),
+				Responses: responses(success("The cargo was booked.", object(map[string]*schema{
+					"tracking_id": str(""),
+				}, "tracking_id")), http.StatusBadRequest),
+			},
+		},
+		"/booking/v1/cargos/{trackingID}": {
+			"get": {
+				OperationID: "loadCargo",
+				Summary:     "A specific cargo.",
+				Tags:        []string{"booking"},
+				Parameters:  []parameter{trackingIDParam},
+				Responses: responses(success("The cargo.", object(map[string]*schema{
+					"cargo": ref("Cargo"),
+				}, "cargo")), http.StatusBadRequest, http.StatusNotFound),
+			},
+		},
+		"/booking/v1/cargos/{trackingID}/history": {
+			"get": {
+				OperationID: "cargoHistory",
+				Summary:     "The amendments made to the booking of a cargo, oldest first.",
+				Tags:        []string{"booking"},
+				Parameters:  []parameter{trackingIDParam},
+				Responses: responses(success("The amendments.", object(map[string]*schema{
+					"amendments": arrayOf(ref("Amendment")),
+				}, "amendments")), http.StatusBadRequest, http.StatusNotFound),
+			},
+		},
+		"/booking/v1/cargos/{trackingID}/request_routes": {
+			"get": {
+				OperationID: "requestRoutes",
+				Summary:     "Possible routes for a cargo, best first.",
+				Tags:        []string{"booking"},
+				Parameters: []parameter{
+					trackingIDParam,
+					queryParam("strategy", "How to rank the routes. Defaults to balanced.",
+						&schema{Type: "string", Enum: []string{"balanced", "fastest", "fewest_transshipments", "cheapest", "most_slack"}}),
+				},
+				Responses: responses(success("The routes that satisfy the route specification of the cargo, ordered by score.", object(map[string]*schema{
+					"routes": arrayOf(ref("RouteCandidate")),
+				}, "routes")), http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable),
+			},
+		},
+		"/booking/v1/cargos/{trackingID}/quote": {
+			"post": {
+				OperationID: "quoteRoute",
+				Summary:     "Price a route requested for a cargo. Assigning the route accepts the quote.",
+				Tags:        []string{"booking"},
+				Parameters:  []parameter{trackingIDParam},
+				RequestBody: jsonBody(closedObject(map[string]*schema{
+					"candidate_id": str("The ID of a route returned by requestRoutes for this cargo."),
+					"size":         {Type: "integer", Minimum: intPtr(1), Description: "Size of the cargo in twenty-foot equivalent units."},
+					"currency":     str("Currency of the quote. Defaults to the currency of the tariff."),
+				}, "candidate_id", "size")),
+				Responses: responses(success("The quote.", object(map[string]*schema{
+					"quote": ref("Quote"),
+				}, "quote")), http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+			},
+		},
+		"/booking/v1/cargos/{trackingID}/assign_to_route": {
+			"post": {
+				OperationID: "assignToRoute",
+				Summary:     "Assign a cargo to a route, given either in full or as the ID of a route requested for the cargo.",
+				Tags:        []string{"booking"},
+				Parameters:  []parameter{trackingIDParam},
+				RequestBody: jsonBody(closedObject(map[string]*schema{
+					"route":        ref("Itinerary"),
+					"candidate_id": str("The ID of a route returned by requestRoutes for this cargo. Exclusive with route."),
+				})),
+				Responses: responses(success("The cargo was assigned to the route.", nil),
+					http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+			},
+		},
+		"/booking/v1/cargos/{trackingID}/change_destination": {
+			"post": {
+				OperationID: "changeDestination",
+				Summary:     "Change the destination of a cargo.",
+				Tags:        []string{"booking"},
+				Parameters:  []parameter{trackingIDParam},
+				RequestBody: jsonBody(closedObject(map[string]*schema{
+					"destination": str("UN/LOCODE of the new destination."),
+				}, "destination")),
+				Responses: responses(success("The destination was changed.", nil),
+					http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity),
+			},
+		},
+		"/booking/v1/cargos/{trackingID}/change_arrival_deadline": {
+			"post": {
+				OperationID: "changeArrivalDeadline",
+				Summary:     "Change the arrival deadline of a cargo.",
+				Tags:        []string{"booking"},
+				Parameters:  []parameter{trackingIDParam},
+				RequestBody: jsonBody(closedObject(map[string]*schema{
+					"arrival_deadline": dateTime("The new arrival deadline."),
+				}, "arrival_deadline")),
+				Responses: responses(success("The arrival deadline was changed.", nil),
+					http.StatusBadRequest, http.StatusNotFound),
+			},
+		},
+		"/booking/v1/cargos/{trackingID}/change_origin": {
+			"post": {
+				OperationID: "changeOrigin",
+				Summary:     "Change where a cargo is picked up, until it has been received.",
+				Tags:        []string{"booking"},
+				Parameters:  []parameter{trackingIDParam},
+				RequestBody: jsonBody(closedObject(map[string]*schema{
+					"origin": str("UN/LOCODE of the new origin."),
+				}, "origin")),
+				Responses: responses(success("The origin was changed.", nil),
+					http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+			},
+		},
+		"/booking/v1/locations": {
+			"get": {
+				OperationID: "listLocations",
+				Summary:     "All registered locations.",
+				Tags:        []string{"booking"},
+				Responses: responses(success("The locations.", object(map[string]*schema{
+					"locations": arrayOf(ref("Location")),
+				}, "locations"))),
+			},
+		},
+		"/tracking/v1/cargos/{trackingID}": {
+			"get": {
+				OperationID: "trackCargo",
+				Summary:     "Track a cargo.",
+				Tags:        []string{"tracking"},
+				Parameters:  []parameter{trackingIDParam},
+				Responses: responses(success("The cargo.", object(map[string]*schema{
+					"cargo": ref("TrackedCargo"),
+				}, "cargo")), http.StatusBadRequest, http.StatusNotFound),
+			},
+		},
+		"/tracking/v1/cargos/{trackingID}/stream": {
+			"get": {
+				OperationID: "streamCargo",
+				Summary:     "Stream the tracking status of a cargo as server-sent events.",
+				Description: "Sends a cargo event with the current status, and another one every time the cargo is handled, routed, inspected or its booking is changed. " +
+					"Event IDs increase with every update. Reconnecting with Last-Event-ID skips the current status if it hasn't changed.",
+				Tags: []string{"tracking"},
+				Parameters: []parameter{
+					trackingIDParam,
+					{Name: "Last-Event-ID", In: "header", Description: "ID of the last event received.",
+						Schema: &schema{Type: "integer", Minimum: intPtr(0)}},
+				},
+				Responses: responses(response{
+					Description: "A stream of cargo events.",
+					Content:     map[string]mediaType{mediaTypeEventStream: {Schema: ref("TrackedCargo")}},
+				}, http.StatusBadRequest, http.StatusNotFound, http.StatusNotImplemented),
+			},
+		},
+		"/handling/v1/incidents": {
+			"post": {
+				OperationID: "registerIncident",
+				Summary:     "Register a handling incident.",
+				Tags:        []string{"handling"},
+				RequestBody: jsonBody(closedObject(map[string]*schema{
+					"completion_time": dateTime(""),
+					"tracking_id":     str(""),
+					"voyage":          str("Voyage number. Not needed for events that don't involve a voyage."),
+					"location":        str("UN/LOCODE of where the cargo was handled."),
+					"event_type": &schema{
+						Type: "string",
+						Enum: []string{"Receive", "Load", "Unload", "Customs", "Claim"},
+					},
+				}, "completion_time", "tracking_id", "location", "event_type")),
+				Responses: responses(success("The incident was registered.", nil),
+					http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity),
+			},
+		},
+		"/webhooks/v1/subscriptions": {
+			"get": {
+				OperationID: "listSubscriptions",
+				Summary:     "Webhook subscriptions.",
+				Tags:        []string{"webhooks"},
+				Parameters: []parameter{
+					queryParam("customer", "Only list the subscriptions of the given customer.", str("")),
+				},
+				Responses: responses(success("The subscriptions, without their secrets.", object(map[string]*schema{
+					"subscriptions": arrayOf(ref("Subscription")),
+				}, "subscriptions")), http.StatusNotImplemented),
+			},
+			"post": {
+				OperationID: "subscribe",
+				Summary:     "Register a callback URL for cargo events.",
+				Description: "Notifications are posted as JSON, signed in the X-Webhook-Signature header as t=<unix time>,v1=<HMAC-SHA256 of \"<unix time>.<body>\">, " +
+					"using the secret of the subscription. Failed notifications are retried with exponential backoff.",
+				Tags: []string{"webhooks"},
+				RequestBody: jsonBody(closedObject(map[string]*schema{
+					"customer":    str("The customer owning the subscription."),
+					"url":         str("Absolute HTTP(S) URL to post notifications to."),
+					"tracking_id": str("Only notify about the given cargo. Notifies about every cargo if missing."),
+					"events":      {Type: "array", Items: eventTypes, MinItems: 1},
+				}, "customer", "url", "events")),
+				Responses: responses(success("The subscription was created. The secret is only returned here.", object(map[string]*schema{
+					"subscription": ref("Subscription"),
+				}, "subscription")), http.StatusBadRequest, http.StatusNotFound, http.StatusNotImplemented),
+			},
+		},
+		"/webhooks/v1/subscriptions/{subscriptionID}": {
+			"get": {
+				OperationID: "loadSubscription",
+				Summary:     "A specific webhook subscription.",
+				Tags:        []string{"webhooks"},
+				Parameters:  []parameter{subscriptionIDParam},
+				Responses: responses(success("The subscription, without its secret.", object(map[string]*schema{
+					"subscription": ref("Subscription"),
+				}, "subscription")), http.StatusNotFound, http.StatusNotImplemented),
+			},
+			"delete": {
+				OperationID: "unsubscribe",
+				Summary:     "Remove a webhook subscription.",
+				Tags:        []string{"webhooks"},
+				Parameters:  []parameter{subscriptionIDParam},
+				Responses: responses(success("The subscription was removed.", nil),
+					http.StatusNotFound, http.StatusNotImplemented),
+			},
+		},
+		"/webhooks/v1/subscriptions/{subscriptionID}/deliveries": {
+			"get": {
+				OperationID: "listDeliveries",
+				Summary:     "The delivery log of a webhook subscription, oldest first.",
+				Tags:        []string{"webhooks"},
+				Parameters:  []parameter{subscriptionIDParam},
+				Responses: responses(success("The deliveries.", object(map[string]*schema{
+					"deliveries": arrayOf(ref("Delivery")),
+				}, "deliveries")), http.StatusNotFound, http.StatusNotImplemented),
+			},
+		},
+	},
+	Security: []map[string][]string{{"apiKey": {}}, {"bearer": {}}},
+	Components: components{
+		SecuritySchemes: map[string]securityScheme{
+			"apiKey": {Type: "apiKey", In: "header", Name: "X-API-Key", Description: "Static API key."},
+			"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "JWT with roles and locations claims."},
+		},
+		Schemas: map[string]*schema{
+			"Cargo": object(map[string]*schema{
+				"tracking_id":        str(""),
+				"origin":             str(""),
+				"destination":        str(""),
+				"arrival_deadline":   dateTime(""),
+				"earliest_departure": dateTime(""),
+				"max_legs":           {Type: "integer"},
+				"avoid_locations":    arrayOf(str("")),
+				"misrouted":          {Type: "boolean"},
+				"routed":             {Type: "boolean"},
+				"legs":               arrayOf(ref("Leg")),
+				"price":              ref("Money"),
+			}, "tracking_id", "origin", "destination", "arrival_deadline", "misrouted", "routed"),
+			"TrackedCargo": object(map[string]*schema{
+				"tracking_id":            str(""),
+				"status_text":            str(""),
+				"origin":                 str(""),
+				"destination":            str(""),
+				"eta":                    dateTime(""),
+				"next_expected_activity": str(""),
+				"arrival_deadline":       dateTime(""),
+				"events": arrayOf(object(map[string]*schema{
+					"description": str(""),
+					"expected":    {Type: "boolean"},
+				})),
+			}, "tracking_id", "status_text", "origin", "destination"),
+			"Itinerary": object(map[string]*schema{
+				"legs": {Type: "array", Items: ref("Leg"), MinItems: 1},
+			}, "legs"),
+			"RouteCandidate": object(map[string]*schema{
+				"id":          str("Assigns the cargo to this route until it expires."),
+				"tracking_id": str(""),
+				"expires_at":  dateTime(""),
+				"legs":        arrayOf(ref("Leg")),
+				"score":       {Type: "number", Description: "The sum of the scores of the costs. Lower is better."},
+				"costs": arrayOf(object(map[string]*schema{
+					"criterion": {Type: "string", Enum: []string{"transit_time", "transshipments", "leg_cost", "deadline_slack"}},
+					"value":     {Type: "number", Description: "Hours for transit_time and deadline_slack, a count for transshipments and the sum of leg costs for leg_cost."},
+					"weight":    {Type: "number"},
+					"score":     {Type: "number", Description: "The value times the weight."},
+				}, "criterion", "value", "weight", "score")),
+				"quote": ref("Quote"),
+			}, "id", "tracking_id", "expires_at", "legs", "score", "costs"),
+			"Quote": object(map[string]*schema{
+				"size": {Type: "integer"},
+				"lines": arrayOf(object(map[string]*schema{
+					"description": str(""),
+					"amount":      ref("Money"),
+				}, "description", "amount")),
+				"total": ref("Money"),
+			}, "size", "lines", "total"),
+			"Amendment": object(map[string]*schema{
+				"id":          str(""),
+				"tracking_id": str(""),
+				"type":        {Type: "string", Enum: []string{"destination_changed", "arrival_deadline_changed", "origin_changed", "route_assigned"}},
+				"actor":       str("Subject of the client that made the change. Missing if the client wasn't authenticated."),
+				"time":        dateTime(""),
+				"previous":    ref("Terms"),
+				"new":         ref("Terms"),
+			}, "id", "tracking_id", "type", "time", "previous", "new"),
+			"Terms": object(map[string]*schema{
+				"origin":           str(""),
+				"destination":      str(""),
+				"arrival_deadline": dateTime(""),
+				"legs":             arrayOf(ref("Leg")),
+				"price":            ref("Money"),
+			}, "origin", "destination", "arrival_deadline"),
+			"Money": object(map[string]*schema{
+				"amount":   {Type: "integer", Description: "Amount in minor units, e.g. cents."},
+				"currency": str("ISO 4217 currency code."),
+			}, "amount", "currency"),
+			"Leg": object(map[string]*schema{
+				"voyage_number": str(""),
+				"from":          str(""),
+				"to":            str(""),
+				"load_time":     dateTime(""),
+				"unload_time":   dateTime(""),
+			}, "voyage_number", "from", "to", "load_time", "unload_time"),
+			"Location": object(map[string]*schema{
+				"locode": str(""),
+				"name":   str(""),
+			}, "locode", "name"),
+			"Subscription": object(map[string]*schema{
+				"id":          str(""),
+				"url":         str(""),
+				"customer":    str(""),
+				"tracking_id": str(""),
+				"events":      arrayOf(eventTypes),
+				"created":     dateTime(""),
+				"secret":      str("Secret used to sign notifications."),
+			}, "id", "url", "customer", "events", "created"),
+			"Delivery": object(map[string]*schema{
+				"id":              str(""),
+				"subscription_id": str(""),
+				"event":           eventTypes,
+				"payload":         object(nil),
+				"status":          {Type: "string", Enum: []string{"pending", "delivered", "failed"}},
+				"next_attempt":    dateTime("When the next attempt is made. Missing unless pending."),
+				"created":         dateTime(""),
+				"attempts": arrayOf(object(map[string]*schema{
+					"time":        dateTime(""),
+					"status_code": {Type: "integer"},
+					"error":       str(""),
+					"duration_ns": {Type: "integer"},
+				}, "time", "duration_ns")),
+			}, "id", "subscription_id", "event", "payload", "status", "attempts", "created"),
+			"Problem": object(map[string]*schema{
+				"type":   str(""),
+				"title":  str(""),
+				"status": {Type: "integer"},
+				"detail": str(""),
+				"code":   str("Stable error code."),
+				"invalid_params": arrayOf(object(map[string]*schema{
+					"name":   str(""),
+					"reason": str(""),
+				}, "name", "reason")),
+			}, "type", "title", "status", "code"),
+		},
+	},
+}
+
+// find returns the operation that handles method and path, and the values
+// of its path parameters.
+func (d *document) find(method, path string) (*operation, map[string]string) {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+
+	for tmpl, item := range d.Paths {
+		op, ok := item[strings.ToLower(method)]
+		if !ok {
+			continue
+		}
+
+		tsegs := strings.Split(strings.Trim(tmpl, "/"), "/")
+		if len(tsegs) != len(segs) {
+			continue
+		}
+
+		params := make(map[string]string)
+		for i, t := range tsegs {
+			if strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}") && segs[i] != "" {
+				params[t[1:len(t)-1]] = segs[i]
+			} else if t != segs[i] {
+				params = nil
+				break
+			}
+		}
+		if params != nil {
+			return op, params
+		}
+	}
+
+	return nil, nil
+}
+
+// resolve follows the reference of s, if any.
+func (d *document) resolve(s *schema) *schema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, schemaPrefix)]
+	}
+	return s
+}
+
+func serveSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(spec)
+}
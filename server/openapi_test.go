@@ -0,0 +1,229 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/go-kit/kit/log"
+
+	"github.com/marcusolsson/goddd/auth"
+)
+
+// undocumented lists the routes that aren't part of the API.
+var undocumented = map[string]bool{
+	"GET /healthz":      true,
+	"GET /metrics":      true,
+	"GET /openapi.json": true,
+	"GET /readyz":       true,
+}
+
+func TestSpecMatchesRoutes(t *testing.T) {
+	s := New(nil, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	var routes []string
+	chi.Walk(s.router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.Replace(route, "/*", "", -1)
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		if r := method + " " + route; !undocumented[r] {
+			routes = append(routes, r)
+		}
+		return nil
+	})
+
+	var documented []string
+	for path, item := range spec.Paths {
+		for method := range item {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	sort.Strings(routes)
+	sort.Strings(documented)
+
+	if got, want := strings.Join(documented, "\n"), strings.Join(routes, "\n"); got != want {
+		t.Errorf("documented operations:\n%s\n\nwant:\n%s", got, want)
+	}
+}
+
+func TestSpecReferences(t *testing.T) {
+	var check func(where string, s *schema)
+	check = func(where string, s *schema) {
+		if s == nil {
+			return
+		}
+		if s.Ref != "" && spec.resolve(s) == nil {
+			t.Errorf("%s: unresolved reference %s", where, s.Ref)
+		}
+		check(where, s.Items)
+		for _, p := range s.Properties {
+			check(where, p)
+		}
+	}
+
+	for path, item := range spec.Paths {
+		for method, op := range item {
+			where := method + " " + path
+			for _, p := range op.Parameters {
+				check(where, p.Schema)
+			}
+			if op.RequestBody != nil {
+				for _, mt := range op.RequestBody.Content {
+					check(where, mt.Schema)
+				}
+			}
+			for _, r := range op.Responses {
+				for _, mt := range r.Content {
+					check(where, mt.Schema)
+				}
+			}
+		}
+	}
+	for name, s := range spec.Components.Schemas {
+		check(name, s)
+	}
+}
+
+func TestServeSpec(t *testing.T) {
+	h := New(nil, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	req, _ := http.NewRequest("GET", "http://example.com/openapi.json", nil)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rec.Code = %d; want = %d", rec.Code, http.StatusOK)
+	}
+
+	var doc map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["openapi"] != "3.0.0" {
+		t.Errorf("openapi = %v; want = %v", doc["openapi"], "3.0.0")
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	var reached bool
+	h := validateRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		if b, _ := ioutil.ReadAll(r.Body); len(b) == 0 && r.Method == "POST" {
+			t.Errorf("%s %s: body was consumed", r.Method, r.URL)
+		}
+	}))
+
+	for _, tt := range []struct {
+		method, url, body string
+		fields            []string
+	}{
+		{"GET", "/booking/v1/cargos?limit=10&sort=-arrival_deadline", "", nil},
+		{"GET", "/booking/v1/cargos?limit=0", "", []string{"limit"}},
+		{"GET", "/booking/v1/cargos?limit=ten&misrouted=maybe", "", []string{"misrouted", "limit"}},
+		{"GET", "/booking/v1/cargos?deadline_after=tomorrow", "", []string{"deadline_after"}},
+		{"GET", "/metrics", "", nil},
+		{"POST", "/booking/v1/cargos", `{"origin":"SESTO","destination":"FIHEL","arrival_deadline":"2016-03-21T19:50:24Z"}`, nil},
+		{"POST", "/booking/v1/cargos", `{"origin":"SESTO","destinaton":"FIHEL","arrival_deadline":"soon"}`, []string{"destination", "arrival_deadline", "destinaton"}},
+		{"POST", "/booking/v1/cargos/ABC123/assign_to_route", `{"route":{"legs":[]}}`, []string{"route.legs"}},
+		{"POST", "/booking/v1/cargos/ABC123/assign_to_route", `{"route":{"legs":[{"voyage_number":"V100","from":1}]}}`, []string{"route.legs[0].to", "route.legs[0].load_time", "route.legs[0].unload_time", "route.legs[0].from"}},
+		{"POST", "/handling/v1/incidents", `{"completion_time":"2016-03-21T19:50:24Z","tracking_id":"ABC123","location":"SESTO","event_type":"Lost"}`, []string{"event_type"}},
+		{"POST", "/handling/v1/incidents", ``, []string{"body"}},
+	} {
+		reached = false
+
+		req, _ := http.NewRequest(tt.method, "http://example.com"+tt.url, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		if tt.fields == nil {
+			if !reached {
+				t.Errorf("%s %s: rejected with %s", tt.method, tt.url, rec.Body)
+			}
+			continue
+		}
+
+		if reached {
+			t.Errorf("%s %s: request was not rejected", tt.method, tt.url)
+			continue
+		}
+
+		p := decodeProblem(t, rec)
+		var names []string
+		for _, f := range p.InvalidParams {
+			names = append(names, f.Name)
+		}
+		if got, want := strings.Join(names, ","), strings.Join(tt.fields, ","); got != want {
+			t.Errorf("%s %s: invalid_params = %s; want = %s", tt.method, tt.url, got, want)
+		}
+	}
+}
+
+func TestValidateRequestsContentType(t *testing.T) {
+	h := validateRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request was not rejected")
+	}))
+
+	req, _ := http.NewRequest("POST", "http://example.com/booking/v1/cargos/ABC123/change_destination", strings.NewReader(`destination=SESTO`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestValidateRequestsBodyTooLarge(t *testing.T) {
+	h := validateRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request was not rejected")
+	}))
+
+	body := `{"origin":"SESTO","destination":"FIHEL","padding":"` + strings.Repeat("x", maxRequestBodySize) + `"}`
+	req, _ := http.NewRequest("POST", "http://example.com/booking/v1/cargos", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestValidateRequestsAfterAuthentication(t *testing.T) {
+	keys := auth.NewAPIKeys(map[string]auth.Principal{
+		"secret": {Subject: "booking-desk", Roles: []auth.Role{auth.RoleAdmin}},
+	})
+
+	h := New(nil, nil, nil, log.NewLogfmtLogger(ioutil.Discard),
+		WithAuthentication(keys),
+		WithRequestValidation(),
+	)
+
+	req, _ := http.NewRequest("GET", "http://example.com/booking/v1/cargos?limit=0", nil)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	req.Header.Set("X-API-Key", "secret")
+	rec = httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// Error codes for errors that originate in the transport rather than in the
+// domain.
+const (
+	codeMalformedRequest shipping.ErrorCode = "malformed_request"
+	codeRequestTooLarge  shipping.ErrorCode = "request_too_large"
+	codeTimeout          shipping.ErrorCode = "timeout"
+	codeInternal         shipping.ErrorCode = "internal_error"
+	codeNotImplemented   shipping.ErrorCode = "not_implemented"
+)
+
+// problemTypePrefix is prepended to an error code to form the problem type.
+const problemTypePrefix = "urn:goddd:problem:"
+
+// problem is a RFC 7807 problem details object.
+type problem struct {
+	Type          string                `json:"type"`
+	Title         string                `json:"title"`
+	Status        int                   `json:"status"`
+	Detail        string                `json:"detail,omitempty"`
+	Code          shipping.ErrorCode    `json:"code"`
+	InvalidParams []shipping.FieldError `json:"invalid_params,omitempty"`
+}
+
+// statusCodes maps error codes to HTTP status codes. Codes not listed here
+// are considered client errors.
+var statusCodes = map[shipping.ErrorCode]int{
+	shipping.CodeInvalidArgument:       http.StatusBadRequest,
+	shipping.CodeInvalidCursor:         http.StatusBadRequest,
+	codeMalformedRequest:               http.StatusBadRequest,
+	codeRequestTooLarge:                http.StatusRequestEntityTooLarge,
+	shipping.CodeUnauthenticated:       http.StatusUnauthorized,
+	shipping.CodePermissionDenied:      http.StatusForbidden,
+	shipping.CodeUnknownCargo:          http.StatusNotFound,
+	shipping.CodeUnknownSubscription:   http.StatusNotFound,
+	shipping.CodeUnknownLocation:       http.StatusUnprocessableEntity,
+	shipping.CodeUnknownVoyage:         http.StatusUnprocessableEntity,
+	shipping.CodeUnknownRouteCandidate: http.StatusUnprocessableEntity,
+	shipping.CodeStaleRouteCandidate:   http.StatusConflict,
+	shipping.CodeCargoReceived:         http.StatusConflict,
+	shipping.CodeNoRate:                http.StatusUnprocessableEntity,
+	shipping.CodeRoutingUnavailable:    http.StatusServiceUnavailable,
+	shipping.CodeRateLimited:           http.StatusTooManyRequests,
+	codeTimeout:                        http.StatusGatewayTimeout,
+	codeInternal:                       http.StatusInternalServerError,
+	codeNotImplemented:                 http.StatusNotImplemented,
+}
+
+// newProblem translates err into the problem returned to the client. Errors
+// that aren't domain errors are reported as internal errors, without
+// revealing their message.
+func newProblem(err error) problem {
+	var e *shipping.Error
+	switch {
+	case errors.As(err, &e):
+	case errors.Is(err, context.DeadlineExceeded):
+		e = shipping.NewError(codeTimeout, "request timed out")
+	default:
+		e = shipping.NewError(codeInternal, "internal error")
+	}
+
+	status, ok := statusCodes[e.Code]
+	if !ok {
+		status = http.StatusBadRequest
+	}
+
+	return problem{
+		Type:          problemTypePrefix + string(e.Code),
+		Title:         http.StatusText(status),
+		Status:        status,
+		Detail:        e.Message,
+		Code:          e.Code,
+		InvalidParams: e.Fields,
+	}
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	p := newProblem(err)
+
+	w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// errNotImplemented is returned when a feature isn't enabled.
+var errNotImplemented = shipping.NewError(codeNotImplemented, "not implemented")
+
+// errMalformedRequest is returned when a request body can't be decoded.
+var errMalformedRequest = shipping.NewError(codeMalformedRequest, "malformed request body")
+
+// errRequestTooLarge is returned when a request body exceeds
+// maxRequestBodySize.
+var errRequestTooLarge = shipping.NewError(codeRequestTooLarge, "request body too large")
+
+// decodeRequest decodes the JSON body of r into v. Decoding errors are
+// reported as malformed requests, naming the offending field if possible.
+func decodeRequest(r *http.Request, v interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return nil
+	}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &typeErr):
+		return errMalformedRequest.WithFields(shipping.FieldError{
+			Name:   typeErr.Field,
+			Reason: fmt.Sprintf("unexpected %s", typeErr.Value),
+		})
+	case errors.As(err, &syntaxErr):
+		return errMalformedRequest.WithFields(shipping.FieldError{
+			Name:   "body",
+			Reason: fmt.Sprintf("invalid JSON at offset %d", syntaxErr.Offset),
+		})
+	case err == io.EOF:
+		return errMalformedRequest.WithFields(shipping.FieldError{
+			Name:   "body",
+			Reason: "is empty",
+		})
+	}
+
+	return errMalformedRequest.WithFields(shipping.FieldError{
+		Name:   "body",
+		Reason: err.Error(),
+	})
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/handling"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/mock"
+	"github.com/marcusolsson/goddd/tracking"
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+func TestNewProblem(t *testing.T) {
+	for _, tt := range []struct {
+		err    error
+		status int
+		code   shipping.ErrorCode
+	}{
+		{shipping.ErrUnknownCargo, http.StatusNotFound, shipping.CodeUnknownCargo},
+		{shipping.ErrUnknownLocation, http.StatusUnprocessableEntity, shipping.CodeUnknownLocation},
+		{shipping.ErrUnknownVoyage, http.StatusUnprocessableEntity, shipping.CodeUnknownVoyage},
+		{shipping.ErrInvalidCursor, http.StatusBadRequest, shipping.CodeInvalidCursor},
+		{fmt.Errorf("%w: connection refused", shipping.ErrRoutingUnavailable), http.StatusServiceUnavailable, shipping.CodeRoutingUnavailable},
+		{webhook.ErrUnknownSubscription, http.StatusNotFound, shipping.CodeUnknownSubscription},
+		{booking.ErrInvalidArgument, http.StatusBadRequest, shipping.CodeInvalidArgument},
+		{handling.ErrInvalidArgument, http.StatusBadRequest, shipping.CodeInvalidArgument},
+		{tracking.ErrInvalidArgument, http.StatusBadRequest, shipping.CodeInvalidArgument},
+		{errMalformedRequest, http.StatusBadRequest, codeMalformedRequest},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, codeTimeout},
+		{errors.New("connection refused"), http.StatusInternalServerError, codeInternal},
+	} {
+		p := newProblem(tt.err)
+		if p.Status != tt.status {
+			t.Errorf("%v: Status = %d; want = %d", tt.err, p.Status, tt.status)
+		}
+		if p.Code != tt.code {
+			t.Errorf("%v: Code = %s; want = %s", tt.err, p.Code, tt.code)
+		}
+		if want := problemTypePrefix + string(tt.code); p.Type != want {
+			t.Errorf("%v: Type = %s; want = %s", tt.err, p.Type, want)
+		}
+	}
+}
+
+func TestMalformedRequest(t *testing.T) {
+	s := booking.NewService(inmem.NewCargoRepository(), inmem.NewLocationRepository(), nil, nil, nil, nil)
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	for _, tt := range []struct {
+		body  string
+		field string
+	}{
+		{``, "body"},
+		{`{"origin":`, "body"},
+		{`{"origin":42}`, "origin"},
+	} {
+		req, _ := http.NewRequest("POST", "http://example.com/booking/v1/cargos", strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		p := decodeProblem(t, rec)
+		if p.Status != http.StatusBadRequest || rec.Code != http.StatusBadRequest {
+			t.Errorf("%q: status = %d, rec.Code = %d; want = %d", tt.body, p.Status, rec.Code, http.StatusBadRequest)
+		}
+		if p.Code != codeMalformedRequest {
+			t.Errorf("%q: code = %s; want = %s", tt.body, p.Code, codeMalformedRequest)
+		}
+		if len(p.InvalidParams) != 1 || p.InvalidParams[0].Name != tt.field {
+			t.Errorf("%q: invalid_params = %v; want field %s", tt.body, p.InvalidParams, tt.field)
+		}
+	}
+}
+
+func TestInvalidArgumentFields(t *testing.T) {
+	s := booking.NewService(inmem.NewCargoRepository(), inmem.NewLocationRepository(), nil, nil, nil, nil)
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	req, _ := http.NewRequest("POST", "http://example.com/booking/v1/cargos", strings.NewReader(`{"origin":"SESTO"}`))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	p := decodeProblem(t, rec)
+	if p.Code != shipping.CodeInvalidArgument {
+		t.Errorf("code = %s; want = %s", p.Code, shipping.CodeInvalidArgument)
+	}
+
+	var names []string
+	for _, f := range p.InvalidParams {
+		names = append(names, f.Name)
+	}
+	if got, want := strings.Join(names, ","), "destination,arrival_deadline"; got != want {
+		t.Errorf("invalid_params = %s; want = %s", got, want)
+	}
+}
+
+func TestUnknownLocation(t *testing.T) {
+	ctx := context.Background()
+
+	cargos := inmem.NewCargoRepository()
+	cargos.Store(ctx, shipping.NewCargo("ABC123", shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.AUMEL,
+	}))
+
+	s := booking.NewService(cargos, inmem.NewLocationRepository(), nil, nil, nil, nil)
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	req, _ := http.NewRequest("POST", "http://example.com/booking/v1/cargos/ABC123/change_destination", strings.NewReader(`{"destination":"XXXXX"}`))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	p := decodeProblem(t, rec)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	if p.Code != shipping.CodeUnknownLocation {
+		t.Errorf("code = %s; want = %s", p.Code, shipping.CodeUnknownLocation)
+	}
+}
+
+func TestRoutingUnavailable(t *testing.T) {
+	ctx := context.Background()
+
+	cargos := inmem.NewCargoRepository()
+	cargos.Store(ctx, shipping.NewCargo("ABC123", shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.AUMEL,
+	}))
+
+	rs := &mock.RoutingService{
+		FetchRoutesFn: func(shipping.RouteSpecification) ([]shipping.Itinerary, error) {
+			return nil, fmt.Errorf("%w: circuit open", shipping.ErrRoutingUnavailable)
+		},
+	}
+
+	s := booking.NewService(cargos, inmem.NewLocationRepository(), nil, rs, inmem.NewRouteCandidateRepository(), inmem.NewAmendmentRepository())
+
+	h := New(s, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	req, _ := http.NewRequest("GET", "http://example.com/booking/v1/cargos/ABC123/request_routes", nil)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	p := decodeProblem(t, rec)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if p.Code != shipping.CodeRoutingUnavailable {
+		t.Errorf("code = %s; want = %s", p.Code, shipping.CodeRoutingUnavailable)
+	}
+}
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) problem {
+	if got, want := rec.Header().Get("Content-Type"), "application/problem+json; charset=utf-8"; got != want {
+		t.Errorf("Content-Type = %q; want = %q", got, want)
+	}
+
+	var p problem
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
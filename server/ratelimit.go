@@ -0,0 +1,67 @@
+package server
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/marcusolsson/goddd/auth"
+	"github.com/marcusolsson/goddd/ratelimit"
+)
+
+// clientKey identifies the client of a request by its kind and a key. An
+// empty kind means the request isn't limited.
+type clientKey func(r *http.Request) (kind, key string)
+
+// rateLimit returns a middleware that applies the rate limit of the named
+// router, if any, to each client as identified by client. Requests from
+// clients that exceed their rate are rejected with 429 Too Many Requests,
+// telling them when to retry.
+func (s *Server) rateLimit(router string, client clientKey) func(http.Handler) http.Handler {
+	limit, ok := s.RateLimits[router]
+	if !ok || limit.Rate == 0 {
+		return func(h http.Handler) http.Handler { return h }
+	}
+
+	l := ratelimit.NewLimiter(limit)
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			kind, key := client(r)
+			if kind == "" {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			if d := l.Reserve(kind + ":" + key); d > 0 {
+				if s.ThrottledRequests != nil {
+					s.ThrottledRequests.With("router", router, "client", kind).Add(1)
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+				encodeError(r.Context(), ratelimit.ErrRateLimited, w)
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// byIP identifies clients by IP address.
+func byIP(r *http.Request) (kind, key string) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip", host
+}
+
+// byClient identifies clients by the principal they authenticated as, or by
+// IP address if they didn't.
+func byClient(r *http.Request) (kind, key string) {
+	if p, ok := auth.FromContext(r.Context()); ok && !p.Anonymous {
+		return "principal", p.Subject
+	}
+	return byIP(r)
+}
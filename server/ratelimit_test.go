@@ -0,0 +1,165 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+
+	"github.com/marcusolsson/goddd/auth"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/ratelimit"
+	"github.com/marcusolsson/goddd/tracking"
+)
+
+func TestRateLimit(t *testing.T) {
+	ts := tracking.NewService(inmem.NewCargoRepository(), inmem.NewHandlingEventRepository())
+
+	var throttled counter
+
+	h := New(nil, ts, nil, log.NewLogfmtLogger(ioutil.Discard), WithRateLimits(ratelimit.Limits{
+		"tracking": {Rate: 0.001, Burst: 2},
+	}, &throttled))
+
+	get := func(path, remoteAddr, key string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "http://example.com"+path, nil)
+		req.RemoteAddr = remoteAddr
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := get("/tracking/v1/cargos/ABC123", "10.0.0.1:1234", ""); rec.Code != http.StatusNotFound {
+			t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusNotFound)
+		}
+	}
+
+	rec := get("/tracking/v1/cargos/ABC123", "10.0.0.1:5678", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("rec.Code = %d; want = %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if ra := rec.Header().Get("Retry-After"); ra != "1000" {
+		t.Errorf("Retry-After = %q; want = %q", ra, "1000")
+	}
+	if throttled.value != 1 {
+		t.Errorf("throttled = %v; want = %v", throttled.value, 1)
+	}
+	if got, want := strings.Join(throttled.labelValues, ","), "router,tracking,client,ip"; got != want {
+		t.Errorf("labelValues = %s; want = %s", got, want)
+	}
+
+	// Unverified API keys don't get a bucket of their own.
+	if rec := get("/tracking/v1/cargos/ABC123", "10.0.0.1:1234", "key"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusTooManyRequests)
+	}
+
+	// Other clients and routers have their own limits.
+	if rec := get("/tracking/v1/cargos/ABC123", "10.0.0.2:1234", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := get("/openapi.json", "10.0.0.1:1234", ""); rec.Code != http.StatusOK {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestRateLimitByPrincipal(t *testing.T) {
+	ts := tracking.NewService(inmem.NewCargoRepository(), inmem.NewHandlingEventRepository())
+
+	keys := auth.NewAPIKeys(map[string]auth.Principal{
+		"secret": {Subject: "tracker", Roles: []auth.Role{auth.RolePublic}},
+	})
+
+	h := New(nil, ts, nil, log.NewLogfmtLogger(ioutil.Discard),
+		WithAuthentication(keys, auth.RolePublic),
+		WithRateLimits(ratelimit.Limits{"tracking": {Rate: 0.001, Burst: 2}}, nil),
+	)
+
+	get := func(remoteAddr, key string) int {
+		req, _ := http.NewRequest("GET", "http://example.com/tracking/v1/cargos/ABC123", nil)
+		req.RemoteAddr = remoteAddr
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 2; i++ {
+		if code := get("10.0.0.1:1234", ""); code != http.StatusNotFound {
+			t.Errorf("code = %d; want = %d", code, http.StatusNotFound)
+		}
+	}
+	if code := get("10.0.0.1:1234", ""); code != http.StatusTooManyRequests {
+		t.Errorf("code = %d; want = %d", code, http.StatusTooManyRequests)
+	}
+
+	// Authenticated clients are only limited by principal, wherever they
+	// call from.
+	for _, addr := range []string{"10.0.0.1:1234", "10.0.0.2:1234"} {
+		if code := get(addr, "secret"); code != http.StatusNotFound {
+			t.Errorf("code = %d; want = %d", code, http.StatusNotFound)
+		}
+	}
+	if code := get("10.0.0.3:1234", "secret"); code != http.StatusTooManyRequests {
+		t.Errorf("code = %d; want = %d", code, http.StatusTooManyRequests)
+	}
+}
+
+func TestRateLimitBeforeAuthentication(t *testing.T) {
+	ts := tracking.NewService(inmem.NewCargoRepository(), inmem.NewHandlingEventRepository())
+
+	keys := auth.NewAPIKeys(map[string]auth.Principal{
+		"secret": {Subject: "tracker", Roles: []auth.Role{auth.RolePublic}},
+	})
+
+	var throttled counter
+
+	h := New(nil, ts, nil, log.NewLogfmtLogger(ioutil.Discard),
+		WithAuthentication(keys),
+		WithRateLimits(ratelimit.Limits{ratelimit.Auth: {Rate: 0.001, Burst: 2}}, &throttled),
+	)
+
+	get := func(key string) int {
+		req, _ := http.NewRequest("GET", "http://example.com/tracking/v1/cargos/ABC123", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for _, key := range []string{"guess1", "guess2"} {
+		if code := get(key); code != http.StatusUnauthorized {
+			t.Errorf("code = %d; want = %d", code, http.StatusUnauthorized)
+		}
+	}
+	if code := get("guess3"); code != http.StatusTooManyRequests {
+		t.Errorf("code = %d; want = %d", code, http.StatusTooManyRequests)
+	}
+	if got, want := strings.Join(throttled.labelValues, ","), "router,auth,client,ip"; got != want {
+		t.Errorf("labelValues = %s; want = %s", got, want)
+	}
+}
+
+// counter is a metrics.Counter that records the label values it was last
+// used with.
+type counter struct {
+	value       float64
+	labelValues []string
+}
+
+func (c *counter) With(labelValues ...string) metrics.Counter {
+	c.labelValues = labelValues
+	return c
+}
+
+func (c *counter) Add(delta float64) { c.value += delta }
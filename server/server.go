@@ -2,57 +2,212 @@ package server
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi"
 	kitlog "github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
 
-	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/auth"
 	"github.com/marcusolsson/goddd/booking"
 	"github.com/marcusolsson/goddd/handling"
+	"github.com/marcusolsson/goddd/ratelimit"
 	"github.com/marcusolsson/goddd/tracking"
+	"github.com/marcusolsson/goddd/webhook"
 )
 
+// requestTimeout bounds the time spent on a single request, including the
+// calls it makes to the repositories and the routing service. Streams aren't
+// bounded.
+const requestTimeout = 30 * time.Second
+
 // Server holds the dependencies for a HTTP server.
 type Server struct {
 	Booking  booking.Service
 	Tracking tracking.Service
 	Handling handling.Service
 
+	// TrackingUpdates, if set, enables streaming of tracking updates.
+	TrackingUpdates *tracking.Broker
+
+	// Webhooks, if set, enables the webhook subscription API.
+	Webhooks webhook.Service
+
+	// Authenticator, if set, requires clients to authenticate. Clients
+	// without credentials are given the AnonymousRoles.
+	Authenticator  auth.Authenticator
+	AnonymousRoles []auth.Role
+
+	// AllowedOrigins lists the origins allowed to make cross-origin
+	// requests. All origins are allowed if it's empty.
+	AllowedOrigins []string
+
+	// RateLimits limits the rate of requests of each client to the routers,
+	// by principal once authenticated and by IP address otherwise, and by IP
+	// address before authentication with the ratelimit.Auth limit.
+	// ThrottledRequests, if set, counts the rejected requests by router and
+	// kind of client.
+	RateLimits        ratelimit.Limits
+	ThrottledRequests metrics.Counter
+
+	// ValidateRequests, if set, rejects requests to the routers that don't
+	// match the OpenAPI specification, once they're authenticated and
+	// within their rate limit.
+	ValidateRequests bool
+
+	// ReadinessChecks are run by /readyz.
+	ReadinessChecks map[string]HealthCheck
+
+	// Tracer, if set, starts a span for every request.
+	Tracer trace.Tracer
+
+	// MetricsPath is where Prometheus metrics are served. They aren't
+	// served if it's empty.
+	MetricsPath string
+
 	Logger kitlog.Logger
 
-	router chi.Router
+	router    chi.Router
+	draining  chan struct{}
+	drainOnce sync.Once
+}
+
+// Option configures optional features of a Server.
+type Option func(*Server)
+
+// WithTrackingUpdates streams tracking updates published by b.
+func WithTrackingUpdates(b *tracking.Broker) Option {
+	return func(s *Server) { s.TrackingUpdates = b }
+}
+
+// WithWebhooks serves the webhook subscription API backed by ws.
+func WithWebhooks(ws webhook.Service) Option {
+	return func(s *Server) { s.Webhooks = ws }
+}
+
+// WithAuthentication requires clients to authenticate with a, and authorizes
+// them by role: booking and webhooks require RoleAdmin, handling requires
+// RoleHandler and tracking requires RolePublic. Clients without credentials
+// are given the anonymous roles.
+func WithAuthentication(a auth.Authenticator, anonymous ...auth.Role) Option {
+	return func(s *Server) {
+		s.Authenticator = a
+		s.AnonymousRoles = anonymous
+	}
+}
+
+// WithAllowedOrigins only allows cross-origin requests from the given
+// origins.
+func WithAllowedOrigins(origins ...string) Option {
+	return func(s *Server) { s.AllowedOrigins = origins }
+}
+
+// WithRateLimits limits the rate of requests of each client to the routers,
+// by principal once authenticated and by IP address otherwise. The
+// ratelimit.Auth limit applies by IP address to all requests before
+// authentication. Throttled requests are counted by throttled, which may be
+// nil.
+func WithRateLimits(limits ratelimit.Limits, throttled metrics.Counter) Option {
+	return func(s *Server) {
+		s.RateLimits = limits
+		s.ThrottledRequests = throttled
+	}
+}
+
+// WithRequestValidation rejects requests to the routers that don't match the
+// OpenAPI specification, once they're authenticated and within their rate
+// limit. Request bodies larger than 1 MiB are rejected with 413 Request
+// Entity Too Large.
+func WithRequestValidation() Option {
+	return func(s *Server) { s.ValidateRequests = true }
+}
+
+// WithTracer traces requests with t. Traces propagated by clients are
+// continued, using the global propagator.
+func WithTracer(t trace.Tracer) Option {
+	return func(s *Server) { s.Tracer = t }
+}
+
+// WithMetricsPath serves Prometheus metrics at path instead of /metrics, or
+// not at all if path is empty.
+func WithMetricsPath(path string) Option {
+	return func(s *Server) { s.MetricsPath = path }
 }
 
 // New returns a new HTTP server.
-func New(bs booking.Service, ts tracking.Service, hs handling.Service, logger kitlog.Logger) *Server {
+func New(bs booking.Service, ts tracking.Service, hs handling.Service, logger kitlog.Logger, opts ...Option) *Server {
 	s := &Server{
 		Booking:  bs,
 		Tracking: ts,
 		Handling: hs,
 		Logger:   logger,
+
+		MetricsPath: "/metrics",
+
+		draining: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
 
 	r := chi.NewRouter()
 
-	r.Use(accessControl)
+	r.Use(s.traceRequests)
+	r.Use(s.accessControl)
+
+	limitAuth := s.rateLimit(ratelimit.Auth, byIP)
 
 	r.Route("/booking", func(r chi.Router) {
+		r.Use(limitAuth)
+		r.Use(s.authenticate)
+		r.Use(s.rateLimit("booking", byClient))
+		r.Use(timeout(requestTimeout))
+		r.Use(s.require(auth.RoleAdmin))
+		r.Use(bookingActor)
+		r.Use(s.validate)
 		h := bookingHandler{s.Booking, s.Logger}
 		r.Mount("/v1", h.router())
 	})
 	r.Route("/tracking", func(r chi.Router) {
-		h := trackingHandler{s.Tracking, s.Logger}
+		r.Use(limitAuth)
+		r.Use(s.authenticate)
+		r.Use(s.rateLimit("tracking", byClient))
+		r.Use(s.require(auth.RolePublic, auth.RoleHandler))
+		r.Use(s.validate)
+		h := trackingHandler{s.Tracking, s.TrackingUpdates, s.draining, s.Logger}
 		r.Mount("/v1", h.router())
 	})
 	r.Route("/handling", func(r chi.Router) {
+		r.Use(limitAuth)
+		r.Use(s.authenticate)
+		r.Use(s.rateLimit("handling", byClient))
+		r.Use(timeout(requestTimeout))
+		r.Use(s.require(auth.RoleHandler))
+		r.Use(s.validate)
 		h := handlingHandler{s.Handling, s.Logger}
 		r.Mount("/v1", h.router())
 	})
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Use(limitAuth)
+		r.Use(s.authenticate)
+		r.Use(s.rateLimit("webhooks", byClient))
+		r.Use(timeout(requestTimeout))
+		r.Use(s.require(auth.RoleAdmin))
+		r.Use(s.validate)
+		h := webhookHandler{s.Webhooks, s.Logger}
+		r.Mount("/v1", h.router())
+	})
 
-	r.Method("GET", "/metrics", promhttp.Handler())
+	r.Get("/healthz", s.healthz)
+	r.Get("/readyz", s.readyz)
+	r.Get("/openapi.json", serveSpec)
+	if s.MetricsPath != "" {
+		r.Method("GET", s.MetricsPath, promhttp.Handler())
+	}
 
 	s.router = r
 
@@ -63,11 +218,18 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.ServeHTTP(w, r)
 }
 
-func accessControl(h http.Handler) http.Handler {
+func (s *Server) accessControl(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type")
+		if len(s.AllowedOrigins) == 0 {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Add("Vary", "Origin")
+			if origin := r.Header.Get("Origin"); contains(s.AllowedOrigins, origin) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-API-Key, Last-Event-ID")
 
 		if r.Method == "OPTIONS" {
 			return
@@ -77,17 +239,21 @@ func accessControl(h http.Handler) http.Handler {
 	})
 }
 
-func encodeError(_ context.Context, err error, w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	switch err {
-	case shipping.ErrUnknownCargo:
-		w.WriteHeader(http.StatusNotFound)
-	case tracking.ErrInvalidArgument:
-		w.WriteHeader(http.StatusBadRequest)
-	default:
-		w.WriteHeader(http.StatusInternalServerError)
+// validate validates requests if ValidateRequests is set.
+func (s *Server) validate(h http.Handler) http.Handler {
+	if !s.ValidateRequests {
+		return h
+	}
+	return validateRequests(h)
+}
+
+func timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
 	}
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"error": err.Error(),
-	})
 }
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/marcusolsson/goddd/tracking"
+)
+
+type deadlineTrackingService struct {
+	hasDeadline bool
+}
+
+func (s *deadlineTrackingService) Track(ctx context.Context, id string) (tracking.Cargo, error) {
+	_, s.hasDeadline = ctx.Deadline()
+	return tracking.Cargo{TrackingID: id}, nil
+}
+
+func TestRequestTimeout(t *testing.T) {
+	var ts deadlineTrackingService
+
+	h := New(nil, &ts, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	req, _ := http.NewRequest("GET", "http://example.com/tracking/v1/cargos/TEST", nil)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusOK)
+	}
+	if !ts.hasDeadline {
+		t.Errorf("request context has no deadline")
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/tracking"
+)
+
+func TestTraceRequests(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	tracer := tp.Tracer("test")
+
+	cargos := inmem.NewCargoRepository()
+	cargos.Store(context.Background(), shipping.NewCargo("TEST", shipping.RouteSpecification{
+		Origin:          shipping.SESTO,
+		Destination:     shipping.FIHEL,
+		ArrivalDeadline: time.Date(2005, 12, 4, 0, 0, 0, 0, time.UTC),
+	}))
+
+	ts := tracking.NewTracingService(tracer, tracking.NewService(cargos, inmem.NewHandlingEventRepository()))
+
+	h := New(nil, ts, nil, log.NewLogfmtLogger(ioutil.Discard), WithTracer(tracer))
+
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+
+	req, _ := http.NewRequest("GET", "http://example.com/tracking/v1/cargos/TEST", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rec.Code = %d; want = %d", rec.Code, http.StatusOK)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("len(spans) = %d; want = %d", len(ended), 2)
+	}
+
+	service, request := ended[0], ended[1]
+
+	if want := "GET /tracking/v1/cargos/{trackingID}"; request.Name() != want {
+		t.Errorf("request.Name() = %q; want = %q", request.Name(), want)
+	}
+	if got := request.Parent().SpanID().String(); got != parentID {
+		t.Errorf("request.Parent().SpanID() = %s; want = %s", got, parentID)
+	}
+	if got := request.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("request.SpanContext().TraceID() = %s; want = %s", got, traceID)
+	}
+	if !hasAttribute(request.Attributes(), attribute.Int("http.response.status_code", http.StatusOK)) {
+		t.Errorf("request.Attributes() = %v; want status code", request.Attributes())
+	}
+
+	if service.Name() != "tracking.Track" {
+		t.Errorf("service.Name() = %q; want = %q", service.Name(), "tracking.Track")
+	}
+	if service.Parent().SpanID() != request.SpanContext().SpanID() {
+		t.Errorf("service span isn't a child of the request span")
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
@@ -1,35 +1,46 @@
 package server
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi"
 	kitlog "github.com/go-kit/kit/log"
 
+	shipping "github.com/marcusolsson/goddd"
 	"github.com/marcusolsson/goddd/tracking"
 )
 
+// heartbeatInterval is how often a comment is sent on idle streams, to keep
+// intermediaries from closing the connection.
+const heartbeatInterval = 15 * time.Second
+
 type trackingHandler struct {
-	s tracking.Service
+	s       tracking.Service
+	updates *tracking.Broker
+
+	// done is closed when the server is draining, to end open streams.
+	done <-chan struct{}
 
 	logger kitlog.Logger
 }
 
 func (h *trackingHandler) router() chi.Router {
 	r := chi.NewRouter()
-	r.Get("/cargos/{trackingID}", h.track)
-	r.Method("GET", "/docs", http.StripPrefix("/tracking/v1/docs", http.FileServer(http.Dir("tracking/docs"))))
+	r.With(timeout(requestTimeout)).Get("/cargos/{trackingID}", h.track)
+	r.Get("/cargos/{trackingID}/stream", h.stream)
 	return r
 }
 
 func (h *trackingHandler) track(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 
 	trackingID := chi.URLParam(r, "trackingID")
 
-	c, err := h.s.Track(trackingID)
+	c, err := h.s.Track(ctx, trackingID)
 	if err != nil {
 		encodeError(ctx, err, w)
 		return
@@ -46,3 +57,111 @@ func (h *trackingHandler) track(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// stream pushes the tracking status of a cargo as server-sent events, every
+// time its delivery is re-derived. The ID of each event is the ID of the
+// latest update of the cargo, so a client reconnecting with Last-Event-ID
+// only receives the status if it has changed since.
+func (h *trackingHandler) stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if h.updates == nil {
+		encodeError(ctx, errNotImplemented, w)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		encodeError(ctx, errNotImplemented, w)
+		return
+	}
+
+	trackingID := chi.URLParam(r, "trackingID")
+
+	var (
+		lastEventID uint64
+		resume      bool
+	)
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			encodeError(ctx, tracking.ErrInvalidArgument.WithFields(shipping.FieldError{
+				Name:   "Last-Event-ID",
+				Reason: "must be a non-negative integer",
+			}), w)
+			return
+		}
+		lastEventID, resume = n, true
+	}
+
+	// Subscribe and read the update ID before reading the current status,
+	// so that no update is missed in between.
+	updates, cancel := h.updates.Subscribe(shipping.TrackingID(trackingID))
+	defer cancel()
+
+	version := h.updates.Version(shipping.TrackingID(trackingID))
+
+	c, err := h.s.Track(ctx, trackingID)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	send := func(c tracking.Cargo, id uint64) error {
+		if resume && id <= lastEventID {
+			return nil
+		}
+
+		data, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: cargo\ndata: %s\n\n", id, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+
+		lastEventID, resume = id, true
+		return nil
+	}
+
+	if err := send(c, version); err != nil {
+		h.logger.Log("error", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-updates:
+			version := h.updates.Version(shipping.TrackingID(trackingID))
+
+			c, err := h.s.Track(ctx, trackingID)
+			if err != nil {
+				h.logger.Log("error", err)
+				return
+			}
+			if err := send(c, version); err != nil {
+				h.logger.Log("error", err)
+				return
+			}
+		}
+	}
+}
@@ -1,17 +1,22 @@
 package server
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-kit/kit/log"
 
 	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/inmem"
 	"github.com/marcusolsson/goddd/mock"
 	"github.com/marcusolsson/goddd/tracking"
 )
@@ -32,7 +37,7 @@ func TestTrackCargo(t *testing.T) {
 		ArrivalDeadline: time.Date(2005, 12, 4, 0, 0, 0, 0, time.UTC),
 	})
 
-	cargos.Store(c)
+	cargos.Store(context.Background(), c)
 
 	logger := log.NewLogfmtLogger(ioutil.Discard)
 
@@ -104,7 +109,7 @@ func TestTrackUnknownCargo(t *testing.T) {
 		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusNotFound)
 	}
 
-	wantContent := "application/json; charset=utf-8"
+	wantContent := "application/problem+json; charset=utf-8"
 	if got := rec.Header().Get("Content-Type"); got != wantContent {
 		t.Errorf("Content-Type = %q; want = %q", got, wantContent)
 	}
@@ -114,31 +119,148 @@ func TestTrackUnknownCargo(t *testing.T) {
 		t.Error(err)
 	}
 
-	err, ok := response["error"]
-	if !ok {
-		t.Error("missing error")
+	if code := response["code"]; code != "unknown_cargo" {
+		t.Errorf(`"code": %q; want = %q`, code, "unknown_cargo")
 	}
-	if err != "unknown cargo" {
-		t.Errorf(`"error": %q; want = %q`, err, "unknown cargo")
+	if status := response["status"]; status != float64(http.StatusNotFound) {
+		t.Errorf(`"status": %v; want = %v`, status, http.StatusNotFound)
+	}
+	if detail := response["detail"]; detail != "unknown cargo" {
+		t.Errorf(`"detail": %q; want = %q`, detail, "unknown cargo")
+	}
+}
+
+func TestStreamCargo(t *testing.T) {
+	ctx := context.Background()
+
+	cargos := inmem.NewCargoRepository()
+	events := inmem.NewHandlingEventRepository()
+
+	cargos.Store(ctx, shipping.NewCargo("TEST", shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.FIHEL,
+	}))
+
+	broker := tracking.NewBroker()
+
+	h := New(nil, tracking.NewService(cargos, events), nil, log.NewLogfmtLogger(ioutil.Discard), WithTrackingUpdates(broker))
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	handle := func() {
+		e := shipping.HandlingEvent{
+			TrackingID: "TEST",
+			Activity:   shipping.HandlingActivity{Type: shipping.Receive, Location: shipping.SESTO},
+		}
+		events.Store(ctx, e)
+		broker.CargoWasHandled(ctx, e)
+	}
+
+	// A new client receives the current status right away.
+	ids, stop := streamEvents(t, srv.URL+"/tracking/v1/cargos/TEST/stream", "")
+	first := <-ids
+
+	handle()
+	handled := <-ids
+	if handled <= first {
+		t.Errorf("id = %d; want > %d", handled, first)
+	}
+	stop()
+
+	// A reconnecting client only receives statuses it hasn't seen, also
+	// when the delivery is re-derived without being handled.
+	ids, stop = streamEvents(t, srv.URL+"/tracking/v1/cargos/TEST/stream", strconv.FormatUint(handled, 10))
+	defer stop()
+
+	c, _ := cargos.Find(ctx, "TEST")
+	c.SpecifyNewRoute(shipping.RouteSpecification{Origin: shipping.SESTO, Destination: shipping.AUMEL})
+	cargos.Store(ctx, c)
+	broker.CargoWasAmended(ctx, c)
+
+	if id := <-ids; id <= handled {
+		t.Errorf("id = %d; want > %d", id, handled)
+	}
+}
+
+func TestStreamUnknownCargo(t *testing.T) {
+	s := tracking.NewService(inmem.NewCargoRepository(), inmem.NewHandlingEventRepository())
+
+	h := New(nil, s, nil, log.NewLogfmtLogger(ioutil.Discard), WithTrackingUpdates(tracking.NewBroker()))
+
+	req, _ := http.NewRequest("GET", "http://example.com/tracking/v1/cargos/not_found/stream", nil)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusNotFound)
 	}
 }
 
+// streamEvents connects to a event stream and sends the IDs of the cargo
+// events it receives on the returned channel.
+func streamEvents(t *testing.T, url, lastEventID string) (<-chan uint64, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req, _ := http.NewRequest("GET", url, nil)
+	req = req.WithContext(ctx)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resp.Header.Get("Content-Type"), "text/event-stream"; got != want {
+		t.Fatalf("Content-Type = %q; want = %q", got, want)
+	}
+
+	ids := make(chan uint64)
+	go func() {
+		defer resp.Body.Close()
+
+		s := bufio.NewScanner(resp.Body)
+		for s.Scan() {
+			if strings.HasPrefix(s.Text(), "id: ") {
+				id, err := strconv.ParseUint(strings.TrimPrefix(s.Text(), "id: "), 10, 64)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				select {
+				case ids <- id:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ids, cancel
+}
+
 type mockCargoRepository struct {
 	cargo *shipping.Cargo
 }
 
-func (r *mockCargoRepository) Store(c *shipping.Cargo) error {
+func (r *mockCargoRepository) Store(_ context.Context, c *shipping.Cargo) error {
 	r.cargo = c
 	return nil
 }
 
-func (r *mockCargoRepository) Find(id shipping.TrackingID) (*shipping.Cargo, error) {
+func (r *mockCargoRepository) Find(_ context.Context, id shipping.TrackingID) (*shipping.Cargo, error) {
 	if r.cargo != nil {
 		return r.cargo, nil
 	}
 	return nil, shipping.ErrUnknownCargo
 }
 
-func (r *mockCargoRepository) FindAll() []*shipping.Cargo {
+func (r *mockCargoRepository) FindAll(_ context.Context) []*shipping.Cargo {
 	return []*shipping.Cargo{r.cargo}
 }
+
+func (r *mockCargoRepository) Query(_ context.Context, q shipping.CargoQuery) (shipping.CargoPage, error) {
+	return q.Apply([]*shipping.Cargo{r.cargo})
+}
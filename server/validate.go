@@ -0,0 +1,265 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// errSpecMismatch is returned when a request doesn't match the OpenAPI
+// specification.
+var errSpecMismatch = shipping.NewError(shipping.CodeInvalidArgument, "request does not match the API specification")
+
+// maxRequestBodySize is the largest request body that's read for validation.
+const maxRequestBodySize = 1 << 20
+
+// validateRequests returns a handler that rejects requests to documented
+// operations that don't match the OpenAPI specification, before passing them
+// on to h. Requests to undocumented paths are passed on as is.
+func validateRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, params := spec.find(r.Method, r.URL.Path)
+		if op == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		if err := spec.validateRequest(w, r, op, params); err != nil {
+			encodeError(r.Context(), err, w)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// validateRequest checks the parameters and body of r against op. The body
+// is restored so that it can be read again by the next handler.
+func (d *document) validateRequest(w http.ResponseWriter, r *http.Request, op *operation, params map[string]string) error {
+	var fields []shipping.FieldError
+
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		var (
+			v  string
+			ok bool
+		)
+		switch p.In {
+		case "path":
+			v, ok = params[p.Name]
+		case "query":
+			if vs, found := query[p.Name]; found && len(vs) > 0 {
+				v, ok = vs[0], true
+			}
+		case "header":
+			if vs := r.Header[http.CanonicalHeaderKey(p.Name)]; len(vs) > 0 {
+				v, ok = vs[0], true
+			}
+		}
+
+		if !ok {
+			if p.Required {
+				fields = append(fields, shipping.FieldError{Name: p.Name, Reason: "is required"})
+			}
+			continue
+		}
+
+		fields = append(fields, d.validateParam(p.Name, d.resolve(p.Schema), v)...)
+	}
+
+	if op.RequestBody != nil {
+		fs, err := d.validateBody(w, r, op.RequestBody)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, fs...)
+	}
+
+	if len(fields) > 0 {
+		return errSpecMismatch.WithFields(fields...)
+	}
+	return nil
+}
+
+// validateParam checks a parameter value, which is always a string on the
+// wire, against s.
+func (d *document) validateParam(name string, s *schema, v string) []shipping.FieldError {
+	var value interface{} = v
+
+	switch s.Type {
+	case "integer":
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return []shipping.FieldError{{Name: name, Reason: "must be an integer"}}
+		}
+		value = json.Number(strconv.Itoa(n))
+	case "boolean":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return []shipping.FieldError{{Name: name, Reason: "must be a boolean"}}
+		}
+		value = b
+	}
+
+	return d.validateValue(name, s, value)
+}
+
+func (d *document) validateBody(w http.ResponseWriter, r *http.Request, body *requestBody) ([]shipping.FieldError, error) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, err := mime.ParseMediaType(ct)
+		if err != nil || mt != mediaTypeJSON {
+			return []shipping.FieldError{{Name: "Content-Type", Reason: "must be " + mediaTypeJSON}}, nil
+		}
+	}
+
+	b, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, errRequestTooLarge
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		if body.Required {
+			return []shipping.FieldError{{Name: "body", Reason: "is required"}}, nil
+		}
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, errMalformedRequest.WithFields(shipping.FieldError{Name: "body", Reason: "is not valid JSON"})
+	}
+
+	return d.validateValue("", body.Content[mediaTypeJSON].Schema, v), nil
+}
+
+// validateValue checks a decoded JSON value against s. Field names are
+// given as paths into the value, such as route.legs[0].from.
+func (d *document) validateValue(name string, s *schema, v interface{}) []shipping.FieldError {
+	s = d.resolve(s)
+	if s == nil {
+		return nil
+	}
+
+	invalid := func(format string, args ...interface{}) []shipping.FieldError {
+		n := name
+		if n == "" {
+			n = "body"
+		}
+		return []shipping.FieldError{{Name: n, Reason: fmt.Sprintf(format, args...)}}
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return invalid("must be an object")
+		}
+
+		var fields []shipping.FieldError
+		for _, p := range s.Required {
+			if _, ok := obj[p]; !ok {
+				fields = append(fields, shipping.FieldError{Name: join(name, p), Reason: "is required"})
+			}
+		}
+
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			ps, ok := s.Properties[k]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					fields = append(fields, shipping.FieldError{Name: join(name, k), Reason: "is not allowed"})
+				}
+				continue
+			}
+			fields = append(fields, d.validateValue(join(name, k), ps, obj[k])...)
+		}
+		return fields
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return invalid("must be an array")
+		}
+		if len(arr) < s.MinItems {
+			return invalid("must have at least %d items", s.MinItems)
+		}
+
+		var fields []shipping.FieldError
+		for i, e := range arr {
+			fields = append(fields, d.validateValue(fmt.Sprintf("%s[%d]", name, i), s.Items, e)...)
+		}
+		return fields
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return invalid("must be a string")
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return invalid("must be a RFC 3339 timestamp")
+			}
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			return invalid("must be one of %s", strings.Join(s.Enum, ", "))
+		}
+	case "integer":
+		num, ok := v.(json.Number)
+		if !ok {
+			return invalid("must be an integer")
+		}
+		n, err := strconv.Atoi(num.String())
+		if err != nil {
+			return invalid("must be an integer")
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return invalid("must be at least %d", *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			return invalid("must be at most %d", *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return invalid("must be a boolean")
+		}
+	}
+
+	return nil
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
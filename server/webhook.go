@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	kitlog "github.com/go-kit/kit/log"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+type webhookHandler struct {
+	s webhook.Service
+
+	logger kitlog.Logger
+}
+
+func (h *webhookHandler) router() chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(h.enabled)
+	r.Route("/subscriptions", func(r chi.Router) {
+		r.Post("/", h.subscribe)
+		r.Get("/", h.listSubscriptions)
+		r.Route("/{subscriptionID}", func(r chi.Router) {
+			r.Get("/", h.loadSubscription)
+			r.Delete("/", h.unsubscribe)
+			r.Get("/deliveries", h.listDeliveries)
+		})
+	})
+
+	return r
+}
+
+// enabled responds with 501 Not Implemented unless webhooks are configured.
+func (h *webhookHandler) enabled(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.s == nil {
+			encodeError(r.Context(), errNotImplemented, w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *webhookHandler) subscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var request struct {
+		Customer   string              `json:"customer"`
+		URL        string              `json:"url"`
+		TrackingID shipping.TrackingID `json:"tracking_id"`
+		Events     []webhook.EventType `json:"events"`
+	}
+
+	if err := decodeRequest(r, &request); err != nil {
+		h.logger.Log("error", err)
+		encodeError(ctx, err, w)
+		return
+	}
+
+	sub, err := h.s.Subscribe(ctx, request.Customer, request.URL, request.TrackingID, request.Events)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+
+	var response = struct {
+		Subscription webhook.Subscription `json:"subscription"`
+	}{
+		Subscription: sub,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Log("error", err)
+		encodeError(ctx, err, w)
+		return
+	}
+}
+
+func (h *webhookHandler) listSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subs := h.s.Subscriptions(ctx, r.URL.Query().Get("customer"))
+
+	var response = struct {
+		Subscriptions []webhook.Subscription `json:"subscriptions"`
+	}{
+		Subscriptions: subs,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Log("error", err)
+		encodeError(ctx, err, w)
+		return
+	}
+}
+
+func (h *webhookHandler) loadSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := webhook.SubscriptionID(chi.URLParam(r, "subscriptionID"))
+
+	sub, err := h.s.Subscription(ctx, id)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+
+	var response = struct {
+		Subscription webhook.Subscription `json:"subscription"`
+	}{
+		Subscription: sub,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Log("error", err)
+		encodeError(ctx, err, w)
+		return
+	}
+}
+
+func (h *webhookHandler) unsubscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := webhook.SubscriptionID(chi.URLParam(r, "subscriptionID"))
+
+	if err := h.s.Unsubscribe(ctx, id); err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+}
+
+func (h *webhookHandler) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := webhook.SubscriptionID(chi.URLParam(r, "subscriptionID"))
+
+	ds, err := h.s.Deliveries(ctx, id)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+
+	var response = struct {
+		Deliveries []webhook.Delivery `json:"deliveries"`
+	}{
+		Deliveries: ds,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Log("error", err)
+		encodeError(ctx, err, w)
+		return
+	}
+}
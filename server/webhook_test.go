@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+func TestWebhookSubscriptions(t *testing.T) {
+	ws := webhook.NewService(inmem.NewSubscriptionRepository(), inmem.NewDeliveryRepository(), inmem.NewCargoRepository())
+
+	h := New(nil, nil, nil, log.NewLogfmtLogger(ioutil.Discard), WithWebhooks(ws))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, "http://example.com"+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("POST", "/webhooks/v1/subscriptions", `{"customer":"acme","url":"https://example.com/hook","events":["cargo.arrived"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rec.Code = %d; want = %d", rec.Code, http.StatusOK)
+	}
+
+	var created struct {
+		Subscription webhook.Subscription `json:"subscription"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Subscription.Secret == "" {
+		t.Error("secret is empty")
+	}
+
+	id := string(created.Subscription.ID)
+
+	rec = do("GET", "/webhooks/v1/subscriptions?customer=acme", "")
+	var list struct {
+		Subscriptions []webhook.Subscription `json:"subscriptions"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Subscriptions) != 1 || list.Subscriptions[0].Secret != "" {
+		t.Errorf("subscriptions = %+v; want one without secret", list.Subscriptions)
+	}
+
+	rec = do("GET", "/webhooks/v1/subscriptions/"+id+"/deliveries", "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusOK)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != `{"deliveries":[]}` {
+		t.Errorf("body = %s; want = %s", body, `{"deliveries":[]}`)
+	}
+
+	if rec = do("DELETE", "/webhooks/v1/subscriptions/"+id, ""); rec.Code != http.StatusOK {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusOK)
+	}
+	if rec = do("GET", "/webhooks/v1/subscriptions/"+id, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestWebhooksNotConfigured(t *testing.T) {
+	h := New(nil, nil, nil, log.NewLogfmtLogger(ioutil.Discard))
+
+	req, _ := http.NewRequest("GET", "http://example.com/webhooks/v1/subscriptions", nil)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("rec.Code = %d; want = %d", rec.Code, http.StatusNotImplemented)
+	}
+}
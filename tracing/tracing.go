@@ -0,0 +1,275 @@
+// Package tracing provides repositories that trace every call to the
+// repositories they decorate, so that time spent in storage shows up in the
+// traces of requests.
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/booking"
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+// start starts a span for a repository call.
+func start(ctx context.Context, tracer trace.Tracer, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// end ends span, marking it as failed if err isn't nil.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// endFind ends the span of a lookup. Not finding anything is an expected
+// outcome rather than a failure, so notFound is only recorded as an
+// attribute.
+func endFind(span trace.Span, err, notFound error) {
+	if errors.Is(err, notFound) {
+		span.SetAttributes(attribute.Bool("found", false))
+		err = nil
+	}
+	end(span, err)
+}
+
+type cargoRepository struct {
+	tracer trace.Tracer
+	next   shipping.CargoRepository
+}
+
+// NewCargoRepository returns a cargo repository that traces calls to r.
+func NewCargoRepository(tracer trace.Tracer, r shipping.CargoRepository) shipping.CargoRepository {
+	return &cargoRepository{tracer, r}
+}
+
+func (r *cargoRepository) Store(ctx context.Context, c *shipping.Cargo) (err error) {
+	ctx, span := start(ctx, r.tracer, "CargoRepository.Store", attribute.String("tracking_id", string(c.TrackingID)))
+	defer func() { end(span, err) }()
+	return r.next.Store(ctx, c)
+}
+
+func (r *cargoRepository) Find(ctx context.Context, id shipping.TrackingID) (c *shipping.Cargo, err error) {
+	ctx, span := start(ctx, r.tracer, "CargoRepository.Find", attribute.String("tracking_id", string(id)))
+	defer func() { endFind(span, err, shipping.ErrUnknownCargo) }()
+	return r.next.Find(ctx, id)
+}
+
+func (r *cargoRepository) FindAll(ctx context.Context) []*shipping.Cargo {
+	ctx, span := start(ctx, r.tracer, "CargoRepository.FindAll")
+	defer span.End()
+	return r.next.FindAll(ctx)
+}
+
+func (r *cargoRepository) Query(ctx context.Context, q shipping.CargoQuery) (page shipping.CargoPage, err error) {
+	ctx, span := start(ctx, r.tracer, "CargoRepository.Query", attribute.Int("limit", q.Limit))
+	defer func() {
+		span.SetAttributes(attribute.Int("cargos", len(page.Cargos)))
+		end(span, err)
+	}()
+	return r.next.Query(ctx, q)
+}
+
+type locationRepository struct {
+	tracer trace.Tracer
+	next   shipping.LocationRepository
+}
+
+// NewLocationRepository returns a location repository that traces calls to
+// r.
+func NewLocationRepository(tracer trace.Tracer, r shipping.LocationRepository) shipping.LocationRepository {
+	return &locationRepository{tracer, r}
+}
+
+func (r *locationRepository) Store(ctx context.Context, l *shipping.Location) (err error) {
+	ctx, span := start(ctx, r.tracer, "LocationRepository.Store", attribute.String("location", string(l.UNLocode)))
+	defer func() { end(span, err) }()
+	return r.next.Store(ctx, l)
+}
+
+func (r *locationRepository) Find(ctx context.Context, locode shipping.UNLocode) (l *shipping.Location, err error) {
+	ctx, span := start(ctx, r.tracer, "LocationRepository.Find", attribute.String("location", string(locode)))
+	defer func() { endFind(span, err, shipping.ErrUnknownLocation) }()
+	return r.next.Find(ctx, locode)
+}
+
+func (r *locationRepository) FindAll(ctx context.Context) []*shipping.Location {
+	ctx, span := start(ctx, r.tracer, "LocationRepository.FindAll")
+	defer span.End()
+	return r.next.FindAll(ctx)
+}
+
+type voyageRepository struct {
+	tracer trace.Tracer
+	next   shipping.VoyageRepository
+}
+
+// NewVoyageRepository returns a voyage repository that traces calls to r.
+func NewVoyageRepository(tracer trace.Tracer, r shipping.VoyageRepository) shipping.VoyageRepository {
+	return &voyageRepository{tracer, r}
+}
+
+func (r *voyageRepository) Store(ctx context.Context, v *shipping.Voyage) (err error) {
+	ctx, span := start(ctx, r.tracer, "VoyageRepository.Store", attribute.String("voyage", string(v.VoyageNumber)))
+	defer func() { end(span, err) }()
+	return r.next.Store(ctx, v)
+}
+
+func (r *voyageRepository) Find(ctx context.Context, number shipping.VoyageNumber) (v *shipping.Voyage, err error) {
+	ctx, span := start(ctx, r.tracer, "VoyageRepository.Find", attribute.String("voyage", string(number)))
+	defer func() { endFind(span, err, shipping.ErrUnknownVoyage) }()
+	return r.next.Find(ctx, number)
+}
+
+func (r *voyageRepository) FindAll(ctx context.Context) []*shipping.Voyage {
+	ctx, span := start(ctx, r.tracer, "VoyageRepository.FindAll")
+	defer span.End()
+	return r.next.FindAll(ctx)
+}
+
+type handlingEventRepository struct {
+	tracer trace.Tracer
+	next   shipping.HandlingEventRepository
+}
+
+// NewHandlingEventRepository returns a handling event repository that traces
+// calls to r.
+func NewHandlingEventRepository(tracer trace.Tracer, r shipping.HandlingEventRepository) shipping.HandlingEventRepository {
+	return &handlingEventRepository{tracer, r}
+}
+
+func (r *handlingEventRepository) Store(ctx context.Context, e shipping.HandlingEvent) {
+	ctx, span := start(ctx, r.tracer, "HandlingEventRepository.Store", attribute.String("tracking_id", string(e.TrackingID)))
+	defer span.End()
+	r.next.Store(ctx, e)
+}
+
+func (r *handlingEventRepository) QueryHandlingHistory(ctx context.Context, id shipping.TrackingID) shipping.HandlingHistory {
+	ctx, span := start(ctx, r.tracer, "HandlingEventRepository.QueryHandlingHistory", attribute.String("tracking_id", string(id)))
+	defer span.End()
+	return r.next.QueryHandlingHistory(ctx, id)
+}
+
+type subscriptionRepository struct {
+	tracer trace.Tracer
+	next   webhook.SubscriptionRepository
+}
+
+// NewSubscriptionRepository returns a webhook subscription repository that
+// traces calls to r.
+func NewSubscriptionRepository(tracer trace.Tracer, r webhook.SubscriptionRepository) webhook.SubscriptionRepository {
+	return &subscriptionRepository{tracer, r}
+}
+
+func (r *subscriptionRepository) Store(ctx context.Context, s *webhook.Subscription) (err error) {
+	ctx, span := start(ctx, r.tracer, "SubscriptionRepository.Store", attribute.String("subscription_id", string(s.ID)))
+	defer func() { end(span, err) }()
+	return r.next.Store(ctx, s)
+}
+
+func (r *subscriptionRepository) Find(ctx context.Context, id webhook.SubscriptionID) (s *webhook.Subscription, err error) {
+	ctx, span := start(ctx, r.tracer, "SubscriptionRepository.Find", attribute.String("subscription_id", string(id)))
+	defer func() { endFind(span, err, webhook.ErrUnknownSubscription) }()
+	return r.next.Find(ctx, id)
+}
+
+func (r *subscriptionRepository) FindAll(ctx context.Context) []*webhook.Subscription {
+	ctx, span := start(ctx, r.tracer, "SubscriptionRepository.FindAll")
+	defer span.End()
+	return r.next.FindAll(ctx)
+}
+
+func (r *subscriptionRepository) Remove(ctx context.Context, id webhook.SubscriptionID) (err error) {
+	ctx, span := start(ctx, r.tracer, "SubscriptionRepository.Remove", attribute.String("subscription_id", string(id)))
+	defer func() { end(span, err) }()
+	return r.next.Remove(ctx, id)
+}
+
+type deliveryRepository struct {
+	tracer trace.Tracer
+	next   webhook.DeliveryRepository
+}
+
+// NewDeliveryRepository returns a webhook delivery repository that traces
+// calls to r.
+func NewDeliveryRepository(tracer trace.Tracer, r webhook.DeliveryRepository) webhook.DeliveryRepository {
+	return &deliveryRepository{tracer, r}
+}
+
+func (r *deliveryRepository) Store(ctx context.Context, d *webhook.Delivery) (err error) {
+	ctx, span := start(ctx, r.tracer, "DeliveryRepository.Store", attribute.String("delivery_id", string(d.ID)))
+	defer func() { end(span, err) }()
+	return r.next.Store(ctx, d)
+}
+
+func (r *deliveryRepository) FindBySubscription(ctx context.Context, id webhook.SubscriptionID) []*webhook.Delivery {
+	ctx, span := start(ctx, r.tracer, "DeliveryRepository.FindBySubscription", attribute.String("subscription_id", string(id)))
+	defer span.End()
+	return r.next.FindBySubscription(ctx, id)
+}
+
+func (r *deliveryRepository) FindPending(ctx context.Context) []*webhook.Delivery {
+	ctx, span := start(ctx, r.tracer, "DeliveryRepository.FindPending")
+	defer span.End()
+	return r.next.FindPending(ctx)
+}
+
+type routeCandidateRepository struct {
+	tracer trace.Tracer
+	next   booking.RouteCandidateRepository
+}
+
+// NewRouteCandidateRepository returns a route candidate repository that
+// traces calls to r.
+func NewRouteCandidateRepository(tracer trace.Tracer, r booking.RouteCandidateRepository) booking.RouteCandidateRepository {
+	return &routeCandidateRepository{tracer, r}
+}
+
+func (r *routeCandidateRepository) Store(ctx context.Context, c *booking.RouteCandidate) (err error) {
+	ctx, span := start(ctx, r.tracer, "RouteCandidateRepository.Store", attribute.String("candidate_id", string(c.ID)))
+	defer func() { end(span, err) }()
+	return r.next.Store(ctx, c)
+}
+
+func (r *routeCandidateRepository) Find(ctx context.Context, id booking.RouteCandidateID) (c *booking.RouteCandidate, err error) {
+	ctx, span := start(ctx, r.tracer, "RouteCandidateRepository.Find", attribute.String("candidate_id", string(id)))
+	defer func() { endFind(span, err, booking.ErrUnknownRouteCandidate) }()
+	return r.next.Find(ctx, id)
+}
+
+type amendmentRepository struct {
+	tracer trace.Tracer
+	next   booking.AmendmentRepository
+}
+
+// NewAmendmentRepository returns a booking amendment repository that traces
+// calls to r.
+func NewAmendmentRepository(tracer trace.Tracer, r booking.AmendmentRepository) booking.AmendmentRepository {
+	return &amendmentRepository{tracer, r}
+}
+
+func (r *amendmentRepository) Store(ctx context.Context, a *booking.Amendment) (err error) {
+	ctx, span := start(ctx, r.tracer, "AmendmentRepository.Store", attribute.String("tracking_id", string(a.TrackingID)))
+	defer func() { end(span, err) }()
+	return r.next.Store(ctx, a)
+}
+
+func (r *amendmentRepository) Remove(ctx context.Context, id booking.AmendmentID) (err error) {
+	ctx, span := start(ctx, r.tracer, "AmendmentRepository.Remove", attribute.String("amendment_id", string(id)))
+	defer func() { end(span, err) }()
+	return r.next.Remove(ctx, id)
+}
+
+func (r *amendmentRepository) FindByTrackingID(ctx context.Context, id shipping.TrackingID) []*booking.Amendment {
+	ctx, span := start(ctx, r.tracer, "AmendmentRepository.FindByTrackingID", attribute.String("tracking_id", string(id)))
+	defer span.End()
+	return r.next.FindByTrackingID(ctx, id)
+}
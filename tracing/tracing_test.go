@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/inmem"
+)
+
+func TestCargoRepository(t *testing.T) {
+	ctx := context.Background()
+
+	spans := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("test")
+
+	r := NewCargoRepository(tracer, inmem.NewCargoRepository())
+
+	c := shipping.NewCargo("TEST", shipping.RouteSpecification{
+		Origin:          shipping.SESTO,
+		Destination:     shipping.FIHEL,
+		ArrivalDeadline: time.Date(2005, 12, 4, 0, 0, 0, 0, time.UTC),
+	})
+	if err := r.Store(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Find(ctx, "TEST"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Find(ctx, "NOPE"); err != shipping.ErrUnknownCargo {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrUnknownCargo)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("len(spans) = %d; want = %d", len(ended), 3)
+	}
+
+	for i, want := range []string{"CargoRepository.Store", "CargoRepository.Find", "CargoRepository.Find"} {
+		if ended[i].Name() != want {
+			t.Errorf("ended[%d].Name() = %q; want = %q", i, ended[i].Name(), want)
+		}
+	}
+
+	// Not finding a cargo isn't a failure of the repository.
+	if got := ended[2].Status().Code; got != codes.Unset {
+		t.Errorf("status of a lookup of an unknown cargo = %v; want = %v", got, codes.Unset)
+	}
+}
@@ -0,0 +1,106 @@
+package tracking
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// Broker notifies subscribers when the tracking status of a cargo may have
+// changed, that is whenever its delivery is re-derived. It implements
+// handling.EventHandler and booking.EventHandler, and should be notified
+// after the changed cargo has been stored. Every handling event re-derives
+// the delivery, so there's no need to also notify it of inspections.
+//
+// Every update is given an ID that's greater than those of the updates
+// before it. IDs start at the time the broker was created, in milliseconds
+// since the epoch, so that they keep increasing when the application is
+// restarted. The IDs of the latest updates are only kept for cargos with
+// subscribers.
+type Broker struct {
+	mtx      sync.Mutex
+	subs     map[shipping.TrackingID]map[chan struct{}]bool
+	last     uint64
+	versions map[shipping.TrackingID]uint64
+}
+
+// NewBroker returns a new Broker without any subscribers.
+func NewBroker() *Broker {
+	start := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	return &Broker{
+		subs:     make(map[shipping.TrackingID]map[chan struct{}]bool),
+		last:     start,
+		versions: make(map[shipping.TrackingID]uint64),
+	}
+}
+
+// Subscribe returns a channel that receives a value whenever an update of
+// the cargo is published. Notifications are coalesced while the subscriber is
+// busy. The returned function cancels the subscription and must be called.
+func (b *Broker) Subscribe(id shipping.TrackingID) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	b.mtx.Lock()
+	if b.subs[id] == nil {
+		b.subs[id] = make(map[chan struct{}]bool)
+	}
+	b.subs[id][ch] = true
+	if _, ok := b.versions[id]; !ok {
+		b.versions[id] = b.last
+	}
+	b.mtx.Unlock()
+
+	return ch, func() {
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+		delete(b.subs[id], ch)
+		if len(b.subs[id]) == 0 {
+			delete(b.subs, id)
+			delete(b.versions, id)
+		}
+	}
+}
+
+// Version returns the ID of the latest update of the cargo. For cargos
+// without subscribers, it's the ID of the latest update of any cargo, which
+// is never less than that of the cargo itself.
+func (b *Broker) Version(id shipping.TrackingID) uint64 {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if v, ok := b.versions[id]; ok {
+		return v
+	}
+	return b.last
+}
+
+// Publish gives the cargo a new update ID and notifies its subscribers.
+func (b *Broker) Publish(id shipping.TrackingID) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.last++
+	if _, ok := b.subs[id]; !ok {
+		return
+	}
+	b.versions[id] = b.last
+
+	for ch := range b.subs[id] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// CargoWasHandled publishes an update of the handled cargo.
+func (b *Broker) CargoWasHandled(_ context.Context, e shipping.HandlingEvent) {
+	b.Publish(e.TrackingID)
+}
+
+// CargoWasAmended publishes an update of the cargo whose booking changed.
+func (b *Broker) CargoWasAmended(_ context.Context, c *shipping.Cargo) {
+	b.Publish(c.TrackingID)
+}
@@ -0,0 +1,83 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+func TestBroker(t *testing.T) {
+	b := NewBroker()
+
+	abc, cancelABC := b.Subscribe("ABC123")
+	xyz, cancelXYZ := b.Subscribe("XYZ789")
+	defer cancelXYZ()
+
+	// Notifications are coalesced, so handling twice must not block.
+	b.CargoWasHandled(context.Background(), shipping.HandlingEvent{TrackingID: "ABC123"})
+	b.CargoWasHandled(context.Background(), shipping.HandlingEvent{TrackingID: "ABC123"})
+
+	select {
+	case <-abc:
+	default:
+		t.Error("subscriber was not notified")
+	}
+
+	select {
+	case <-xyz:
+		t.Error("subscriber of another cargo was notified")
+	default:
+	}
+
+	cancelABC()
+
+	if _, ok := b.subs["ABC123"]; ok {
+		t.Error("subscription was not removed")
+	}
+}
+
+func TestBrokerVersion(t *testing.T) {
+	b := NewBroker()
+
+	_, cancel := b.Subscribe("ABC123")
+
+	start := b.Version("ABC123")
+	if v := b.Version("XYZ789"); v != start {
+		t.Errorf("Version(XYZ789) = %d; want = %d", v, start)
+	}
+
+	// Every kind of update gives the cargo a new, greater ID.
+	b.CargoWasHandled(context.Background(), shipping.HandlingEvent{TrackingID: "ABC123"})
+	handled := b.Version("ABC123")
+	if handled <= start {
+		t.Errorf("Version(ABC123) = %d; want > %d", handled, start)
+	}
+
+	b.CargoWasAmended(context.Background(), &shipping.Cargo{TrackingID: "ABC123"})
+	amended := b.Version("ABC123")
+	if amended <= handled {
+		t.Errorf("Version(ABC123) = %d; want > %d", amended, handled)
+	}
+
+	// Updates of other cargos leave the version of a subscribed cargo as
+	// it was, while cargos without subscribers get the latest ID.
+	b.CargoWasHandled(context.Background(), shipping.HandlingEvent{TrackingID: "XYZ789"})
+	if v := b.Version("ABC123"); v != amended {
+		t.Errorf("Version(ABC123) = %d; want = %d", v, amended)
+	}
+	latest := b.Version("XYZ789")
+	if latest <= amended {
+		t.Errorf("Version(XYZ789) = %d; want > %d", latest, amended)
+	}
+
+	// Versions are forgotten along with the last subscriber, without going
+	// back.
+	cancel()
+	if len(b.versions) != 0 {
+		t.Errorf("len(versions) = %d; want = %d", len(b.versions), 0)
+	}
+	if v := b.Version("ABC123"); v != latest {
+		t.Errorf("Version(ABC123) = %d; want = %d", v, latest)
+	}
+}
@@ -0,0 +1,41 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// Endpoints collects the endpoints of a tracking service, to be exposed by a
+// transport.
+type Endpoints struct {
+	TrackEndpoint endpoint.Endpoint
+}
+
+// MakeEndpoints returns the endpoints of s.
+func MakeEndpoints(s Service) Endpoints {
+	return Endpoints{
+		TrackEndpoint: makeTrackEndpoint(s),
+	}
+}
+
+// TrackRequest is the request of the Track endpoint.
+type TrackRequest struct {
+	ID string
+}
+
+// TrackResponse is the response of the Track endpoint.
+type TrackResponse struct {
+	Cargo Cargo
+}
+
+func makeTrackEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(TrackRequest)
+		c, err := s.Track(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		return TrackResponse{Cargo: c}, nil
+	}
+}
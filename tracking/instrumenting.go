@@ -1,6 +1,7 @@
 package tracking
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-kit/kit/metrics"
@@ -21,11 +22,11 @@ func NewInstrumentingService(counter metrics.Counter, latency metrics.Histogram,
 	}
 }
 
-func (s *instrumentingService) Track(id string) (Cargo, error) {
+func (s *instrumentingService) Track(ctx context.Context, id string) (Cargo, error) {
 	defer func(begin time.Time) {
 		s.requestCount.With("method", "track").Add(1)
 		s.requestLatency.With("method", "track").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return s.next.Track(id)
+	return s.next.Track(ctx, id)
 }
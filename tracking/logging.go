@@ -1,6 +1,7 @@
 package tracking
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-kit/kit/log"
@@ -16,9 +17,9 @@ func NewLoggingService(logger log.Logger, s Service) Service {
 	return &loggingService{logger, s}
 }
 
-func (s *loggingService) Track(id string) (c Cargo, err error) {
+func (s *loggingService) Track(ctx context.Context, id string) (c Cargo, err error) {
 	defer func(begin time.Time) {
 		s.logger.Log("method", "track", "tracking_id", id, "took", time.Since(begin), "err", err)
 	}(time.Now())
-	return s.next.Track(id)
+	return s.next.Track(ctx, id)
 }
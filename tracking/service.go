@@ -3,7 +3,7 @@
 package tracking
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -12,12 +12,12 @@ import (
 )
 
 // ErrInvalidArgument is returned when one or more arguments are invalid.
-var ErrInvalidArgument = errors.New("invalid argument")
+var ErrInvalidArgument = shipping.NewError(shipping.CodeInvalidArgument, "invalid argument")
 
 // Service is the interface that provides the basic Track method.
 type Service interface {
 	// Track returns a cargo matching a tracking ID.
-	Track(id string) (Cargo, error)
+	Track(ctx context.Context, id string) (Cargo, error)
 }
 
 type service struct {
@@ -25,15 +25,15 @@ type service struct {
 	handlingEvents shipping.HandlingEventRepository
 }
 
-func (s *service) Track(id string) (Cargo, error) {
+func (s *service) Track(ctx context.Context, id string) (Cargo, error) {
 	if id == "" {
-		return Cargo{}, ErrInvalidArgument
+		return Cargo{}, ErrInvalidArgument.WithFields(shipping.FieldError{Name: "tracking_id", Reason: "is required"})
 	}
-	c, err := s.cargos.Find(shipping.TrackingID(id))
+	c, err := s.cargos.Find(ctx, shipping.TrackingID(id))
 	if err != nil {
 		return Cargo{}, err
 	}
-	return assemble(c, s.handlingEvents), nil
+	return assemble(ctx, c, s.handlingEvents), nil
 }
 
 // NewService returns a new instance of the default Service.
@@ -71,7 +71,7 @@ type Event struct {
 	Expected    bool   `json:"expected"`
 }
 
-func assemble(c *shipping.Cargo, events shipping.HandlingEventRepository) Cargo {
+func assemble(ctx context.Context, c *shipping.Cargo, events shipping.HandlingEventRepository) Cargo {
 	return Cargo{
 		TrackingID:           string(c.TrackingID),
 		Origin:               string(c.Origin),
@@ -80,7 +80,7 @@ func assemble(c *shipping.Cargo, events shipping.HandlingEventRepository) Cargo
 		NextExpectedActivity: nextExpectedActivity(c),
 		ArrivalDeadline:      c.RouteSpecification.ArrivalDeadline,
 		StatusText:           assembleStatusText(c),
-		Events:               assembleEvents(c, events),
+		Events:               assembleEvents(ctx, c, events),
 	}
 }
 
@@ -129,8 +129,8 @@ func assembleStatusText(c *shipping.Cargo) string {
 	}
 }
 
-func assembleEvents(c *shipping.Cargo, handlingEvents shipping.HandlingEventRepository) []Event {
-	h := handlingEvents.QueryHandlingHistory(c.TrackingID)
+func assembleEvents(ctx context.Context, c *shipping.Cargo, handlingEvents shipping.HandlingEventRepository) []Event {
+	h := handlingEvents.QueryHandlingHistory(ctx, c.TrackingID)
 
 	var events []Event
 	for _, e := range h.HandlingEvents {
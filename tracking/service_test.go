@@ -1,6 +1,7 @@
 package tracking
 
 import (
+	"context"
 	"testing"
 
 	shipping "github.com/marcusolsson/goddd"
@@ -23,7 +24,7 @@ func TestTrack(t *testing.T) {
 
 	s := NewService(&cargos, &events)
 
-	c, err := s.Track("FTL456")
+	c, err := s.Track(context.Background(), "FTL456")
 	if err != nil {
 		t.Fatal(err)
 	}
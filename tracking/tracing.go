@@ -0,0 +1,33 @@
+package tracking
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracingService struct {
+	tracer trace.Tracer
+	next   Service
+}
+
+// NewTracingService returns a new instance of a tracing Service.
+func NewTracingService(tracer trace.Tracer, s Service) Service {
+	return &tracingService{tracer, s}
+}
+
+func (s *tracingService) Track(ctx context.Context, id string) (c Cargo, err error) {
+	ctx, span := s.tracer.Start(ctx, "tracking.Track", trace.WithAttributes(
+		attribute.String("tracking_id", id),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+	return s.next.Track(ctx, id)
+}
@@ -1,7 +1,7 @@
 package shipping
 
 import (
-	"errors"
+	"context"
 	"time"
 )
 
@@ -33,9 +33,11 @@ type CarrierMovement struct {
 }
 
 // ErrUnknownVoyage is used when a voyage could not be found.
-var ErrUnknownVoyage = errors.New("unknown voyage")
+var ErrUnknownVoyage = NewError(CodeUnknownVoyage, "unknown voyage")
 
 // VoyageRepository provides access a voyage store.
 type VoyageRepository interface {
-	Find(VoyageNumber) (*Voyage, error)
+	Store(ctx context.Context, voyage *Voyage) error
+	Find(context.Context, VoyageNumber) (*Voyage, error)
+	FindAll(ctx context.Context) []*Voyage
 }
@@ -0,0 +1,333 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// Defaults used by NewDispatcher.
+const (
+	DefaultMaxAttempts = 8
+	DefaultMinBackoff  = 10 * time.Second
+	DefaultMaxBackoff  = time.Hour
+	DefaultTimeout     = 10 * time.Second
+	DefaultWorkers     = 8
+)
+
+// Notification is the JSON body posted to subscribers.
+type Notification struct {
+	ID      DeliveryID `json:"id"`
+	Event   EventType  `json:"event"`
+	Created time.Time  `json:"created"`
+	Cargo   Cargo      `json:"cargo"`
+}
+
+// Cargo is a read model of the cargo a notification is about.
+type Cargo struct {
+	TrackingID        string    `json:"tracking_id"`
+	Origin            string    `json:"origin"`
+	Destination       string    `json:"destination"`
+	ArrivalDeadline   time.Time `json:"arrival_deadline"`
+	TransportStatus   string    `json:"transport_status"`
+	LastKnownLocation string    `json:"last_known_location"`
+	CurrentVoyage     string    `json:"current_voyage,omitempty"`
+	Misdirected       bool      `json:"misdirected"`
+}
+
+func assemble(c *shipping.Cargo) Cargo {
+	return Cargo{
+		TrackingID:        string(c.TrackingID),
+		Origin:            string(c.Origin),
+		Destination:       string(c.RouteSpecification.Destination),
+		ArrivalDeadline:   c.RouteSpecification.ArrivalDeadline,
+		TransportStatus:   c.Delivery.TransportStatus.String(),
+		LastKnownLocation: string(c.Delivery.LastKnownLocation),
+		CurrentVoyage:     string(c.Delivery.CurrentVoyage),
+		Misdirected:       c.Delivery.IsMisdirected,
+	}
+}
+
+// Dispatcher is an inspection event handler that records a delivery for every
+// matching subscription, and posts them to the subscribers in the background.
+// Failed deliveries are retried with exponential backoff until they succeed
+// or run out of attempts.
+//
+// Deliveries are sent by a bounded number of workers, and to each
+// subscription one at a time, so that a slow subscriber holds up no more than
+// one worker and never the deliveries to other subscribers.
+type Dispatcher struct {
+	subscriptions SubscriptionRepository
+	deliveries    DeliveryRepository
+
+	// Client is used to post notifications.
+	Client *http.Client
+
+	// MaxAttempts is the number of attempts made before a delivery fails.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry. It doubles for every
+	// attempt, up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Workers is the number of deliveries sent at the same time.
+	Workers int
+
+	logger log.Logger
+	wake   chan struct{}
+
+	mtx     sync.Mutex
+	busy    map[SubscriptionID]bool
+	sending int
+	wg      sync.WaitGroup
+}
+
+// NewDispatcher returns a new Dispatcher with default settings. Deliveries
+// aren't sent until Run is called.
+func NewDispatcher(subscriptions SubscriptionRepository, deliveries DeliveryRepository, logger log.Logger) *Dispatcher {
+	return &Dispatcher{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		Client:        &http.Client{Timeout: DefaultTimeout},
+		MaxAttempts:   DefaultMaxAttempts,
+		MinBackoff:    DefaultMinBackoff,
+		MaxBackoff:    DefaultMaxBackoff,
+		Workers:       DefaultWorkers,
+		logger:        logger,
+		wake:          make(chan struct{}, 1),
+		busy:          make(map[SubscriptionID]bool),
+	}
+}
+
+// CargoWasMisdirected notifies subscribers that a cargo was misdirected.
+func (d *Dispatcher) CargoWasMisdirected(ctx context.Context, c *shipping.Cargo) {
+	d.notify(ctx, CargoMisdirected, c)
+}
+
+// CargoHasArrived notifies subscribers that a cargo has arrived.
+func (d *Dispatcher) CargoHasArrived(ctx context.Context, c *shipping.Cargo) {
+	d.notify(ctx, CargoArrived, c)
+}
+
+func (d *Dispatcher) notify(ctx context.Context, event EventType, c *shipping.Cargo) {
+	now := time.Now()
+
+	for _, s := range d.subscriptions.FindAll(ctx) {
+		if !s.Matches(event, c.TrackingID) {
+			continue
+		}
+
+		n := Notification{
+			ID:      NextDeliveryID(),
+			Event:   event,
+			Created: now,
+			Cargo:   assemble(c),
+		}
+
+		payload, err := json.Marshal(n)
+		if err != nil {
+			d.logger.Log("event", event, "subscription", s.ID, "err", err)
+			continue
+		}
+
+		del := &Delivery{
+			ID:           n.ID,
+			Subscription: s.ID,
+			Event:        event,
+			Payload:      payload,
+			Status:       Pending,
+			NextAttempt:  now,
+			Created:      now,
+		}
+		if err := d.deliveries.Store(ctx, del); err != nil {
+			d.logger.Log("event", event, "subscription", s.ID, "err", err)
+		}
+	}
+
+	d.wakeUp()
+}
+
+// wakeUp makes Run look for deliveries to send.
+func (d *Dispatcher) wakeUp() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends pending deliveries as they become due, until ctx is done. It
+// waits for the deliveries being sent to be abandoned before returning.
+// Deliveries left pending by a previous run are picked up again.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	defer d.wg.Wait()
+
+	for {
+		next := d.sendDue(ctx)
+
+		var (
+			timer   *time.Timer
+			timeout <-chan time.Time
+		)
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			timeout = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-d.wake:
+		case <-timeout:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// sendDue starts sending the deliveries that are due, as long as there are
+// idle workers, and returns when the next one is. It returns the zero time if
+// there's nothing left to send. Due deliveries that have to wait for a worker,
+// or for an earlier delivery to the same subscription, are picked up when a
+// worker wakes Run.
+func (d *Dispatcher) sendDue(ctx context.Context) time.Time {
+	// Workers record the outcome of a delivery before they free its
+	// subscription, so holding the lock while finding the pending deliveries
+	// keeps them from being sent twice.
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	var next time.Time
+
+	now := time.Now()
+	for _, del := range d.deliveries.FindPending(ctx) {
+		if del.NextAttempt.After(now) {
+			if next.IsZero() || del.NextAttempt.Before(next) {
+				next = del.NextAttempt
+			}
+			continue
+		}
+
+		if d.busy[del.Subscription] || d.sending >= max(d.Workers, 1) {
+			continue
+		}
+
+		d.busy[del.Subscription] = true
+		d.sending++
+		d.wg.Add(1)
+		go d.work(ctx, del)
+	}
+
+	return next
+}
+
+// work sends del, then frees its worker and subscription for the next
+// delivery.
+func (d *Dispatcher) work(ctx context.Context, del *Delivery) {
+	defer d.wg.Done()
+
+	d.send(ctx, del)
+
+	d.mtx.Lock()
+	delete(d.busy, del.Subscription)
+	d.sending--
+	d.mtx.Unlock()
+
+	d.wakeUp()
+}
+
+// send makes an attempt at delivering del and records the outcome.
+func (d *Dispatcher) send(ctx context.Context, del *Delivery) {
+	begin := time.Now()
+
+	var statusCode int
+	err := func() error {
+		s, err := d.subscriptions.Find(ctx, del.Subscription)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequest("POST", s.URL, bytes.NewReader(del.Payload))
+		if err != nil {
+			return err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "goddd-webhook")
+		req.Header.Set("X-Webhook-ID", string(del.ID))
+		req.Header.Set("X-Webhook-Event", string(del.Event))
+		req.Header.Set("X-Webhook-Signature", Sign(s.Secret, begin, del.Payload))
+
+		resp, err := d.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+
+		statusCode = resp.StatusCode
+		if statusCode < 200 || statusCode > 299 {
+			return fmt.Errorf("unexpected status: %s", resp.Status)
+		}
+		return nil
+	}()
+
+	if ctx.Err() != nil {
+		// Shutting down. Leave the delivery to the next run.
+		return
+	}
+
+	a := Attempt{
+		Time:       begin,
+		StatusCode: statusCode,
+		Duration:   time.Since(begin),
+	}
+	if err != nil {
+		a.Error = err.Error()
+	}
+	del.Attempts = append(del.Attempts, a)
+
+	switch {
+	case err == nil:
+		del.Status = Delivered
+		del.NextAttempt = time.Time{}
+	case errors.Is(err, ErrUnknownSubscription) || len(del.Attempts) >= d.MaxAttempts:
+		del.Status = Failed
+		del.NextAttempt = time.Time{}
+	default:
+		del.NextAttempt = time.Now().Add(d.backoff(len(del.Attempts)))
+	}
+
+	if err != nil {
+		d.logger.Log("delivery", del.ID, "subscription", del.Subscription, "attempt", len(del.Attempts), "status", del.Status, "err", err)
+	}
+
+	if err := d.deliveries.Store(context.Background(), del); err != nil {
+		d.logger.Log("delivery", del.ID, "err", err)
+	}
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	b := d.MinBackoff
+	for i := 1; i < attempts && b < d.MaxBackoff; i++ {
+		b *= 2
+	}
+	if b > d.MaxBackoff {
+		b = d.MaxBackoff
+	}
+	return b
+}
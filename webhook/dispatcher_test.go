@@ -0,0 +1,240 @@
+package webhook_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+
+	type notification struct {
+		header http.Header
+		body   []byte
+	}
+
+	received := make(chan notification, 1)
+	var failures int32 = 2
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&failures, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		received <- notification{r.Header, b}
+	}))
+	defer srv.Close()
+
+	var (
+		cargos        = inmem.NewCargoRepository()
+		subscriptions = inmem.NewSubscriptionRepository()
+		deliveries    = inmem.NewDeliveryRepository()
+	)
+
+	c := shipping.NewCargo("ABC123", shipping.RouteSpecification{
+		Origin:      shipping.SESTO,
+		Destination: shipping.AUMEL,
+	})
+	cargos.Store(ctx, c)
+
+	s := webhook.NewService(subscriptions, deliveries, cargos)
+
+	sub, err := s.Subscribe(ctx, "acme", srv.URL, "ABC123", []webhook.EventType{webhook.CargoArrived})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.Subscribe(ctx, "acme", srv.URL, "", []webhook.EventType{webhook.CargoMisdirected})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := webhook.NewDispatcher(subscriptions, deliveries, log.NewNopLogger())
+	d.MinBackoff = time.Millisecond
+	d.MaxBackoff = time.Millisecond
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go d.Run(runCtx)
+
+	d.CargoHasArrived(ctx, c)
+
+	var n notification
+	select {
+	case n = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification not received")
+	}
+
+	if got := n.header.Get("X-Webhook-Event"); got != string(webhook.CargoArrived) {
+		t.Errorf("X-Webhook-Event = %q; want = %q", got, webhook.CargoArrived)
+	}
+	if err := webhook.Verify(sub.Secret, n.header.Get("X-Webhook-Signature"), n.body, time.Minute); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+
+	// The delivery history is updated after the notification has been received.
+	var history []webhook.Delivery
+	for i := 0; i < 100; i++ {
+		history, _ = s.Deliveries(ctx, sub.ID)
+		if len(history) == 1 && history[0].Status == webhook.Delivered {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(history) != 1 {
+		t.Fatalf("len(history) = %d; want = %d", len(history), 1)
+	}
+	if history[0].Status != webhook.Delivered {
+		t.Errorf("Status = %s; want = %s", history[0].Status, webhook.Delivered)
+	}
+	if n := len(history[0].Attempts); n != 3 {
+		t.Errorf("len(Attempts) = %d; want = %d", n, 3)
+	}
+	if code := history[0].Attempts[0].StatusCode; code != http.StatusServiceUnavailable {
+		t.Errorf("StatusCode = %d; want = %d", code, http.StatusServiceUnavailable)
+	}
+
+	if history, _ := s.Deliveries(ctx, other.ID); len(history) != 0 {
+		t.Errorf("len(history) = %d; want = %d", len(history), 0)
+	}
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	var (
+		subscriptions = inmem.NewSubscriptionRepository()
+		deliveries    = inmem.NewDeliveryRepository()
+	)
+
+	s := webhook.NewService(subscriptions, deliveries, inmem.NewCargoRepository())
+
+	sub, err := s.Subscribe(ctx, "acme", srv.URL, "", []webhook.EventType{webhook.CargoMisdirected})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := webhook.NewDispatcher(subscriptions, deliveries, log.NewNopLogger())
+	d.MaxAttempts = 3
+	d.MinBackoff = time.Millisecond
+	d.MaxBackoff = time.Millisecond
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go d.Run(runCtx)
+
+	d.CargoWasMisdirected(ctx, shipping.NewCargo("ABC123", shipping.RouteSpecification{}))
+
+	var history []webhook.Delivery
+	for i := 0; i < 100; i++ {
+		history, _ = s.Deliveries(ctx, sub.ID)
+		if len(history) == 1 && history[0].Status == webhook.Failed {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(history) != 1 || history[0].Status != webhook.Failed {
+		t.Fatalf("history = %+v; want a failed delivery", history)
+	}
+	if n := len(history[0].Attempts); n != 3 {
+		t.Errorf("len(Attempts) = %d; want = %d", n, 3)
+	}
+}
+
+func TestDispatcherSlowSubscriber(t *testing.T) {
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	var inFlight, maxInFlight int32
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		<-release
+	}))
+	defer slow.Close()
+
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer fast.Close()
+
+	var (
+		subscriptions = inmem.NewSubscriptionRepository()
+		deliveries    = inmem.NewDeliveryRepository()
+	)
+
+	s := webhook.NewService(subscriptions, deliveries, inmem.NewCargoRepository())
+
+	slowSub, err := s.Subscribe(ctx, "acme", slow.URL, "", []webhook.EventType{webhook.CargoMisdirected})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fastSub, err := s.Subscribe(ctx, "acme", fast.URL, "", []webhook.EventType{webhook.CargoMisdirected})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := webhook.NewDispatcher(subscriptions, deliveries, log.NewNopLogger())
+	d.Workers = 2
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go d.Run(runCtx)
+
+	c := shipping.NewCargo("ABC123", shipping.RouteSpecification{})
+	d.CargoWasMisdirected(ctx, c)
+	d.CargoWasMisdirected(ctx, c)
+
+	delivered := func(id webhook.SubscriptionID) bool {
+		history, _ := s.Deliveries(ctx, id)
+		for _, del := range history {
+			if del.Status != webhook.Delivered {
+				return false
+			}
+		}
+		return len(history) == 2
+	}
+
+	// The fast subscriber gets its notifications while the slow one is
+	// still busy with its first.
+	for i := 0; i < 100 && !delivered(fastSub.ID); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !delivered(fastSub.ID) {
+		t.Fatal("notifications to the fast subscriber weren't delivered")
+	}
+
+	close(release)
+
+	for i := 0; i < 100 && !delivered(slowSub.ID); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !delivered(slowSub.ID) {
+		t.Fatal("notifications to the slow subscriber weren't delivered")
+	}
+	if n := atomic.LoadInt32(&maxInFlight); n != 1 {
+		t.Errorf("concurrent deliveries to the slow subscriber = %d; want = %d", n, 1)
+	}
+}
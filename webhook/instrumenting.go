@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+type instrumentingService struct {
+	requestCount   metrics.Counter
+	requestLatency metrics.Histogram
+	next           Service
+}
+
+// NewInstrumentingService returns an instance of an instrumenting Service.
+func NewInstrumentingService(counter metrics.Counter, latency metrics.Histogram, s Service) Service {
+	return &instrumentingService{
+		requestCount:   counter,
+		requestLatency: latency,
+		next:           s,
+	}
+}
+
+func (s *instrumentingService) Subscribe(ctx context.Context, customer, callbackURL string, id shipping.TrackingID, events []EventType) (Subscription, error) {
+	defer func(begin time.Time) {
+		s.requestCount.With("method", "subscribe").Add(1)
+		s.requestLatency.With("method", "subscribe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return s.next.Subscribe(ctx, customer, callbackURL, id, events)
+}
+
+func (s *instrumentingService) Unsubscribe(ctx context.Context, id SubscriptionID) error {
+	defer func(begin time.Time) {
+		s.requestCount.With("method", "unsubscribe").Add(1)
+		s.requestLatency.With("method", "unsubscribe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return s.next.Unsubscribe(ctx, id)
+}
+
+func (s *instrumentingService) Subscription(ctx context.Context, id SubscriptionID) (Subscription, error) {
+	defer func(begin time.Time) {
+		s.requestCount.With("method", "load").Add(1)
+		s.requestLatency.With("method", "load").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return s.next.Subscription(ctx, id)
+}
+
+func (s *instrumentingService) Subscriptions(ctx context.Context, customer string) []Subscription {
+	defer func(begin time.Time) {
+		s.requestCount.With("method", "list_subscriptions").Add(1)
+		s.requestLatency.With("method", "list_subscriptions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return s.next.Subscriptions(ctx, customer)
+}
+
+func (s *instrumentingService) Deliveries(ctx context.Context, id SubscriptionID) ([]Delivery, error) {
+	defer func(begin time.Time) {
+		s.requestCount.With("method", "list_deliveries").Add(1)
+		s.requestLatency.With("method", "list_deliveries").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return s.next.Deliveries(ctx, id)
+}
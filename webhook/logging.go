@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+type loggingService struct {
+	logger log.Logger
+	next   Service
+}
+
+// NewLoggingService returns a new instance of a logging Service.
+func NewLoggingService(logger log.Logger, s Service) Service {
+	return &loggingService{logger, s}
+}
+
+func (s *loggingService) Subscribe(ctx context.Context, customer, callbackURL string, id shipping.TrackingID, events []EventType) (sub Subscription, err error) {
+	defer func(begin time.Time) {
+		s.logger.Log(
+			"method", "subscribe",
+			"customer", customer,
+			"url", callbackURL,
+			"tracking_id", id,
+			"subscription", sub.ID,
+			"took", time.Since(begin),
+			"err", err,
+		)
+	}(time.Now())
+	return s.next.Subscribe(ctx, customer, callbackURL, id, events)
+}
+
+func (s *loggingService) Unsubscribe(ctx context.Context, id SubscriptionID) (err error) {
+	defer func(begin time.Time) {
+		s.logger.Log("method", "unsubscribe", "subscription", id, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return s.next.Unsubscribe(ctx, id)
+}
+
+func (s *loggingService) Subscription(ctx context.Context, id SubscriptionID) (sub Subscription, err error) {
+	defer func(begin time.Time) {
+		s.logger.Log("method", "load", "subscription", id, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return s.next.Subscription(ctx, id)
+}
+
+func (s *loggingService) Subscriptions(ctx context.Context, customer string) []Subscription {
+	defer func(begin time.Time) {
+		s.logger.Log("method", "list_subscriptions", "customer", customer, "took", time.Since(begin))
+	}(time.Now())
+	return s.next.Subscriptions(ctx, customer)
+}
+
+func (s *loggingService) Deliveries(ctx context.Context, id SubscriptionID) (ds []Delivery, err error) {
+	defer func(begin time.Time) {
+		s.logger.Log("method", "list_deliveries", "subscription", id, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return s.next.Deliveries(ctx, id)
+}
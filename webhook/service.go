@@ -0,0 +1,155 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// ErrInvalidArgument is returned when one or more arguments are invalid.
+var ErrInvalidArgument = shipping.NewError(shipping.CodeInvalidArgument, "invalid argument")
+
+// Service is the interface that provides webhook subscription methods.
+type Service interface {
+	// Subscribe registers a callback URL for events about a cargo, or about
+	// every cargo if id is empty. The returned subscription holds the secret
+	// used to sign the notifications.
+	Subscribe(ctx context.Context, customer, callbackURL string, id shipping.TrackingID, events []EventType) (Subscription, error)
+
+	// Unsubscribe removes a subscription.
+	Unsubscribe(ctx context.Context, id SubscriptionID) error
+
+	// Subscription returns a subscription, without its secret.
+	Subscription(ctx context.Context, id SubscriptionID) (Subscription, error)
+
+	// Subscriptions returns all subscriptions, or those of a customer if it's
+	// given, without their secrets.
+	Subscriptions(ctx context.Context, customer string) []Subscription
+
+	// Deliveries returns the delivery log of a subscription, oldest first.
+	Deliveries(ctx context.Context, id SubscriptionID) ([]Delivery, error)
+}
+
+type service struct {
+	subscriptions SubscriptionRepository
+	deliveries    DeliveryRepository
+	cargos        shipping.CargoRepository
+}
+
+func (s *service) Subscribe(ctx context.Context, customer, callbackURL string, id shipping.TrackingID, events []EventType) (Subscription, error) {
+	var fields []shipping.FieldError
+	if customer == "" {
+		fields = append(fields, shipping.FieldError{Name: "customer", Reason: "is required"})
+	}
+	if callbackURL == "" {
+		fields = append(fields, shipping.FieldError{Name: "url", Reason: "is required"})
+	} else if u, err := url.Parse(callbackURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fields = append(fields, shipping.FieldError{Name: "url", Reason: "must be an absolute HTTP(S) URL"})
+	}
+	if len(events) == 0 {
+		fields = append(fields, shipping.FieldError{Name: "events", Reason: "is required"})
+	}
+	for _, e := range events {
+		if !validEvent(e) {
+			fields = append(fields, shipping.FieldError{Name: "events", Reason: "unknown event " + string(e)})
+		}
+	}
+	if len(fields) > 0 {
+		return Subscription{}, ErrInvalidArgument.WithFields(fields...)
+	}
+
+	if id != "" {
+		if _, err := s.cargos.Find(ctx, id); err != nil {
+			return Subscription{}, err
+		}
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	sub := &Subscription{
+		ID:       NextSubscriptionID(),
+		URL:      callbackURL,
+		Customer: customer,
+		Cargo:    id,
+		Events:   events,
+		Created:  time.Now(),
+		Secret:   secret,
+	}
+
+	if err := s.subscriptions.Store(ctx, sub); err != nil {
+		return Subscription{}, err
+	}
+
+	return *sub, nil
+}
+
+func (s *service) Unsubscribe(ctx context.Context, id SubscriptionID) error {
+	return s.subscriptions.Remove(ctx, id)
+}
+
+func (s *service) Subscription(ctx context.Context, id SubscriptionID) (Subscription, error) {
+	sub, err := s.subscriptions.Find(ctx, id)
+	if err != nil {
+		return Subscription{}, err
+	}
+	sub.Secret = ""
+	return *sub, nil
+}
+
+func (s *service) Subscriptions(ctx context.Context, customer string) []Subscription {
+	result := []Subscription{}
+	for _, sub := range s.subscriptions.FindAll(ctx) {
+		if customer != "" && sub.Customer != customer {
+			continue
+		}
+		sub.Secret = ""
+		result = append(result, *sub)
+	}
+	return result
+}
+
+func (s *service) Deliveries(ctx context.Context, id SubscriptionID) ([]Delivery, error) {
+	if _, err := s.subscriptions.Find(ctx, id); err != nil {
+		return nil, err
+	}
+
+	result := []Delivery{}
+	for _, d := range s.deliveries.FindBySubscription(ctx, id) {
+		result = append(result, *d)
+	}
+	return result, nil
+}
+
+// NewService returns a new instance of the default Service.
+func NewService(subscriptions SubscriptionRepository, deliveries DeliveryRepository, cargos shipping.CargoRepository) Service {
+	return &service{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		cargos:        cargos,
+	}
+}
+
+func validEvent(e EventType) bool {
+	for _, t := range EventTypes {
+		if t == e {
+			return true
+		}
+	}
+	return false
+}
+
+// newSecret returns a random secret for signing notifications.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
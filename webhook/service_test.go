@@ -0,0 +1,66 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	shipping "github.com/marcusolsson/goddd"
+	"github.com/marcusolsson/goddd/inmem"
+	"github.com/marcusolsson/goddd/webhook"
+)
+
+func TestSubscribe(t *testing.T) {
+	ctx := context.Background()
+
+	s := webhook.NewService(inmem.NewSubscriptionRepository(), inmem.NewDeliveryRepository(), inmem.NewCargoRepository())
+
+	sub, err := s.Subscribe(ctx, "acme", "https://example.com/hook", "", []webhook.EventType{webhook.CargoArrived})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Secret == "" {
+		t.Error("Secret is empty")
+	}
+
+	got, err := s.Subscription(ctx, sub.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Secret != "" {
+		t.Errorf("Secret = %q; want = %q", got.Secret, "")
+	}
+
+	if n := len(s.Subscriptions(ctx, "other")); n != 0 {
+		t.Errorf("len(Subscriptions) = %d; want = %d", n, 0)
+	}
+
+	if err := s.Unsubscribe(ctx, sub.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Deliveries(ctx, sub.ID); !errors.Is(err, webhook.ErrUnknownSubscription) {
+		t.Errorf("err = %v; want = %v", err, webhook.ErrUnknownSubscription)
+	}
+}
+
+func TestSubscribeInvalidArguments(t *testing.T) {
+	ctx := context.Background()
+
+	s := webhook.NewService(inmem.NewSubscriptionRepository(), inmem.NewDeliveryRepository(), inmem.NewCargoRepository())
+
+	_, err := s.Subscribe(ctx, "", "ftp://example.com", "", []webhook.EventType{"cargo.lost"})
+	if !errors.Is(err, webhook.ErrInvalidArgument) {
+		t.Fatalf("err = %v; want = %v", err, webhook.ErrInvalidArgument)
+	}
+
+	var e *shipping.Error
+	errors.As(err, &e)
+	if n := len(e.Fields); n != 3 {
+		t.Errorf("len(Fields) = %d; want = %d (%v)", n, 3, e.Fields)
+	}
+
+	_, err = s.Subscribe(ctx, "acme", "https://example.com/hook", "ABC123", []webhook.EventType{webhook.CargoArrived})
+	if !errors.Is(err, shipping.ErrUnknownCargo) {
+		t.Errorf("err = %v; want = %v", err, shipping.ErrUnknownCargo)
+	}
+}
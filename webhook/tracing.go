@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+type tracingService struct {
+	tracer trace.Tracer
+	next   Service
+}
+
+// NewTracingService returns a new instance of a tracing Service.
+func NewTracingService(tracer trace.Tracer, s Service) Service {
+	return &tracingService{tracer, s}
+}
+
+func (s *tracingService) Subscribe(ctx context.Context, customer, callbackURL string, id shipping.TrackingID, events []EventType) (sub Subscription, err error) {
+	ctx, span := s.tracer.Start(ctx, "webhook.Subscribe", trace.WithAttributes(
+		attribute.String("customer", customer),
+		attribute.String("tracking_id", string(id)),
+	))
+	defer func() {
+		span.SetAttributes(attribute.String("subscription_id", string(sub.ID)))
+		endSpan(span, err)
+	}()
+	return s.next.Subscribe(ctx, customer, callbackURL, id, events)
+}
+
+func (s *tracingService) Unsubscribe(ctx context.Context, id SubscriptionID) (err error) {
+	ctx, span := s.tracer.Start(ctx, "webhook.Unsubscribe", trace.WithAttributes(
+		attribute.String("subscription_id", string(id)),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.Unsubscribe(ctx, id)
+}
+
+func (s *tracingService) Subscription(ctx context.Context, id SubscriptionID) (sub Subscription, err error) {
+	ctx, span := s.tracer.Start(ctx, "webhook.Subscription", trace.WithAttributes(
+		attribute.String("subscription_id", string(id)),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.Subscription(ctx, id)
+}
+
+func (s *tracingService) Subscriptions(ctx context.Context, customer string) []Subscription {
+	ctx, span := s.tracer.Start(ctx, "webhook.Subscriptions", trace.WithAttributes(
+		attribute.String("customer", customer),
+	))
+	defer span.End()
+	return s.next.Subscriptions(ctx, customer)
+}
+
+func (s *tracingService) Deliveries(ctx context.Context, id SubscriptionID) (ds []Delivery, err error) {
+	ctx, span := s.tracer.Start(ctx, "webhook.Deliveries", trace.WithAttributes(
+		attribute.String("subscription_id", string(id)),
+	))
+	defer func() { endSpan(span, err) }()
+	return s.next.Deliveries(ctx, id)
+}
+
+// endSpan ends span, marking it as failed if err isn't nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
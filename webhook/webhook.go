@@ -0,0 +1,183 @@
+// Package webhook provides the use-case of notifying external systems about
+// cargo inspection events, by posting signed JSON to callback URLs.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pborman/uuid"
+
+	shipping "github.com/marcusolsson/goddd"
+)
+
+// ErrUnknownSubscription is used when a subscription could not be found.
+var ErrUnknownSubscription = shipping.NewError(shipping.CodeUnknownSubscription, "unknown subscription")
+
+// ErrInvalidSignature is returned by Verify when a signature doesn't match.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// EventType identifies the kind of event a notification is sent for.
+type EventType string
+
+// Events that can be subscribed to.
+const (
+	CargoMisdirected EventType = "cargo.misdirected"
+	CargoArrived     EventType = "cargo.arrived"
+)
+
+// EventTypes lists all events that can be subscribed to.
+var EventTypes = []EventType{CargoMisdirected, CargoArrived}
+
+// SubscriptionID uniquely identifies a subscription.
+type SubscriptionID string
+
+// NextSubscriptionID generates a new subscription ID.
+func NextSubscriptionID() SubscriptionID {
+	return SubscriptionID(uuid.New())
+}
+
+// Subscription registers a callback URL of a customer for events about a
+// single cargo or, if no cargo is given, about every cargo.
+type Subscription struct {
+	ID       SubscriptionID      `json:"id"`
+	URL      string              `json:"url"`
+	Customer string              `json:"customer"`
+	Cargo    shipping.TrackingID `json:"tracking_id,omitempty"`
+	Events   []EventType         `json:"events"`
+	Created  time.Time           `json:"created"`
+
+	// Secret is used to sign notifications. It's only returned when the
+	// subscription is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+// Matches returns whether the subscription wants to be notified about event
+// for the given cargo.
+func (s *Subscription) Matches(event EventType, id shipping.TrackingID) bool {
+	if s.Cargo != "" && s.Cargo != id {
+		return false
+	}
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryID uniquely identifies a delivery.
+type DeliveryID string
+
+// NextDeliveryID generates a new delivery ID.
+func NextDeliveryID() DeliveryID {
+	return DeliveryID(uuid.New())
+}
+
+// DeliveryStatus describes how far a delivery has come.
+type DeliveryStatus string
+
+// Possible delivery statuses.
+const (
+	Pending   DeliveryStatus = "pending"
+	Delivered DeliveryStatus = "delivered"
+	Failed    DeliveryStatus = "failed"
+)
+
+// Delivery is a notification sent, or to be sent, to a subscription. The
+// payload is fixed when the event happens, so that retries send the same
+// notification.
+type Delivery struct {
+	ID           DeliveryID      `json:"id"`
+	Subscription SubscriptionID  `json:"subscription_id"`
+	Event        EventType       `json:"event"`
+	Payload      json.RawMessage `json:"payload"`
+	Status       DeliveryStatus  `json:"status"`
+	Attempts     []Attempt       `json:"attempts"`
+	NextAttempt  time.Time       `json:"next_attempt,omitzero"`
+	Created      time.Time       `json:"created"`
+}
+
+// Attempt records a single try at delivering a notification.
+type Attempt struct {
+	Time       time.Time     `json:"time"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration_ns"`
+}
+
+// SubscriptionRepository provides access to a subscription store.
+type SubscriptionRepository interface {
+	Store(ctx context.Context, s *Subscription) error
+	Find(ctx context.Context, id SubscriptionID) (*Subscription, error)
+	FindAll(ctx context.Context) []*Subscription
+	Remove(ctx context.Context, id SubscriptionID) error
+}
+
+// DeliveryRepository provides access to the delivery log.
+type DeliveryRepository interface {
+	Store(ctx context.Context, d *Delivery) error
+
+	// FindBySubscription returns the deliveries to a subscription, oldest
+	// first.
+	FindBySubscription(ctx context.Context, id SubscriptionID) []*Delivery
+
+	// FindPending returns all deliveries that are still pending.
+	FindPending(ctx context.Context) []*Delivery
+}
+
+// Sign returns the signature header value of a notification body sent at t.
+// The signature is a HMAC-SHA256 of the timestamp and the body, so that a
+// captured notification can't be replayed later on.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+// Verify checks a signature header created by Sign. Signatures older than
+// tolerance are rejected, unless tolerance is zero.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			sig = kv[1]
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 && time.Since(time.Unix(unix, 0)) > tolerance {
+		return fmt.Errorf("%w: too old", ErrInvalidSignature)
+	}
+
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, mac(secret, ts, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(ts))
+	m.Write([]byte("."))
+	m.Write(body)
+	return m.Sum(nil)
+}
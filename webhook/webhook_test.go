@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+
+	sig := Sign("secret", time.Now(), body)
+
+	if err := Verify("secret", sig, body, time.Minute); err != nil {
+		t.Errorf("Verify() = %v; want = %v", err, nil)
+	}
+	if err := Verify("other", sig, body, time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() = %v; want = %v", err, ErrInvalidSignature)
+	}
+	if err := Verify("secret", sig, []byte(`{"id":"2"}`), time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() = %v; want = %v", err, ErrInvalidSignature)
+	}
+
+	old := Sign("secret", time.Now().Add(-time.Hour), body)
+	if err := Verify("secret", old, body, time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() = %v; want = %v", err, ErrInvalidSignature)
+	}
+	if err := Verify("secret", old, body, 0); err != nil {
+		t.Errorf("Verify() = %v; want = %v", err, nil)
+	}
+}
+
+func TestSubscriptionMatches(t *testing.T) {
+	for _, tt := range []struct {
+		sub   Subscription
+		event EventType
+		want  bool
+	}{
+		{Subscription{Cargo: "ABC123", Events: []EventType{CargoArrived}}, CargoArrived, true},
+		{Subscription{Cargo: "ABC123", Events: []EventType{CargoArrived}}, CargoMisdirected, false},
+		{Subscription{Cargo: "XYZ789", Events: []EventType{CargoArrived}}, CargoArrived, false},
+		{Subscription{Events: []EventType{CargoArrived}}, CargoArrived, true},
+	} {
+		if got := tt.sub.Matches(tt.event, "ABC123"); got != tt.want {
+			t.Errorf("%+v.Matches(%s) = %v; want = %v", tt.sub, tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dispatcher{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	for attempts, want := range []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := d.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v; want = %v", attempts, got, want)
+		}
+	}
+}